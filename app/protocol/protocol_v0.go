@@ -127,6 +127,9 @@ type ProtocolV0 struct {
 	upgradeKeeper  upgrade.Keeper
 	debugKeeper    debug.Keeper
 
+	// the slashing keeper with the direct lookup of the signing infos for staking
+	signingInfoKeeper staking.SigningInfoKeeper
+
 	stopped     bool
 	anteHandler sdk.AnteHandler // ante handler for fee and auth
 	router      sdk.Router      // handle any kind of message
@@ -280,6 +283,7 @@ func (p *ProtocolV0) produceKeepers() {
 	p.mintKeeper = mint.NewKeeper(
 		p.cdc, p.keys[mint.StoreKey], mintSubspace, &stakingKeeper, p.supplyKeeper, auth.FeeCollectorName,
 	)
+	stakingKeeper.SetMintKeeper(p.mintKeeper)
 
 	p.distrKeeper = distr.NewKeeper(p.cdc, p.keys[distr.StoreKey],
 		distrSubspace, &stakingKeeper, p.supplyKeeper,
@@ -289,6 +293,7 @@ func (p *ProtocolV0) produceKeepers() {
 	p.slashingKeeper = slashing.NewKeeper(
 		p.cdc, p.keys[slashing.StoreKey], &stakingKeeper, slashingSubspace, slashing.DefaultCodespace,
 	)
+	p.signingInfoKeeper = staking.NewSigningInfoKeeper(p.slashingKeeper, p.keys[slashing.StoreKey], p.cdc)
 
 	p.crisisKeeper = crisis.NewKeeper(crisisSubspace, p.invCheckPeriod, p.supplyKeeper, auth.FeeCollectorName)
 
@@ -316,11 +321,13 @@ func (p *ProtocolV0) produceKeepers() {
 	govRouter.AddRoute(gov.RouterKey, gov.ProposalHandler).
 		AddRoute(params.RouterKey, params.NewParamChangeProposalHandler(&p.paramsKeeper)).
 		AddRoute(dex.RouterKey, dex.NewProposalHandler(&p.dexKeeper)).
-		AddRoute(upgrade.RouterKey, upgrade.NewAppUpgradeProposalHandler(&p.upgradeKeeper))
+		AddRoute(upgrade.RouterKey, upgrade.NewAppUpgradeProposalHandler(&p.upgradeKeeper)).
+		AddRoute(staking.RouterKey, staking.NewProposalHandler(&p.stakingKeeper, p.signingInfoKeeper))
 	govProposalHandlerRouter := keeper.NewProposalHandlerRouter()
 	govProposalHandlerRouter.AddRoute(params.RouterKey, &p.paramsKeeper).
 		AddRoute(dex.RouterKey, &p.dexKeeper).
-		AddRoute(upgrade.RouterKey, &p.upgradeKeeper)
+		AddRoute(upgrade.RouterKey, &p.upgradeKeeper).
+		AddRoute(staking.RouterKey, &p.stakingKeeper)
 	p.govKeeper = gov.NewKeeper(
 		p.cdc, p.keys[gov.StoreKey], p.paramsKeeper, govSubspace,
 		p.supplyKeeper, &stakingKeeper, gov.DefaultCodespace, govRouter,
@@ -459,6 +466,9 @@ func (p *ProtocolV0) InitChainer(ctx sdk.Context, req abci.RequestInitChain) abc
 
 // BeginBlocker set function to BaseApp as a hook
 func (p *ProtocolV0) BeginBlocker(ctx sdk.Context, req abci.RequestBeginBlock) abci.ResponseBeginBlock {
+	// the staking store written by the earlier software is migrated on the first block after the upgrade, before any
+	// module reads it
+	p.stakingKeeper.MigrateStore(ctx, p.signingInfoKeeper)
 	return p.mm.BeginBlock(ctx, req)
 }
 
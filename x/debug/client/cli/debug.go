@@ -8,6 +8,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/client/context"
 	"github.com/cosmos/cosmos-sdk/codec"
 	"github.com/okex/okchain/x/debug/types"
+	stakingtypes "github.com/okex/okchain/x/staking/types"
 	"github.com/spf13/cobra"
 )
 
@@ -24,6 +25,7 @@ func GetDebugCmd(cdc *codec.Codec) *cobra.Command {
 	queryCmd.AddCommand(client.GetCommands(
 		CmdSetLogLevel(queryRoute, cdc),
 		CmdDumpStore(queryRoute, cdc),
+		CmdPowerIndex(cdc),
 	)...)
 
 	return queryCmd
@@ -58,6 +60,27 @@ func CmdDumpStore(queryRoute string, cdc *codec.Codec) *cobra.Command {
 	}
 }
 
+// CmdPowerIndex queries the raw entries of the staking validator power index in the consensus order
+func CmdPowerIndex(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "power-index",
+		Args:  cobra.NoArgs,
+		Short: "Query the raw entries of the validator power index in the consensus order",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			res, _, err := cliCtx.QueryWithData(
+				fmt.Sprintf("custom/%s/%s", stakingtypes.QuerierRoute, stakingtypes.QueryPowerIndex), nil)
+			if err != nil {
+				return err
+			}
+
+			var entries stakingtypes.PowerIndexEntries
+			cdc.MustUnmarshalJSON(res, &entries)
+			return cliCtx.PrintOutput(entries)
+		},
+	}
+}
+
 // CmdSetLogLevel sets log level dynamically
 func CmdSetLogLevel(queryRoute string, cdc *codec.Codec) *cobra.Command {
 	return &cobra.Command{
@@ -6,6 +6,7 @@ import (
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/okex/okchain/x/distribution/keeper"
+	"github.com/okex/okchain/x/distribution/types"
 )
 
 // BeginBlocker set the proposer for determining distribution during endblock
@@ -25,10 +26,28 @@ func BeginBlocker(ctx sdk.Context, req abci.RequestBeginBlock, k keeper.Keeper)
 		previousProposer := k.GetPreviousProposerConsAddr(ctx)
 
 		/* allocate tokens by okchain custom rule */
-		k.AllocateTokens(ctx, previousTotalPower, previousProposer, req.LastCommitInfo.GetVotes())
+		// the fees are kept in the fee collector until the epoch ends if they are distributed per epoch, and the
+		// weights of the signers and the proposer of every block are accumulated to allocate them
+		if k.GetRewardCadence(ctx) == types.RewardCadenceEpoch {
+			k.RecordEpochWeights(ctx, previousProposer, req.LastCommitInfo.GetVotes())
+			if k.IsBeginningOfEpoch(ctx) {
+				k.AllocateTokensByEpochWeights(ctx)
+			}
+		} else {
+			k.AllocateTokens(ctx, previousTotalPower, previousProposer, req.LastCommitInfo.GetVotes())
+			// drop the weights left since the fees were last distributed per epoch
+			if k.IsBeginningOfEpoch(ctx) {
+				k.DeleteEpochWeights(ctx)
+			}
+		}
 	}
 
 	// record the proposer for when we payout on the next block
 	consAddr := sdk.ConsAddress(req.Header.ProposerAddress)
 	k.SetPreviousProposerConsAddr(ctx, consAddr)
+
+	// recompute the total pending rewards at the beginning of a new epoch
+	if k.IsBeginningOfEpoch(ctx) {
+		k.UpdateTotalPendingRewards(ctx)
+	}
 }
@@ -3,6 +3,7 @@ package distribution
 import (
 	"testing"
 
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/okex/okchain/x/distribution/keeper"
 	"github.com/stretchr/testify/require"
 	abci "github.com/tendermint/tendermint/abci/types"
@@ -24,3 +25,102 @@ func TestBeginBlocker(t *testing.T) {
 		require.Equal(t, k.GetPreviousProposerConsAddr(ctx), valConsAddrs[index])
 	}
 }
+
+func TestBeginBlockerRewardCadence(t *testing.T) {
+	_, valConsPks, valConsAddrs := keeper.GetTestAddrs()
+	votes := make([]abci.VoteInfo, len(valConsPks))
+	for i, pk := range valConsPks {
+		votes[i] = abci.VoteInfo{Validator: abci.Validator{Address: pk.Address(), Power: 1}, SignedLastBlock: true}
+	}
+	fee := keeper.NewTestDecCoins(100, 0)
+
+	// distribute the fees collected in the blocks 1 to 4 with the epoch ending at the block 4
+	distribute := func(cadence string) (commissions []sdk.DecCoins) {
+		ctx, ak, k, sk, supplyKeeper := keeper.CreateTestInputDefault(t, false, 1000)
+		k.SetRewardCadence(ctx, cadence)
+		k.SetPreviousProposerConsAddr(ctx, valConsAddrs[0])
+		sk.SetTheEndOfLastEpoch(ctx.WithBlockHeight(1))
+		for i := int64(2); i <= 5; i++ {
+			ctx = ctx.WithBlockHeight(i)
+			feeCollector := supplyKeeper.GetModuleAccount(ctx, k.GetFeeCollectorName())
+			feeCoins, _ := fee.TruncateDecimal()
+			require.NoError(t, feeCollector.SetCoins(feeCollector.GetCoins().Add(feeCoins)))
+			ak.SetAccount(ctx, feeCollector)
+
+			req := abci.RequestBeginBlock{Header: abci.Header{Height: i, ProposerAddress: valConsAddrs[0].Bytes()},
+				LastCommitInfo: abci.LastCommitInfo{Votes: votes}}
+			BeginBlocker(ctx, req, k)
+			commissions = append(commissions, k.SumPendingRewards(ctx))
+			if i == 4 {
+				sk.SetTheEndOfLastEpoch(ctx)
+			}
+		}
+		require.True(t, supplyKeeper.GetModuleAccount(ctx, k.GetFeeCollectorName()).GetCoins().IsZero())
+		return
+	}
+
+	// the rewards accrue every block
+	commissions := distribute(RewardCadenceBlock)
+	for i := range commissions {
+		require.Equal(t, fee.MulDec(sdk.NewDec(int64(i+1))), commissions[i])
+	}
+
+	// the rewards accrue at the beginning of each epoch
+	commissions = distribute(RewardCadenceEpoch)
+	require.Equal(t, []sdk.DecCoins{fee, fee, fee, fee.MulDec(sdk.NewDec(4))}, commissions)
+}
+
+func TestBeginBlockerEpochWeights(t *testing.T) {
+	valOpAddrs, valConsPks, valConsAddrs := keeper.GetTestAddrs()
+	fee := keeper.NewTestDecCoins(100, 0)
+
+	// distribute the fees collected in the blocks 1 to 5 with the epochs ending at the blocks 1 and 5, while the
+	// signers and the proposer vary block by block
+	distribute := func(cadence string) (commissions []sdk.DecCoins) {
+		ctx, ak, k, sk, supplyKeeper := keeper.CreateTestInputDefault(t, false, 1000)
+		k.SetRewardCadence(ctx, cadence)
+		k.SetPreviousProposerConsAddr(ctx, valConsAddrs[0])
+		sk.SetTheEndOfLastEpoch(ctx.WithBlockHeight(1))
+		for i, signers := range []int{4, 1, 2, 4, 4} {
+			height := int64(i + 2)
+			ctx = ctx.WithBlockHeight(height)
+			feeCollector := supplyKeeper.GetModuleAccount(ctx, k.GetFeeCollectorName())
+			feeCoins, _ := fee.TruncateDecimal()
+			require.NoError(t, feeCollector.SetCoins(feeCollector.GetCoins().Add(feeCoins)))
+			ak.SetAccount(ctx, feeCollector)
+
+			votes := make([]abci.VoteInfo, signers)
+			for j := range votes {
+				votes[j] = abci.VoteInfo{Validator: abci.Validator{Address: valConsPks[j].Address(), Power: 1},
+					SignedLastBlock: true}
+			}
+			req := abci.RequestBeginBlock{Header: abci.Header{Height: height,
+				ProposerAddress: valConsAddrs[i%2].Bytes()}, LastCommitInfo: abci.LastCommitInfo{Votes: votes}}
+			BeginBlocker(ctx, req, k)
+			if height == 5 {
+				sk.SetTheEndOfLastEpoch(ctx)
+			}
+		}
+		require.True(t, supplyKeeper.GetModuleAccount(ctx, k.GetFeeCollectorName()).GetCoins().IsZero())
+
+		// the weights are reset for the next epoch
+		k.IterateEpochWeights(ctx, keeper.EpochSignerWeightPrefix, func(sdk.ValAddress, sdk.Dec) (stop bool) {
+			t.Fatal("the signer weights should be reset at the end of the epoch")
+			return true
+		})
+		for _, valOpAddr := range valOpAddrs {
+			commissions = append(commissions, k.GetValidatorAccumulatedCommission(ctx, valOpAddr))
+		}
+		return
+	}
+
+	// the validators earn the same over the epoch no matter the fees are distributed per block or per epoch
+	byBlock := distribute(RewardCadenceBlock)
+	byEpoch := distribute(RewardCadenceEpoch)
+	require.Equal(t, byBlock, byEpoch)
+	total := sdk.DecCoins{}
+	for _, commission := range byEpoch {
+		total = total.Add(commission)
+	}
+	require.Equal(t, fee.MulDec(sdk.NewDec(5)), total)
+}
@@ -17,6 +17,8 @@ const (
 	CodeInvalidInput            = types.CodeInvalidInput
 	CodeNoValidatorCommission   = types.CodeNoValidatorCommission
 	CodeSetWithdrawAddrDisabled = types.CodeSetWithdrawAddrDisabled
+	CodeUnknownValidator        = types.CodeUnknownValidator
+	CodeInsufficientCommission  = types.CodeInsufficientCommission
 	ModuleName                  = types.ModuleName
 	StoreKey                    = types.StoreKey
 	RouterKey                   = types.RouterKey
@@ -24,7 +26,11 @@ const (
 	QueryParams                 = types.QueryParams
 	QueryValidatorCommission    = types.QueryValidatorCommission
 	QueryWithdrawAddr           = types.QueryWithdrawAddr
+	QueryValidatorWithdrawAddr  = types.QueryValidatorWithdrawAddr
 	ParamWithdrawAddrEnabled    = types.ParamWithdrawAddrEnabled
+	ParamRewardCadence          = types.ParamRewardCadence
+	RewardCadenceBlock          = types.RewardCadenceBlock
+	RewardCadenceEpoch          = types.RewardCadenceEpoch
 )
 
 var (
@@ -35,6 +41,10 @@ var (
 	GetValidatorAccumulatedCommissionAddress = keeper.GetValidatorAccumulatedCommissionAddress
 	GetDelegatorWithdrawAddrKey              = keeper.GetDelegatorWithdrawAddrKey
 	GetValidatorAccumulatedCommissionKey     = keeper.GetValidatorAccumulatedCommissionKey
+	GetValidatorWithdrawInfoAddress          = keeper.GetValidatorWithdrawInfoAddress
+	GetValidatorWithdrawAddrKey              = keeper.GetValidatorWithdrawAddrKey
+	GetEpochSignerWeightKey                  = keeper.GetEpochSignerWeightKey
+	GetEpochProposerWeightKey                = keeper.GetEpochProposerWeightKey
 	ParamKeyTable                            = keeper.ParamKeyTable
 	NewQuerier                               = keeper.NewQuerier
 	RegisterCodec                            = types.RegisterCodec
@@ -43,20 +53,30 @@ var (
 	ErrNilValidatorAddr                      = types.ErrNilValidatorAddr
 	ErrNoValidatorCommission                 = types.ErrNoValidatorCommission
 	ErrSetWithdrawAddrDisabled               = types.ErrSetWithdrawAddrDisabled
+	ErrUnknownValidator                      = types.ErrUnknownValidator
+	ErrInvalidCommissionAmount               = types.ErrInvalidCommissionAmount
+	ErrInsufficientCommission                = types.ErrInsufficientCommission
 	NewGenesisState                          = types.NewGenesisState
 	DefaultGenesisState                      = types.DefaultGenesisState
 	ValidateGenesis                          = types.ValidateGenesis
+	ValidateRewardCadence                    = types.ValidateRewardCadence
 	NewMsgSetWithdrawAddress                 = types.NewMsgSetWithdrawAddress
 	NewMsgWithdrawValidatorCommission        = types.NewMsgWithdrawValidatorCommission
+	NewMsgSetValidatorWithdrawAddress        = types.NewMsgSetValidatorWithdrawAddress
 	NewQueryValidatorCommissionParams        = types.NewQueryValidatorCommissionParams
 	NewQueryDelegatorWithdrawAddrParams      = types.NewQueryDelegatorWithdrawAddrParams
+	NewQueryValidatorWithdrawAddrParams      = types.NewQueryValidatorWithdrawAddrParams
 	InitialValidatorAccumulatedCommission    = types.InitialValidatorAccumulatedCommission
 
 	// variable aliases
 	ProposerKey                          = keeper.ProposerKey
 	DelegatorWithdrawAddrPrefix          = keeper.DelegatorWithdrawAddrPrefix
 	ValidatorAccumulatedCommissionPrefix = keeper.ValidatorAccumulatedCommissionPrefix
+	ValidatorWithdrawAddrPrefix          = keeper.ValidatorWithdrawAddrPrefix
+	EpochSignerWeightPrefix              = keeper.EpochSignerWeightPrefix
+	EpochProposerWeightPrefix            = keeper.EpochProposerWeightPrefix
 	ParamStoreKeyWithdrawAddrEnabled     = keeper.ParamStoreKeyWithdrawAddrEnabled
+	ParamStoreKeyRewardCadence           = keeper.ParamStoreKeyRewardCadence
 	ModuleCdc                            = types.ModuleCdc
 	EventTypeSetWithdrawAddress          = types.EventTypeSetWithdrawAddress
 	EventTypeCommission                  = types.EventTypeCommission
@@ -72,11 +92,14 @@ type (
 	Keeper                               = keeper.Keeper
 	CodeType                             = types.CodeType
 	DelegatorWithdrawInfo                = types.DelegatorWithdrawInfo
+	ValidatorWithdrawInfo                = types.ValidatorWithdrawInfo
 	ValidatorAccumulatedCommissionRecord = types.ValidatorAccumulatedCommissionRecord
 	GenesisState                         = types.GenesisState
 	MsgSetWithdrawAddress                = types.MsgSetWithdrawAddress
 	MsgWithdrawValidatorCommission       = types.MsgWithdrawValidatorCommission
+	MsgSetValidatorWithdrawAddress       = types.MsgSetValidatorWithdrawAddress
 	QueryValidatorCommissionParams       = types.QueryValidatorCommissionParams
 	QueryDelegatorWithdrawAddrParams     = types.QueryDelegatorWithdrawAddrParams
+	QueryValidatorWithdrawAddrParams     = types.QueryValidatorWithdrawAddrParams
 	ValidatorAccumulatedCommission       = types.ValidatorAccumulatedCommission
 )
@@ -29,6 +29,8 @@ func GetQueryCmd(queryRoute string, cdc *codec.Codec) *cobra.Command {
 	distQueryCmd.AddCommand(client.GetCommands(
 		GetCmdQueryParams(queryRoute, cdc),
 		GetCmdQueryValidatorCommission(queryRoute, cdc),
+		GetCmdQueryValidatorWithdrawAddr(queryRoute, cdc),
+		GetCmdQueryTotalPendingRewards(queryRoute, cdc),
 	)...)
 
 	return distQueryCmd
@@ -92,3 +94,74 @@ $ %s query distr commission okchainvaloper1alq9na49n9yycysh889rl90g9nhe58lcs50wu
 		},
 	}
 }
+
+// GetCmdQueryValidatorWithdrawAddr implements the query validator withdraw address command.
+func GetCmdQueryValidatorWithdrawAddr(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "validator-withdraw-addr [validator]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Query the address where the commission of a validator is withdrawn to",
+		Long: strings.TrimSpace(
+			fmt.Sprintf(`Query the address where the commission of a validator is withdrawn to.
+
+Example:
+$ %s query distr validator-withdraw-addr okchainvaloper1alq9na49n9yycysh889rl90g9nhe58lcs50wu5
+`,
+				version.ClientName,
+			),
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			validatorAddr, err := sdk.ValAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+
+			res, err := common.QueryValidatorWithdrawAddr(cliCtx, queryRoute, validatorAddr)
+			if err != nil {
+				return err
+			}
+
+			var withdrawAddr sdk.AccAddress
+			if err := cdc.UnmarshalJSON(res, &withdrawAddr); err != nil {
+				return err
+			}
+			return cliCtx.PrintOutput(withdrawAddr)
+		},
+	}
+}
+
+// GetCmdQueryTotalPendingRewards implements the query total pending rewards command.
+func GetCmdQueryTotalPendingRewards(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "total-pending-rewards",
+		Args:  cobra.NoArgs,
+		Short: "Query the sum of the unwithdrawn rewards in the network",
+		Long: strings.TrimSpace(
+			fmt.Sprintf(`Query the sum of the unwithdrawn rewards in the network, which is recomputed at the beginning
+of each epoch and returned with the height of the computation.
+
+Example:
+$ %s query distr total-pending-rewards
+`,
+				version.ClientName,
+			),
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			res, _, err := cliCtx.QueryWithData(
+				fmt.Sprintf("custom/%s/%s", queryRoute, types.QueryTotalPendingRewards), nil)
+			if err != nil {
+				return err
+			}
+
+			var totalRewards types.TotalPendingRewards
+			if err := cdc.UnmarshalJSON(res, &totalRewards); err != nil {
+				return err
+			}
+			return cliCtx.PrintOutput(totalRewards)
+		},
+	}
+}
@@ -13,10 +13,13 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/cosmos/cosmos-sdk/x/auth/client/utils"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"github.com/okex/okchain/x/distribution/types"
 )
 
+const flagCommissionAmount = "amount"
+
 // GetTxCmd returns the transaction commands for this module
 func GetTxCmd(storeKey string, cdc *codec.Codec) *cobra.Command {
 	distTxCmd := &cobra.Command{
@@ -30,6 +33,7 @@ func GetTxCmd(storeKey string, cdc *codec.Codec) *cobra.Command {
 	distTxCmd.AddCommand(client.PostCommands(
 		GetCmdWithdrawRewards(cdc),
 		GetCmdSetWithdrawAddr(cdc),
+		GetCmdSetValidatorWithdrawAddr(cdc),
 	)...)
 
 	return distTxCmd
@@ -67,6 +71,39 @@ $ %s tx distr set-withdraw-addr okchain1hw4r48aww06ldrfeuq2v438ujnl6alszzzqpph -
 	}
 }
 
+// GetCmdSetValidatorWithdrawAddr command to replace the address where the commission of a validator is withdrawn to
+func GetCmdSetValidatorWithdrawAddr(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-validator-withdraw-addr [withdraw-addr]",
+		Short: "change the address where the commission of a validator is withdrawn to",
+		Long: strings.TrimSpace(
+			fmt.Sprintf(`Set the withdraw address for the commission of the validator operated by the from address.
+Set it to the operator address itself to reset it to default.
+
+Example:
+$ %s tx distr set-validator-withdraw-addr okchain1hw4r48aww06ldrfeuq2v438ujnl6alszzzqpph --from mykey
+`,
+				version.ClientName,
+			),
+		),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			valAddr := sdk.ValAddress(cliCtx.GetFromAddress())
+			withdrawAddr, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid address：%s", args[0])
+			}
+
+			msg := types.NewMsgSetValidatorWithdrawAddress(valAddr, withdrawAddr)
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
 // GetCmdWithdrawRewards command to withdraw rewards
 func GetCmdWithdrawRewards(cdc *codec.Codec) *cobra.Command {
 	cmd := &cobra.Command{
@@ -76,8 +113,9 @@ func GetCmdWithdrawRewards(cdc *codec.Codec) *cobra.Command {
 			fmt.Sprintf(`
 Example:
 $ %s tx distr withdraw-rewards okchainvaloper1alq9na49n9yycysh889rl90g9nhe58lcs50wu5 --from mykey 
+$ %s tx distr withdraw-rewards okchainvaloper1alq9na49n9yycysh889rl90g9nhe58lcs50wu5 --amount 1.5okt --from mykey
 `,
-				version.ClientName,
+				version.ClientName, version.ClientName,
 			),
 		),
 		Args: cobra.ExactArgs(1),
@@ -90,11 +128,17 @@ $ %s tx distr withdraw-rewards okchainvaloper1alq9na49n9yycysh889rl90g9nhe58lcs5
 				return err
 			}
 
+			amount, err := sdk.ParseDecCoins(viper.GetString(flagCommissionAmount))
+			if err != nil {
+				return err
+			}
+
 			// only withdraw commission of validator
-			msg := types.NewMsgWithdrawValidatorCommission(valAddr)
+			msg := types.NewMsgWithdrawValidatorCommission(valAddr, amount)
 
 			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
 		},
 	}
+	cmd.Flags().String(flagCommissionAmount, "", "part of the accumulated commission to withdraw, all of it by default")
 	return cmd
 }
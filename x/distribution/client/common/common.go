@@ -16,7 +16,13 @@ func QueryParams(cliCtx context.CLIContext, queryRoute string) (PrettyParams, er
 		return PrettyParams{}, err
 	}
 
-	return newPrettyParams(retWithdrawAddrEnabled), nil
+	route = fmt.Sprintf("custom/%s/params/%s", queryRoute, types.ParamRewardCadence)
+	retRewardCadence, _, err := cliCtx.QueryWithData(route, []byte{})
+	if err != nil {
+		return PrettyParams{}, err
+	}
+
+	return newPrettyParams(retWithdrawAddrEnabled, retRewardCadence), nil
 }
 
 // QueryValidatorCommission returns a validator's commission.
@@ -29,10 +35,20 @@ func QueryValidatorCommission(cliCtx context.CLIContext, queryRoute string, vali
 	return res, err
 }
 
+// QueryValidatorWithdrawAddr returns the address where the commission of a validator is withdrawn to.
+func QueryValidatorWithdrawAddr(cliCtx context.CLIContext, queryRoute string, validatorAddr sdk.ValAddress) (
+	[]byte, error) {
+	res, _, err := cliCtx.QueryWithData(
+		fmt.Sprintf("custom/%s/%s", queryRoute, types.QueryValidatorWithdrawAddr),
+		cliCtx.Codec.MustMarshalJSON(types.NewQueryValidatorWithdrawAddrParams(validatorAddr)),
+	)
+	return res, err
+}
+
 // WithdrawValidatorRewardsAndCommission builds a two-message message slice to be
 // used to withdraw both validation's commission and self-delegation reward.
 func WithdrawValidatorRewardsAndCommission(validatorAddr sdk.ValAddress) ([]sdk.Msg, error) {
-	commissionMsg := types.NewMsgWithdrawValidatorCommission(validatorAddr)
+	commissionMsg := types.NewMsgWithdrawValidatorCommission(validatorAddr, nil)
 	if err := commissionMsg.ValidateBasic(); err != nil {
 		return nil, err
 	}
@@ -8,17 +8,20 @@ import (
 // PrettyParams is the struct for CLI output
 type PrettyParams struct {
 	WithdrawAddrEnabled json.RawMessage `json:"withdraw_addr_enabled"`
+	RewardCadence       json.RawMessage `json:"reward_cadence"`
 }
 
 // newPrettyParams creates a new PrettyParams
-func newPrettyParams(withdrawAddrEnabled json.RawMessage) PrettyParams {
+func newPrettyParams(withdrawAddrEnabled, rewardCadence json.RawMessage) PrettyParams {
 	return PrettyParams{
 		WithdrawAddrEnabled: withdrawAddrEnabled,
+		RewardCadence:       rewardCadence,
 	}
 }
 
 // String returns the params string
 func (pp PrettyParams) String() string {
 	return fmt.Sprintf(`Distribution Params:
-  Withdraw Addr Enabled:  %s`, pp.WithdrawAddrEnabled)
+  Withdraw Addr Enabled:  %s
+  Reward Cadence:         %s`, pp.WithdrawAddrEnabled, pp.RewardCadence)
 }
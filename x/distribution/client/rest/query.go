@@ -26,6 +26,12 @@ func registerQueryRoutes(cliCtx context.CLIContext, r *mux.Router, queryRoute st
 		accumulatedCommissionHandlerFn(cliCtx, queryRoute),
 	).Methods("GET")
 
+	// Get the commission withdrawal address of a validator
+	r.HandleFunc(
+		"/distribution/validators/{validatorAddr}/withdraw_address",
+		validatorWithdrawalAddrHandlerFn(cliCtx, queryRoute),
+	).Methods("GET")
+
 	// Get the current distribution parameter values
 	r.HandleFunc(
 		"/distribution/parameters",
@@ -100,3 +106,28 @@ func accumulatedCommissionHandlerFn(cliCtx context.CLIContext, queryRoute string
 		rest.PostProcessResponse(w, cliCtx, res)
 	}
 }
+
+// HTTP request handler to query the commission withdrawal address of a validator
+func validatorWithdrawalAddrHandlerFn(cliCtx context.CLIContext, queryRoute string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		validatorAddr, ok := checkValidatorAddressVar(w, r)
+		if !ok {
+			return
+		}
+
+		cliCtx, ok = rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
+		if !ok {
+			return
+		}
+
+		bz := cliCtx.Codec.MustMarshalJSON(types.NewQueryValidatorWithdrawAddrParams(validatorAddr))
+		res, height, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", queryRoute, types.QueryValidatorWithdrawAddr), bz)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		cliCtx = cliCtx.WithHeight(height)
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
@@ -22,6 +22,12 @@ func registerTxRoutes(cliCtx context.CLIContext, r *mux.Router, _ string) {
 		setDelegatorWithdrawalAddrHandlerFn(cliCtx),
 	).Methods("POST")
 
+	// Replace the commission withdrawal address of a validator
+	r.HandleFunc(
+		"/distribution/validators/{validatorAddr}/withdraw_address",
+		setValidatorWithdrawalAddrHandlerFn(cliCtx),
+	).Methods("POST")
+
 	// Withdraw validator rewards and commission
 	r.HandleFunc(
 		"/distribution/validators/{validatorAddr}/rewards",
@@ -71,6 +77,36 @@ func setDelegatorWithdrawalAddrHandlerFn(cliCtx context.CLIContext) http.Handler
 	}
 }
 
+// Replace the commission withdrawal address of a validator
+func setValidatorWithdrawalAddrHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req setWithdrawalAddrReq
+
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			return
+		}
+
+		req.BaseReq = req.BaseReq.Sanitize()
+		if !req.BaseReq.ValidateBasic(w) {
+			return
+		}
+
+		// read and validate URL's variables
+		valAddr, ok := checkValidatorAddressVar(w, r)
+		if !ok {
+			return
+		}
+
+		msg := types.NewMsgSetValidatorWithdrawAddress(valAddr, req.WithdrawAddress)
+		if err := msg.ValidateBasic(); err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, req.BaseReq, []sdk.Msg{msg})
+	}
+}
+
 // Withdraw validator rewards and commission
 func withdrawValidatorRewardsHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -13,6 +13,7 @@ func InitGenesis(ctx sdk.Context, keeper Keeper, supplyKeeper types.SupplyKeeper
 
 	keeper.SetPreviousProposerConsAddr(ctx, data.PreviousProposer)
 	keeper.SetWithdrawAddrEnabled(ctx, data.WithdrawAddrEnabled)
+	keeper.SetRewardCadence(ctx, data.RewardCadence)
 	for _, dwi := range data.DelegatorWithdrawInfos {
 		keeper.SetDelegatorWithdrawAddr(ctx, dwi.DelegatorAddress, dwi.WithdrawAddress)
 	}
@@ -20,6 +21,9 @@ func InitGenesis(ctx sdk.Context, keeper Keeper, supplyKeeper types.SupplyKeeper
 		keeper.SetValidatorAccumulatedCommission(ctx, acc.ValidatorAddress, acc.Accumulated)
 		moduleHoldings = moduleHoldings.Add(acc.Accumulated)
 	}
+	for _, vwi := range data.ValidatorWithdrawInfos {
+		keeper.SetValidatorWithdrawAddr(ctx, vwi.ValidatorAddress, vwi.WithdrawAddress)
+	}
 
 	// check if the module account exists
 	moduleAcc := keeper.GetDistributionAccount(ctx)
@@ -58,5 +62,14 @@ func ExportGenesis(ctx sdk.Context, keeper Keeper) types.GenesisState {
 		},
 	)
 
-	return types.NewGenesisState(withdrawAddrEnabled, dwi, pp, acc)
+	vwi := make([]types.ValidatorWithdrawInfo, 0)
+	keeper.IterateValidatorWithdrawAddrs(ctx, func(val sdk.ValAddress, addr sdk.AccAddress) (stop bool) {
+		vwi = append(vwi, types.ValidatorWithdrawInfo{
+			ValidatorAddress: val,
+			WithdrawAddress:  addr,
+		})
+		return false
+	})
+
+	return types.NewGenesisState(withdrawAddrEnabled, dwi, pp, acc, vwi, keeper.GetRewardCadence(ctx))
 }
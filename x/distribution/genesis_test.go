@@ -30,16 +30,19 @@ func TestInitGenesis(t *testing.T) {
 	valOpAddrs, _, valConsAddrs := keeper.GetTestAddrs()
 	dwis := make([]DelegatorWithdrawInfo, length)
 	accs := make([]ValidatorAccumulatedCommissionRecord, length)
+	vwis := make([]ValidatorWithdrawInfo, length)
 	for i, valAddr := range valOpAddrs {
 		accs[i].ValidatorAddress = valAddr
 		accs[i].Accumulated = tests[i].commission
 		dwis[i].DelegatorAddress, dwis[i].WithdrawAddress = keeper.TestAddrs[i*2], keeper.TestAddrs[i*2+1]
+		vwis[i].ValidatorAddress, vwis[i].WithdrawAddress = valAddr, keeper.TestAddrs[i]
 	}
 
-	genesisState := NewGenesisState(true, dwis, valConsAddrs[0], accs)
+	genesisState := NewGenesisState(true, dwis, valConsAddrs[0], accs, vwis, RewardCadenceEpoch)
 	InitGenesis(ctx, k, supplyKeeper, genesisState)
 	require.Equal(t, genesisState.WithdrawAddrEnabled, k.GetWithdrawAddrEnabled(ctx))
 	require.Equal(t, genesisState.PreviousProposer, k.GetPreviousProposerConsAddr(ctx))
+	require.Equal(t, genesisState.RewardCadence, k.GetRewardCadence(ctx))
 	for i := range accs {
 		require.Equal(t, genesisState.DelegatorWithdrawInfos[i].WithdrawAddress,
 			k.GetDelegatorWithdrawAddr(ctx, dwis[i].DelegatorAddress))
@@ -47,6 +50,7 @@ func TestInitGenesis(t *testing.T) {
 			k.GetValidatorAccumulatedCommission(ctx, accs[i].ValidatorAddress))
 		require.Equal(t, tests[i].commission,
 			k.GetValidatorAccumulatedCommission(ctx, accs[i].ValidatorAddress))
+		require.Equal(t, vwis[i].WithdrawAddress, k.GetValidatorWithdrawAddr(ctx, vwis[i].ValidatorAddress))
 	}
 
 	actualGenesis := ExportGenesis(ctx, k)
@@ -54,4 +58,15 @@ func TestInitGenesis(t *testing.T) {
 	require.ElementsMatch(t, genesisState.DelegatorWithdrawInfos, actualGenesis.DelegatorWithdrawInfos)
 	require.Equal(t, genesisState.PreviousProposer, actualGenesis.PreviousProposer)
 	require.ElementsMatch(t, genesisState.ValidatorAccumulatedCommissions, actualGenesis.ValidatorAccumulatedCommissions)
+	require.ElementsMatch(t, genesisState.ValidatorWithdrawInfos, actualGenesis.ValidatorWithdrawInfos)
+	require.Equal(t, genesisState.RewardCadence, actualGenesis.RewardCadence)
+}
+
+func TestValidateGenesis(t *testing.T) {
+	genesisState := DefaultGenesisState()
+	require.NoError(t, ValidateGenesis(genesisState))
+	genesisState.RewardCadence = RewardCadenceEpoch
+	require.NoError(t, ValidateGenesis(genesisState))
+	genesisState.RewardCadence = "week"
+	require.Error(t, ValidateGenesis(genesisState))
 }
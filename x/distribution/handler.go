@@ -20,6 +20,9 @@ func NewHandler(k keeper.Keeper) sdk.Handler {
 		case types.MsgWithdrawValidatorCommission:
 			return handleMsgWithdrawValidatorCommission(ctx, msg, k)
 
+		case types.MsgSetValidatorWithdrawAddress:
+			return handleMsgSetValidatorWithdrawAddress(ctx, msg, k)
+
 		default:
 			errMsg := fmt.Sprintf("unrecognized distribution message type: %T", msg)
 			return sdk.ErrUnknownRequest(errMsg).Result()
@@ -48,7 +51,31 @@ func handleMsgModifyWithdrawAddress(ctx sdk.Context, msg types.MsgSetWithdrawAdd
 func handleMsgWithdrawValidatorCommission(ctx sdk.Context,
 	msg types.MsgWithdrawValidatorCommission, k keeper.Keeper) sdk.Result {
 
-	_, err := k.WithdrawValidatorCommission(ctx, msg.ValidatorAddress)
+	var err sdk.Error
+	if msg.Amount.Empty() {
+		_, err = k.WithdrawValidatorCommission(ctx, msg.ValidatorAddress)
+	} else {
+		_, err = k.WithdrawPartialValidatorCommission(ctx, msg.ValidatorAddress, msg.Amount)
+	}
+	if err != nil {
+		return err.Result()
+	}
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+			sdk.NewAttribute(sdk.AttributeKeySender, msg.ValidatorAddress.String()),
+		),
+	)
+
+	return sdk.Result{Events: ctx.EventManager().Events()}
+}
+
+func handleMsgSetValidatorWithdrawAddress(ctx sdk.Context,
+	msg types.MsgSetValidatorWithdrawAddress, k keeper.Keeper) sdk.Result {
+
+	err := k.SetValidatorWithdrawAddress(ctx, msg.ValidatorAddress, msg.WithdrawAddress)
 	if err != nil {
 		return err.Result()
 	}
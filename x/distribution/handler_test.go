@@ -33,7 +33,7 @@ func TestHandler(t *testing.T) {
 	k.AllocateTokens(ctx, 100, valConsAddrs[0], votes)
 
 	//send withdraw-commission msgWithdrawValCommission
-	msgWithdrawValCommission := types.NewMsgWithdrawValidatorCommission(valOpAddrs[0])
+	msgWithdrawValCommission := types.NewMsgWithdrawValidatorCommission(valOpAddrs[0], nil)
 	require.True(t, dh(ctx, msgWithdrawValCommission).IsOK())
 	require.False(t, dh(ctx, msgWithdrawValCommission).IsOK())
 
@@ -51,6 +51,116 @@ func TestHandler(t *testing.T) {
 	require.False(t, dh(ctx, fakeMsg).IsOK())
 }
 
+func TestHandlerSetValidatorWithdrawAddress(t *testing.T) {
+	valOpAddrs, valConsPks, valConsAddrs := keeper.GetTestAddrs()
+	ctx, ak, _, k, sk, _, supplyKeeper := keeper.CreateTestInputAdvanced(t, false, 1000)
+	dh := NewHandler(k)
+
+	// create one validator
+	sh := staking.NewHandler(sk)
+	skMsg := staking.NewMsgCreateValidator(valOpAddrs[0], valConsPks[0],
+		staking.Description{}, keeper.NewTestDecCoin(1, 0))
+	require.True(t, sh(ctx, skMsg).IsOK())
+
+	// default to the operator
+	operatorAddr := sdk.AccAddress(valOpAddrs[0])
+	require.Equal(t, operatorAddr, k.GetValidatorWithdrawAddr(ctx, valOpAddrs[0]))
+
+	// set a custom address
+	coldWallet := keeper.TestAddrs[5]
+	msg := types.NewMsgSetValidatorWithdrawAddress(valOpAddrs[0], coldWallet)
+	require.True(t, dh(ctx, msg).IsOK())
+	require.Equal(t, coldWallet, k.GetValidatorWithdrawAddr(ctx, valOpAddrs[0]))
+
+	// the commission is withdrawn to the custom address
+	feeCollector := supplyKeeper.GetModuleAccount(ctx, k.GetFeeCollectorName())
+	require.NoError(t, feeCollector.SetCoins(keeper.NewTestDecCoins(1, 0)))
+	ak.SetAccount(ctx, feeCollector)
+	abciVal := abci.Validator{Address: valConsPks[0].Address(), Power: 1}
+	votes := []abci.VoteInfo{{Validator: abciVal, SignedLastBlock: true}}
+	k.AllocateTokens(ctx, 100, valConsAddrs[0], votes)
+
+	operatorCoins := ak.GetAccount(ctx, operatorAddr).GetCoins()
+	walletCoins := ak.GetAccount(ctx, coldWallet).GetCoins()
+	require.True(t, dh(ctx, types.NewMsgWithdrawValidatorCommission(valOpAddrs[0], nil)).IsOK())
+	require.Equal(t, operatorCoins, ak.GetAccount(ctx, operatorAddr).GetCoins())
+	require.Equal(t, walletCoins.Add(keeper.NewTestDecCoins(1, 0)), ak.GetAccount(ctx, coldWallet).GetCoins())
+
+	// reset to default
+	msg = types.NewMsgSetValidatorWithdrawAddress(valOpAddrs[0], operatorAddr)
+	require.True(t, dh(ctx, msg).IsOK())
+	require.Equal(t, operatorAddr, k.GetValidatorWithdrawAddr(ctx, valOpAddrs[0]))
+	k.IterateValidatorWithdrawAddrs(ctx, func(sdk.ValAddress, sdk.AccAddress) (stop bool) {
+		t.Fatal("the withdraw address should be deleted when reset")
+		return true
+	})
+
+	// validator not found
+	msg = types.NewMsgSetValidatorWithdrawAddress(valOpAddrs[1], coldWallet)
+	require.False(t, dh(ctx, msg).IsOK())
+
+	// blacklisted address
+	msg = types.NewMsgSetValidatorWithdrawAddress(valOpAddrs[0], supplyKeeper.GetModuleAddress(ModuleName))
+	require.False(t, dh(ctx, msg).IsOK())
+
+	// disabled
+	k.SetWithdrawAddrEnabled(ctx, false)
+	msg = types.NewMsgSetValidatorWithdrawAddress(valOpAddrs[0], coldWallet)
+	require.False(t, dh(ctx, msg).IsOK())
+}
+
+func TestHandlerWithdrawPartialValidatorCommission(t *testing.T) {
+	valOpAddrs, valConsPks, valConsAddrs := keeper.GetTestAddrs()
+	ctx, ak, _, k, sk, _, supplyKeeper := keeper.CreateTestInputAdvanced(t, false, 1000)
+	dh := NewHandler(k)
+
+	// create one validator
+	sh := staking.NewHandler(sk)
+	skMsg := staking.NewMsgCreateValidator(valOpAddrs[0], valConsPks[0],
+		staking.Description{}, keeper.NewTestDecCoin(1, 0))
+	require.True(t, sh(ctx, skMsg).IsOK())
+
+	// accumulate 2okt of commission
+	feeCollector := supplyKeeper.GetModuleAccount(ctx, k.GetFeeCollectorName())
+	require.NoError(t, feeCollector.SetCoins(keeper.NewTestDecCoins(2, 0)))
+	ak.SetAccount(ctx, feeCollector)
+	abciVal := abci.Validator{Address: valConsPks[0].Address(), Power: 1}
+	votes := []abci.VoteInfo{{Validator: abciVal, SignedLastBlock: true}}
+	k.AllocateTokens(ctx, 100, valConsAddrs[0], votes)
+	require.Equal(t, keeper.NewTestDecCoins(2, 0), k.GetValidatorAccumulatedCommission(ctx, valOpAddrs[0]))
+
+	operatorAddr := sdk.AccAddress(valOpAddrs[0])
+	operatorCoins := ak.GetAccount(ctx, operatorAddr).GetCoins()
+
+	// partial
+	msg := types.NewMsgWithdrawValidatorCommission(valOpAddrs[0], keeper.NewTestDecCoins(5, 1))
+	require.True(t, dh(ctx, msg).IsOK())
+	require.Equal(t, keeper.NewTestDecCoins(15, 1), k.GetValidatorAccumulatedCommission(ctx, valOpAddrs[0]))
+	operatorCoins = operatorCoins.Add(keeper.NewTestDecCoins(5, 1))
+	require.Equal(t, operatorCoins, ak.GetAccount(ctx, operatorAddr).GetCoins())
+
+	// over-amount
+	msg = types.NewMsgWithdrawValidatorCommission(valOpAddrs[0], keeper.NewTestDecCoins(2, 0))
+	require.False(t, dh(ctx, msg).IsOK())
+	msg = types.NewMsgWithdrawValidatorCommission(valOpAddrs[0], sdk.DecCoins{sdk.NewDecCoin("xxb", sdk.OneInt())})
+	require.False(t, dh(ctx, msg).IsOK())
+	require.Equal(t, keeper.NewTestDecCoins(15, 1), k.GetValidatorAccumulatedCommission(ctx, valOpAddrs[0]))
+	require.Equal(t, operatorCoins, ak.GetAccount(ctx, operatorAddr).GetCoins())
+
+	// full, the decimal part is left to withdraw later
+	msg = types.NewMsgWithdrawValidatorCommission(valOpAddrs[0], nil)
+	require.True(t, dh(ctx, msg).IsOK())
+	require.Equal(t, keeper.NewTestDecCoins(5, 1), k.GetValidatorAccumulatedCommission(ctx, valOpAddrs[0]))
+	operatorCoins = operatorCoins.Add(keeper.NewTestDecCoins(1, 0))
+	require.Equal(t, operatorCoins, ak.GetAccount(ctx, operatorAddr).GetCoins())
+
+	// the rest can be withdrawn by the amount
+	msg = types.NewMsgWithdrawValidatorCommission(valOpAddrs[0], keeper.NewTestDecCoins(5, 1))
+	require.True(t, dh(ctx, msg).IsOK())
+	require.True(t, k.GetValidatorAccumulatedCommission(ctx, valOpAddrs[0]).IsZero())
+	require.False(t, dh(ctx, msg).IsOK())
+}
+
 // msg struct for changing the withdraw address for a delegator (or validator self-delegation)
 type MsgFake struct {
 }
@@ -77,3 +187,37 @@ func (msg MsgFake) GetSignBytes() []byte {
 func (msg MsgFake) ValidateBasic() sdk.Error {
 	return nil
 }
+
+func TestValidatorRemovedWithdrawToValidatorWithdrawAddress(t *testing.T) {
+	valOpAddrs, valConsPks, valConsAddrs := keeper.GetTestAddrs()
+	ctx, ak, _, k, sk, _, supplyKeeper := keeper.CreateTestInputAdvanced(t, false, 1000)
+	dh := NewHandler(k)
+	sh := staking.NewHandler(sk)
+	skMsg := staking.NewMsgCreateValidator(valOpAddrs[0], valConsPks[0],
+		staking.Description{}, keeper.NewTestDecCoin(1, 0))
+	require.True(t, sh(ctx, skMsg).IsOK())
+
+	coldWallet := keeper.TestAddrs[5]
+	require.True(t, dh(ctx, types.NewMsgSetValidatorWithdrawAddress(valOpAddrs[0], coldWallet)).IsOK())
+	feeCollector := supplyKeeper.GetModuleAccount(ctx, k.GetFeeCollectorName())
+	require.NoError(t, feeCollector.SetCoins(keeper.NewTestDecCoins(1, 0)))
+	ak.SetAccount(ctx, feeCollector)
+	abciVal := abci.Validator{Address: valConsPks[0].Address(), Power: 1}
+	votes := []abci.VoteInfo{{Validator: abciVal, SignedLastBlock: true}}
+	k.AllocateTokens(ctx, 100, valConsAddrs[0], votes)
+
+	// the commission left is force-withdrawn to the custom address when the validator is removed
+	operatorAddr := sdk.AccAddress(valOpAddrs[0])
+	operatorCoins := ak.GetAccount(ctx, operatorAddr).GetCoins()
+	walletCoins := ak.GetAccount(ctx, coldWallet).GetCoins()
+	k.Hooks().AfterValidatorRemoved(ctx, valConsAddrs[0], valOpAddrs[0])
+	require.Equal(t, operatorCoins, ak.GetAccount(ctx, operatorAddr).GetCoins())
+	require.Equal(t, walletCoins.Add(keeper.NewTestDecCoins(1, 0)), ak.GetAccount(ctx, coldWallet).GetCoins())
+	require.True(t, k.GetValidatorAccumulatedCommission(ctx, valOpAddrs[0]).IsZero())
+
+	// the withdraw address record is deleted along with the validator
+	k.IterateValidatorWithdrawAddrs(ctx, func(sdk.ValAddress, sdk.AccAddress) (stop bool) {
+		t.Fatal("the withdraw address should be deleted when the validator is removed")
+		return true
+	})
+}
@@ -89,6 +89,25 @@ func (k Keeper) AllocateTokens(ctx sdk.Context, totalPreviousPower int64,
 
 func (k Keeper) allocateByVal(ctx sdk.Context, rewards sdk.DecCoins, previousVotes []abci.VoteInfo) sdk.DecCoins {
 	logger := k.Logger(ctx)
+	validators := k.getUnjailedSigners(ctx, previousVotes)
+
+	//calculate the proportion of every valid validator
+	powerFraction := sdk.NewDec(1).QuoTruncate(sdk.NewDec(int64(len(validators))))
+
+	//beginning allocating rewards
+	remaining := rewards
+	for _, val := range validators {
+		reward := rewards.MulDecTruncate(powerFraction)
+		k.AllocateTokensToValidator(ctx, val, reward)
+		logger.Debug("allocate by equal", val.GetOperator(), reward.String())
+		remaining = remaining.Sub(reward)
+	}
+	return remaining
+}
+
+// getUnjailedSigners returns the validators signing the previous block which aren't jailed
+func (k Keeper) getUnjailedSigners(ctx sdk.Context, previousVotes []abci.VoteInfo) []stakingexported.ValidatorI {
+	logger := k.Logger(ctx)
 
 	//count the total sum of the unJailed val
 	validators := make([]stakingexported.ValidatorI, 0)
@@ -107,19 +126,7 @@ func (k Keeper) allocateByVal(ctx sdk.Context, rewards sdk.DecCoins, previousVot
 			validators = append(validators, validator)
 		}
 	}
-
-	//calculate the proportion of every valid validator
-	powerFraction := sdk.NewDec(1).QuoTruncate(sdk.NewDec(int64(len(validators))))
-
-	//beginning allocating rewards
-	remaining := rewards
-	for _, val := range validators {
-		reward := rewards.MulDecTruncate(powerFraction)
-		k.AllocateTokensToValidator(ctx, val, reward)
-		logger.Debug("allocate by equal", val.GetOperator(), reward.String())
-		remaining = remaining.Sub(reward)
-	}
-	return remaining
+	return validators
 }
 
 func (k Keeper) allocateByVotePower(ctx sdk.Context, rewards sdk.DecCoins) sdk.DecCoins {
@@ -158,6 +165,113 @@ func (k Keeper) allocateByVotePower(ctx sdk.Context, rewards sdk.DecCoins) sdk.D
 	return remaining
 }
 
+// RecordEpochWeights accumulates the weights of the validators for the fees of the previous block, which are
+// allocated by AllocateTokensByEpochWeights at the end of the epoch when the fees are distributed per epoch. Every
+// unjailed signer weighs its equal share of the block as allocateByVal, and the proposer weighs the whole block
+func (k Keeper) RecordEpochWeights(ctx sdk.Context, previousProposer sdk.ConsAddress, previousVotes []abci.VoteInfo) {
+	validators := k.getUnjailedSigners(ctx, previousVotes)
+	if len(validators) != 0 {
+		share := sdk.OneDec().QuoTruncate(sdk.NewDec(int64(len(validators))))
+		for _, val := range validators {
+			k.addEpochWeight(ctx, GetEpochSignerWeightKey(val.GetOperator()), share)
+		}
+	}
+
+	proposer := k.stakingKeeper.ValidatorByConsAddr(ctx, previousProposer)
+	if proposer != nil && !proposer.IsJailed() {
+		k.addEpochWeight(ctx, GetEpochProposerWeightKey(proposer.GetOperator()), sdk.OneDec())
+	}
+}
+
+// AllocateTokensByEpochWeights allocates the fees collected over the epoch just ended by the weights accumulated
+// per block, and resets the weights for the next epoch
+//1. 25% rewards to validators, by the signer weights.
+//2. 75% rewards to validators and candidators, by votes' wight
+//3. the remaining to the proposers, by the proposer weights
+func (k Keeper) AllocateTokensByEpochWeights(ctx sdk.Context) {
+	logger := k.Logger(ctx)
+	defer k.DeleteEpochWeights(ctx)
+
+	feeCollector := k.supplyKeeper.GetModuleAccount(ctx, k.feeCollectorName)
+	feesCollected := feeCollector.GetCoins()
+	if feesCollected.Empty() {
+		logger.Debug("No fee to distributed.")
+		return
+	}
+	logger.Debug("AllocateTokensByEpochWeights", "TotalFee", feesCollected.String())
+
+	signerWeights := k.getEpochWeights(ctx, EpochSignerWeightPrefix)
+	if len(signerWeights) == 0 {
+		// no validator signed in the epoch, just return without allocate the fees util the next epoch
+		logger.Error("no signer weight in the epoch, skip this allocation of fees")
+		return
+	}
+
+	// transfer collected fees to the distribution module account
+	err := k.supplyKeeper.SendCoinsFromModuleToModule(ctx, k.feeCollectorName, types.ModuleName, feesCollected)
+	if err != nil {
+		panic(err)
+	}
+
+	fee1, fee2 := feesCollected.MulDecTruncate(valPortion), feesCollected.MulDecTruncate(votePortion)
+	remaining := feesCollected.Sub(fee1.Add(fee2))
+	remain1 := k.allocateByWeights(ctx, fee1, signerWeights) //allocate rewards by the signer weights
+	remain2 := k.allocateByVotePower(ctx, fee2)              //allocate rewards by votes
+	remaining = remaining.Add(remain1.Add(remain2))
+
+	// if it remains some coins, allocate to the proposers of the epoch
+	if !remaining.IsZero() {
+		remaining = k.allocateByWeights(ctx, remaining, k.getEpochWeights(ctx, EpochProposerWeightPrefix))
+	}
+
+	// transfer the remaining to fee module account back if no proposer is able to receive it
+	if !remaining.IsZero() {
+		err := k.supplyKeeper.SendCoinsFromModuleToModule(ctx, types.ModuleName, k.feeCollectorName, remaining)
+		if err != nil {
+			panic(err)
+		}
+		logger.Debug("No Proposer to receive remaining", "Remainder to feeCollector", remaining)
+	}
+}
+
+// validatorWeight is the weight of a validator accumulated in the epoch
+type validatorWeight struct {
+	validator stakingexported.ValidatorI
+	weight    sdk.Dec
+}
+
+// getEpochWeights returns the signer or the proposer weights accumulated in the epoch, skipping the validators
+// removed during the epoch
+func (k Keeper) getEpochWeights(ctx sdk.Context, prefix []byte) (weights []validatorWeight) {
+	k.IterateEpochWeights(ctx, prefix, func(valAddr sdk.ValAddress, weight sdk.Dec) (stop bool) {
+		if validator := k.stakingKeeper.Validator(ctx, valAddr); validator != nil {
+			weights = append(weights, validatorWeight{validator, weight})
+		}
+		return false
+	})
+	return
+}
+
+// allocateByWeights allocates the rewards to the validators proportionally by their weights and returns the remaining
+func (k Keeper) allocateByWeights(ctx sdk.Context, rewards sdk.DecCoins, weights []validatorWeight) sdk.DecCoins {
+	totalWeight := sdk.ZeroDec()
+	for _, w := range weights {
+		totalWeight = totalWeight.Add(w.weight)
+	}
+	if !totalWeight.IsPositive() {
+		return rewards
+	}
+
+	remaining := rewards
+	for _, w := range weights {
+		reward := rewards.MulDecTruncate(w.weight.QuoTruncate(totalWeight))
+		k.AllocateTokensToValidator(ctx, w.validator, reward)
+		k.Logger(ctx).Debug("allocate by weight", w.validator.GetOperator(), reward.String())
+		remaining = remaining.Sub(reward)
+	}
+	return remaining
+}
+
 // AllocateTokensToValidator allocate tokens to a particular validator, splitting according to commissions
 func (k Keeper) AllocateTokensToValidator(ctx sdk.Context, val exported.ValidatorI, tokens sdk.DecCoins) {
 	// split tokens between validator and delegators according to commissions
@@ -5,6 +5,7 @@ import (
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/okex/okchain/x/distribution/types"
 	"github.com/okex/okchain/x/staking"
 	"github.com/stretchr/testify/require"
 	abci "github.com/tendermint/tendermint/abci/types"
@@ -93,6 +94,45 @@ func TestAllocateTokensToManyValidators(t *testing.T) {
 	}
 }
 
+func TestTotalPendingRewards(t *testing.T) {
+	ctx, _, k, sk, _ := CreateTestInputDefault(t, false, 1000)
+	valOpAddrs, _, _ := GetTestAddrs()
+	querier := NewQuerier(k)
+	queryTotalRewards := func() (totalRewards types.TotalPendingRewards) {
+		bz, err := querier(ctx, []string{types.QueryTotalPendingRewards}, abci.RequestQuery{})
+		require.Nil(t, err)
+		require.NoError(t, k.cdc.UnmarshalJSON(bz, &totalRewards))
+		return
+	}
+
+	// nothing cached before the first epoch begins
+	totalRewards := queryTotalRewards()
+	require.True(t, totalRewards.Rewards.IsZero())
+	require.Equal(t, int64(0), totalRewards.Height)
+
+	for i, valOpAddr := range valOpAddrs {
+		k.AllocateTokensToValidator(ctx, sk.Validator(ctx, valOpAddr), NewTestDecCoins(int64(i+1), 0))
+	}
+	ctx = ctx.WithBlockHeight(10)
+	k.UpdateTotalPendingRewards(ctx)
+	expected := types.NewTotalPendingRewards(NewTestDecCoins(int64(len(valOpAddrs)*(len(valOpAddrs)+1)/2), 0), 10)
+	require.Equal(t, expected, queryTotalRewards())
+	require.Equal(t, k.SumPendingRewards(ctx), queryTotalRewards().Rewards)
+
+	// the cached value is kept until the next epoch begins
+	k.AllocateTokensToValidator(ctx, sk.Validator(ctx, valOpAddrs[0]), NewTestDecCoins(1, 0))
+	ctx = ctx.WithBlockHeight(11)
+	require.Equal(t, expected, queryTotalRewards())
+	require.NotEqual(t, k.SumPendingRewards(ctx), queryTotalRewards().Rewards)
+
+	require.False(t, k.IsBeginningOfEpoch(ctx))
+	sk.SetTheEndOfLastEpoch(ctx)
+	ctx = ctx.WithBlockHeight(12)
+	require.True(t, k.IsBeginningOfEpoch(ctx))
+	k.UpdateTotalPendingRewards(ctx)
+	require.Equal(t, types.NewTotalPendingRewards(k.SumPendingRewards(ctx), 12), queryTotalRewards())
+}
+
 func setTestFees(t *testing.T, ctx sdk.Context, k Keeper, ak auth.AccountKeeper, fees sdk.DecCoins) {
 	feeCollector := k.supplyKeeper.GetModuleAccount(ctx, k.feeCollectorName)
 	require.NotNil(t, feeCollector)
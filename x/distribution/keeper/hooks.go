@@ -36,9 +36,8 @@ func (h Hooks) AfterValidatorRemoved(ctx sdk.Context, _ sdk.ConsAddress, valAddr
 			}
 		}
 
-		accAddr := sdk.AccAddress(valAddr)
-		withdrawAddr := h.k.GetDelegatorWithdrawAddr(ctx, accAddr)
-		// add to validator account
+		withdrawAddr := h.k.GetValidatorWithdrawAddr(ctx, valAddr)
+		// add to the commission withdraw address of the validator
 		if !coins.IsZero() {
 			err := h.k.supplyKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, withdrawAddr, coins)
 			if err != nil {
@@ -47,8 +46,9 @@ func (h Hooks) AfterValidatorRemoved(ctx sdk.Context, _ sdk.ConsAddress, valAddr
 		}
 	}
 
-	// remove commission record
+	// remove commission record and withdraw address record
 	h.k.deleteValidatorAccumulatedCommission(ctx, valAddr)
+	h.k.DeleteValidatorWithdrawAddr(ctx, valAddr)
 }
 
 // AfterValidatorDestroyed nothing to do
@@ -60,3 +60,4 @@ func (h Hooks) AfterValidatorDestroyed(ctx sdk.Context, consAddr sdk.ConsAddress
 func (h Hooks) BeforeValidatorModified(_ sdk.Context, _ sdk.ValAddress)                         {}
 func (h Hooks) AfterValidatorBonded(_ sdk.Context, _ sdk.ConsAddress, _ sdk.ValAddress)         {}
 func (h Hooks) AfterValidatorBeginUnbonding(_ sdk.Context, _ sdk.ConsAddress, _ sdk.ValAddress) {}
+func (h Hooks) BeforeDelegationRemoved(_ sdk.Context, _ sdk.AccAddress, _ sdk.ValAddress)       {}
@@ -74,6 +74,38 @@ func (k Keeper) SetWithdrawAddr(ctx sdk.Context, delegatorAddr sdk.AccAddress, w
 	return nil
 }
 
+// SetValidatorWithdrawAddress sets a new address that will receive the commission of a validator upon withdrawal.
+// Setting it to the operator address itself resets it to default
+func (k Keeper) SetValidatorWithdrawAddress(ctx sdk.Context, valAddr sdk.ValAddress,
+	withdrawAddr sdk.AccAddress) sdk.Error {
+	if k.stakingKeeper.Validator(ctx, valAddr) == nil {
+		return types.ErrUnknownValidator(k.codespace, valAddr)
+	}
+
+	if k.blacklistedAddrs[withdrawAddr.String()] {
+		return sdk.ErrUnauthorized(fmt.Sprintf("%s is blacklisted from receiving external funds", withdrawAddr))
+	}
+
+	if !k.GetWithdrawAddrEnabled(ctx) {
+		return types.ErrSetWithdrawAddrDisabled(k.codespace)
+	}
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeSetWithdrawAddress,
+			sdk.NewAttribute(types.AttributeKeyValidator, valAddr.String()),
+			sdk.NewAttribute(types.AttributeKeyWithdrawAddress, withdrawAddr.String()),
+		),
+	)
+
+	if withdrawAddr.Equals(sdk.AccAddress(valAddr)) {
+		k.DeleteValidatorWithdrawAddr(ctx, valAddr)
+		return nil
+	}
+	k.SetValidatorWithdrawAddr(ctx, valAddr, withdrawAddr)
+	return nil
+}
+
 // WithdrawValidatorCommission withdraws validator commission
 func (k Keeper) WithdrawValidatorCommission(ctx sdk.Context, valAddr sdk.ValAddress) (sdk.Coins, sdk.Error) {
 	// fetch validator accumulated commission
@@ -83,11 +115,32 @@ func (k Keeper) WithdrawValidatorCommission(ctx sdk.Context, valAddr sdk.ValAddr
 	}
 
 	commission, remainder := accumCommission.TruncateDecimal()
+	return k.withdrawValidatorCommission(ctx, valAddr, commission, remainder)
+}
+
+// WithdrawPartialValidatorCommission withdraws the amount out of the accumulated commission of a validator and leaves
+// the rest to withdraw later
+func (k Keeper) WithdrawPartialValidatorCommission(ctx sdk.Context, valAddr sdk.ValAddress, amount sdk.DecCoins) (
+	sdk.Coins, sdk.Error) {
+	accumCommission := k.GetValidatorAccumulatedCommission(ctx, valAddr)
+	if accumCommission.IsZero() {
+		return nil, types.ErrNoValidatorCommission(k.codespace)
+	}
+
+	remainder, hasNeg := accumCommission.SafeSub(amount)
+	if hasNeg {
+		return nil, types.ErrInsufficientCommission(k.codespace, amount, accumCommission)
+	}
+	return k.withdrawValidatorCommission(ctx, valAddr, amount, remainder)
+}
+
+// withdrawValidatorCommission sends the commission to the withdraw address of the validator and keeps the remainder
+func (k Keeper) withdrawValidatorCommission(ctx sdk.Context, valAddr sdk.ValAddress, commission sdk.Coins,
+	remainder sdk.DecCoins) (sdk.Coins, sdk.Error) {
 	k.SetValidatorAccumulatedCommission(ctx, valAddr, remainder) // leave remainder to withdraw later
 
 	if !commission.IsZero() {
-		accAddr := sdk.AccAddress(valAddr)
-		withdrawAddr := k.GetDelegatorWithdrawAddr(ctx, accAddr)
+		withdrawAddr := k.GetValidatorWithdrawAddr(ctx, valAddr)
 		err := k.supplyKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, withdrawAddr, commission)
 		if err != nil {
 			return nil, err
@@ -103,3 +156,25 @@ func (k Keeper) WithdrawValidatorCommission(ctx sdk.Context, valAddr sdk.ValAddr
 
 	return commission, nil
 }
+
+// SumPendingRewards sums up the unwithdrawn rewards in the network, which are all accumulated as the commission of
+// the validators. It iterates all the validators, so the result is cached by UpdateTotalPendingRewards for queries
+func (k Keeper) SumPendingRewards(ctx sdk.Context) sdk.DecCoins {
+	totalRewards := sdk.DecCoins{}
+	k.IterateValidatorAccumulatedCommissions(ctx,
+		func(_ sdk.ValAddress, commission types.ValidatorAccumulatedCommission) (stop bool) {
+			totalRewards = totalRewards.Add(commission)
+			return false
+		})
+	return totalRewards
+}
+
+// IsBeginningOfEpoch checks whether the current block is the first one of an epoch
+func (k Keeper) IsBeginningOfEpoch(ctx sdk.Context) bool {
+	return ctx.BlockHeight() == k.stakingKeeper.GetTheEndOfLastEpoch(ctx)+1
+}
+
+// UpdateTotalPendingRewards recomputes the total pending rewards and caches it with the current height
+func (k Keeper) UpdateTotalPendingRewards(ctx sdk.Context) {
+	k.SetTotalPendingRewards(ctx, types.NewTotalPendingRewards(k.SumPendingRewards(ctx), ctx.BlockHeight()))
+}
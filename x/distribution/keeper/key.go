@@ -18,12 +18,25 @@ const (
 // - 0x03<accAddr_Bytes>: sdk.AccAddress
 //
 // - 0x07<valAddr_Bytes>: ValidatorCurrentRewards
+//
+// - 0x08<valAddr_Bytes>: sdk.AccAddress
+//
+// - 0x09: TotalPendingRewards
+//
+// - 0x0A<valAddr_Bytes>: sdk.Dec
+//
+// - 0x0B<valAddr_Bytes>: sdk.Dec
 var (
 	ProposerKey                          = []byte{0x01} // key for the proposer operator address
 	DelegatorWithdrawAddrPrefix          = []byte{0x03} // key for delegator withdraw address
 	ValidatorAccumulatedCommissionPrefix = []byte{0x07} // key for accumulated validator commission
+	ValidatorWithdrawAddrPrefix          = []byte{0x08} // key for validator commission withdraw address
+	TotalPendingRewardsKey               = []byte{0x09} // key for the cached total pending rewards
+	EpochSignerWeightPrefix              = []byte{0x0A} // key for the signer weight of a validator in the epoch
+	EpochProposerWeightPrefix            = []byte{0x0B} // key for the proposer weight of a validator in the epoch
 
 	ParamStoreKeyWithdrawAddrEnabled = []byte("withdrawaddrenabled")
+	ParamStoreKeyRewardCadence       = []byte("rewardcadence")
 )
 
 // GetDelegatorWithdrawInfoAddress returns an address from a delegator's withdraw info key
@@ -53,3 +66,27 @@ func GetDelegatorWithdrawAddrKey(delAddr sdk.AccAddress) []byte {
 func GetValidatorAccumulatedCommissionKey(v sdk.ValAddress) []byte {
 	return append(ValidatorAccumulatedCommissionPrefix, v.Bytes()...)
 }
+
+// GetValidatorWithdrawInfoAddress returns an address from a validator's withdraw info key
+func GetValidatorWithdrawInfoAddress(key []byte) (valAddr sdk.ValAddress) {
+	addr := key[1:]
+	if len(addr) != sdk.AddrLen {
+		panic("unexpected key length")
+	}
+	return sdk.ValAddress(addr)
+}
+
+// GetValidatorWithdrawAddrKey returns the key for a validator's commission withdraw addr
+func GetValidatorWithdrawAddrKey(valAddr sdk.ValAddress) []byte {
+	return append(ValidatorWithdrawAddrPrefix, valAddr.Bytes()...)
+}
+
+// GetEpochSignerWeightKey returns the key for the signer weight accumulated by a validator in the current epoch
+func GetEpochSignerWeightKey(valAddr sdk.ValAddress) []byte {
+	return append(EpochSignerWeightPrefix, valAddr.Bytes()...)
+}
+
+// GetEpochProposerWeightKey returns the key for the proposer weight accumulated by a validator in the current epoch
+func GetEpochProposerWeightKey(valAddr sdk.ValAddress) []byte {
+	return append(EpochProposerWeightPrefix, valAddr.Bytes()...)
+}
@@ -3,12 +3,14 @@ package keeper
 import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/params"
+	"github.com/okex/okchain/x/distribution/types"
 )
 
 // ParamKeyTable is the type declaration for parameters
 func ParamKeyTable() params.KeyTable {
 	return params.NewKeyTable(
 		ParamStoreKeyWithdrawAddrEnabled, true,
+		ParamStoreKeyRewardCadence, "",
 	)
 }
 
@@ -25,3 +27,17 @@ func (k Keeper) GetWithdrawAddrEnabled(ctx sdk.Context) bool {
 func (k Keeper) SetWithdrawAddrEnabled(ctx sdk.Context, enabled bool) {
 	k.paramSpace.Set(ctx, ParamStoreKeyWithdrawAddrEnabled, &enabled)
 }
+
+// GetRewardCadence returns the cadence to distribute the rewards, which is block if it has never been set
+// nolint: errcheck
+func (k Keeper) GetRewardCadence(ctx sdk.Context) string {
+	cadence := types.RewardCadenceBlock
+	k.paramSpace.GetIfExists(ctx, ParamStoreKeyRewardCadence, &cadence)
+	return cadence
+}
+
+// SetRewardCadence sets the cadence to distribute the rewards
+// nolint: errcheck
+func (k Keeper) SetRewardCadence(ctx sdk.Context, cadence string) {
+	k.paramSpace.Set(ctx, ParamStoreKeyRewardCadence, &cadence)
+}
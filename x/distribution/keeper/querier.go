@@ -23,6 +23,12 @@ func NewQuerier(k Keeper) sdk.Querier {
 		case types.QueryWithdrawAddr:
 			return queryDelegatorWithdrawAddress(ctx, path[1:], req, k)
 
+		case types.QueryValidatorWithdrawAddr:
+			return queryValidatorWithdrawAddress(ctx, path[1:], req, k)
+
+		case types.QueryTotalPendingRewards:
+			return queryTotalPendingRewards(ctx, k)
+
 		default:
 			return nil, sdk.ErrUnknownRequest("unknown distr query endpoint")
 		}
@@ -37,6 +43,12 @@ func queryParams(ctx sdk.Context, path []string, req abci.RequestQuery, k Keeper
 			return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
 		}
 		return bz, nil
+	case types.ParamRewardCadence:
+		bz, err := codec.MarshalJSONIndent(k.cdc, k.GetRewardCadence(ctx))
+		if err != nil {
+			return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+		}
+		return bz, nil
 	default:
 		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("%s is not a valid query request path", req.Path))
 	}
@@ -74,3 +86,31 @@ func queryDelegatorWithdrawAddress(ctx sdk.Context, _ []string, req abci.Request
 
 	return bz, nil
 }
+
+func queryValidatorWithdrawAddress(ctx sdk.Context, _ []string, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QueryValidatorWithdrawAddrParams
+	err := k.cdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrUnknownRequest(sdk.AppendMsgToErr("incorrectly formatted request data", err.Error()))
+	}
+
+	if k.stakingKeeper.Validator(ctx, params.ValidatorAddress) == nil {
+		return nil, types.ErrUnknownValidator(k.codespace, params.ValidatorAddress)
+	}
+	withdrawAddr := k.GetValidatorWithdrawAddr(ctx, params.ValidatorAddress)
+
+	bz, err := codec.MarshalJSONIndent(k.cdc, withdrawAddr)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+
+	return bz, nil
+}
+
+func queryTotalPendingRewards(ctx sdk.Context, k Keeper) ([]byte, sdk.Error) {
+	bz, err := codec.MarshalJSONIndent(k.cdc, k.GetTotalPendingRewards(ctx))
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+	return bz, nil
+}
@@ -36,6 +36,44 @@ func (k Keeper) IterateDelegatorWithdrawAddrs(ctx sdk.Context,
 	}
 }
 
+// GetValidatorWithdrawAddr returns the address where the commission of a validator is withdrawn to, defaulting to the
+// withdraw address of the operator account
+func (k Keeper) GetValidatorWithdrawAddr(ctx sdk.Context, valAddr sdk.ValAddress) sdk.AccAddress {
+	store := ctx.KVStore(k.storeKey)
+	b := store.Get(GetValidatorWithdrawAddrKey(valAddr))
+	if b == nil {
+		return k.GetDelegatorWithdrawAddr(ctx, sdk.AccAddress(valAddr))
+	}
+	return sdk.AccAddress(b)
+}
+
+// SetValidatorWithdrawAddr sets the validator commission withdraw address
+func (k Keeper) SetValidatorWithdrawAddr(ctx sdk.Context, valAddr sdk.ValAddress, withdrawAddr sdk.AccAddress) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(GetValidatorWithdrawAddrKey(valAddr), withdrawAddr.Bytes())
+}
+
+// DeleteValidatorWithdrawAddr deletes the validator commission withdraw address
+func (k Keeper) DeleteValidatorWithdrawAddr(ctx sdk.Context, valAddr sdk.ValAddress) {
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(GetValidatorWithdrawAddrKey(valAddr))
+}
+
+// IterateValidatorWithdrawAddrs iterates over validator commission withdraw addrs
+func (k Keeper) IterateValidatorWithdrawAddrs(ctx sdk.Context,
+	handler func(val sdk.ValAddress, addr sdk.AccAddress) (stop bool)) {
+	store := ctx.KVStore(k.storeKey)
+	iter := sdk.KVStorePrefixIterator(store, ValidatorWithdrawAddrPrefix)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		addr := sdk.AccAddress(iter.Value())
+		val := GetValidatorWithdrawInfoAddress(iter.Key())
+		if handler(val, addr) {
+			break
+		}
+	}
+}
+
 // GetPreviousProposerConsAddr returns the proposer public key for this block
 func (k Keeper) GetPreviousProposerConsAddr(ctx sdk.Context) (consAddr sdk.ConsAddress) {
 	store := ctx.KVStore(k.storeKey)
@@ -103,3 +141,62 @@ func (k Keeper) IterateValidatorAccumulatedCommissions(ctx sdk.Context,
 		}
 	}
 }
+
+// GetTotalPendingRewards returns the total pending rewards cached at the beginning of the current epoch
+func (k Keeper) GetTotalPendingRewards(ctx sdk.Context) (totalRewards types.TotalPendingRewards) {
+	b := ctx.KVStore(k.storeKey).Get(TotalPendingRewardsKey)
+	if b == nil {
+		return types.NewTotalPendingRewards(sdk.DecCoins{}, 0)
+	}
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(b, &totalRewards)
+	return totalRewards
+}
+
+// SetTotalPendingRewards caches the total pending rewards
+func (k Keeper) SetTotalPendingRewards(ctx sdk.Context, totalRewards types.TotalPendingRewards) {
+	b := k.cdc.MustMarshalBinaryLengthPrefixed(totalRewards)
+	ctx.KVStore(k.storeKey).Set(TotalPendingRewardsKey, b)
+}
+
+// addEpochWeight adds the weight to the signer or the proposer weight of a validator keyed by key
+func (k Keeper) addEpochWeight(ctx sdk.Context, key []byte, weight sdk.Dec) {
+	store := ctx.KVStore(k.storeKey)
+	total := weight
+	if b := store.Get(key); b != nil {
+		var accumulated sdk.Dec
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(b, &accumulated)
+		total = total.Add(accumulated)
+	}
+	store.Set(key, k.cdc.MustMarshalBinaryLengthPrefixed(total))
+}
+
+// IterateEpochWeights iterates over the signer or the proposer weights accumulated in the current epoch
+func (k Keeper) IterateEpochWeights(ctx sdk.Context, prefix []byte,
+	handler func(val sdk.ValAddress, weight sdk.Dec) (stop bool)) {
+	store := ctx.KVStore(k.storeKey)
+	iter := sdk.KVStorePrefixIterator(store, prefix)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		var weight sdk.Dec
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(iter.Value(), &weight)
+		if handler(sdk.ValAddress(iter.Key()[1:]), weight) {
+			break
+		}
+	}
+}
+
+// DeleteEpochWeights deletes all the signer and the proposer weights accumulated in the current epoch
+func (k Keeper) DeleteEpochWeights(ctx sdk.Context) {
+	store := ctx.KVStore(k.storeKey)
+	for _, prefix := range [][]byte{EpochSignerWeightPrefix, EpochProposerWeightPrefix} {
+		iter := sdk.KVStorePrefixIterator(store, prefix)
+		var keys [][]byte
+		for ; iter.Valid(); iter.Next() {
+			keys = append(keys, iter.Key())
+		}
+		iter.Close()
+		for _, key := range keys {
+			store.Delete(key)
+		}
+	}
+}
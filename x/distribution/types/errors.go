@@ -2,6 +2,8 @@
 package types
 
 import (
+	"fmt"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
@@ -12,6 +14,8 @@ const (
 	CodeInvalidInput            CodeType          = 103
 	CodeNoValidatorCommission   CodeType          = 105
 	CodeSetWithdrawAddrDisabled CodeType          = 106
+	CodeUnknownValidator        CodeType          = 107
+	CodeInsufficientCommission  CodeType          = 108
 )
 
 func ErrNilDelegatorAddr(codespace sdk.CodespaceType) sdk.Error {
@@ -29,3 +33,13 @@ func ErrNoValidatorCommission(codespace sdk.CodespaceType) sdk.Error {
 func ErrSetWithdrawAddrDisabled(codespace sdk.CodespaceType) sdk.Error {
 	return sdk.NewError(codespace, CodeSetWithdrawAddrDisabled, "set withdraw address disabled")
 }
+func ErrUnknownValidator(codespace sdk.CodespaceType, valAddr sdk.ValAddress) sdk.Error {
+	return sdk.NewError(codespace, CodeUnknownValidator, fmt.Sprintf("validator %s does not exist", valAddr))
+}
+func ErrInvalidCommissionAmount(codespace sdk.CodespaceType, amount sdk.DecCoins) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidInput, fmt.Sprintf("invalid commission amount to withdraw: %s", amount))
+}
+func ErrInsufficientCommission(codespace sdk.CodespaceType, amount, accumCommission sdk.DecCoins) sdk.Error {
+	return sdk.NewError(codespace, CodeInsufficientCommission,
+		fmt.Sprintf("commission to withdraw %s exceeds the accumulated commission %s", amount, accumCommission))
+}
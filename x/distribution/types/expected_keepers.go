@@ -34,6 +34,9 @@ type StakingKeeper interface {
 
 	GetLastTotalPower(ctx sdk.Context) sdk.Int
 	GetLastValidatorPower(ctx sdk.Context, valAddr sdk.ValAddress) int64
+
+	// GetTheEndOfLastEpoch returns the height of the last block of the last epoch
+	GetTheEndOfLastEpoch(ctx sdk.Context) int64
 }
 
 // StakingHooks event hooks for staking validator object (noalias)
@@ -11,6 +11,13 @@ type DelegatorWithdrawInfo struct {
 	WithdrawAddress  sdk.AccAddress `json:"withdraw_address" yaml:"withdraw_address"`
 }
 
+// ValidatorWithdrawInfo is the address for where the commission of a validator is withdrawn to
+// this struct is only used at genesis to feed in validator withdraw addresses
+type ValidatorWithdrawInfo struct {
+	ValidatorAddress sdk.ValAddress `json:"validator_address" yaml:"validator_address"`
+	WithdrawAddress  sdk.AccAddress `json:"withdraw_address" yaml:"withdraw_address"`
+}
+
 // ValidatorAccumulatedCommissionRecord is used for import / export via genesis json
 type ValidatorAccumulatedCommissionRecord struct {
 	ValidatorAddress sdk.ValAddress                 `json:"validator_address" yaml:"validator_address"`
@@ -23,16 +30,20 @@ type GenesisState struct {
 	DelegatorWithdrawInfos          []DelegatorWithdrawInfo                `json:"delegator_withdraw_infos" yaml:"delegator_withdraw_infos"`
 	PreviousProposer                sdk.ConsAddress                        `json:"previous_proposer" yaml:"previous_proposer"`
 	ValidatorAccumulatedCommissions []ValidatorAccumulatedCommissionRecord `json:"validator_accumulated_commissions" yaml:"validator_accumulated_commissions"`
+	ValidatorWithdrawInfos          []ValidatorWithdrawInfo                `json:"validator_withdraw_infos" yaml:"validator_withdraw_infos"`
+	RewardCadence                   string                                 `json:"reward_cadence" yaml:"reward_cadence"`
 }
 
 // NewGenesisState creates a new object of GenesisState
 func NewGenesisState(withdrawAddrEnabled bool, dwis []DelegatorWithdrawInfo, pp sdk.ConsAddress,
-	acc []ValidatorAccumulatedCommissionRecord) GenesisState {
+	acc []ValidatorAccumulatedCommissionRecord, vwis []ValidatorWithdrawInfo, rewardCadence string) GenesisState {
 	return GenesisState{
 		WithdrawAddrEnabled:             withdrawAddrEnabled,
 		DelegatorWithdrawInfos:          dwis,
 		PreviousProposer:                pp,
 		ValidatorAccumulatedCommissions: acc,
+		ValidatorWithdrawInfos:          vwis,
+		RewardCadence:                   rewardCadence,
 	}
 }
 
@@ -43,10 +54,12 @@ func DefaultGenesisState() GenesisState {
 		DelegatorWithdrawInfos:          []DelegatorWithdrawInfo{},
 		PreviousProposer:                nil,
 		ValidatorAccumulatedCommissions: []ValidatorAccumulatedCommissionRecord{},
+		ValidatorWithdrawInfos:          []ValidatorWithdrawInfo{},
+		RewardCadence:                   RewardCadenceBlock,
 	}
 }
 
 // ValidateGenesis validates the genesis state of distribution genesis input
 func ValidateGenesis(data GenesisState) error {
-	return nil
+	return ValidateRewardCadence(data.RewardCadence)
 }
@@ -6,7 +6,7 @@ import (
 )
 
 // Verify interface at compile time
-var _, _ sdk.Msg = &MsgSetWithdrawAddress{}, &MsgWithdrawValidatorCommission{}
+var _, _, _ sdk.Msg = &MsgSetWithdrawAddress{}, &MsgWithdrawValidatorCommission{}, &MsgSetValidatorWithdrawAddress{}
 
 // msg struct for changing the withdraw address for a delegator (or validator self-delegation)
 type MsgSetWithdrawAddress struct {
@@ -49,11 +49,14 @@ func (msg MsgSetWithdrawAddress) ValidateBasic() sdk.Error {
 // msg struct for validator withdraw
 type MsgWithdrawValidatorCommission struct {
 	ValidatorAddress sdk.ValAddress `json:"validator_address" yaml:"validator_address"`
+	// part of the accumulated commission to withdraw, all of it is withdrawn if it's empty
+	Amount sdk.DecCoins `json:"amount,omitempty" yaml:"amount,omitempty"`
 }
 
-func NewMsgWithdrawValidatorCommission(valAddr sdk.ValAddress) MsgWithdrawValidatorCommission {
+func NewMsgWithdrawValidatorCommission(valAddr sdk.ValAddress, amount sdk.DecCoins) MsgWithdrawValidatorCommission {
 	return MsgWithdrawValidatorCommission{
 		ValidatorAddress: valAddr,
+		Amount:           amount,
 	}
 }
 
@@ -76,5 +79,46 @@ func (msg MsgWithdrawValidatorCommission) ValidateBasic() sdk.Error {
 	if msg.ValidatorAddress.Empty() {
 		return ErrNilValidatorAddr(DefaultCodespace)
 	}
+	if !msg.Amount.Empty() && !msg.Amount.IsValid() {
+		return ErrInvalidCommissionAmount(DefaultCodespace, msg.Amount)
+	}
+	return nil
+}
+
+// msg struct for changing the address where the commission of a validator is withdrawn to
+type MsgSetValidatorWithdrawAddress struct {
+	ValidatorAddress sdk.ValAddress `json:"validator_address" yaml:"validator_address"`
+	WithdrawAddress  sdk.AccAddress `json:"withdraw_address" yaml:"withdraw_address"`
+}
+
+func NewMsgSetValidatorWithdrawAddress(valAddr sdk.ValAddress, withdrawAddr sdk.AccAddress) MsgSetValidatorWithdrawAddress {
+	return MsgSetValidatorWithdrawAddress{
+		ValidatorAddress: valAddr,
+		WithdrawAddress:  withdrawAddr,
+	}
+}
+
+func (msg MsgSetValidatorWithdrawAddress) Route() string { return ModuleName }
+func (msg MsgSetValidatorWithdrawAddress) Type() string  { return "set_validator_withdraw_address" }
+
+// Return address that must sign over msg.GetSignBytes()
+func (msg MsgSetValidatorWithdrawAddress) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{sdk.AccAddress(msg.ValidatorAddress.Bytes())}
+}
+
+// get the bytes for the message signer to sign on
+func (msg MsgSetValidatorWithdrawAddress) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// quick validity check
+func (msg MsgSetValidatorWithdrawAddress) ValidateBasic() sdk.Error {
+	if msg.ValidatorAddress.Empty() {
+		return ErrNilValidatorAddr(DefaultCodespace)
+	}
+	if msg.WithdrawAddress.Empty() {
+		return ErrNilWithdrawAddr(DefaultCodespace)
+	}
 	return nil
 }
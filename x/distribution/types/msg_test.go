@@ -34,12 +34,13 @@ func TestNewMsgSetWithdrawAddress(t *testing.T) {
 
 // TestNewMsgWithdrawValidatorCommission test ValidateBasic for MsgWithdrawValidatorCommission
 func TestNewMsgWithdrawValidatorCommission(t *testing.T) {
-	msg := NewMsgWithdrawValidatorCommission(valAddr1)
+	msg := NewMsgWithdrawValidatorCommission(valAddr1, nil)
 	bz := ModuleCdc.MustMarshalJSON(msg)
 	require.Equal(t, ModuleName, msg.Route())
 	require.Equal(t, "withdraw_validator_commission", msg.Type())
 	require.Equal(t, []sdk.AccAddress{valAddr1.Bytes()}, msg.GetSigners())
 	require.Equal(t, sdk.MustSortJSON(bz), msg.GetSignBytes())
+	require.NotContains(t, string(msg.GetSignBytes()), "amount")
 	require.NoError(t, msg.ValidateBasic())
 }
 
@@ -71,13 +72,18 @@ func TestMsgSetWithdrawAddress(t *testing.T) {
 func TestMsgWithdrawValidatorCommission(t *testing.T) {
 	tests := []struct {
 		validatorAddr sdk.ValAddress
+		amount        sdk.DecCoins
 		expectPass    bool
 	}{
-		{valAddr1, true},
-		{emptyValAddr, false},
+		{valAddr1, nil, true},
+		{emptyValAddr, nil, false},
+		{valAddr1, sdk.DecCoins{sdk.NewDecCoinFromDec("okt", sdk.NewDecWithPrec(5, 1))}, true},
+		{valAddr1, sdk.DecCoins{sdk.NewDecCoinFromDec("okt", sdk.ZeroDec())}, false},
+		{valAddr1, sdk.DecCoins{sdk.NewDecCoinFromDec("okt", sdk.OneDec()),
+			sdk.NewDecCoinFromDec("btc", sdk.OneDec())}, false},
 	}
 	for i, tc := range tests {
-		msg := NewMsgWithdrawValidatorCommission(tc.validatorAddr)
+		msg := NewMsgWithdrawValidatorCommission(tc.validatorAddr, tc.amount)
 		if tc.expectPass {
 			require.Nil(t, msg.ValidateBasic(), "test index: %v", i)
 		} else {
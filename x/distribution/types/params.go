@@ -0,0 +1,22 @@
+package types
+
+import "fmt"
+
+// the cadences to distribute the rewards
+const (
+	// RewardCadenceBlock distributes the collected fees at the beginning of every block
+	RewardCadenceBlock = "block"
+	// RewardCadenceEpoch batches the fees collected during an epoch and distributes them at the beginning of the next
+	RewardCadenceEpoch = "epoch"
+)
+
+// ValidateRewardCadence checks whether the reward cadence is either block or epoch
+func ValidateRewardCadence(cadence string) error {
+	switch cadence {
+	case RewardCadenceBlock, RewardCadenceEpoch:
+		return nil
+	default:
+		return fmt.Errorf("invalid reward cadence %q, which should be %q or %q", cadence, RewardCadenceBlock,
+			RewardCadenceEpoch)
+	}
+}
@@ -1,6 +1,10 @@
 package types
 
-import sdk "github.com/cosmos/cosmos-sdk/types"
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
 
 // querier keys
 const (
@@ -8,7 +12,11 @@ const (
 	QueryValidatorCommission = "validator_commission"
 	QueryWithdrawAddr        = "withdraw_addr"
 
+	QueryValidatorWithdrawAddr = "validator_withdraw_addr"
+	QueryTotalPendingRewards   = "total_pending_rewards"
+
 	ParamWithdrawAddrEnabled = "withdraw_addr_enabled"
+	ParamRewardCadence       = "reward_cadence"
 )
 
 // QueryValidatorCommissionParams is the struct of params for query 'custom/distr/validator_commission'
@@ -32,3 +40,35 @@ type QueryDelegatorWithdrawAddrParams struct {
 func NewQueryDelegatorWithdrawAddrParams(delegatorAddr sdk.AccAddress) QueryDelegatorWithdrawAddrParams {
 	return QueryDelegatorWithdrawAddrParams{DelegatorAddress: delegatorAddr}
 }
+
+// QueryValidatorWithdrawAddrParams is the struct of params for query 'custom/distr/validator_withdraw_addr'
+type QueryValidatorWithdrawAddrParams struct {
+	ValidatorAddress sdk.ValAddress `json:"validator_address" yaml:"validator_address"`
+}
+
+// NewQueryValidatorWithdrawAddrParams creates a new instance of QueryValidatorWithdrawAddrParams
+func NewQueryValidatorWithdrawAddrParams(validatorAddr sdk.ValAddress) QueryValidatorWithdrawAddrParams {
+	return QueryValidatorWithdrawAddrParams{ValidatorAddress: validatorAddr}
+}
+
+// TotalPendingRewards is the sum of the unwithdrawn rewards in the network, which is recomputed at the beginning
+// of each epoch
+type TotalPendingRewards struct {
+	Rewards sdk.DecCoins `json:"rewards" yaml:"rewards"`
+	Height  int64        `json:"height" yaml:"height"`
+}
+
+// NewTotalPendingRewards creates a new instance of TotalPendingRewards
+func NewTotalPendingRewards(rewards sdk.DecCoins, height int64) TotalPendingRewards {
+	return TotalPendingRewards{
+		Rewards: rewards,
+		Height:  height,
+	}
+}
+
+// String returns a human readable string representation of TotalPendingRewards
+func (tpr TotalPendingRewards) String() string {
+	return fmt.Sprintf(`Total Pending Rewards:
+  Rewards: %s
+  Height:  %d`, tpr.Rewards, tpr.Height)
+}
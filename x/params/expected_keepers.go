@@ -2,6 +2,7 @@ package params
 
 import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkparams "github.com/cosmos/cosmos-sdk/x/params"
 )
 
 // BankKeeper shows the expected action of bank keeper
@@ -12,6 +13,7 @@ type BankKeeper interface {
 // StakingKeeper shows the expected action of staking keeper
 type StakingKeeper interface {
 	IsValidator(ctx sdk.Context, addr sdk.AccAddress) bool
+	ValidateParamChange(ctx sdk.Context, change sdkparams.ParamChange) sdk.Error
 }
 
 // GovKeeper shows the expected action of gov keeper
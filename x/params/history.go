@@ -0,0 +1,105 @@
+package params
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// ParamChangeRecord is an applied param change kept in the history for auditing
+type ParamChangeRecord struct {
+	ProposalID uint64 `json:"proposal_id" yaml:"proposal_id"`
+	Height     int64  `json:"height" yaml:"height"`
+	Subspace   string `json:"subspace" yaml:"subspace"`
+	Key        string `json:"key" yaml:"key"`
+	Subkey     string `json:"subkey,omitempty" yaml:"subkey,omitempty"`
+	// raw json values in the store before and after the change
+	OldValue string `json:"old_value" yaml:"old_value"`
+	NewValue string `json:"new_value" yaml:"new_value"`
+}
+
+// NewParamChangeRecord creates a new instance of ParamChangeRecord
+func NewParamChangeRecord(proposalID uint64, height int64, change ParamChange, oldValue, newValue []byte,
+) ParamChangeRecord {
+	return ParamChangeRecord{
+		ProposalID: proposalID,
+		Height:     height,
+		Subspace:   change.Subspace,
+		Key:        change.Key,
+		Subkey:     change.Subkey,
+		OldValue:   string(oldValue),
+		NewValue:   string(newValue),
+	}
+}
+
+// String returns a human readable string representation of ParamChangeRecord
+func (pcr ParamChangeRecord) String() string {
+	return fmt.Sprintf(`Param Change:
+  Proposal ID:	%d
+  Height:	%d
+  Subspace:	%s
+  Key:		%s
+  Subkey:	%s
+  Old Value:	%s
+  New Value:	%s`, pcr.ProposalID, pcr.Height, pcr.Subspace, pcr.Key, pcr.Subkey, pcr.OldValue, pcr.NewValue)
+}
+
+// getParamStoreKey gets the key of the param in its subspace store
+func getParamStoreKey(change ParamChange) []byte {
+	if len(change.Subkey) == 0 {
+		return []byte(change.Key)
+	}
+	return []byte(change.Key + "/" + change.Subkey)
+}
+
+// appendParamChange appends an applied param change to the end of the history
+func (keeper Keeper) appendParamChange(ctx sdk.Context, record ParamChangeRecord) {
+	store := ctx.KVStore(keeper.storeKey)
+	var seq uint64
+	if bz := store.Get(ParamChangeHistorySeqKey); bz != nil {
+		seq = binary.BigEndian.Uint64(bz) + 1
+	}
+	store.Set(GetParamChangeHistoryKey(seq), keeper.cdc.MustMarshalBinaryLengthPrefixed(record))
+	store.Set(ParamChangeHistorySeqKey, sdk.Uint64ToBigEndian(seq))
+}
+
+// pruneParamChangeHistory deletes the param changes applied beyond the retention window
+func (keeper Keeper) pruneParamChangeHistory(ctx sdk.Context) {
+	retention := keeper.GetParams(ctx).ChangeHistoryRetention
+	if retention == 0 || ctx.BlockHeight() <= int64(retention) {
+		return
+	}
+
+	minHeight := ctx.BlockHeight() - int64(retention)
+	store := ctx.KVStore(keeper.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, ParamChangeHistoryKey)
+	defer iterator.Close()
+	// the history is append-only so that the heights never decrease
+	var keys [][]byte
+	for ; iterator.Valid(); iterator.Next() {
+		var record ParamChangeRecord
+		keeper.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &record)
+		if record.Height >= minHeight {
+			break
+		}
+		keys = append(keys, iterator.Key())
+	}
+	for _, key := range keys {
+		store.Delete(key)
+	}
+}
+
+// GetParamChangeHistory returns the applied param changes kept in the history from the oldest to the latest
+func (keeper Keeper) GetParamChangeHistory(ctx sdk.Context) (records []ParamChangeRecord) {
+	records = []ParamChangeRecord{}
+	store := ctx.KVStore(keeper.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, ParamChangeHistoryKey)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var record ParamChangeRecord
+		keeper.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &record)
+		records = append(records, record)
+	}
+	return
+}
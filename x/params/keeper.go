@@ -9,6 +9,9 @@ import (
 // Keeper is the struct of params keeper
 type Keeper struct {
 	sdkparams.Keeper
+	// the params store shared with the subspaces, also keeping the history of the applied param changes
+	storeKey sdk.StoreKey
+	cdc      *codec.Codec
 	// the reference to the Paramstore to get and set gov specific params
 	paramSpace sdkparams.Subspace
 	// the reference to the DelegationSet and ValidatorSet to get information about validators and delegators
@@ -23,7 +26,9 @@ type Keeper struct {
 func NewKeeper(cdc *codec.Codec, key *sdk.KVStoreKey, tkey *sdk.TransientStoreKey, codespace sdk.CodespaceType) (
 	k Keeper) {
 	k = Keeper{
-		Keeper: sdkparams.NewKeeper(cdc, key, tkey, codespace),
+		Keeper:   sdkparams.NewKeeper(cdc, key, tkey, codespace),
+		storeKey: key,
+		cdc:      cdc,
 	}
 	k.paramSpace = k.Subspace(DefaultParamspace).WithKeyTable(ParamKeyTable())
 	return k
@@ -1,6 +1,21 @@
 package params
 
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
 var (
 	// ParamStoreKeyParamsParams is the raw store key for params module
 	ParamStoreKeyParamsParams = []byte("paramsparams")
+
+	// ParamChangeHistoryKey is the key prefix of the applied param changes in the params store, which never collides
+	// with the subspaces prefixed by the printable module names
+	ParamChangeHistoryKey = []byte{0x01}
+	// ParamChangeHistorySeqKey is the key of the sequence of the last applied param change
+	ParamChangeHistorySeqKey = []byte{0x02}
 )
+
+// GetParamChangeHistoryKey gets the key of an applied param change by its sequence
+func GetParamChangeHistoryKey(seq uint64) []byte {
+	return append(ParamChangeHistoryKey, sdk.Uint64ToBigEndian(seq)...)
+}
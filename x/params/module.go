@@ -111,8 +111,8 @@ func (am AppModule) ExportGenesis(ctx sdk.Context) json.RawMessage {
 // nolint
 func (AppModule) RegisterInvariants(ir sdk.InvariantRegistry)        {}
 func (AppModule) NewHandler() sdk.Handler                            { return nil }
-func (AppModule) QuerierRoute() string                               { return "" }
-func (AppModule) NewQuerierHandler() sdk.Querier                     { return nil }
+func (AppModule) QuerierRoute() string                               { return RouterKey }
+func (am AppModule) NewQuerierHandler() sdk.Querier                  { return NewQuerier(am.keeper) }
 func (AppModule) BeginBlock(_ sdk.Context, _ abci.RequestBeginBlock) {}
 func (AppModule) EndBlock(ctx sdk.Context, _ abci.RequestEndBlock) []abci.ValidatorUpdate {
 	return []abci.ValidatorUpdate{}
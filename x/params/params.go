@@ -23,15 +23,18 @@ type Params struct {
 	VotingPeriod time.Duration `json:"voting_period"`
 	// block height for dex list can not be greater than DexListMaxBlockHeight
 	MaxBlockHeight uint64 `json:"max_block_height"`
+	// number of blocks to keep the applied param changes in the history, 0 to keep all of them
+	ChangeHistoryRetention uint64 `json:"change_history_retention"`
 }
 
 // DefaultParams returns the instance of Params with default value
 func DefaultParams() Params {
 	minDeposit := sdk.DecCoins{sdk.NewDecCoin(sdk.DefaultBondDenom, sdk.NewInt(100))}
 	return Params{
-		MaxDepositPeriod: time.Hour * 24,
-		MinDeposit:       minDeposit,
-		VotingPeriod:     time.Hour * 72,
-		MaxBlockHeight:   100000,
+		MaxDepositPeriod:       time.Hour * 24,
+		MinDeposit:             minDeposit,
+		VotingPeriod:           time.Hour * 72,
+		MaxBlockHeight:         100000,
+		ChangeHistoryRetention: 864000,
 	}
 }
@@ -37,16 +37,22 @@ func handleParameterChangeProposal(ctx sdk.Context, k *Keeper, proposal *govtype
 	}
 
 	defer k.gk.RemoveFromWaitingProposalQueue(ctx, paramProposal.Height, proposal.ProposalID)
-	return changeParams(ctx, k, paramProposal)
+	return changeParams(ctx, k, proposal.ProposalID, paramProposal)
 }
 
-func changeParams(ctx sdk.Context, k *Keeper, paramProposal ParameterChangeProposal) sdk.Error {
+func changeParams(ctx sdk.Context, k *Keeper, proposalID uint64, paramProposal ParameterChangeProposal) sdk.Error {
 	for _, c := range paramProposal.Changes {
 		ss, ok := k.GetSubspace(c.Subspace)
 		if !ok {
 			return sdkparams.ErrUnknownSubspace(k.Codespace(), c.Subspace)
 		}
 
+		if err := k.sk.ValidateParamChange(ctx, c); err != nil {
+			return err
+		}
+
+		storeKey := getParamStoreKey(c)
+		oldValue := ss.GetRaw(ctx, storeKey)
 		var err error
 		if len(c.Subkey) == 0 {
 			k.Logger(ctx).Info(
@@ -64,7 +70,10 @@ func changeParams(ctx sdk.Context, k *Keeper, paramProposal ParameterChangePropo
 		if err != nil {
 			return sdkparams.ErrSettingParameter(k.Codespace(), c.Key, c.Subkey, c.Value, err.Error())
 		}
+		k.appendParamChange(ctx, NewParamChangeRecord(proposalID, ctx.BlockHeight(), c, oldValue,
+			ss.GetRaw(ctx, storeKey)))
 	}
+	k.pruneParamChangeHistory(ctx)
 	return nil
 }
 
@@ -125,9 +134,9 @@ func (keeper Keeper) CheckMsgSubmitProposal(ctx sdk.Context, msg govtypes.MsgSub
 		return govtypes.ErrInvalidHeight(DefaultCodespace, paramsChangeProposal.Height, curHeight, maxHeight)
 	}
 
-	// run simulation with cache context
+	// run simulation with cache context, the proposal id isn't assigned yet
 	cacheCtx, _ := ctx.CacheContext()
-	return changeParams(cacheCtx, &keeper, paramsChangeProposal)
+	return changeParams(cacheCtx, &keeper, 0, paramsChangeProposal)
 }
 
 // nolint
@@ -0,0 +1,104 @@
+package params
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkparams "github.com/cosmos/cosmos-sdk/x/params"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+	dbm "github.com/tendermint/tm-db"
+)
+
+const testSubspace = "test"
+
+var (
+	keyMaxValidators = []byte("MaxValidators")
+	keyDenoms        = []byte("Denoms")
+)
+
+type mockStakingKeeper struct{}
+
+func (mockStakingKeeper) IsValidator(_ sdk.Context, _ sdk.AccAddress) bool { return true }
+func (mockStakingKeeper) ValidateParamChange(_ sdk.Context, _ sdkparams.ParamChange) sdk.Error {
+	return nil
+}
+
+func createTestInput(t *testing.T) (sdk.Context, Keeper) {
+	keyParams := sdk.NewKVStoreKey(StoreKey)
+	tkeyParams := sdk.NewTransientStoreKey(TStoreKey)
+	db := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(keyParams, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(tkeyParams, sdk.StoreTypeTransient, db)
+	require.NoError(t, ms.LoadLatestVersion())
+	ctx := sdk.NewContext(ms, abci.Header{}, false, log.NewNopLogger())
+
+	cdc := codec.New()
+	k := NewKeeper(cdc, keyParams, tkeyParams, DefaultCodespace)
+	k.SetStakingKeeper(mockStakingKeeper{})
+	k.SetParams(ctx, DefaultParams())
+	ss := k.Subspace(testSubspace).WithKeyTable(NewKeyTable().
+		RegisterType(keyMaxValidators, uint16(0)).
+		RegisterType(keyDenoms, []string{}))
+	ss.Set(ctx, keyMaxValidators, uint16(21))
+	return ctx, k
+}
+
+func TestParamChangeHistory(t *testing.T) {
+	ctx, k := createTestInput(t)
+	require.Equal(t, []ParamChangeRecord{}, k.GetParamChangeHistory(ctx))
+
+	apply := func(height int64, proposalID uint64, changes ...ParamChange) {
+		proposal := NewParameterChangeProposal("title", "desc", changes, uint64(height))
+		require.Nil(t, changeParams(ctx.WithBlockHeight(height), &k, proposalID, proposal))
+	}
+	apply(10, 1, NewParamChange(testSubspace, string(keyMaxValidators), `30`))
+	apply(20, 2, NewParamChange(testSubspace, string(keyMaxValidators), `40`),
+		NewParamChange(testSubspace, string(keyDenoms), `["okt"]`))
+
+	expected := []ParamChangeRecord{
+		{ProposalID: 1, Height: 10, Subspace: testSubspace, Key: "MaxValidators", OldValue: `21`, NewValue: `30`},
+		{ProposalID: 2, Height: 20, Subspace: testSubspace, Key: "MaxValidators", OldValue: `30`, NewValue: `40`},
+		{ProposalID: 2, Height: 20, Subspace: testSubspace, Key: "Denoms", OldValue: "", NewValue: `["okt"]`},
+	}
+	require.Equal(t, expected, k.GetParamChangeHistory(ctx))
+
+	// a failed change isn't recorded
+	proposal := NewParameterChangeProposal("title", "desc",
+		[]ParamChange{NewParamChange("unknown", string(keyMaxValidators), `50`)}, 30)
+	cacheCtx, _ := ctx.CacheContext()
+	require.NotNil(t, changeParams(cacheCtx.WithBlockHeight(30), &k, 3, proposal))
+	require.Equal(t, expected, k.GetParamChangeHistory(cacheCtx))
+
+	// query
+	querier := NewQuerier(k)
+	bz, err := querier(ctx, []string{QueryParamChangeHistory}, abci.RequestQuery{})
+	require.Nil(t, err)
+	var records []ParamChangeRecord
+	require.NoError(t, ModuleCdc.UnmarshalJSON(bz, &records))
+	require.Equal(t, expected, records)
+	_, err = querier(ctx, []string{"unknown"}, abci.RequestQuery{})
+	require.NotNil(t, err)
+
+	// the changes beyond the retention window are pruned with the next change
+	params := k.GetParams(ctx)
+	params.ChangeHistoryRetention = 100
+	k.SetParams(ctx, params)
+	apply(120, 4, NewParamChange(testSubspace, string(keyMaxValidators), `50`))
+	require.Equal(t, append(expected[1:], ParamChangeRecord{ProposalID: 4, Height: 120, Subspace: testSubspace,
+		Key: "MaxValidators", OldValue: `40`, NewValue: `50`}), k.GetParamChangeHistory(ctx))
+	apply(121, 5, NewParamChange(testSubspace, string(keyMaxValidators), `60`))
+	history := k.GetParamChangeHistory(ctx)
+	require.Equal(t, 2, len(history))
+	require.Equal(t, []uint64{4, 5}, []uint64{history[0].ProposalID, history[1].ProposalID})
+
+	// keep all with the zero retention
+	params.ChangeHistoryRetention = 0
+	k.SetParams(ctx, params)
+	apply(1000, 6, NewParamChange(testSubspace, string(keyMaxValidators), `70`))
+	require.Equal(t, 3, len(k.GetParamChangeHistory(ctx)))
+}
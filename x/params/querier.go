@@ -0,0 +1,33 @@
+package params
+
+import (
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// query endpoints supported by the params querier
+const (
+	QueryParamChangeHistory = "changeHistory"
+)
+
+// NewQuerier creates a querier for params module
+func NewQuerier(keeper Keeper) sdk.Querier {
+	return func(ctx sdk.Context, path []string, req abci.RequestQuery) ([]byte, sdk.Error) {
+		switch path[0] {
+		case QueryParamChangeHistory:
+			return queryParamChangeHistory(ctx, keeper)
+		default:
+			return nil, sdk.ErrUnknownRequest("unknown params query endpoint")
+		}
+	}
+}
+
+func queryParamChangeHistory(ctx sdk.Context, keeper Keeper) ([]byte, sdk.Error) {
+	res, err := codec.MarshalJSONIndent(ModuleCdc, keeper.GetParamChangeHistory(ctx))
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+
+	return res, nil
+}
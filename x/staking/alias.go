@@ -52,6 +52,9 @@ var (
 	NewDescription                     = types.NewDescription
 	NewMsgVote                         = types.NewMsgVote
 	NewGenesisState                    = types.NewGenesisState
+	NewUnjailValidatorProposal         = types.NewUnjailValidatorProposal
+
+	NewForceUpdateValidatorStateProposal = types.NewForceUpdateValidatorStateProposal
 
 	// variable aliases
 	ModuleCdc     = types.ModuleCdc
@@ -60,6 +63,7 @@ var (
 
 type (
 	Keeper                    = keeper.Keeper
+	UnjailValidatorProposal   = types.UnjailValidatorProposal
 	GenesisState              = types.GenesisState
 	Validator                 = types.Validator
 	Validators                = types.Validators
@@ -69,4 +73,6 @@ type (
 	Delegator                 = types.Delegator
 	UndelegationInfo          = types.UndelegationInfo
 	ProxyDelegatorKeyExported = types.ProxyDelegatorKeyExported
+
+	ForceUpdateValidatorStateProposal = types.ForceUpdateValidatorStateProposal
 )
@@ -23,6 +23,14 @@ const (
 
 	FlagNodeID = "node-id"
 	FlagIP     = "ip"
+
+	FlagAcceptingDelegations = "accepting-delegations"
+	FlagMaxDelegatorCount    = "max-delegator-count"
+	FlagMinDelegation        = "min-delegation"
+
+	FlagPage   = "page"
+	FlagLimit  = "limit"
+	FlagFormat = "format"
 )
 
 // common flagsets to add to various functions
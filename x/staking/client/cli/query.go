@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/context"
@@ -26,9 +27,13 @@ func GetQueryCmd(queryRoute string, cdc *codec.Codec) *cobra.Command {
 	}
 	stakingQueryCmd.AddCommand(client.GetCommands(
 		GetCmdQueryDelegator(queryRoute, cdc),
+		GetCmdQueryPortfolio(queryRoute, cdc),
 		GetCmdQueryValidatorVotes(queryRoute, cdc),
+		GetCmdQueryUnvotedValidators(queryRoute, cdc),
+		GetCmdQueryDelegatorDelegations(queryRoute, cdc),
 		GetCmdQueryValidator(queryRoute, cdc),
 		GetCmdQueryValidators(queryRoute, cdc),
+		GetCmdExportValidator(queryRoute, cdc),
 		GetCmdQueryProxy(queryRoute, cdc),
 		GetCmdQueryParams(queryRoute, cdc),
 		GetCmdQueryPool(queryRoute, cdc))...)
@@ -279,6 +284,49 @@ $ %s query staking delegator okchain1hw4r48aww06ldrfeuq2v438ujnl6alszzzqpph
 	}
 }
 
+// GetCmdQueryPortfolio gets command for querying all the staking info of a delegator in one call
+func GetCmdQueryPortfolio(storeName string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "portfolio [address]",
+		Short: "query the staking portfolio of a delegator",
+		Args:  cobra.ExactArgs(1),
+		Long: strings.TrimSpace(
+			fmt.Sprintf(`Query the delegation, the votes to each validator and the undelegation of a delegator
+
+Example:
+$ %s query staking portfolio okchain1hw4r48aww06ldrfeuq2v438ujnl6alszzzqpph
+`,
+				version.ClientName,
+			),
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			delAddr, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid address：%s", args[0])
+			}
+
+			bytes, err := cdc.MarshalJSON(types.NewQueryDelegatorParams(delAddr))
+			if err != nil {
+				return err
+			}
+
+			route := fmt.Sprintf("custom/%s/%s", storeName, types.QueryDelegatorPortfolio)
+			resp, _, err := cliCtx.QueryWithData(route, bytes)
+			if err != nil {
+				return err
+			}
+
+			var portfolio types.DelegatorPortfolio
+			if err := cdc.UnmarshalJSON(resp, &portfolio); err != nil {
+				return err
+			}
+
+			return cliCtx.PrintOutput(portfolio)
+		},
+	}
+}
+
 // DelegatorResponse is designed for delegator info query
 type DelegatorResponse struct {
 	DelegatorAddress     sdk.AccAddress   `json:"delegator_address" yaml:"delegator_address"`
@@ -384,3 +432,100 @@ $ %s query staking votes-to okchainvaloper1alq9na49n9yycysh889rl90g9nhe58lcs50wu
 		},
 	}
 }
+
+// GetCmdQueryUnvotedValidators gets command for querying the bonded validators that a delegator isn't voting for
+func GetCmdQueryUnvotedValidators(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unvoted-validators [address]",
+		Short: "query the bonded validators that a delegator isn't voting for",
+		Args:  cobra.ExactArgs(1),
+		Long: strings.TrimSpace(
+			fmt.Sprintf(`Query the bonded validators that a delegator isn't voting for, which are the candidates to add
+to its vote set, and how many more validators it's able to vote for.
+
+Example:
+$ %s query staking unvoted-validators okchain1hw4r48aww06ldrfeuq2v438ujnl6alszzzqpph --page 1 --limit 10
+`,
+				version.ClientName,
+			),
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			delAddr, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid address：%s", args[0])
+			}
+
+			params := types.NewQueryUnvotedValidatorsParams(delAddr, viper.GetInt(FlagPage), viper.GetInt(FlagLimit))
+			bytes, err := cdc.MarshalJSON(params)
+			if err != nil {
+				return err
+			}
+
+			route := fmt.Sprintf("custom/%s/%s", queryRoute, types.QueryUnvotedValidators)
+			resp, _, err := cliCtx.QueryWithData(route, bytes)
+			if err != nil {
+				return err
+			}
+
+			var result types.UnvotedValidatorsResult
+			if err := cdc.UnmarshalJSON(resp, &result); err != nil {
+				return err
+			}
+
+			return cliCtx.PrintOutput(result)
+		},
+	}
+
+	cmd.Flags().Int(FlagPage, 1, "page number of the validators to query")
+	cmd.Flags().Int(FlagLimit, 0, "number of the validators per page, up to the param MaxValidators by default")
+	return cmd
+}
+
+// GetCmdQueryDelegatorDelegations gets command for querying the votes of a delegator page by page
+func GetCmdQueryDelegatorDelegations(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delegations [address]",
+		Short: "query the votes of a delegator page by page",
+		Args:  cobra.ExactArgs(1),
+		Long: strings.TrimSpace(
+			fmt.Sprintf(`Query the votes that a delegator makes to the validators page by page.
+
+Example:
+$ %s query staking delegations okchain1hw4r48aww06ldrfeuq2v438ujnl6alszzzqpph --page 2 --limit 10
+`,
+				version.ClientName,
+			),
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			delAddr, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid address：%s", args[0])
+			}
+
+			params := types.NewQueryDelegatorDelegationsParams(delAddr, viper.GetInt(FlagPage), viper.GetInt(FlagLimit))
+			bytes, err := cdc.MarshalJSON(params)
+			if err != nil {
+				return err
+			}
+
+			route := fmt.Sprintf("custom/%s/%s", queryRoute, types.QueryDelegatorDelegations)
+			resp, _, err := cliCtx.QueryWithData(route, bytes)
+			if err != nil {
+				return err
+			}
+
+			var result types.DelegatorDelegationsResult
+			if err := cdc.UnmarshalJSON(resp, &result); err != nil {
+				return err
+			}
+
+			return cliCtx.PrintOutput(result)
+		},
+	}
+
+	cmd.Flags().Int(FlagPage, 1, "page number of the votes to query")
+	cmd.Flags().Int(FlagLimit, 0, "number of the votes per page, up to the param MaxValsToVote by default")
+	return cmd
+}
@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/version"
+	"github.com/okex/okchain/x/staking/types"
+)
+
+// formats of the validator export
+const (
+	exportFormatCSV  = "csv"
+	exportFormatJSON = "json"
+)
+
+var (
+	validatorCSVHeader = []string{"operator_address", "moniker", "identity", "status", "jailed", "delegator_shares",
+		"min_self_delegation"}
+	delegatorCSVHeader = []string{"delegator_address", "shares", "tokens"}
+)
+
+// delegatorExport is a row of the delegators in the validator export, where the tokens are the ones behind the votes
+type delegatorExport struct {
+	DelegatorAddress sdk.AccAddress `json:"delegator_address"`
+	Shares           sdk.Dec        `json:"shares"`
+	Tokens           sdk.Dec        `json:"tokens"`
+}
+
+// validatorExport is the full record of a validator together with all the delegators voting to it
+type validatorExport struct {
+	Validator  types.StandardizedValidator `json:"validator"`
+	Delegators []delegatorExport           `json:"delegators"`
+}
+
+// GetCmdExportValidator gets command for exporting a validator with all its delegators
+func GetCmdExportValidator(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-validator [validator-addr]",
+		Short: "export a validator with all its delegators in csv or json",
+		Args:  cobra.ExactArgs(1),
+		Long: strings.TrimSpace(
+			fmt.Sprintf(`Export the record of a validator with a row per delegator voting to it, including the address,
+the shares voted and the tokens behind the shares.
+
+Example:
+$ %s query staking export-validator okchainvaloper1alq9na49n9yycysh889rl90g9nhe58lcs50wu5 --format csv > val.csv
+`,
+				version.ClientName,
+			),
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			valAddr, err := sdk.ValAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+
+			format := viper.GetString(FlagFormat)
+			if format != exportFormatCSV && format != exportFormatJSON {
+				return fmt.Errorf("invalid format %s, expected %s or %s", format, exportFormatCSV, exportFormatJSON)
+			}
+
+			resp, _, err := cliCtx.QueryStore(types.GetValidatorKey(valAddr), queryRoute)
+			if err != nil {
+				return err
+			}
+			if len(resp) == 0 {
+				return fmt.Errorf("no validator found with address %s", valAddr)
+			}
+			export := validatorExport{Validator: types.MustUnmarshalValidator(cdc, resp).Standardize()}
+
+			votes, err := queryAllValidatorDelegations(cliCtx, queryRoute, cdc, valAddr)
+			if err != nil {
+				return err
+			}
+			export.Delegators = make([]delegatorExport, 0, len(votes))
+			for _, vote := range votes {
+				delegator := types.NewDelegator(vote.VoterAddr)
+				resp, _, err := cliCtx.QueryStore(types.GetDelegatorKey(vote.VoterAddr), queryRoute)
+				if err != nil {
+					return err
+				}
+				if len(resp) != 0 {
+					cdc.MustUnmarshalBinaryLengthPrefixed(resp, &delegator)
+				}
+				export.Delegators = append(export.Delegators, delegatorExport{
+					DelegatorAddress: vote.VoterAddr,
+					Shares:           vote.Votes,
+					Tokens:           delegator.Tokens.Add(delegator.TotalDelegatedTokens),
+				})
+			}
+
+			if format == exportFormatCSV {
+				return writeValidatorExportCSV(cmd.OutOrStdout(), export)
+			}
+			bytes, err := codec.MarshalJSONIndent(cdc, export)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), string(bytes))
+			return err
+		},
+	}
+
+	cmd.Flags().String(FlagFormat, exportFormatCSV, "output format of the export, csv or json")
+	return cmd
+}
+
+// queryAllValidatorDelegations queries all the votes to a validator page by page, so that the size of each query is
+// bounded no matter how many delegators the validator has
+func queryAllValidatorDelegations(cliCtx context.CLIContext, queryRoute string, cdc *codec.Codec,
+	valAddr sdk.ValAddress) (votes types.VoteResponses, err error) {
+	route := fmt.Sprintf("custom/%s/%s", queryRoute, types.QueryValidatorDelegations)
+	for page := 1; ; page++ {
+		params := types.NewQueryValidatorDelegationsParams(valAddr, page, types.DefaultValidatorDelegationsLimit)
+		bytes, err := cdc.MarshalJSON(params)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, _, err := cliCtx.QueryWithData(route, bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		var result types.ValidatorDelegationsResult
+		if err := cdc.UnmarshalJSON(resp, &result); err != nil {
+			return nil, err
+		}
+
+		votes = append(votes, result.Votes...)
+		if len(result.Votes) == 0 || len(votes) >= result.Total {
+			return votes, nil
+		}
+	}
+}
+
+// writeValidatorExportCSV writes the validator export in csv, where the metadata of the validator comes first and is
+// followed by an empty line and the rows of the delegators
+func writeValidatorExportCSV(w io.Writer, export validatorExport) error {
+	validator := export.Validator
+	records := [][]string{
+		validatorCSVHeader,
+		{validator.OperatorAddress.String(), validator.Description.Moniker, validator.Description.Identity,
+			validator.Status.String(), fmt.Sprintf("%t", validator.Jailed), validator.DelegatorShares.String(),
+			validator.MinSelfDelegation.String()},
+		{},
+		delegatorCSVHeader,
+	}
+	for _, delegator := range export.Delegators {
+		records = append(records,
+			[]string{delegator.DelegatorAddress.String(), delegator.Shares.String(), delegator.Tokens.String()})
+	}
+
+	writer := csv.NewWriter(w)
+	return writer.WriteAll(records)
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/okex/okchain/x/staking/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteValidatorExportCSV(t *testing.T) {
+	pubKey := newPubKey("0000000000000000000000000000000000000000000000000000000000000000")
+	valAddr := sdk.ValAddress(pubKey.Address())
+	validator := types.NewValidator(valAddr, pubKey, types.NewDescription("moniker", "identity", "", ""))
+	validator.DelegatorShares = sdk.NewDec(300)
+	delAddrs := []sdk.AccAddress{sdk.AccAddress([]byte("delegator1__________")),
+		sdk.AccAddress([]byte("delegator2__________"))}
+	export := validatorExport{
+		Validator: validator.Standardize(),
+		Delegators: []delegatorExport{
+			{DelegatorAddress: delAddrs[0], Shares: sdk.NewDec(100), Tokens: sdk.NewDec(50)},
+			{DelegatorAddress: delAddrs[1], Shares: sdk.NewDec(200), Tokens: sdk.NewDec(100)},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeValidatorExportCSV(&buf, export))
+	reader := csv.NewReader(&buf)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	require.NoError(t, err)
+
+	// the empty line between the sections is skipped by the reader
+	require.Equal(t, 5, len(records))
+	require.Equal(t, validatorCSVHeader, records[0])
+	require.Equal(t, []string{valAddr.String(), "moniker", "identity", sdk.Unbonded.String(), "false",
+		sdk.NewDec(300).String(), sdk.OneDec().String()}, records[1])
+	require.Equal(t, delegatorCSVHeader, records[2])
+	require.Equal(t, []string{delAddrs[0].String(), sdk.NewDec(100).String(), sdk.NewDec(50).String()}, records[3])
+	require.Equal(t, []string{delAddrs[1].String(), sdk.NewDec(200).String(), sdk.NewDec(100).String()}, records[4])
+
+	// a validator without delegators
+	buf.Reset()
+	export.Delegators = nil
+	require.NoError(t, writeValidatorExportCSV(&buf, export))
+	reader = csv.NewReader(&buf)
+	reader.FieldsPerRecord = -1
+	records, err = reader.ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, 3, len(records))
+	require.Equal(t, delegatorCSVHeader, records[2])
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/spf13/cobra"
 	flag "github.com/spf13/pflag"
@@ -38,6 +39,7 @@ func GetTxCmd(storeKey string, cdc *codec.Codec) *cobra.Command {
 			GetCmdDelegate(cdc),
 			GetCmdUndelegate(cdc),
 			GetCmdVote(cdc),
+			GetCmdEstimateGas(cdc),
 		)...)
 
 	stakingTxCmd.AddCommand(GetCmdProxy(cdc))
@@ -114,7 +116,13 @@ func GetCmdEditValidator(cdc *codec.Codec) *cobra.Command {
 			//}
 			//
 			//msg := types.NewMsgEditValidator(sdk.ValAddress(valAddr), description, newRate, newMinSelfDelegation)
-			msg := types.NewMsgEditValidator(sdk.ValAddress(valAddr), description)
+			acceptingDelegations, maxDelegatorCount, minDelegation, err := getDelegationPolicy()
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgEditValidator(sdk.ValAddress(valAddr), description, acceptingDelegations,
+				maxDelegatorCount, minDelegation)
 
 			// build and sign the transaction, then broadcast to Tendermint
 			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
@@ -123,10 +131,43 @@ func GetCmdEditValidator(cdc *codec.Codec) *cobra.Command {
 
 	cmd.Flags().AddFlagSet(fsDescriptionEdit)
 	//cmd.Flags().AddFlagSet(fsCommissionUpdate)
+	cmd.Flags().String(FlagAcceptingDelegations, "",
+		"Whether the validator accepts the votes from new delegators (true|false), unchanged if empty")
+	cmd.Flags().String(FlagMaxDelegatorCount, "",
+		"The max number of delegators voting to the validator, 0 means no limit, unchanged if empty")
+	cmd.Flags().String(FlagMinDelegation, "",
+		"The min tokens of a new delegator voting to the validator, 0 means the global floor only, unchanged if empty")
 
 	return cmd
 }
 
+// getDelegationPolicy parses the delegation acceptance policy from the flags, nil for the one unchanged
+func getDelegationPolicy() (acceptingDelegations *bool, maxDelegatorCount *uint64, minDelegation *sdk.Dec,
+	err error) {
+	if str := viper.GetString(FlagAcceptingDelegations); str != "" {
+		accepting, err := strconv.ParseBool(str)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid --%s: %s", FlagAcceptingDelegations, err)
+		}
+		acceptingDelegations = &accepting
+	}
+	if str := viper.GetString(FlagMaxDelegatorCount); str != "" {
+		count, err := strconv.ParseUint(str, 10, 64)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid --%s: %s", FlagMaxDelegatorCount, err)
+		}
+		maxDelegatorCount = &count
+	}
+	if str := viper.GetString(FlagMinDelegation); str != "" {
+		amount, err := sdk.NewDecFromStr(str)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid --%s: %s", FlagMinDelegation, err)
+		}
+		minDelegation = &amount
+	}
+	return acceptingDelegations, maxDelegatorCount, minDelegation, nil
+}
+
 //__________________________________________________________
 
 var (
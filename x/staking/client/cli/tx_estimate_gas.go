@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/version"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/auth/client/utils"
+	"github.com/okex/okchain/x/staking/types"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// msg types supported by the command estimate-gas
+const (
+	estimateGasDelegate        = "delegate"
+	estimateGasUndelegate      = "unbond"
+	estimateGasCreateValidator = "create-validator"
+	estimateGasVote            = "vote"
+)
+
+// GetCmdEstimateGas gets command for estimating the gas of a staking msg by simulating it against a node
+func GetCmdEstimateGas(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "estimate-gas [msg-type] [args...]",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "estimate the gas of a staking msg by simulating it",
+		Long: strings.TrimSpace(
+			fmt.Sprintf(`Estimate the gas of a staking msg by simulating it against a node. The msg-type is one of
+%s, %s, %s and %s, followed by the args of the corresponding command.
+
+Example:
+$ %s tx staking estimate-gas delegate 1000okt --from mykey
+$ %s tx staking estimate-gas unbond 1okt --from mykey
+$ %s tx staking estimate-gas vote okchainvaloper1alq9na49n9yycysh889rl90g9nhe58lcs50wu5 --from mykey
+$ %s tx staking estimate-gas create-validator --pubkey=okchainvalconspub1zcjduepq... --moniker=mynode --from mykey
+`,
+				estimateGasDelegate, estimateGasUndelegate, estimateGasCreateValidator, estimateGasVote,
+				version.ClientName, version.ClientName, version.ClientName, version.ClientName),
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			txBldr, msg, err := buildMsgForEstimation(cliCtx, txBldr, args[0], args[1:])
+			if err != nil {
+				return err
+			}
+
+			txBldr, err = utils.PrepareTxBuilder(txBldr, cliCtx)
+			if err != nil {
+				return err
+			}
+
+			estimated, adjusted, err := estimateGas(cliCtx.QueryWithData, cdc, txBldr, []sdk.Msg{msg})
+			if err != nil {
+				return err
+			}
+
+			return printOutput(cmd.OutOrStdout(), cliCtx, gasEstimate{estimated,
+				fmt.Sprintf("%v", txBldr.GasAdjustment()), adjusted})
+		},
+	}
+
+	cmd.Flags().AddFlagSet(FsPk)
+	cmd.Flags().AddFlagSet(fsDescriptionCreate)
+
+	return cmd
+}
+
+// gasEstimate is the output of the command estimate-gas. The gas adjustment is formatted as a string, since amino
+// doesn't encode floats
+type gasEstimate struct {
+	Estimated     uint64 `json:"estimated" yaml:"estimated"`
+	GasAdjustment string `json:"gas_adjustment" yaml:"gas_adjustment"`
+	Adjusted      uint64 `json:"adjusted" yaml:"adjusted"`
+}
+
+// String returns a human readable string representation of gasEstimate
+func (ge gasEstimate) String() string {
+	return fmt.Sprintf("estimated gas: %d\nadjusted gas (with adjustment %s): %d", ge.Estimated, ge.GasAdjustment,
+		ge.Adjusted)
+}
+
+// printOutput prints in the output format of the cli context as cliCtx.PrintOutput does, but into the writer given,
+// e.g. the output of the command
+func printOutput(w io.Writer, cliCtx context.CLIContext, toPrint fmt.Stringer) (err error) {
+	var out []byte
+	switch cliCtx.OutputFormat {
+	case "json":
+		if cliCtx.Indent {
+			out, err = cliCtx.Codec.MarshalJSONIndent(toPrint, "", "  ")
+		} else {
+			out, err = cliCtx.Codec.MarshalJSON(toPrint)
+		}
+	default:
+		out, err = yaml.Marshal(toPrint)
+	}
+	if err != nil {
+		return
+	}
+
+	_, err = fmt.Fprintln(w, string(out))
+	return
+}
+
+// buildMsgForEstimation builds the staking msg of the msg type with the args as the corresponding tx command does
+func buildMsgForEstimation(cliCtx context.CLIContext, txBldr auth.TxBuilder, msgType string, args []string) (
+	auth.TxBuilder, sdk.Msg, error) {
+	switch msgType {
+	case estimateGasDelegate, estimateGasUndelegate:
+		if len(args) != 1 {
+			return txBldr, nil, fmt.Errorf("%s requires exactly one arg [amount]", msgType)
+		}
+		amount, err := sdk.ParseDecCoin(args[0])
+		if err != nil {
+			return txBldr, nil, err
+		}
+		if msgType == estimateGasDelegate {
+			return txBldr, types.NewMsgDelegate(cliCtx.GetFromAddress(), amount), nil
+		}
+		return txBldr, types.NewMsgUndelegate(cliCtx.GetFromAddress(), amount), nil
+	case estimateGasVote:
+		if len(args) != 1 {
+			return txBldr, nil, fmt.Errorf("%s requires exactly one arg [validator-addr1, ... validator-addrN]",
+				msgType)
+		}
+		valAddrs, err := getValsSet(args[0])
+		if err != nil {
+			return txBldr, nil, err
+		}
+		return txBldr, types.NewMsgVote(cliCtx.GetFromAddress(), valAddrs), nil
+	case estimateGasCreateValidator:
+		if len(args) != 0 {
+			return txBldr, nil, fmt.Errorf("%s requires no args but the flags", msgType)
+		}
+		return BuildCreateValidatorMsg(cliCtx, txBldr)
+	default:
+		return txBldr, nil, fmt.Errorf("unsupported msg type %s, expected one of %s, %s, %s and %s", msgType,
+			estimateGasDelegate, estimateGasUndelegate, estimateGasCreateValidator, estimateGasVote)
+	}
+}
+
+// estimateGas simulates the msgs through the query function and returns both the estimated gas and the adjusted one
+func estimateGas(queryFunc func(string, []byte) ([]byte, int64, error), cdc *codec.Codec, txBldr auth.TxBuilder,
+	msgs []sdk.Msg) (estimated, adjusted uint64, err error) {
+	txBytes, err := txBldr.BuildTxForSim(msgs)
+	if err != nil {
+		return
+	}
+
+	return utils.CalculateGas(queryFunc, cdc, txBytes, txBldr.GasAdjustment())
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/okex/okchain/x/staking/types"
+	"github.com/stretchr/testify/require"
+)
+
+func makeTestCodec() *codec.Codec {
+	cdc := codec.New()
+	sdk.RegisterCodec(cdc)
+	auth.RegisterCodec(cdc)
+	types.RegisterCodec(cdc)
+	codec.RegisterCrypto(cdc)
+	return cdc
+}
+
+func TestBuildMsgForEstimation(t *testing.T) {
+	cliCtx := context.NewCLIContext()
+	txBldr := auth.NewTxBuilder(nil, 0, 0, 0, 0, false, "", "", nil, nil)
+	valAddr := sdk.ValAddress(newPubKey("0000000000000000000000000000000000000000000000000000000000000000").Address())
+
+	_, msg, err := buildMsgForEstimation(cliCtx, txBldr, estimateGasDelegate, []string{"10okt"})
+	require.NoError(t, err)
+	require.IsType(t, types.MsgDelegate{}, msg)
+
+	_, msg, err = buildMsgForEstimation(cliCtx, txBldr, estimateGasUndelegate, []string{"10okt"})
+	require.NoError(t, err)
+	require.IsType(t, types.MsgUndelegate{}, msg)
+
+	_, msg, err = buildMsgForEstimation(cliCtx, txBldr, estimateGasVote, []string{valAddr.String()})
+	require.NoError(t, err)
+	require.IsType(t, types.MsgVote{}, msg)
+
+	// wrong number of args
+	_, _, err = buildMsgForEstimation(cliCtx, txBldr, estimateGasDelegate, nil)
+	require.Error(t, err)
+	_, _, err = buildMsgForEstimation(cliCtx, txBldr, estimateGasVote, []string{"a", "b"})
+	require.Error(t, err)
+
+	// invalid amount
+	_, _, err = buildMsgForEstimation(cliCtx, txBldr, estimateGasDelegate, []string{"okt"})
+	require.Error(t, err)
+
+	// unsupported msg type
+	_, _, err = buildMsgForEstimation(cliCtx, txBldr, "edit-validator", nil)
+	require.Error(t, err)
+}
+
+func TestEstimateGas(t *testing.T) {
+	cdc := makeTestCodec()
+	txBldr := auth.NewTxBuilder(auth.DefaultTxEncoder(cdc), 0, 0, 0, 1.5, true, "okchain", "", nil, nil)
+	var valAddrs []sdk.ValAddress
+	for _, b := range []string{"0", "1", "2"} {
+		valAddrs = append(valAddrs, sdk.ValAddress(newPubKey(strings.Repeat(b, 64)).Address()))
+	}
+	msg := types.NewMsgVote(sdk.AccAddress(valAddrs[0]), valAddrs)
+
+	// mock the simulation response of a node
+	queryFunc := func(path string, data []byte) ([]byte, int64, error) {
+		require.Equal(t, "/app/simulate", path)
+		var tx auth.StdTx
+		require.NoError(t, cdc.UnmarshalBinaryLengthPrefixed(data, &tx))
+		require.Equal(t, []sdk.Msg{msg}, tx.GetMsgs())
+		return cdc.MustMarshalBinaryLengthPrefixed(sdk.Result{GasUsed: 10000}), 0, nil
+	}
+	estimated, adjusted, err := estimateGas(queryFunc, cdc, txBldr, []sdk.Msg{msg})
+	require.NoError(t, err)
+	require.Equal(t, uint64(10000), estimated)
+	require.Equal(t, uint64(15000), adjusted)
+
+	// the simulation fails
+	queryFunc = func(string, []byte) ([]byte, int64, error) {
+		return nil, 0, errors.New("insufficient votes")
+	}
+	_, _, err = estimateGas(queryFunc, cdc, txBldr, []sdk.Msg{msg})
+	require.Error(t, err)
+
+	// the response isn't a result
+	queryFunc = func(string, []byte) ([]byte, int64, error) {
+		return []byte("invalid"), 0, nil
+	}
+	_, _, err = estimateGas(queryFunc, cdc, txBldr, []sdk.Msg{msg})
+	require.Error(t, err)
+}
+
+func TestPrintGasEstimate(t *testing.T) {
+	cliCtx := context.NewCLIContext().WithCodec(makeTestCodec())
+	estimate := gasEstimate{10000, "1.5", 15000}
+
+	var buf bytes.Buffer
+	cliCtx.OutputFormat = "json"
+	require.NoError(t, printOutput(&buf, cliCtx, estimate))
+	require.Equal(t, `{"estimated":"10000","gas_adjustment":"1.5","adjusted":"15000"}`+"\n", buf.String())
+
+	buf.Reset()
+	cliCtx.OutputFormat = "text"
+	require.NoError(t, printOutput(&buf, cliCtx, estimate))
+	require.Equal(t, "estimated: 10000\ngas_adjustment: \"1.5\"\nadjusted: 15000\n\n", buf.String())
+}
@@ -132,6 +132,17 @@ func GetCmdVote(cdc *codec.Codec) *cobra.Command {
 			}
 
 			msg := types.NewMsgVote(voterAddr, valAddrs)
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			// the limit is only able to be checked with a node to query
+			if !cliCtx.GenerateOnly {
+				if err := checkValsToVote(cliCtx, valAddrs); err != nil {
+					return err
+				}
+			}
+
 			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
 
 		},
@@ -271,10 +282,42 @@ func getValsSet(address string) (valAddrs []sdk.ValAddress, err error) {
 	lenVals := len(addrs)
 	valAddrs = make([]sdk.ValAddress, lenVals)
 	for i := 0; i < lenVals; i++ {
-		valAddrs[i], err = sdk.ValAddressFromBech32(addrs[i])
+		addr := strings.TrimSpace(addrs[i])
+		if len(addr) == 0 {
+			return nil, fmt.Errorf("empty target validator address in: %s", address)
+		}
+		// all the votes of the voter go to every target validator, so there's no weight to split them
+		if strings.Contains(addr, ":") {
+			return nil, fmt.Errorf("vote weights are not supported, each target validator gets all the votes: %s", addr)
+		}
+		valAddrs[i], err = sdk.ValAddressFromBech32(addr)
 		if err != nil {
 			return nil, fmt.Errorf("invalid target validator address: %s", addrs[i])
 		}
 	}
 	return
 }
+
+// checkValsToVote checks the number of the target validators against the max limit on chain
+func checkValsToVote(cliCtx context.CLIContext, valAddrs []sdk.ValAddress) error {
+	route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryParameters)
+	bz, _, err := cliCtx.QueryWithData(route, nil)
+	if err != nil {
+		return err
+	}
+
+	var params types.Params
+	if err := cliCtx.Codec.UnmarshalJSON(bz, &params); err != nil {
+		return err
+	}
+
+	return validateValsCount(valAddrs, params.MaxValsToVote)
+}
+
+// validateValsCount returns an error if there are more target validators than the max number to vote
+func validateValsCount(valAddrs []sdk.ValAddress, maxValsToVote uint16) error {
+	if len(valAddrs) > int(maxValsToVote) {
+		return types.ErrExceedValidatorAddrs(types.DefaultCodespace, int(maxValsToVote))
+	}
+	return nil
+}
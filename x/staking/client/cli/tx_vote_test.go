@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/okex/okchain/x/staking/types"
 	"github.com/stretchr/testify/require"
 	"github.com/tendermint/tendermint/crypto"
 	"github.com/tendermint/tendermint/crypto/ed25519"
@@ -39,6 +40,38 @@ func TestGetValsSet(t *testing.T) {
 	valAddrs, err := getValsSet(arg)
 	require.NoError(t, err)
 	require.Equal(t, expectedValAddrs, valAddrs)
+
+	// spaces around the addresses
+	valAddrs, err = getValsSet(" " + strings.Join(valAddrsStr, " , ") + " ")
+	require.NoError(t, err)
+	require.Equal(t, expectedValAddrs, valAddrs)
+
+	// parsed into the vote msg
+	msg := types.NewMsgVote(accAddrs[0], valAddrs)
+	require.Nil(t, msg.ValidateBasic())
+	require.Equal(t, expectedValAddrs, msg.ValAddrs)
+
+	// duplicate validators are rejected by the msg
+	valAddrs, err = getValsSet(strings.Join(append(valAddrsStr, valAddrsStr[0]), ","))
+	require.NoError(t, err)
+	require.NotNil(t, types.NewMsgVote(accAddrs[0], valAddrs).ValidateBasic())
+
+	// weights aren't supported
+	_, err = getValsSet(valAddrsStr[0] + ":0.5," + valAddrsStr[1] + ":0.5")
+	require.Error(t, err)
+
+	// invalid address
+	_, err = getValsSet(valAddrsStr[0] + ",okchainvaloper1xxx")
+	require.Error(t, err)
+	_, err = getValsSet(valAddrsStr[0] + ",")
+	require.Error(t, err)
+}
+
+func TestValidateValsCount(t *testing.T) {
+	valAddrs := make([]sdk.ValAddress, 3)
+	require.NoError(t, validateValsCount(valAddrs, 3))
+	require.NoError(t, validateValsCount(valAddrs, 30))
+	require.Error(t, validateValsCount(valAddrs, 2))
 }
 
 func newPubKey(pubKey string) (res crypto.PubKey) {
@@ -297,3 +297,46 @@ func TestProxy(t *testing.T) {
 	smTestCase.printParticipantSnapshot(t)
 	smTestCase.Run(t)
 }
+
+func TestProxyBindingsNeverDrift(t *testing.T) {
+	_, _, mk := CreateTestInput(t, false, SufficientInitPower)
+	params := DefaultParams()
+	startUpStatus := baseValidatorStatus{NewValidator(StartUpValidatorAddr, StartUpValidatorPubkey, Description{})}
+
+	bAction := baseAction{mk}
+	inputActions := []IAction{
+		newDelegatorAction{bAction, ProxiedDelegator, MaxDelegatedToken, sdk.DefaultBondDenom},
+		baseProxyRegAction{bAction, ProxiedDelegator, true},
+		newDelegatorAction{bAction, ValidDelegator1, MaxDelegatedToken, sdk.DefaultBondDenom},
+		proxyBindAction{bAction, ValidDelegator1, ProxiedDelegator},
+
+		// a delegator bound to a proxy fails to register as a proxy
+		baseProxyRegAction{bAction, ValidDelegator1, true},
+
+		// the proxy undelegating all releases the delegators bound to it
+		delegatorUnbondAction{bAction, ProxiedDelegator, MaxDelegatedToken, sdk.DefaultBondDenom},
+	}
+
+	expZeroDec := sdk.ZeroDec()
+	doubleProxyChecker := andChecker{[]actResChecker{
+		noErrorInHandlerResult(false),
+		queryDelegatorProxyCheck(ValidDelegator1, false, true, &expZeroDec, &ProxiedDelegator, nil),
+		queryDelegatorProxyCheck(ProxiedDelegator, true, false, &MaxDelegatedToken, nil, nil),
+	}}
+	proxyUnbondChecker := andChecker{[]actResChecker{
+		noErrorInHandlerResult(true),
+		queryDelegatorProxyCheck(ValidDelegator1, false, false, &expZeroDec, nil, nil),
+	}}
+	actionsAndChecker := []actResChecker{
+		noErrorInHandlerResult(true),
+		noErrorInHandlerResult(true),
+		noErrorInHandlerResult(true),
+		queryDelegatorProxyCheck(ProxiedDelegator, true, false, &MaxDelegatedToken, nil,
+			[]sdk.AccAddress{ValidDelegator1}),
+		doubleProxyChecker.GetChecker(),
+		proxyUnbondChecker.GetChecker(),
+	}
+
+	smTestCase := newValidatorSMTestCase(mk, params, startUpStatus, inputActions, actionsAndChecker, t)
+	smTestCase.Run(t)
+}
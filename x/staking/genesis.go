@@ -19,26 +19,40 @@ import (
 // Returns final validator set after applying all declaration and delegations
 func InitGenesis(ctx sdk.Context, keeper Keeper, accountKeeper types.AccountKeeper,
 	supplyKeeper types.SupplyKeeper, data types.GenesisState) (res []abci.ValidatorUpdate) {
-	bondedTokens, notBondedTokens := sdk.ZeroDec(), sdk.ZeroDec()
+	bondedCoins, notBondedCoins := sdk.DecCoins{}, sdk.DecCoins{}
 
 	// We need to pretend to be "n blocks before genesis", where "n" is the validator update delay, so that e.g.
 	// slashing periods are correctly initialized for the validator set e.g. with a one-block offset - the first
 	// TM block is at height 1, so state updates applied from genesis.json are in block 0.
 	ctx = ctx.WithBlockHeight(1 - sdk.ValidatorUpdateDelay)
+	if data.EpochDuration != nil {
+		epoch, err := data.EpochDuration.Blocks()
+		if err != nil {
+			panic(err)
+		}
+		data.Params.Epoch = epoch
+	}
 	keeper.SetParams(ctx, data.Params)
+	keeper.SetPowerReduction(ctx, data.Params.PowerReduction)
+	keeper.SetPowerTieBreak(ctx, data.Params.PowerTieBreak)
+	keeper.SetAppliedBondDenoms(ctx, data.Params.BondDenoms)
 	keeper.SetLastTotalPower(ctx, data.LastTotalPower)
+	keeper.SetEpochNumber(ctx, data.EpochNumber)
+	// the store is initialized in the current version, which needs no migration
+	keeper.SetStoreVersion(ctx, types.StoreVersion)
 
 	for _, validator := range data.Validators {
-		initValidator(ctx, validator, keeper, &bondedTokens, data.Exported)
+		initValidator(ctx, validator, keeper, data.Params.BondDenom, &bondedCoins, data.Exported)
 	}
 
 	for _, delegator := range data.Delegators {
-		initDelegator(ctx, delegator, keeper, &bondedTokens)
+		initDelegator(ctx, delegator, keeper, data.Params.BondDenom, &bondedCoins)
 	}
 
 	for _, ubd := range data.UnbondingDelegations {
-		initUnbondingDelegation(ctx, ubd, keeper, &notBondedTokens)
+		initUnbondingDelegation(ctx, ubd, keeper, data.Params.BondDenom, &notBondedCoins)
 	}
+	initPendingDelegations(ctx, data.PendingDelegations, keeper, &notBondedCoins)
 	for _, voteExported := range data.Votes {
 		keeper.SetVote(ctx, voteExported.VoterAddress, voteExported.ValidatorAddress, voteExported.Votes)
 	}
@@ -46,8 +60,7 @@ func InitGenesis(ctx sdk.Context, keeper Keeper, accountKeeper types.AccountKeep
 		keeper.SetProxyBinding(ctx, proxyDelegatorKeyExported.ProxyAddr, proxyDelegatorKeyExported.DelAddr, false)
 	}
 
-	checkPools(ctx, keeper, sdk.NewDecCoinFromDec(data.Params.BondDenom, bondedTokens),
-		sdk.NewDecCoinFromDec(data.Params.BondDenom, notBondedTokens), data.Exported)
+	checkPools(ctx, keeper, bondedCoins, notBondedCoins, data.Exported)
 
 	// don't need to run Tendermint updates if we exported
 	if data.Exported {
@@ -57,7 +70,7 @@ func InitGenesis(ctx sdk.Context, keeper Keeper, accountKeeper types.AccountKeep
 			if !found {
 				panic(fmt.Sprintf("validator %s not found", lv.Address))
 			}
-			update := validator.ABCIValidatorUpdate()
+			update := validator.ABCIValidatorUpdateByVotes(keeper.GetPowerReduction(ctx))
 			update.Power = lv.Power // keep the next-val-set offset, use the last power for the first block
 			res = append(res, update)
 		}
@@ -68,22 +81,18 @@ func InitGenesis(ctx sdk.Context, keeper Keeper, accountKeeper types.AccountKeep
 	return res
 }
 
-// assume that there is only okt in pool, if not panics
-func checkTokenSum(tokenSum sdk.DecCoin, pool supplyexported.ModuleAccountI) {
+// assume that there are only bondable coins in pool, if not panics
+func checkTokenSum(tokenSum sdk.DecCoins, pool supplyexported.ModuleAccountI) {
 	poolCoins := pool.GetCoins()
 	if !poolCoins.IsZero() {
-		if len(poolCoins) != 1 {
-			panic(fmt.Sprintf("only okt in %s, but there are %d kinds of coins", pool.GetName(), len(poolCoins)))
-		}
-
-		if !tokenSum.ToCoins().IsEqual(poolCoins) {
+		if diff, _ := poolCoins.SafeSub(tokenSum); !diff.IsZero() {
 			panic(fmt.Sprintf("coins in %s don't match the token sum, tokenSum: %s, poolCoins: %s",
 				pool.GetName(), tokenSum.String(), poolCoins.String()))
 		}
 	}
 }
 
-func checkPools(ctx sdk.Context, keeper Keeper, bondedDecCoin, notBondedDecCoin sdk.DecCoin, isExported bool) {
+func checkPools(ctx sdk.Context, keeper Keeper, bondedDecCoins, notBondedDecCoins sdk.DecCoins, isExported bool) {
 	bondedPool := keeper.GetBondedPool(ctx)
 	if bondedPool == nil {
 		panic(fmt.Sprintf("%s module account has not been set", types.BondedPoolName))
@@ -93,28 +102,48 @@ func checkPools(ctx sdk.Context, keeper Keeper, bondedDecCoin, notBondedDecCoin
 		panic(fmt.Sprintf("%s module account has not been set", types.NotBondedPoolName))
 	}
 	if isExported {
-		checkTokenSum(bondedDecCoin, bondedPool)
-		checkTokenSum(notBondedDecCoin, notBondedPool)
+		checkTokenSum(bondedDecCoins, bondedPool)
+		checkTokenSum(notBondedDecCoins, notBondedPool)
 	}
 }
 
-func initUnbondingDelegation(ctx sdk.Context, ubd UndelegationInfo, keeper Keeper, notBondedTokens *sdk.Dec) {
+func initUnbondingDelegation(ctx sdk.Context, ubd UndelegationInfo, keeper Keeper, bondDenom string,
+	pNotBondedCoins *sdk.DecCoins) {
 	keeper.SetUndelegating(ctx, ubd)
 	keeper.SetAddrByTimeKeyWithNilValue(ctx, ubd.CompletionTime, ubd.DelegatorAddress)
-	*notBondedTokens = notBondedTokens.Add(ubd.Quantity)
+	for _, entry := range ubd.Entries {
+		keeper.SetAddrByTimeKeyWithNilValue(ctx, entry.CompletionTime, ubd.DelegatorAddress)
+	}
+	*pNotBondedCoins = pNotBondedCoins.Add(ubd.GetCoins(bondDenom))
 }
 
-func initDelegator(ctx sdk.Context, delegator Delegator, keeper Keeper, pBondedTokens *sdk.Dec) {
+// initPendingDelegations sets the delegations deferred to the end of the epoch, whose coins are escrowed in the not
+// bonded pool
+func initPendingDelegations(ctx sdk.Context, pending []types.PendingDelegation, keeper Keeper,
+	pNotBondedCoins *sdk.DecCoins) {
+	if len(pending) == 0 {
+		return
+	}
+	keeper.SetPendingDelegations(ctx, pending)
+	for _, pendingDelegation := range pending {
+		*pNotBondedCoins = pNotBondedCoins.Add(pendingDelegation.Amount.ToCoins())
+	}
+}
+
+func initDelegator(ctx sdk.Context, delegator Delegator, keeper Keeper, bondDenom string, pBondedCoins *sdk.DecCoins) {
 	keeper.SetDelegator(ctx, delegator)
-	*pBondedTokens = pBondedTokens.Add(delegator.Tokens)
+	*pBondedCoins = pBondedCoins.Add(delegator.GetDelegatedCoins(bondDenom))
 }
 
-func initValidator(ctx sdk.Context, valExported ValidatorExport, keeper Keeper, pBondedTokens *sdk.Dec, exported bool) {
+func initValidator(ctx sdk.Context, valExported ValidatorExport, keeper Keeper, bondDenom string,
+	pBondedCoins *sdk.DecCoins, exported bool) {
 	validator := valExported.Import()
 	keeper.SetValidator(ctx, validator)
 
 	// manually set indices for the first time
 	keeper.SetValidatorByConsAddr(ctx, validator)
+	keeper.SetValidatorByMoniker(ctx, validator)
+	keeper.SetValidatorByIdentity(ctx, validator)
 	keeper.SetValidatorByPowerIndex(ctx, validator)
 
 	// call the creation hook if not exported
@@ -127,7 +156,7 @@ func initValidator(ctx sdk.Context, valExported ValidatorExport, keeper Keeper,
 		keeper.InsertValidatorQueue(ctx, validator)
 	}
 	// all the msd on validator should be added into bonded pool
-	*pBondedTokens = pBondedTokens.Add(validator.MinSelfDelegation)
+	*pBondedCoins = pBondedCoins.Add(sdk.NewDecCoinsFromDec(bondDenom, validator.MinSelfDelegation))
 }
 
 // ExportGenesis returns a GenesisState for a given context and keeper
@@ -173,6 +202,8 @@ func ExportGenesis(ctx sdk.Context, keeper Keeper) types.GenesisState {
 		UnbondingDelegations: undelegationInfos,
 		Votes:                votesExportedSlice,
 		ProxyDelegatorKeys:   proxyDelegatorKeys,
+		PendingDelegations:   keeper.GetPendingDelegations(ctx),
+		EpochNumber:          keeper.CurrentEpochNumber(ctx),
 		Exported:             true,
 	}
 }
@@ -182,7 +213,7 @@ func GetLatestGenesisValidator(ctx sdk.Context, keeper Keeper) (vals []tmtypes.G
 	keeper.IterateLastValidators(ctx, func(_ int64, validator exported.ValidatorI) (stop bool) {
 		vals = append(vals, tmtypes.GenesisValidator{
 			PubKey: validator.GetConsPubKey(),
-			Power:  validator.GetConsensusPower(),
+			Power:  keeper.GetLastValidatorPower(ctx, validator.GetOperator()),
 			Name:   validator.GetMoniker(),
 		})
 
@@ -199,6 +230,11 @@ func ValidateGenesis(data types.GenesisState) error {
 	if err != nil {
 		return err
 	}
+	if data.EpochDuration != nil {
+		if _, err := data.EpochDuration.Blocks(); err != nil {
+			return err
+		}
+	}
 	return data.Params.Validate()
 }
 
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/cosmos/cosmos-sdk/x/supply"
+	supplyexported "github.com/cosmos/cosmos-sdk/x/supply/exported"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/okex/okchain/x/staking/exported"
@@ -47,7 +48,7 @@ func TestInitGenesis(t *testing.T) {
 	delegators[0] = types.NewDelegator(Addrs[10])
 
 	unbondingDelegations := make([]types.UndelegationInfo, 1)
-	unbondingDelegations[0] = types.NewUndelegationInfo(Addrs[11], sdk.NewDec(100), time.Now().Add(time.Minute*10))
+	unbondingDelegations[0] = types.NewUndelegationInfo(Addrs[11], sdk.NewDec(100), nil, time.Now().Add(time.Minute*10))
 
 	proxysDelegator := make([]ProxyDelegatorKeyExported, 2)
 	proxysDelegator[0].DelAddr = Addrs[2]
@@ -87,7 +88,7 @@ func TestInitGenesis(t *testing.T) {
 
 	abcivals := make([]abci.ValidatorUpdate, len(vals))
 	for i, val := range validators {
-		abcivals[i] = val.ABCIValidatorUpdateByVotes()
+		abcivals[i] = val.ABCIValidatorUpdateByVotes(keeper.GetPowerReduction(ctx))
 	}
 	require.EqualValues(t, abcivals, vals)
 
@@ -124,7 +125,7 @@ func TestInitGenesis(t *testing.T) {
 	require.Equal(t, actualGenesis.Validators[1].Import(), resVal)
 	// 0x23
 	newKeeper.IterateBondedValidatorsByPower(newCtx, func(index int64, validator exported.ValidatorI) (stop bool) {
-		require.Equal(t, actualGenesis.Validators[index].Import(), validator)
+		require.Equal(t, actualGenesis.Validators[index].Import(), validator.(types.ValidatorWithPowerReduction).Validator)
 		return false
 	})
 	// 0x51
@@ -214,6 +215,50 @@ func TestInitGenesisLargeValidatorSet(t *testing.T) {
 	require.EqualValues(t, len(vals), keeper.GetParams(ctx).MaxValidators)
 }
 
+func setPoolCoins(t *testing.T, ctx sdk.Context, supplyKeeper supply.Keeper, poolName string, coins sdk.DecCoins) {
+	pool := supplyKeeper.GetModuleAccount(ctx, poolName)
+	require.NoError(t, pool.SetCoins(coins))
+	supplyKeeper.SetModuleAccount(ctx, pool)
+}
+
+func TestInitGenesisMultiDenom(t *testing.T) {
+	ctx, _, mKeeper := CreateTestInput(t, false, 1000)
+	keeper := mKeeper.Keeper
+	params := keeper.GetParams(ctx)
+	params.BondDenoms = append(params.BondDenoms, types.NewWeightedDenom("xxb", sdk.NewDecWithPrec(5, 1)))
+
+	delegator := types.NewDelegator(Addrs[10])
+	delegator.DelegatedCoins = sdk.NewDecCoinsFromDec("okt", sdk.NewDec(100)).
+		Add(sdk.NewDecCoinsFromDec("xxb", sdk.NewDec(100)))
+	delegator.Tokens = params.BondDenoms.WeightedAmount(delegator.DelegatedCoins)
+	require.True(t, delegator.Tokens.Equal(sdk.NewDec(150)))
+	undelegation := types.NewUndelegationInfo(Addrs[11], sdk.NewDec(5), sdk.NewDecCoinsFromDec("xxb", sdk.NewDec(10)),
+		time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	genesisState := NewGenesisState(params, nil, []Delegator{delegator})
+	genesisState.UnbondingDelegations = []types.UndelegationInfo{undelegation}
+	require.NoError(t, ValidateGenesis(genesisState))
+	InitGenesis(ctx, keeper, nil, mKeeper.SupplyKeeper, genesisState)
+
+	actualGenesis := ExportGenesis(ctx, keeper)
+	require.Equal(t, params, actualGenesis.Params)
+	require.Equal(t, genesisState.Delegators, actualGenesis.Delegators)
+	require.Equal(t, genesisState.UnbondingDelegations, actualGenesis.UnbondingDelegations)
+
+	// the exported genesis is checked with the pools in each denom
+	newCtx, _, newMKeeper := CreateTestInput(t, false, 1000)
+	newSupplyKeeper := newMKeeper.SupplyKeeper
+	setPoolCoins(t, newCtx, newSupplyKeeper, types.BondedPoolName, delegator.DelegatedCoins)
+	setPoolCoins(t, newCtx, newSupplyKeeper, types.NotBondedPoolName, undelegation.Coins)
+	InitGenesis(newCtx, newMKeeper.Keeper, nil, newSupplyKeeper, actualGenesis)
+	require.Equal(t, actualGenesis, ExportGenesis(newCtx, newMKeeper.Keeper))
+
+	setPoolCoins(t, newCtx, newSupplyKeeper, types.NotBondedPoolName, sdk.NewDecCoinsFromDec("okt", sdk.NewDec(10)))
+	require.Panics(t, func() {
+		InitGenesis(newCtx, newMKeeper.Keeper, nil, newSupplyKeeper, actualGenesis)
+	})
+}
+
 func TestValidateGenesis(t *testing.T) {
 	genValidators := make([]Validator, 1, 5)
 	pk := ed25519.GenPrivKey().PubKey()
@@ -241,6 +286,15 @@ func TestValidateGenesis(t *testing.T) {
 			(*data).Validators[0].Jailed = true
 			(*data).Validators[0].Status = sdk.Bonded
 		}, true},
+		// validate the epoch duration
+		{"epoch duration", func(data *types.GenesisState) {
+			epochDuration := types.NewEpochDuration(time.Hour, 3*time.Second)
+			(*data).EpochDuration = &epochDuration
+		}, false},
+		{"epoch duration not a whole number of blocks", func(data *types.GenesisState) {
+			epochDuration := types.NewEpochDuration(time.Hour, 7*time.Second)
+			(*data).EpochDuration = &epochDuration
+		}, true},
 	}
 
 	for _, tt := range tests {
@@ -255,3 +309,149 @@ func TestValidateGenesis(t *testing.T) {
 		})
 	}
 }
+
+func TestInitGenesisPoolAccounts(t *testing.T) {
+	ctx, _, mKeeper := CreateTestInput(t, false, 1000)
+	keeper := mKeeper.Keeper
+	InitGenesis(ctx, keeper, nil, mKeeper.SupplyKeeper, DefaultGenesisState())
+
+	pools := map[string]supplyexported.ModuleAccountI{
+		BondedPoolName:    keeper.GetBondedPool(ctx),
+		NotBondedPoolName: keeper.GetNotBondedPool(ctx),
+	}
+	for name, pool := range pools {
+		require.NotNil(t, pool, name)
+		require.Equal(t, name, pool.GetName())
+		require.Equal(t, supply.NewModuleAddress(name), pool.GetAddress())
+		require.True(t, pool.HasPermission(supply.Burner), name)
+		require.True(t, pool.HasPermission(supply.Staking), name)
+		require.False(t, pool.HasPermission(supply.Minter), name)
+	}
+}
+
+func TestEpochNumberGenesis(t *testing.T) {
+	ctx, _, mKeeper := CreateTestInput(t, false, 1000)
+	keeper := mKeeper.Keeper
+	params := keeper.GetParams(ctx)
+	params.Epoch = 2
+	keeper.SetParams(ctx, params)
+	keeper.SetEpoch(ctx, params.Epoch)
+	require.Equal(t, uint64(0), keeper.CurrentEpochNumber(ctx))
+
+	// the epoch number increases only at each boundary
+	for height := int64(1); height <= 6; height++ {
+		EndBlocker(ctx.WithBlockHeight(height), keeper)
+		require.Equal(t, uint64(height/2), keeper.CurrentEpochNumber(ctx))
+	}
+	epochInfo := keeper.GetEpochInfo(ctx)
+	require.Equal(t, uint64(3), epochInfo.EpochNumber)
+	require.Equal(t, int64(6), epochInfo.LastEpochEndHeight)
+	require.Equal(t, int64(8), epochInfo.EpochEndHeight)
+
+	// the epoch number survives the genesis export and import
+	exported := ExportGenesis(ctx, keeper)
+	require.Equal(t, uint64(3), exported.EpochNumber)
+	newCtx, _, newMKeeper := CreateTestInput(t, false, 1000)
+	InitGenesis(newCtx, newMKeeper.Keeper, nil, newMKeeper.SupplyKeeper, exported)
+	require.Equal(t, uint64(3), newMKeeper.Keeper.CurrentEpochNumber(newCtx))
+	EndBlocker(newCtx.WithBlockHeight(int64(newMKeeper.Keeper.GetEpoch(newCtx))), newMKeeper.Keeper)
+	require.Equal(t, uint64(4), newMKeeper.Keeper.CurrentEpochNumber(newCtx))
+}
+
+func TestBondHeightGenesis(t *testing.T) {
+	ctx, _, mKeeper := CreateTestInput(t, false, 1000000)
+	keeper := mKeeper.Keeper
+	params := keeper.GetParams(ctx)
+	params.PowerTieBreak = types.TieBreakOldestFirst
+	keeper.SetParams(ctx, params)
+	handler := NewHandler(keeper)
+
+	// the validators with equal power are created at different heights
+	valAddrs := []sdk.ValAddress{sdk.ValAddress(Addrs[0]), sdk.ValAddress(Addrs[1])}
+	for i, height := range []int64{7, 5} {
+		msg := NewTestMsgCreateValidator(valAddrs[i], PKs[i], DefaultValidInitMsd)
+		got := handler(ctx.WithBlockHeight(height), msg)
+		require.True(t, got.IsOK(), got.Log)
+		validator, found := keeper.GetValidator(ctx, valAddrs[i])
+		require.True(t, found)
+		require.Equal(t, height, validator.BondHeight)
+	}
+
+	// the tie-break rule takes effect after the epoch ends
+	require.Equal(t, types.TieBreakByAddress, keeper.GetPowerTieBreak(ctx))
+	EndBlocker(ctx.WithBlockHeight(int64(keeper.GetEpoch(ctx))), keeper)
+	require.Equal(t, types.TieBreakOldestFirst, keeper.GetPowerTieBreak(ctx))
+	requireOldestFirst := func(ctx sdk.Context, keeper Keeper) {
+		iterator := keeper.ValidatorsPowerStoreIterator(ctx)
+		defer iterator.Close()
+		require.True(t, iterator.Valid())
+		require.Equal(t, valAddrs[1], sdk.ValAddress(iterator.Value()))
+	}
+	requireOldestFirst(ctx, keeper)
+
+	// the bond heights and the tie-break rule survive the genesis export and import
+	exported := ExportGenesis(ctx, keeper)
+	newCtx, _, newMKeeper := CreateTestInput(t, false, 1000000)
+	InitGenesis(newCtx, newMKeeper.Keeper, nil, newMKeeper.SupplyKeeper, exported)
+	for i, height := range []int64{7, 5} {
+		validator, found := newMKeeper.Keeper.GetValidator(newCtx, valAddrs[i])
+		require.True(t, found)
+		require.Equal(t, height, validator.BondHeight)
+	}
+	require.Equal(t, types.TieBreakOldestFirst, newMKeeper.Keeper.GetPowerTieBreak(newCtx))
+	requireOldestFirst(newCtx, newMKeeper.Keeper)
+}
+
+func TestPendingDelegationsGenesis(t *testing.T) {
+	ctx, _, mKeeper := CreateTestInput(t, false, SufficientInitPower)
+	keeper := mKeeper.Keeper
+	params := keeper.GetParams(ctx)
+	params.Epoch = 4
+	params.EpochBoundaryGrace = 2
+	keeper.SetParams(ctx, params)
+	keeper.SetEpoch(ctx, params.Epoch)
+	amount := sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(100))
+	got := NewHandler(keeper)(ctx.WithBlockHeight(3), NewMsgDelegate(Addrs[0], amount))
+	require.True(t, got.IsOK(), got.Log)
+	pending := []types.PendingDelegation{types.NewPendingDelegation(Addrs[0], amount)}
+	require.Equal(t, pending, keeper.GetPendingDelegations(ctx))
+
+	// the pending delegations survive the genesis export and import, and are applied at the end of the epoch
+	exported := ExportGenesis(ctx, keeper)
+	require.Equal(t, pending, exported.PendingDelegations)
+	newCtx, _, newMKeeper := CreateTestInput(t, false, SufficientInitPower)
+	newKeeper := newMKeeper.Keeper
+	clearNotBondedPool(t, newCtx, newMKeeper.SupplyKeeper)
+	require.NoError(t, newMKeeper.SupplyKeeper.SendCoinsFromAccountToModule(newCtx, Addrs[0], NotBondedPoolName,
+		amount.ToCoins()))
+	InitGenesis(newCtx, newKeeper, nil, newMKeeper.SupplyKeeper, exported)
+	require.Equal(t, pending, newKeeper.GetPendingDelegations(newCtx))
+	newKeeper.SetEpoch(newCtx, params.Epoch)
+	EndBlocker(newCtx.WithBlockHeight(4), newKeeper)
+	require.Empty(t, newKeeper.GetPendingDelegations(newCtx))
+	delegator, found := newKeeper.GetDelegator(newCtx, Addrs[0])
+	require.True(t, found)
+	require.True(t, amount.Amount.Equal(delegator.Tokens))
+}
+
+func TestEpochDurationGenesis(t *testing.T) {
+	ctx, _, mKeeper := CreateTestInput(t, false, 1000)
+	genesisState := types.DefaultGenesisState()
+	epochDuration := types.NewEpochDuration(10*time.Minute, 3*time.Second)
+	genesisState.EpochDuration = &epochDuration
+	InitGenesis(ctx, mKeeper.Keeper, nil, mKeeper.SupplyKeeper, genesisState)
+	require.Equal(t, uint16(200), mKeeper.Keeper.ParamsEpoch(ctx))
+
+	// the block count is exported in the Epoch param only
+	exported := ExportGenesis(ctx, mKeeper.Keeper)
+	require.Nil(t, exported.EpochDuration)
+	require.Equal(t, uint16(200), exported.Params.Epoch)
+
+	// the invalid conversion panics
+	ctx, _, mKeeper = CreateTestInput(t, false, 1000)
+	epochDuration = types.NewEpochDuration(10*time.Minute, 7*time.Second)
+	genesisState.EpochDuration = &epochDuration
+	require.Panics(t, func() {
+		InitGenesis(ctx, mKeeper.Keeper, nil, mKeeper.SupplyKeeper, genesisState)
+	})
+}
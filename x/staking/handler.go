@@ -2,14 +2,12 @@ package staking
 
 import (
 	"fmt"
-
-	"github.com/tendermint/tendermint/libs/common"
+	"strconv"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/okex/okchain/x/staking/keeper"
 	"github.com/okex/okchain/x/staking/types"
 	abci "github.com/tendermint/tendermint/abci/types"
-	tmtypes "github.com/tendermint/tendermint/types"
 )
 
 // NewHandler manages all tx treatment
@@ -47,17 +45,13 @@ func NewHandler(k keeper.Keeper) sdk.Handler {
 func EndBlocker(ctx sdk.Context, k keeper.Keeper) []abci.ValidatorUpdate {
 	// calculate validator set changes
 	validatorUpdates := make([]abci.ValidatorUpdate, 0)
+	// alert the large power changes before the validator set is updated
+	emitLargePowerChangeEvents(ctx, k)
 	if k.IsEndOfEpoch(ctx) {
-		oldEpoch, newEpoch := k.GetEpoch(ctx), k.ParamsEpoch(ctx)
-		if oldEpoch != newEpoch {
-			k.SetEpoch(ctx, newEpoch)
-		}
-		k.SetTheEndOfLastEpoch(ctx)
-		//ctx.Logger().Debug("validatorUpdates epoch", "old", oldEpoch, "new", newEpoch)
-		//ctx.Logger().Debug(fmt.Sprintf("old epoch end blockHeight: %d", lastEpochEndHeight))
-
+		validatorUpdates = k.ProcessEpochEnd(ctx)
+	} else if k.IsValidatorSetRecomputed(ctx) {
+		// the max validators has been changed immediately within the epoch
 		validatorUpdates = k.ApplyAndReturnValidatorSetUpdates(ctx)
-		// dont forget to delete in case that some validator need to kick out when an epoch ends
 		k.DeleteAbandonedValidatorAddrs(ctx)
 	} else if k.IsKickedOut(ctx) {
 		// if there are some validators to kick out in an epoch
@@ -73,7 +67,7 @@ func EndBlocker(ctx sdk.Context, k keeper.Keeper) []abci.ValidatorUpdate {
 			oldTime, delAddr := types.SplitCompleteTimeWithAddrKey(key)
 			k.DeleteAddrByTimeKey(ctx, oldTime, delAddr)
 
-			quantity, err := k.CompleteUndelegation(ctx, delAddr)
+			quantity, err := k.CompleteMatureUndelegation(ctx, delAddr, oldTime)
 			if err != nil {
 				ctx.Logger().Error(fmt.Sprintf("complete undelegate failed: %s", err.Result().Data))
 			} else {
@@ -93,29 +87,9 @@ func EndBlocker(ctx sdk.Context, k keeper.Keeper) []abci.ValidatorUpdate {
 
 // These functions assumes everything has been authenticated, now we just perform action and save
 func handleMsgCreateValidator(ctx sdk.Context, msg types.MsgCreateValidator, k keeper.Keeper) sdk.Result {
-	if _, found := k.GetValidator(ctx, msg.ValidatorAddress); found {
-		return ErrValidatorOwnerExists(k.Codespace()).Result()
-	}
-	if _, found := k.GetValidatorByConsAddr(ctx, sdk.GetConsAddress(msg.PubKey)); found {
-		return ErrValidatorPubKeyExists(k.Codespace()).Result()
-	}
-	if msg.MinSelfDelegation.Denom != k.BondDenom(ctx) {
-		return ErrBadDenom(k.Codespace()).Result()
-	}
-
-	if msdLimit := k.ParamsMinSelfDelegationLimited(ctx); msg.MinSelfDelegation.Amount.LT(msdLimit) {
-		return types.ErrInsufficientMinSelfDelegation(k.Codespace(), msdLimit).Result()
-	}
-	if _, err := msg.Description.EnsureLength(); err != nil {
+	if err := k.ValidateMsgCreateValidator(ctx, msg); err != nil {
 		return err.Result()
 	}
-	if ctx.ConsensusParams() != nil {
-		tmPubKey := tmtypes.TM2PB.PubKey(msg.PubKey)
-		if !common.StringInSlice(tmPubKey.Type, ctx.ConsensusParams().Validator.PubKeyTypes) {
-			return ErrValidatorPubKeyTypeNotSupported(k.Codespace(), tmPubKey.Type,
-				ctx.ConsensusParams().Validator.PubKeyTypes).Result()
-		}
-	}
 
 	validator := NewValidator(msg.ValidatorAddress, msg.PubKey, msg.Description)
 	commission := NewCommission(sdk.NewDec(1), sdk.NewDec(1), sdk.NewDec(0))
@@ -124,8 +98,11 @@ func handleMsgCreateValidator(ctx sdk.Context, msg types.MsgCreateValidator, k k
 		return err.Result()
 	}
 	validator.MinSelfDelegation = msg.MinSelfDelegation.Amount
+	validator.BondHeight = ctx.BlockHeight()
 	k.SetValidator(ctx, validator)
 	k.SetValidatorByConsAddr(ctx, validator)
+	k.SetValidatorByMoniker(ctx, validator)
+	k.SetValidatorByIdentity(ctx, validator)
 	k.SetNewValidatorByPowerIndex(ctx, validator)
 	// vote msd for validator itself
 	if err = k.VoteMinSelfDelegation(ctx, msg.DelegatorAddress, &validator, msg.MinSelfDelegation); err != nil {
@@ -151,12 +128,34 @@ func handleMsgEditValidator(ctx sdk.Context, msg types.MsgEditValidator, k keepe
 	}
 
 	// replace all editable fields (clients should autofill existing values)
-	description, err := validator.Description.UpdateDescription(msg.Description)
-	if err != nil {
-		return err.Result()
+	if msg.Description != (Description{}) {
+		description, err := validator.Description.UpdateDescription(msg.Description)
+		if err != nil {
+			return err.Result()
+		}
+
+		if k.ParamsEnforceUniqueMoniker(ctx) && k.IsMonikerTaken(ctx, description.Moniker, validator.OperatorAddress) {
+			return types.ErrValidatorMonikerExists(k.Codespace(), description.Moniker).Result()
+		}
+
+		// refresh the moniker and identity indexes
+		k.DeleteValidatorByMoniker(ctx, validator)
+		k.DeleteValidatorByIdentity(ctx, validator)
+		validator.Description = description
+		k.SetValidatorByMoniker(ctx, validator)
+		k.SetValidatorByIdentity(ctx, validator)
 	}
 
-	validator.Description = description
+	// update the delegation acceptance policy
+	if msg.AcceptingDelegations != nil {
+		validator.ClosedToDelegations = !*msg.AcceptingDelegations
+	}
+	if msg.MaxDelegatorCount != nil {
+		validator.MaxDelegatorCount = *msg.MaxDelegatorCount
+	}
+	if msg.MinDelegation != nil {
+		validator.MinDelegation = *msg.MinDelegation
+	}
 
 	k.SetValidator(ctx, validator)
 
@@ -172,3 +171,22 @@ func handleMsgEditValidator(ctx sdk.Context, msg types.MsgEditValidator, k keepe
 
 	return sdk.Result{Events: ctx.EventManager().Events()}
 }
+
+// emitLargePowerChangeEvents emits an event for each validator whose power changed in the block and whose projected
+// power is far from the one in the validator set of last epoch
+func emitLargePowerChangeEvents(ctx sdk.Context, k keeper.Keeper) {
+	k.IteratePowerChangedValidators(ctx, func(_ int64, valAddr sdk.ValAddress) (stop bool) {
+		lastPower, projectedPower := k.ValidatorPowerDelta(ctx, valAddr)
+		if k.IsLargePowerChange(ctx, lastPower, projectedPower) {
+			ctx.EventManager().EmitEvent(
+				sdk.NewEvent(
+					types.EventTypeLargePowerChange,
+					sdk.NewAttribute(types.AttributeKeyValidator, valAddr.String()),
+					sdk.NewAttribute(types.AttributeKeyLastPower, strconv.FormatInt(lastPower, 10)),
+					sdk.NewAttribute(types.AttributeKeyProjectedPower, strconv.FormatInt(projectedPower, 10)),
+				),
+			)
+		}
+		return false
+	})
+}
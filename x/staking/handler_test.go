@@ -1,7 +1,10 @@
 package staking
 
 import (
+	"bytes"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -24,6 +27,17 @@ func setInstantUnbondPeriod(keeper keep.Keeper, ctx sdk.Context) types.Params {
 	return params
 }
 
+// deliverMsg runs the handler on the msg in a cached context, which is written only if the msg succeeds, so that a
+// failed msg doesn't persist any state change as in the baseapp
+func deliverMsg(ctx sdk.Context, handler sdk.Handler, msg sdk.Msg) sdk.Result {
+	cacheCtx, write := ctx.CacheContext()
+	got := handler(cacheCtx, msg)
+	if got.IsOK() {
+		write()
+	}
+	return got
+}
+
 //______________________________________________________________________
 
 func TestValidatorByPowerIndex(t *testing.T) {
@@ -47,7 +61,7 @@ func TestValidatorByPowerIndex(t *testing.T) {
 	// verify that the by power index exists
 	validator, found := keeper.GetValidator(ctx, validatorAddr)
 	require.True(t, found)
-	power := GetValidatorsByPowerIndexKey(validator)
+	power := GetValidatorsByPowerIndexKey(validator, keeper.GetPowerReduction(ctx))
 	require.True(t, ValidatorByPowerIndexExists(ctx, mKeeper, power))
 
 	// create a second validator keep it bonded
@@ -63,7 +77,7 @@ func TestValidatorByPowerIndex(t *testing.T) {
 	_, found = keeper.GetValidator(ctx, validatorAddr)
 	require.True(t, found)
 
-	powerIndex := GetValidatorsByPowerIndexKey(validator)
+	powerIndex := GetValidatorsByPowerIndexKey(validator, keeper.GetPowerReduction(ctx))
 	require.True(t, ValidatorByPowerIndexExists(ctx, mKeeper, powerIndex))
 
 }
@@ -177,7 +191,7 @@ func TestEditValidatorDecreaseMinSelfDelegation(t *testing.T) {
 		InitMsd2000, false)
 
 	// edit validator
-	msgEditValidator := NewMsgEditValidator(validatorAddr, Description{Moniker: "moniker"})
+	msgEditValidator := NewMsgEditValidator(validatorAddr, Description{Moniker: "moniker"}, nil, nil, nil)
 	require.Nil(t, msgEditValidator.ValidateBasic())
 
 	// TODO: EditValidator not fully implemented yet.
@@ -186,3 +200,1055 @@ func TestEditValidatorDecreaseMinSelfDelegation(t *testing.T) {
 	//SimpleCheckValidator(t, ctx, keeper, validatorAddr, newMinSelfDelegation, sdk.Bonded,
 	//	sdk.NewDecFromIntWithPrec(newMinSelfDelegation, 8), false)
 }
+
+func TestEnforceUniqueMoniker(t *testing.T) {
+	addr1, addr2, addr3 := sdk.ValAddress(keep.Addrs[0]), sdk.ValAddress(keep.Addrs[1]), sdk.ValAddress(keep.Addrs[2])
+	ctx, _, mKeeper := CreateTestInput(t, false, SufficientInitPower)
+	keeper := mKeeper.Keeper
+	params := keeper.GetParams(ctx)
+	params.EnforceUniqueMoniker = true
+	keeper.SetParams(ctx, params)
+	handler := NewHandler(keeper)
+	msd := sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, DefaultValidInitMsd)
+
+	msgCreateValidator := NewMsgCreateValidator(addr1, keep.PKs[0], Description{Moniker: "OKChain"}, msd)
+	got := handler(ctx, msgCreateValidator)
+	require.True(t, got.IsOK(), "%v", got)
+
+	// lookup is case-insensitive
+	validator, found := keeper.GetValidatorByMoniker(ctx, "okchain")
+	require.True(t, found)
+	require.Equal(t, addr1, validator.OperatorAddress)
+
+	// duplicate moniker in a different case is rejected
+	msgCreateValidator = NewMsgCreateValidator(addr2, keep.PKs[1], Description{Moniker: "okCHAIN"}, msd)
+	got = handler(ctx, msgCreateValidator)
+	require.False(t, got.IsOK(), "%v", got)
+
+	msgCreateValidator = NewMsgCreateValidator(addr2, keep.PKs[1], Description{Moniker: "another"}, msd)
+	got = handler(ctx, msgCreateValidator)
+	require.True(t, got.IsOK(), "%v", got)
+
+	// editing into a taken moniker is rejected
+	got = handler(ctx, NewMsgEditValidator(addr2, Description{Moniker: "OKCHAIN"}, nil, nil, nil))
+	require.False(t, got.IsOK(), "%v", got)
+
+	// re-casing its own moniker is allowed
+	got = handler(ctx, NewMsgEditValidator(addr1, Description{Moniker: "okchain"}, nil, nil, nil))
+	require.True(t, got.IsOK(), "%v", got)
+	validator, found = keeper.GetValidatorByMoniker(ctx, "OKCHAIN")
+	require.True(t, found)
+	require.Equal(t, addr1, validator.OperatorAddress)
+
+	// renaming releases the old moniker
+	got = handler(ctx, NewMsgEditValidator(addr1, Description{Moniker: "renamed"}, nil, nil, nil))
+	require.True(t, got.IsOK(), "%v", got)
+	_, found = keeper.GetValidatorByMoniker(ctx, "okchain")
+	require.False(t, found)
+	msgCreateValidator = NewMsgCreateValidator(addr3, keep.PKs[2], Description{Moniker: "OkChain"}, msd)
+	got = handler(ctx, msgCreateValidator)
+	require.True(t, got.IsOK(), "%v", got)
+
+	// duplicates are allowed when the param is disabled
+	params.EnforceUniqueMoniker = false
+	keeper.SetParams(ctx, params)
+	got = handler(ctx, NewMsgEditValidator(addr2, Description{Moniker: "RENAMED"}, nil, nil, nil))
+	require.True(t, got.IsOK(), "%v", got)
+}
+
+func TestQueryValidatorsByIdentity(t *testing.T) {
+	addrs := []sdk.ValAddress{sdk.ValAddress(keep.Addrs[0]), sdk.ValAddress(keep.Addrs[1]),
+		sdk.ValAddress(keep.Addrs[2]), sdk.ValAddress(keep.Addrs[3])}
+	ctx, _, mKeeper := CreateTestInput(t, false, SufficientInitPower)
+	keeper := mKeeper.Keeper
+	handler := NewHandler(keeper)
+	querier := keep.NewQuerier(keeper)
+	msd := sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, DefaultValidInitMsd)
+	queryByIdentity := func(identity string) (valAddrs []sdk.ValAddress) {
+		bz, err := types.ModuleCdc.MarshalJSON(types.NewQueryValidatorsByIdentityParams(identity))
+		require.NoError(t, err)
+		data, sdkErr := querier(ctx, []string{types.QueryValidatorsByIdentity}, abci.RequestQuery{Data: bz})
+		require.Nil(t, sdkErr)
+		var validators types.Validators
+		require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &validators))
+		for _, validator := range validators {
+			valAddrs = append(valAddrs, validator.OperatorAddress)
+		}
+		return
+	}
+
+	// addrs[1] and addrs[2] share an identity while addrs[3] has none
+	for i, identity := range []string{"5A5D6C9E9E3C1D31", "7E8F5A6B1C2D3E4F", "7E8F5A6B1C2D3E4F", ""} {
+		description := Description{Moniker: strconv.Itoa(i), Identity: identity}
+		got := handler(ctx, NewMsgCreateValidator(addrs[i], keep.PKs[i], description, msd))
+		require.True(t, got.IsOK(), "%v", got)
+	}
+	require.Equal(t, []sdk.ValAddress{addrs[0]}, queryByIdentity("5A5D6C9E9E3C1D31"))
+	shared := queryByIdentity("7E8F5A6B1C2D3E4F")
+	require.Equal(t, 2, len(shared))
+	require.Contains(t, shared, addrs[1])
+	require.Contains(t, shared, addrs[2])
+	require.Empty(t, queryByIdentity(""))
+	require.Empty(t, queryByIdentity("unknown"))
+
+	// editing the identity moves the validator to the new one
+	description := Description{Moniker: "1", Identity: "5A5D6C9E9E3C1D31"}
+	got := handler(ctx, NewMsgEditValidator(addrs[1], description, nil, nil, nil))
+	require.True(t, got.IsOK(), "%v", got)
+	require.Equal(t, []sdk.ValAddress{addrs[2]}, queryByIdentity("7E8F5A6B1C2D3E4F"))
+	require.Equal(t, 2, len(queryByIdentity("5A5D6C9E9E3C1D31")))
+
+	// clearing the identity drops the validator from the index
+	got = handler(ctx, NewMsgEditValidator(addrs[2], Description{Moniker: "2"}, nil, nil, nil))
+	require.True(t, got.IsOK(), "%v", got)
+	require.Empty(t, queryByIdentity("7E8F5A6B1C2D3E4F"))
+	require.Empty(t, queryByIdentity(""))
+}
+
+func TestPowerReductionTakesEffectAtEpochEnd(t *testing.T) {
+	addr1, addr2 := sdk.ValAddress(keep.Addrs[0]), sdk.ValAddress(keep.Addrs[1])
+	ctx, _, mKeeper := CreateTestInput(t, false, SufficientInitPower)
+	keeper := mKeeper.Keeper
+	handler := NewHandler(keeper)
+	defaultPowerReduction := keeper.GetPowerReduction(ctx)
+	require.True(t, defaultPowerReduction.Equal(types.DefaultPowerReduction))
+
+	got := handler(ctx, NewTestMsgCreateValidator(addr1, keep.PKs[0], DefaultValidInitMsd))
+	require.True(t, got.IsOK(), "%v", got)
+	got = handler(ctx, NewTestMsgCreateValidator(addr2, keep.PKs[1], sdk.NewDec(5000)))
+	require.True(t, got.IsOK(), "%v", got)
+
+	// both validators are elected with the default power reduction
+	updates := EndBlocker(ctx, keeper)
+	require.Equal(t, 2, len(updates))
+	val1, _ := keeper.GetValidator(ctx, addr1)
+	val2, _ := keeper.GetValidator(ctx, addr2)
+	require.Equal(t, val1.ConsensusPowerByVotes(defaultPowerReduction), keeper.GetLastValidatorPower(ctx, addr1))
+	require.Equal(t, val2.ConsensusPowerByVotes(defaultPowerReduction), keeper.GetLastValidatorPower(ctx, addr2))
+
+	// raise the power reduction to make val1 powerless
+	newPowerReduction := defaultPowerReduction.MulRaw(3000)
+	params := keeper.GetParams(ctx)
+	params.PowerReduction = newPowerReduction
+	keeper.SetParams(ctx, params)
+	require.Equal(t, int64(0), val1.PotentialConsensusPowerByVotes(newPowerReduction))
+	require.True(t, val2.PotentialConsensusPowerByVotes(newPowerReduction) > 0)
+
+	// nothing changes in the middle of the epoch
+	ctx = ctx.WithBlockHeight(1)
+	updates = EndBlocker(ctx, keeper)
+	require.Equal(t, 0, len(updates))
+	require.True(t, keeper.GetPowerReduction(ctx).Equal(defaultPowerReduction))
+
+	// the validator set is reshaped at the end of the epoch
+	ctx = ctx.WithBlockHeight(int64(keeper.GetEpoch(ctx)))
+	updates = EndBlocker(ctx, keeper)
+	require.True(t, keeper.GetPowerReduction(ctx).Equal(newPowerReduction))
+	require.Equal(t, 2, len(updates))
+	require.Equal(t, val2.ConsensusPowerByVotes(newPowerReduction), keeper.GetLastValidatorPower(ctx, addr2))
+	require.Equal(t, int64(0), keeper.GetLastValidatorPower(ctx, addr1))
+
+	// the power index is rebuilt with the new power reduction
+	require.True(t, ValidatorByPowerIndexExists(ctx, mKeeper, GetValidatorsByPowerIndexKey(val1, newPowerReduction)))
+	require.True(t, ValidatorByPowerIndexExists(ctx, mKeeper, GetValidatorsByPowerIndexKey(val2, newPowerReduction)))
+	_, broken := keep.NonNegativePowerInvariantCustom(keeper)(ctx)
+	require.False(t, broken)
+}
+
+func TestUnbondingTimeTakesEffectAtEpochEnd(t *testing.T) {
+	ctx, _, mKeeper := CreateTestInput(t, false, SufficientInitPower)
+	keeper := mKeeper.Keeper
+	oldUnbondingTime := keeper.UnbondingTime(ctx)
+	newUnbondingTime := oldUnbondingTime * 2
+	require.Nil(t, keeper.SetUnbondingTime(ctx, newUnbondingTime))
+
+	// nothing changes in the middle of the epoch
+	ctx = ctx.WithBlockHeight(1)
+	EndBlocker(ctx, keeper)
+	require.Equal(t, oldUnbondingTime, keeper.UnbondingTime(ctx))
+
+	// applied at the end of the epoch
+	ctx = ctx.WithBlockHeight(int64(keeper.GetEpoch(ctx)))
+	EndBlocker(ctx, keeper)
+	require.Equal(t, newUnbondingTime, keeper.UnbondingTime(ctx))
+	_, found := keeper.GetPendingUnbondingTime(ctx)
+	require.False(t, found)
+}
+
+func TestSetMaxValidators(t *testing.T) {
+	ctx, _, mKeeper := CreateTestInput(t, false, SufficientInitPower)
+	keeper := mKeeper.Keeper
+	handler := NewHandler(keeper)
+	epoch := int64(keeper.GetEpoch(ctx))
+	bondedCount := func() (count int) {
+		keeper.IterateLastValidatorPowers(ctx, func(sdk.ValAddress, int64) bool {
+			count++
+			return false
+		})
+		return
+	}
+
+	for i := 0; i < 3; i++ {
+		valAddr, voterAddr := sdk.ValAddress(keep.Addrs[i]), keep.Addrs[i+3]
+		got := handler(ctx, NewTestMsgCreateValidator(valAddr, keep.PKs[i], DefaultValidInitMsd))
+		require.True(t, got.IsOK(), "%v", got)
+		amount := sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(int64(100*(i+1))))
+		require.True(t, handler(ctx, types.NewMsgDelegate(voterAddr, amount)).IsOK())
+		require.True(t, handler(ctx, types.NewMsgVote(voterAddr, []sdk.ValAddress{valAddr})).IsOK())
+	}
+	ctx = ctx.WithBlockHeight(epoch)
+	EndBlocker(ctx, keeper)
+	require.Equal(t, 3, bondedCount())
+
+	// invalid
+	require.NotNil(t, keeper.SetMaxValidators(ctx, 0, false))
+	require.NotNil(t, keeper.SetMaxValidators(ctx, 0, true))
+	require.NotNil(t, keeper.SetMaxValidators(ctx, types.MaxMaxValidators+1, false))
+
+	// deferred doesn't change the current set until the end of the epoch
+	oldMaxValidators := keeper.MaxValidators(ctx)
+	ctx = ctx.WithBlockHeight(epoch + 1).WithEventManager(sdk.NewEventManager())
+	require.Nil(t, keeper.SetMaxValidators(ctx, 2, false))
+	events := ctx.EventManager().Events()
+	require.Equal(t, 1, len(events))
+	require.Equal(t, types.EventTypeSetMaxValidators, events[0].Type)
+	require.Equal(t, types.AttributeValueDeferred, string(events[0].Attributes[1].Value))
+	require.Empty(t, EndBlocker(ctx, keeper))
+	require.Equal(t, oldMaxValidators, keeper.MaxValidators(ctx))
+	require.Equal(t, 3, bondedCount())
+
+	ctx = ctx.WithBlockHeight(epoch * 2)
+	require.NotEmpty(t, EndBlocker(ctx, keeper))
+	require.Equal(t, uint16(2), keeper.MaxValidators(ctx))
+	require.Equal(t, 2, bondedCount())
+	_, found := keeper.GetPendingMaxValidators(ctx)
+	require.False(t, found)
+
+	// immediate drops the validators within the epoch, which overrides the deferred one
+	require.Nil(t, keeper.SetMaxValidators(ctx.WithBlockHeight(epoch*2+1), 3, false))
+	ctx = ctx.WithBlockHeight(epoch*2 + 2).WithEventManager(sdk.NewEventManager())
+	require.Nil(t, keeper.SetMaxValidators(ctx, 1, true))
+	events = ctx.EventManager().Events()
+	require.Equal(t, 1, len(events))
+	require.Equal(t, types.AttributeValueImmediate, string(events[0].Attributes[1].Value))
+	require.Equal(t, uint16(1), keeper.MaxValidators(ctx))
+	_, found = keeper.GetPendingMaxValidators(ctx)
+	require.False(t, found)
+	require.NotEmpty(t, EndBlocker(ctx, keeper))
+	require.Equal(t, 1, bondedCount())
+}
+
+func TestBondedSetSizeGuard(t *testing.T) {
+	ctx, _, mKeeper := CreateTestInput(t, false, SufficientInitPower)
+	keeper := mKeeper.Keeper
+	handler := NewHandler(keeper)
+	epoch := int64(keeper.GetEpoch(ctx))
+	params := keeper.GetParams(ctx)
+	params.MaxValidators = 3
+	keeper.SetParams(ctx, params)
+
+	for i := 0; i < 4; i++ {
+		valAddr, voterAddr := sdk.ValAddress(keep.Addrs[i]), keep.Addrs[i+4]
+		got := handler(ctx, NewTestMsgCreateValidator(valAddr, keep.PKs[i], DefaultValidInitMsd))
+		require.True(t, got.IsOK(), "%v", got)
+		amount := sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(int64(100*(i+1))))
+		require.True(t, handler(ctx, types.NewMsgDelegate(voterAddr, amount)).IsOK())
+		require.True(t, handler(ctx, types.NewMsgVote(voterAddr, []sdk.ValAddress{valAddr})).IsOK())
+	}
+	ctx = ctx.WithBlockHeight(epoch)
+	require.NotPanics(t, func() { EndBlocker(ctx, keeper) })
+	require.Equal(t, int64(0), keeper.GetLastValidatorPower(ctx, sdk.ValAddress(keep.Addrs[0])))
+
+	// a stale entry of the last validator set and the max validators lowered without a recompute oversize the set,
+	// which the next update of the validator set refuses to carry on with
+	keeper.SetLastValidatorPower(ctx, sdk.ValAddress(keep.Addrs[0]), 1)
+	params.MaxValidators = 2
+	keeper.SetParams(ctx, params)
+	keeper.AppendAbandonedValidatorAddrs(ctx, sdk.ConsAddress(keep.PKs[3].Address()))
+	ctx = ctx.WithBlockHeight(epoch + 1)
+	require.False(t, keeper.IsEndOfEpoch(ctx))
+	require.PanicsWithValue(t, "the bonded validator set has 3 validators, more than the max validators 2",
+		func() { EndBlocker(ctx, keeper) })
+}
+
+func TestQueryUnbondingValidators(t *testing.T) {
+	ctx, _, mKeeper := CreateTestInput(t, false, SufficientInitPower)
+	keeper := mKeeper.Keeper
+	handler := NewHandler(keeper)
+	querier := keep.NewQuerier(keeper)
+	epoch := int64(keeper.GetEpoch(ctx))
+	blockTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx = ctx.WithBlockTime(blockTime)
+	queryUnbondingValidators := func() (unbondingValidators []types.UnbondingValidator) {
+		data, err := querier(ctx, []string{types.QueryUnbondingValidators}, abci.RequestQuery{})
+		require.Nil(t, err)
+		require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &unbondingValidators))
+		return
+	}
+
+	valAddrs := make([]sdk.ValAddress, 3)
+	for i := range valAddrs {
+		valAddrs[i] = sdk.ValAddress(keep.Addrs[i])
+		voterAddr := keep.Addrs[i+3]
+		got := handler(ctx, NewTestMsgCreateValidator(valAddrs[i], keep.PKs[i], DefaultValidInitMsd))
+		require.True(t, got.IsOK(), "%v", got)
+		amount := sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(int64(100*(i+1))))
+		require.True(t, handler(ctx, types.NewMsgDelegate(voterAddr, amount)).IsOK())
+		require.True(t, handler(ctx, types.NewMsgVote(voterAddr, []sdk.ValAddress{valAddrs[i]})).IsOK())
+	}
+	ctx = ctx.WithBlockHeight(epoch)
+	EndBlocker(ctx, keeper)
+	require.Empty(t, queryUnbondingValidators())
+
+	// the validators with the fewest votes are pushed out of the set at the epoch boundary
+	require.Nil(t, keeper.SetMaxValidators(ctx, 1, false))
+	ctx = ctx.WithBlockHeight(epoch * 2).WithBlockTime(blockTime.Add(time.Hour))
+	EndBlocker(ctx, keeper)
+	unbondingValidators := queryUnbondingValidators()
+	require.Equal(t, 2, len(unbondingValidators))
+	completionTime := ctx.BlockTime().Add(keeper.UnbondingTime(ctx))
+	expectedConsAddrs := map[string]sdk.ConsAddress{
+		valAddrs[0].String(): sdk.GetConsAddress(keep.PKs[0]),
+		valAddrs[1].String(): sdk.GetConsAddress(keep.PKs[1]),
+	}
+	for _, unbondingValidator := range unbondingValidators {
+		consAddr, found := expectedConsAddrs[unbondingValidator.OperatorAddress.String()]
+		require.True(t, found)
+		require.Equal(t, consAddr, unbondingValidator.ConsAddress)
+		require.Equal(t, epoch*2, unbondingValidator.UnbondingHeight)
+		require.True(t, unbondingValidator.UnbondingCompletionTime.Equal(completionTime))
+	}
+
+	// gone once the unbonding completes
+	ctx = ctx.WithBlockHeight(epoch*2 + 1).WithBlockTime(completionTime)
+	EndBlocker(ctx, keeper)
+	require.Empty(t, queryUnbondingValidators())
+}
+
+func TestBondedSnapshotRecordedAtEpochEnd(t *testing.T) {
+	ctx, _, mKeeper := CreateTestInput(t, false, SufficientInitPower)
+	keeper := mKeeper.Keeper
+	epoch := int64(keeper.GetEpoch(ctx))
+
+	// nothing is recorded in the middle of the epoch
+	ctx = ctx.WithBlockHeight(1)
+	EndBlocker(ctx, keeper)
+	require.Empty(t, keeper.GetBondedHistory(ctx, 0, 10))
+
+	for i := int64(1); i <= 3; i++ {
+		ctx = ctx.WithBlockHeight(epoch * i)
+		EndBlocker(ctx, keeper)
+	}
+	history := keeper.GetBondedHistory(ctx, 0, 10)
+	require.Equal(t, 3, len(history))
+	for i, snapshot := range history {
+		require.Equal(t, uint64(i), snapshot.EpochNumber)
+		require.Equal(t, epoch*int64(i+1), snapshot.Height)
+	}
+}
+
+func TestLargePowerChangeEvent(t *testing.T) {
+	addr1, addr2 := sdk.ValAddress(keep.Addrs[0]), sdk.ValAddress(keep.Addrs[1])
+	ctx, _, mKeeper := CreateTestInput(t, false, SufficientInitPower)
+	keeper := mKeeper.Keeper
+	handler := NewHandler(keeper)
+	require.True(t, keeper.ParamsPowerAlertThreshold(ctx).Equal(types.DefaultPowerAlertThreshold))
+
+	largePowerChangeEvents := func(ctx sdk.Context) (events []sdk.Event) {
+		for _, event := range ctx.EventManager().Events() {
+			if event.Type == types.EventTypeLargePowerChange {
+				events = append(events, event)
+			}
+		}
+		return
+	}
+	vote := func(ctx sdk.Context, delAddr sdk.AccAddress, amount sdk.Dec, valAddrs ...sdk.ValAddress) {
+		got := handler(ctx, types.NewMsgDelegate(delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, amount)))
+		require.True(t, got.IsOK(), "%v", got)
+		got = handler(ctx, types.NewMsgVote(delAddr, valAddrs))
+		require.True(t, got.IsOK(), "%v", got)
+	}
+
+	got := handler(ctx, NewTestMsgCreateValidator(addr1, keep.PKs[0], DefaultValidInitMsd))
+	require.True(t, got.IsOK(), "%v", got)
+	got = handler(ctx, NewTestMsgCreateValidator(addr2, keep.PKs[1], DefaultValidInitMsd))
+	require.True(t, got.IsOK(), "%v", got)
+	vote(ctx, keep.Addrs[2], sdk.NewDec(1000), addr1, addr2)
+	require.Equal(t, 2, len(EndBlocker(ctx, keeper)))
+	lastPower := keeper.GetLastValidatorPower(ctx, addr1)
+	require.True(t, lastPower > 0)
+
+	// not crossing the threshold
+	ctx = ctx.WithBlockHeight(1).WithEventManager(sdk.NewEventManager())
+	vote(ctx, keep.Addrs[3], sdk.NewDec(10), addr1)
+	last, projected := keeper.ValidatorPowerDelta(ctx, addr1)
+	require.Equal(t, lastPower, last)
+	require.True(t, projected > last)
+	require.False(t, keeper.IsLargePowerChange(ctx, last, projected))
+	EndBlocker(ctx, keeper)
+	require.Equal(t, 0, len(largePowerChangeEvents(ctx)))
+
+	// crossing the threshold
+	ctx = ctx.WithBlockHeight(2).WithEventManager(sdk.NewEventManager())
+	vote(ctx, keep.Addrs[4], sdk.NewDec(5000), addr1)
+	last, projected = keeper.ValidatorPowerDelta(ctx, addr1)
+	require.True(t, keeper.IsLargePowerChange(ctx, last, projected))
+	EndBlocker(ctx, keeper)
+	events := largePowerChangeEvents(ctx)
+	require.Equal(t, 1, len(events))
+	require.Equal(t, addr1.String(), string(events[0].Attributes[0].Value))
+	require.Equal(t, strconv.FormatInt(last, 10), string(events[0].Attributes[1].Value))
+	require.Equal(t, strconv.FormatInt(projected, 10), string(events[0].Attributes[2].Value))
+
+	// the power of the jailed validator is projected to zero
+	val2, found := keeper.GetValidator(ctx, addr2)
+	require.True(t, found)
+	keeper.Jail(ctx, val2.ConsAddress())
+	last, projected = keeper.ValidatorPowerDelta(ctx, addr2)
+	require.True(t, last > 0)
+	require.Equal(t, int64(0), projected)
+
+	// zero threshold disables the alert
+	params := keeper.GetParams(ctx)
+	params.PowerAlertThreshold = sdk.ZeroDec()
+	keeper.SetParams(ctx, params)
+	ctx = ctx.WithBlockHeight(3).WithEventManager(sdk.NewEventManager())
+	EndBlocker(ctx, keeper)
+	require.Equal(t, 0, len(largePowerChangeEvents(ctx)))
+}
+
+func TestDelegationAcceptancePolicy(t *testing.T) {
+	addr1, addr2 := sdk.ValAddress(keep.Addrs[0]), sdk.ValAddress(keep.Addrs[1])
+	ctx, _, mKeeper := CreateTestInput(t, false, SufficientInitPower)
+	keeper := mKeeper.Keeper
+	handler := NewHandler(keeper)
+	delegate := func(delAddr sdk.AccAddress) {
+		got := handler(ctx, types.NewMsgDelegate(delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(100))))
+		require.True(t, got.IsOK(), "%v", got)
+	}
+	vote := func(delAddr sdk.AccAddress, valAddrs ...sdk.ValAddress) sdk.Result {
+		return deliverMsg(ctx, handler, types.NewMsgVote(delAddr, valAddrs))
+	}
+	editPolicy := func(valAddr sdk.ValAddress, acceptingDelegations *bool, maxDelegatorCount *uint64) {
+		got := handler(ctx, NewMsgEditValidator(valAddr, Description{}, acceptingDelegations, maxDelegatorCount, nil))
+		require.True(t, got.IsOK(), "%v", got)
+	}
+
+	got := handler(ctx, NewTestMsgCreateValidator(addr1, keep.PKs[0], DefaultValidInitMsd))
+	require.True(t, got.IsOK(), "%v", got)
+	got = handler(ctx, NewTestMsgCreateValidator(addr2, keep.PKs[1], DefaultValidInitMsd))
+	require.True(t, got.IsOK(), "%v", got)
+	val1, found := keeper.GetValidator(ctx, addr1)
+	require.True(t, found)
+	require.False(t, val1.ClosedToDelegations)
+	require.Equal(t, uint64(0), val1.MaxDelegatorCount)
+	for _, addr := range keep.Addrs[2:6] {
+		delegate(addr)
+	}
+	require.True(t, vote(keep.Addrs[2], addr1).IsOK())
+
+	// closed to new delegators, while the existing one keeps on voting
+	closed, description := false, val1.Description
+	editPolicy(addr1, &closed, nil)
+	val1, found = keeper.GetValidator(ctx, addr1)
+	require.True(t, found)
+	require.True(t, val1.ClosedToDelegations)
+	require.Equal(t, description, val1.Description)
+	got = vote(keep.Addrs[3], addr1)
+	require.False(t, got.IsOK())
+	require.Equal(t, types.CodeInvalidVote, got.Code)
+	got = vote(keep.Addrs[3], addr2, addr1)
+	require.False(t, got.IsOK())
+	require.True(t, vote(keep.Addrs[3], addr2).IsOK())
+	require.True(t, vote(keep.Addrs[2], addr1, addr2).IsOK())
+
+	// reopened with a cap on the number of delegators
+	accepting, maxDelegatorCount := true, uint64(2)
+	editPolicy(addr1, &accepting, &maxDelegatorCount)
+	require.True(t, vote(keep.Addrs[3], addr1).IsOK())
+	require.Equal(t, uint64(2), keeper.GetValidatorVoterCount(ctx, addr1))
+	got = vote(keep.Addrs[4], addr1)
+	require.False(t, got.IsOK())
+	require.Equal(t, types.CodeInvalidVote, got.Code)
+	require.True(t, vote(keep.Addrs[3], addr1).IsOK())
+
+	// a delegator leaving makes the room for a new one
+	require.True(t, vote(keep.Addrs[3], addr2).IsOK())
+	require.True(t, vote(keep.Addrs[4], addr1).IsOK())
+
+	// the cap is lifted by zero
+	maxDelegatorCount = 0
+	editPolicy(addr1, nil, &maxDelegatorCount)
+	require.True(t, vote(keep.Addrs[5], addr1).IsOK())
+	require.Equal(t, uint64(3), keeper.GetValidatorVoterCount(ctx, addr1))
+
+	// the policy is persisted in the exported genesis
+	editPolicy(addr2, &closed, &maxDelegatorCount)
+	val2, found := keeper.GetValidator(ctx, addr2)
+	require.True(t, found)
+	require.Equal(t, val2.ClosedToDelegations, val2.Export().Import().ClosedToDelegations)
+	require.True(t, val2.Standardize().ClosedToDelegations)
+
+	// the validators exported by the earlier software lack the policy, which leaves them open to delegations
+	bz := types.ModuleCdc.MustMarshalJSON(val2.Export())
+	bz = bytes.Replace(bz, []byte(`"closed_to_delegations":true,`), nil, 1)
+	var valExported types.ValidatorExported
+	types.ModuleCdc.MustUnmarshalJSON(bz, &valExported)
+	require.False(t, valExported.Import().ClosedToDelegations)
+}
+
+func TestValidatorMinDelegation(t *testing.T) {
+	addr1, addr2 := sdk.ValAddress(keep.Addrs[0]), sdk.ValAddress(keep.Addrs[1])
+	ctx, _, mKeeper := CreateTestInput(t, false, SufficientInitPower)
+	keeper := mKeeper.Keeper
+	handler := NewHandler(keeper)
+	delegate := func(delAddr sdk.AccAddress, amount int64) {
+		got := handler(ctx, types.NewMsgDelegate(delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(amount))))
+		require.True(t, got.IsOK(), "%v", got)
+	}
+	vote := func(delAddr sdk.AccAddress, valAddrs ...sdk.ValAddress) sdk.Result {
+		return deliverMsg(ctx, handler, types.NewMsgVote(delAddr, valAddrs))
+	}
+	editMinDelegation := func(valAddr sdk.ValAddress, minDelegation sdk.Dec) {
+		got := handler(ctx, NewMsgEditValidator(valAddr, Description{}, nil, nil, &minDelegation))
+		require.True(t, got.IsOK(), "%v", got)
+	}
+
+	got := handler(ctx, NewTestMsgCreateValidator(addr1, keep.PKs[0], DefaultValidInitMsd))
+	require.True(t, got.IsOK(), "%v", got)
+	got = handler(ctx, NewTestMsgCreateValidator(addr2, keep.PKs[1], DefaultValidInitMsd))
+	require.True(t, got.IsOK(), "%v", got)
+	val1, found := keeper.GetValidator(ctx, addr1)
+	require.True(t, found)
+	require.True(t, val1.MinDelegation.IsZero())
+
+	// the validator's floor binds over the global one
+	editMinDelegation(addr1, sdk.NewDec(50))
+	val1, found = keeper.GetValidator(ctx, addr1)
+	require.True(t, found)
+	require.True(t, val1.MinDelegation.Equal(sdk.NewDec(50)))
+	require.True(t, sdk.NewDec(10).GT(keeper.ParamsMinDelegation(ctx)))
+	delegate(keep.Addrs[2], 100)
+	delegate(keep.Addrs[3], 10)
+	require.True(t, vote(keep.Addrs[2], addr1).IsOK())
+	got = vote(keep.Addrs[3], addr1)
+	require.False(t, got.IsOK())
+	require.Equal(t, types.CodeInvalidVote, got.Code)
+	got = vote(keep.Addrs[3], addr2, addr1)
+	require.False(t, got.IsOK())
+	require.True(t, vote(keep.Addrs[3], addr2).IsOK())
+	require.NotNil(t, keeper.CanDelegate(ctx, keep.Addrs[4], addr1,
+		sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(10))))
+	require.Nil(t, keeper.CanDelegate(ctx, keep.Addrs[4], addr1,
+		sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(50))))
+
+	// exactly at the validator's floor
+	delegate(keep.Addrs[3], 40)
+	require.True(t, vote(keep.Addrs[3], addr1).IsOK())
+
+	// a higher floor doesn't affect the existing delegators
+	editMinDelegation(addr1, sdk.NewDec(200))
+	require.True(t, vote(keep.Addrs[2], addr1, addr2).IsOK())
+
+	// zero falls back to the global floor
+	editMinDelegation(addr1, sdk.ZeroDec())
+	delegate(keep.Addrs[5], 10)
+	require.True(t, vote(keep.Addrs[5], addr1).IsOK())
+}
+
+func TestPruneDestroyedValidator(t *testing.T) {
+	valAddr, voterAddr := sdk.ValAddress(keep.Addrs[0]), keep.Addrs[2]
+	otherValAddr := sdk.ValAddress(keep.Addrs[1])
+	ctx, _, mKeeper := CreateTestInput(t, false, SufficientInitPower)
+	keeper := mKeeper.Keeper
+	handler := NewHandler(keeper)
+	blockTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx = ctx.WithBlockTime(blockTime)
+	requireOK := func(got sdk.Result) {
+		require.True(t, got.IsOK(), "%v", got)
+	}
+
+	requireOK(handler(ctx, NewTestMsgCreateValidator(valAddr, keep.PKs[0], DefaultValidInitMsd)))
+	requireOK(handler(ctx, NewTestMsgCreateValidator(otherValAddr, keep.PKs[1], DefaultValidInitMsd)))
+	requireOK(handler(ctx, types.NewMsgDelegate(voterAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(100)))))
+	requireOK(handler(ctx, types.NewMsgVote(voterAddr, []sdk.ValAddress{valAddr})))
+	keeper.ApplyAndReturnValidatorSetUpdates(ctx)
+	validator, found := keeper.GetValidator(ctx, valAddr)
+	require.True(t, found)
+	require.True(t, validator.IsBonded())
+
+	// destroyed while bonded, the validator stays while it's unbonding so that the evidence is still able to arrive
+	requireOK(handler(ctx, types.NewMsgDestroyValidator(sdk.AccAddress(valAddr))))
+	keeper.ApplyAndReturnValidatorSetUpdates(ctx)
+	validator, found = keeper.GetValidator(ctx, valAddr)
+	require.True(t, found)
+	require.True(t, validator.IsUnbonding())
+	require.True(t, validator.MinSelfDelegation.IsZero())
+
+	// fully unbonded with the votes left, the validator stays
+	ctx = ctx.WithBlockTime(blockTime.Add(keeper.UnbondingTime(ctx)))
+	keeper.UnbondAllMatureValidatorQueue(ctx)
+	validator, found = keeper.GetValidator(ctx, valAddr)
+	require.True(t, found)
+	require.True(t, validator.IsUnbonded())
+
+	// pruned once the last vote leaves
+	requireOK(handler(ctx, types.NewMsgVote(voterAddr, []sdk.ValAddress{otherValAddr})))
+	_, found = keeper.GetValidator(ctx, valAddr)
+	require.False(t, found)
+	_, found = keeper.GetValidatorByConsAddr(ctx, sdk.GetConsAddress(keep.PKs[0]))
+	require.False(t, found)
+}
+
+func TestSelfDelegationOnly(t *testing.T) {
+	addr1, addr2 := sdk.ValAddress(keep.Addrs[0]), sdk.ValAddress(keep.Addrs[1])
+	ctx, _, mKeeper := CreateTestInput(t, false, SufficientInitPower)
+	keeper := mKeeper.Keeper
+	handler := NewHandler(keeper)
+	vote := func(delAddr sdk.AccAddress, valAddrs ...sdk.ValAddress) sdk.Result {
+		return deliverMsg(ctx, handler, types.NewMsgVote(delAddr, valAddrs))
+	}
+
+	for i, valAddr := range []sdk.ValAddress{addr1, addr2} {
+		got := handler(ctx, NewTestMsgCreateValidator(valAddr, keep.PKs[i], DefaultValidInitMsd))
+		require.True(t, got.IsOK(), "%v", got)
+	}
+	for _, addr := range keep.Addrs[:3] {
+		got := handler(ctx, types.NewMsgDelegate(addr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(100))))
+		require.True(t, got.IsOK(), "%v", got)
+	}
+	params := keeper.GetParams(ctx)
+	require.False(t, params.SelfDelegationOnly)
+	params.SelfDelegationOnly = true
+	keeper.SetParams(ctx, params)
+
+	// external delegations are rejected
+	got := vote(keep.Addrs[2], addr1)
+	require.False(t, got.IsOK())
+	require.Equal(t, types.CodeInvalidVote, got.Code)
+	got = vote(keep.Addrs[0], addr1, addr2)
+	require.False(t, got.IsOK())
+	require.Equal(t, types.CodeInvalidVote, got.Code)
+
+	// the operators vote for their own validators
+	require.True(t, vote(keep.Addrs[0], addr1).IsOK())
+	require.True(t, vote(keep.Addrs[1], addr2).IsOK())
+
+	// external delegations are accepted again once the mode is off
+	params.SelfDelegationOnly = false
+	keeper.SetParams(ctx, params)
+	require.True(t, vote(keep.Addrs[2], addr1, addr2).IsOK())
+}
+
+func TestMaxValidatorVotes(t *testing.T) {
+	valAddr := sdk.ValAddress(keep.Addrs[0])
+	ctx, _, mKeeper := CreateTestInput(t, false, SufficientInitPower)
+	keeper := mKeeper.Keeper
+	handler := NewHandler(keeper)
+	deliver := func(msg sdk.Msg) sdk.Result {
+		return deliverMsg(ctx, handler, msg)
+	}
+	delegate := func(delAddr sdk.AccAddress, amount int64) sdk.Result {
+		return deliver(types.NewMsgDelegate(delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(amount))))
+	}
+	vote := func(delAddr sdk.AccAddress) sdk.Result {
+		return deliver(types.NewMsgVote(delAddr, []sdk.ValAddress{valAddr}))
+	}
+	shares := func() sdk.Dec {
+		validator, found := keeper.GetValidator(ctx, valAddr)
+		require.True(t, found)
+		return validator.DelegatorShares
+	}
+	setMaxValidatorVotes := func(maxValidatorVotes sdk.Int) {
+		params := keeper.GetParams(ctx)
+		params.MaxValidatorVotes = maxValidatorVotes
+		keeper.SetParams(ctx, params)
+	}
+
+	got := handler(ctx, NewTestMsgCreateValidator(valAddr, keep.PKs[0], DefaultValidInitMsd))
+	require.True(t, got.IsOK(), "%v", got)
+	for _, delAddr := range keep.Addrs[1:4] {
+		require.True(t, delegate(delAddr, 100).IsOK())
+	}
+	require.True(t, keeper.ParamsMaxValidatorVotes(ctx).IsZero())
+	require.True(t, vote(keep.Addrs[1]).IsOK())
+	votesPer100 := shares().Sub(DefaultValidInitMsd)
+
+	// the vote fits the cap exactly
+	setMaxValidatorVotes(shares().Add(votesPer100).Ceil().TruncateInt())
+	require.True(t, vote(keep.Addrs[2]).IsOK())
+
+	// a further vote or delegation is over the cap
+	sharesAtCap := shares()
+	got = vote(keep.Addrs[3])
+	require.False(t, got.IsOK())
+	require.Equal(t, types.CodeInvalidVote, got.Code)
+	got = delegate(keep.Addrs[2], 100)
+	require.False(t, got.IsOK())
+	require.Equal(t, types.CodeInvalidVote, got.Code)
+	require.True(t, sharesAtCap.Equal(shares()))
+
+	// withdrawing votes is always allowed
+	got = deliver(types.NewMsgUndelegate(keep.Addrs[2], sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(50))))
+	require.True(t, got.IsOK(), "%v", got)
+	require.True(t, shares().LT(sharesAtCap))
+
+	// zero means unlimited
+	setMaxValidatorVotes(sdk.ZeroInt())
+	require.True(t, vote(keep.Addrs[3]).IsOK())
+	require.True(t, delegate(keep.Addrs[2], 1000).IsOK())
+}
+
+func TestMaxValidatorVoteShare(t *testing.T) {
+	addr1, addr2 := sdk.ValAddress(keep.Addrs[0]), sdk.ValAddress(keep.Addrs[1])
+	ctx, _, mKeeper := CreateTestInput(t, false, SufficientInitPower)
+	keeper := mKeeper.Keeper
+	handler := NewHandler(keeper)
+	deliver := func(msg sdk.Msg) sdk.Result {
+		return deliverMsg(ctx, handler, msg)
+	}
+	amount := func(amount int64) sdk.DecCoin {
+		return sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(amount))
+	}
+	vote := func(delAddr sdk.AccAddress, valAddrs ...sdk.ValAddress) sdk.Result {
+		return deliver(types.NewMsgVote(delAddr, valAddrs))
+	}
+	requireCapReached := func(got sdk.Result) {
+		require.False(t, got.IsOK())
+		require.Equal(t, types.CodeInvalidVote, got.Code)
+	}
+
+	for i, valAddr := range []sdk.ValAddress{addr1, addr2} {
+		got := handler(ctx, NewTestMsgCreateValidator(valAddr, keep.PKs[i], DefaultValidInitMsd))
+		require.True(t, got.IsOK(), "%v", got)
+	}
+	for _, delAddr := range keep.Addrs[2:5] {
+		require.True(t, deliver(types.NewMsgDelegate(delAddr, amount(100))).IsOK())
+	}
+
+	// zero means unlimited
+	require.True(t, keeper.ParamsMaxValidatorVoteShare(ctx).IsZero())
+	require.True(t, vote(keep.Addrs[2], addr1).IsOK())
+
+	params := keeper.GetParams(ctx)
+	params.MaxValidatorVoteShare = sdk.NewDecWithPrec(5, 1)
+	keeper.SetParams(ctx, params)
+
+	// over the cap
+	requireCapReached(vote(keep.Addrs[3], addr1))
+
+	// the validators share the total votes equally, right at the cap
+	require.True(t, vote(keep.Addrs[3], addr2).IsOK())
+	requireCapReached(vote(keep.Addrs[4], addr1))
+	requireCapReached(deliver(types.NewMsgDelegate(keep.Addrs[2], amount(100))))
+	require.True(t, vote(keep.Addrs[4], addr1, addr2).IsOK())
+	val1, found := keeper.GetValidator(ctx, addr1)
+	require.True(t, found)
+	val2, found := keeper.GetValidator(ctx, addr2)
+	require.True(t, found)
+	require.True(t, val1.DelegatorShares.Equal(val2.DelegatorShares))
+
+	// withdrawing votes is always allowed
+	require.True(t, deliver(types.NewMsgUndelegate(keep.Addrs[2], amount(50))).IsOK())
+}
+
+func TestValidatorSelfUndelegateCooldown(t *testing.T) {
+	valAddr, otherAddr := sdk.ValAddress(keep.Addrs[0]), keep.Addrs[1]
+	ctx, _, mKeeper := CreateTestInput(t, false, SufficientInitPower)
+	keeper := mKeeper.Keeper
+	handler := NewHandler(keeper)
+	blockTime := time.Now()
+	undelegate := func(delAddr sdk.AccAddress, elapsed time.Duration) sdk.Result {
+		return deliverMsg(ctx.WithBlockTime(blockTime.Add(elapsed)), handler,
+			types.NewMsgUndelegate(delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(10))))
+	}
+
+	got := handler(ctx, NewTestMsgCreateValidator(valAddr, keep.PKs[0], DefaultValidInitMsd))
+	require.True(t, got.IsOK(), "%v", got)
+	for _, delAddr := range []sdk.AccAddress{keep.Addrs[0], otherAddr} {
+		got = handler(ctx, types.NewMsgDelegate(delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(100))))
+		require.True(t, got.IsOK(), "%v", got)
+	}
+	cooldown := time.Hour
+	params := keeper.GetParams(ctx)
+	params.ValidatorSelfUndelegateCooldown = cooldown
+	keeper.SetParams(ctx, params)
+
+	// the first self-undelegation is not limited and starts the cooldown
+	require.True(t, undelegate(keep.Addrs[0], 0).IsOK())
+	lastTime, found := keeper.GetLastSelfUndelegationTime(ctx, valAddr)
+	require.True(t, found)
+	require.True(t, lastTime.Equal(blockTime))
+
+	// the repeated self-undelegation within the cooldown is rejected without restarting it
+	got = undelegate(keep.Addrs[0], cooldown-time.Second)
+	require.False(t, got.IsOK())
+	require.Equal(t, types.CodeInvalidDelegation, got.Code)
+	lastTime, _ = keeper.GetLastSelfUndelegationTime(ctx, valAddr)
+	require.True(t, lastTime.Equal(blockTime))
+
+	// the delegators who aren't validators are not affected
+	require.True(t, undelegate(otherAddr, 0).IsOK())
+	require.True(t, undelegate(otherAddr, 0).IsOK())
+	_, found = keeper.GetLastSelfUndelegationTime(ctx, sdk.ValAddress(otherAddr))
+	require.False(t, found)
+
+	// allowed once the cooldown has passed, which starts the next one
+	require.True(t, undelegate(keep.Addrs[0], cooldown).IsOK())
+	lastTime, _ = keeper.GetLastSelfUndelegationTime(ctx, valAddr)
+	require.True(t, lastTime.Equal(blockTime.Add(cooldown)))
+	require.False(t, undelegate(keep.Addrs[0], 2*cooldown-time.Second).IsOK())
+	require.True(t, undelegate(keep.Addrs[0], 2*cooldown).IsOK())
+
+	// zero disables the cooldown
+	params.ValidatorSelfUndelegateCooldown = 0
+	keeper.SetParams(ctx, params)
+	require.True(t, undelegate(keep.Addrs[0], 2*cooldown).IsOK())
+	require.True(t, undelegate(keep.Addrs[0], 2*cooldown).IsOK())
+}
+
+func TestMinValidators(t *testing.T) {
+	ctx, _, mKeeper := CreateTestInput(t, false, SufficientInitPower)
+	keeper := mKeeper.Keeper
+	handler := NewHandler(keeper)
+	delAddr := keep.Addrs[5]
+	vote := func(valAddrs ...sdk.ValAddress) sdk.Result {
+		return deliverMsg(ctx, handler, types.NewMsgVote(delAddr, valAddrs))
+	}
+	createValidator := func(i int) sdk.ValAddress {
+		valAddr := sdk.ValAddress(keep.Addrs[i])
+		got := handler(ctx, NewTestMsgCreateValidator(valAddr, keep.PKs[i], DefaultValidInitMsd))
+		require.True(t, got.IsOK(), "%v", got)
+		return valAddr
+	}
+
+	params := keeper.GetParams(ctx)
+	require.Equal(t, uint16(0), params.MinValidators)
+	params.MinValidators = 3
+	keeper.SetParams(ctx, params)
+	got := handler(ctx, types.NewMsgDelegate(delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(100))))
+	require.True(t, got.IsOK(), "%v", got)
+
+	// below the threshold
+	addr1, addr2 := createValidator(0), createValidator(1)
+	got = vote(addr1)
+	require.False(t, got.IsOK())
+	require.Equal(t, types.CodeInvalidVote, got.Code)
+
+	// at the threshold
+	addr3 := createValidator(2)
+	require.True(t, vote(addr1, addr2, addr3).IsOK())
+
+	// a destroyed validator isn't counted
+	got = handler(ctx, types.NewMsgDestroyValidator(keep.Addrs[2]))
+	require.True(t, got.IsOK(), "%v", got)
+	require.False(t, vote(addr1, addr2).IsOK())
+
+	// disabled
+	params.MinValidators = 0
+	keeper.SetParams(ctx, params)
+	require.True(t, vote(addr1, addr2).IsOK())
+}
+
+func TestValidatorDelegations(t *testing.T) {
+	ctx, _, mKeeper := CreateTestInput(t, false, SufficientInitPower)
+	keeper := mKeeper.Keeper
+	handler := NewHandler(keeper)
+	querier := NewQuerier(keeper)
+	requireOK := func(got sdk.Result) {
+		require.True(t, got.IsOK(), "%v", got)
+	}
+	valAddr := sdk.ValAddress(keep.Addrs[0])
+	requireOK(handler(ctx, NewTestMsgCreateValidator(valAddr, keep.PKs[0], DefaultValidInitMsd)))
+	queryDelegations := func(page, limit int) (result types.ValidatorDelegationsResult) {
+		bz := types.ModuleCdc.MustMarshalJSON(types.NewQueryValidatorDelegationsParams(valAddr, page, limit))
+		data, err := querier(ctx, []string{types.QueryValidatorDelegations}, abci.RequestQuery{Data: bz})
+		require.Nil(t, err)
+		require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &result))
+		return
+	}
+	getVoters := func(votes types.VoteResponses) (voters []sdk.AccAddress) {
+		for _, vote := range votes {
+			voters = append(voters, vote.VoterAddr)
+		}
+		return
+	}
+
+	// no voter
+	result := queryDelegations(1, 0)
+	require.Equal(t, 0, result.Total)
+	require.Empty(t, result.Votes)
+
+	// 5 voters
+	amount := sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(100))
+	delAddrs := keep.Addrs[5:10]
+	for _, delAddr := range delAddrs {
+		requireOK(handler(ctx, types.NewMsgDelegate(delAddr, amount)))
+		requireOK(handler(ctx, types.NewMsgVote(delAddr, []sdk.ValAddress{valAddr})))
+	}
+	all, total := keeper.GetValidatorDelegations(ctx, valAddr, 1, 0)
+	require.Equal(t, 5, total)
+	require.Equal(t, keeper.GetValidatorVotes(ctx, valAddr), all)
+	require.ElementsMatch(t, delAddrs, getVoters(all))
+
+	// multiple pages
+	var paged types.VoteResponses
+	for page := 1; page <= 3; page++ {
+		result = queryDelegations(page, 2)
+		require.Equal(t, 5, result.Total)
+		paged = append(paged, result.Votes...)
+	}
+	require.Equal(t, all, paged)
+	require.Equal(t, all[4:], queryDelegations(3, 2).Votes)
+	result = queryDelegations(4, 2)
+	require.Equal(t, 5, result.Total)
+	require.Empty(t, result.Votes)
+
+	// the voter leaves the listing once it undelegates all
+	requireOK(handler(ctx, types.NewMsgUndelegate(delAddrs[0], amount)))
+	result = queryDelegations(1, 0)
+	require.Equal(t, 4, result.Total)
+	require.NotContains(t, getVoters(result.Votes), delAddrs[0])
+	require.ElementsMatch(t, delAddrs[1:], getVoters(result.Votes))
+}
+
+func TestDelegatorHistory(t *testing.T) {
+	valAddr := sdk.ValAddress(keep.Addrs[0])
+	delAddr := keep.Addrs[1]
+	ctx, _, mKeeper := CreateTestInput(t, false, SufficientInitPower)
+	keeper := mKeeper.Keeper
+	handler := NewHandler(keeper)
+	amount := sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(100))
+
+	got := handler(ctx, NewTestMsgCreateValidator(valAddr, keep.PKs[0], DefaultValidInitMsd))
+	require.True(t, got.IsOK(), "%v", got)
+
+	// nothing is recorded while RecordDelegatorHistory is off
+	got = handler(ctx, types.NewMsgDelegate(delAddr, amount))
+	require.True(t, got.IsOK(), "%v", got)
+	require.Empty(t, keeper.GetDelegatorHistory(ctx, delAddr))
+
+	params := keeper.GetParams(ctx)
+	params.RecordDelegatorHistory = true
+	keeper.SetParams(ctx, params)
+
+	ctx = ctx.WithBlockHeight(10)
+	got = handler(ctx, types.NewMsgDelegate(delAddr, amount))
+	require.True(t, got.IsOK(), "%v", got)
+	ctx = ctx.WithBlockHeight(11)
+	got = handler(ctx, types.NewMsgVote(delAddr, []sdk.ValAddress{valAddr}))
+	require.True(t, got.IsOK(), "%v", got)
+	ctx = ctx.WithBlockHeight(12)
+	got = handler(ctx, types.NewMsgUndelegate(delAddr, amount))
+	require.True(t, got.IsOK(), "%v", got)
+
+	history := keeper.GetDelegatorHistory(ctx, delAddr)
+	require.Equal(t, 3, len(history))
+	actions := []string{types.DelegatorActionDelegate, types.DelegatorActionVote, types.DelegatorActionUndelegate}
+	for i, action := range history {
+		require.Equal(t, uint64(i), action.Sequence)
+		require.Equal(t, actions[i], action.Action)
+		require.Equal(t, int64(10+i), action.Height)
+	}
+	require.Equal(t, amount, history[0].Amount)
+	require.Equal(t, []sdk.ValAddress{valAddr}, history[1].ValidatorAddresses)
+	require.True(t, sdk.NewDec(200).Equal(history[1].Amount.Amount))
+	require.Equal(t, amount, history[2].Amount)
+
+	// a failed msg leaves no record
+	got = handler(ctx, types.NewMsgUndelegate(delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(1000))))
+	require.False(t, got.IsOK())
+	require.Equal(t, 3, len(keeper.GetDelegatorHistory(ctx, delAddr)))
+}
+
+func TestEpochBoundaryGrace(t *testing.T) {
+	ctx, ak, mKeeper := CreateTestInput(t, false, SufficientInitPower)
+	keeper := mKeeper.Keeper
+	handler := NewHandler(keeper)
+	params := keeper.GetParams(ctx)
+	params.Epoch = 4
+	params.EpochBoundaryGrace = 2
+	params.RecordDelegatorHistory = true
+	keeper.SetParams(ctx, params)
+	keeper.SetEpoch(ctx, params.Epoch)
+	amount := sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(100))
+	delegatorTokens := func(delAddr sdk.AccAddress) sdk.Dec {
+		delegator, found := keeper.GetDelegator(ctx, delAddr)
+		if !found {
+			return sdk.ZeroDec()
+		}
+		return delegator.Tokens
+	}
+
+	// outside the grace window, the delegation is applied at once
+	ctx = ctx.WithBlockHeight(2)
+	require.False(t, keeper.IsWithinEpochBoundaryGrace(ctx))
+	got := handler(ctx, types.NewMsgDelegate(keep.Addrs[0], amount))
+	require.True(t, got.IsOK(), "%v", got)
+	require.True(t, amount.Amount.Equal(delegatorTokens(keep.Addrs[0])))
+	require.Empty(t, keeper.GetPendingDelegations(ctx))
+
+	// within the grace window, the delegation is deferred to the end of the epoch
+	ctx = ctx.WithBlockHeight(3)
+	require.True(t, keeper.IsWithinEpochBoundaryGrace(ctx))
+	got = handler(ctx, types.NewMsgDelegate(keep.Addrs[1], amount))
+	require.True(t, got.IsOK(), "%v", got)
+	require.True(t, delegatorTokens(keep.Addrs[1]).IsZero())
+	require.Empty(t, keeper.GetDelegatorHistory(ctx, keep.Addrs[1]))
+	require.Equal(t, []types.PendingDelegation{types.NewPendingDelegation(keep.Addrs[1], amount)},
+		keeper.GetPendingDelegations(ctx))
+	// the coins deferred are escrowed in the not bonded pool at once
+	balance := func(addr sdk.AccAddress) sdk.Dec {
+		return ak.GetAccount(ctx, addr).GetCoins().AmountOf(sdk.DefaultBondDenom)
+	}
+	notBonded := keeper.GetNotBondedPool(ctx).GetCoins().AmountOf(sdk.DefaultBondDenom)
+	require.True(t, balance(keep.Addrs[0]).Sub(balance(keep.Addrs[1])).Equal(sdk.ZeroDec()))
+	_, broken := keep.ModuleAccountInvariantsCustom(keeper)(ctx)
+	require.False(t, broken)
+	deferred := false
+	for _, event := range got.Events {
+		for _, attr := range event.Attributes {
+			if string(attr.Key) == types.AttributeKeyMode && string(attr.Value) == types.AttributeValueDeferred {
+				deferred = true
+			}
+		}
+	}
+	require.True(t, deferred)
+
+	// an invalid delegation is rejected at once instead of deferred
+	got = handler(ctx, types.NewMsgDelegate(keep.Addrs[2], sdk.NewDecCoinFromDec(sdk.DefaultBondDenom,
+		sdk.NewDecWithPrec(1, 8))))
+	require.False(t, got.IsOK())
+	require.Equal(t, 1, len(keeper.GetPendingDelegations(ctx)))
+
+	// a deferred delegation no longer valid at the end of the epoch returns the coins escrowed
+	balanceBefore := balance(keep.Addrs[2])
+	got = handler(ctx, types.NewMsgDelegate(keep.Addrs[2], amount))
+	require.True(t, got.IsOK(), "%v", got)
+	require.True(t, balanceBefore.Sub(amount.Amount).Equal(balance(keep.Addrs[2])))
+	require.True(t, notBonded.Add(amount.Amount).Equal(
+		keeper.GetNotBondedPool(ctx).GetCoins().AmountOf(sdk.DefaultBondDenom)))
+	params.MaxDelegations = 2
+	keeper.SetParams(ctx, params)
+
+	// the deferred delegation is applied from the escrow once the epoch ends
+	ctx = ctx.WithBlockHeight(4)
+	require.True(t, keeper.IsEndOfEpoch(ctx))
+	EndBlocker(ctx, keeper)
+	require.True(t, amount.Amount.Equal(delegatorTokens(keep.Addrs[1])))
+	require.True(t, delegatorTokens(keep.Addrs[2]).IsZero())
+	require.True(t, balanceBefore.Equal(balance(keep.Addrs[2])))
+	require.Empty(t, keeper.GetPendingDelegations(ctx))
+	// the history records the deferred delegations as they are applied or dropped
+	require.Equal(t, types.DelegatorHistory{types.NewDelegatorAction(0, types.DelegatorActionDelegate, 4, amount, nil)},
+		keeper.GetDelegatorHistory(ctx, keep.Addrs[1]))
+	require.Equal(t, types.DelegatorHistory{
+		types.NewDelegatorAction(0, types.DelegatorActionDelegateDropped, 4, amount, nil),
+	}, keeper.GetDelegatorHistory(ctx, keep.Addrs[2]))
+	_, broken = keep.ModuleAccountInvariantsCustom(keeper)(ctx)
+	require.False(t, broken)
+
+	// the next epoch starts outside the grace window
+	require.False(t, keeper.IsWithinEpochBoundaryGrace(ctx.WithBlockHeight(5)))
+	require.True(t, keeper.IsWithinEpochBoundaryGrace(ctx.WithBlockHeight(7)))
+}
@@ -1,6 +1,7 @@
 package staking
 
 import (
+	"fmt"
 	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -90,6 +91,11 @@ func regProxy(ctx sdk.Context, proxyAddr sdk.AccAddress, k keeper.Keeper) sdk.Re
 
 	}
 
+	// double proxy is denied on okchain, the delegator must unbind its proxy before becoming a proxy
+	if proxy.HasProxy() {
+		return types.ErrDoubleProxy(types.DefaultCodespace, proxyAddr.String()).Result()
+	}
+
 	proxy.RegProxy(true)
 	k.SetDelegator(ctx, proxy)
 
@@ -141,6 +147,9 @@ func handleMsgVote(ctx sdk.Context, msg types.MsgVote, k keeper.Keeper) sdk.Resu
 	} else if len(msg.ValAddrs) > maxValsToVote {
 		return types.ErrExceedValidatorAddrs(DefaultCodespace, maxValsToVote).Result()
 	}
+	if !k.HasMinValidators(ctx) {
+		return types.ErrNotEnoughValidators(types.DefaultCodespace, k.ParamsMinValidators(ctx)).Result()
+	}
 
 	// 0. check whether the voter has delegation
 	delegator, found := k.GetDelegator(ctx, msg.DelAddr)
@@ -166,9 +175,15 @@ func handleMsgVote(ctx sdk.Context, msg types.MsgVote, k keeper.Keeper) sdk.Resu
 	if sdkErr = validateVoting(vals); sdkErr != nil {
 		return sdkErr.Result()
 	}
+	if sdkErr = k.ValidateSelfDelegationOnly(ctx, msg.DelAddr, vals); sdkErr != nil {
+		return sdkErr.Result()
+	}
 
 	// 4. get the total amount of self token and delegated token
 	totalTokens := delegator.Tokens.Add(delegator.TotalDelegatedTokens)
+	if sdkErr = k.ValidateDelegationPolicy(ctx, vals, lastVals, totalTokens); sdkErr != nil {
+		return sdkErr.Result()
+	}
 
 	// 5. vote for the vals this time
 	votes, sdkErr := k.VoteValidators(ctx, msg.DelAddr, vals, totalTokens)
@@ -180,6 +195,8 @@ func handleMsgVote(ctx sdk.Context, msg types.MsgVote, k keeper.Keeper) sdk.Resu
 	delegator.ValidatorAddresses = getValsAddrs(vals)
 	delegator.Shares = votes
 	k.SetDelegator(ctx, delegator)
+	k.RecordDelegatorAction(ctx, msg.DelAddr, types.DelegatorActionVote,
+		sdk.NewDecCoinFromDec(k.BondDenom(ctx), totalTokens), delegator.ValidatorAddresses)
 
 	ctx.EventManager().EmitEvent(buildEventForHandlerVote(delegator))
 	return sdk.Result{Events: ctx.EventManager().Events()}
@@ -235,14 +252,34 @@ func buildEventForHandlerVote(delegator types.Delegator) sdk.Event {
 
 func handleMsgDelegate(ctx sdk.Context, msg types.MsgDelegate, k keeper.Keeper) sdk.Result {
 
-	if msg.Amount.Denom != k.BondDenom(ctx) {
+	if _, found := k.ParamsBondDenoms(ctx).Weight(msg.Amount.Denom); !found {
 		return ErrBadDenom(k.Codespace()).Result()
 	}
 
+	// the delegation received within the epoch boundary grace takes effect after the end of the epoch
+	if k.IsWithinEpochBoundaryGrace(ctx) {
+		if err := k.DeferDelegation(ctx, msg.DelegatorAddress, msg.Amount); err != nil {
+			return err.Result()
+		}
+		// the delegation is recorded in the history of the delegator once it's applied or dropped
+		ctx.EventManager().EmitEvent(
+			sdk.NewEvent(
+				types.EventTypeDelegate,
+				sdk.NewAttribute(types.AttributeKeyValidator, msg.DelegatorAddress.String()),
+				sdk.NewAttribute(sdk.AttributeKeyAmount, msg.Amount.String()),
+				sdk.NewAttribute(types.AttributeKeyMode, types.AttributeValueDeferred),
+				sdk.NewAttribute(types.AttributeKeyEffectiveHeight,
+					fmt.Sprintf("%d", k.GetTheEndOfLastEpoch(ctx)+int64(k.GetEpoch(ctx)))),
+			),
+		)
+		return sdk.Result{Events: ctx.EventManager().Events()}
+	}
+
 	err := k.Delegate(ctx, msg.DelegatorAddress, msg.Amount)
 	if err != nil {
 		return err.Result()
 	}
+	k.RecordDelegatorAction(ctx, msg.DelegatorAddress, types.DelegatorActionDelegate, msg.Amount, nil)
 
 	ctx.EventManager().EmitEvents(sdk.Events{
 		sdk.NewEvent(
@@ -255,14 +292,19 @@ func handleMsgDelegate(ctx sdk.Context, msg types.MsgDelegate, k keeper.Keeper)
 }
 
 func handleMsgUndelegate(ctx sdk.Context, msg types.MsgUndelegate, k keeper.Keeper) sdk.Result {
-	if msg.Amount.Denom != k.BondDenom(ctx) {
-		return ErrBadDenom(k.Codespace()).Result()
+	if err := k.ValidateSelfUndelegateCooldown(ctx, msg.DelegatorAddress); err != nil {
+		return err.Result()
 	}
 
-	completionTime, err := k.Undelegate(ctx, msg.DelegatorAddress, msg.Amount)
+	// the denom is checked in BeginUnbonding, since the coins in the denom which isn't bondable any more can still
+	// be undelegated
+	undelegation, err := k.BeginUnbonding(ctx, msg.DelegatorAddress, msg.Amount)
 	if err != nil {
 		return err.Result()
 	}
+	completionTime := undelegation.CompletionTime
+	k.RecordSelfUndelegation(ctx, msg.DelegatorAddress)
+	k.RecordDelegatorAction(ctx, msg.DelegatorAddress, types.DelegatorActionUndelegate, msg.Amount, nil)
 
 	ctx.EventManager().EmitEvents(sdk.Events{
 		sdk.NewEvent(
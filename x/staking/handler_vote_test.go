@@ -83,3 +83,69 @@ func TestHandlerBadMessage(t *testing.T) {
 	r := handler(ctx, msg)
 	require.False(t, r.IsOK(), r)
 }
+
+func TestHandlerRegProxyDeniesDoubleProxy(t *testing.T) {
+	ctx, _, mockKeeper := CreateTestInput(t, false, SufficientInitPower)
+	keeper := mockKeeper.Keeper
+	handler := NewHandler(keeper)
+	proxyAddr, delAddr := Addrs[1], Addrs[2]
+	amount := sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(100))
+
+	for _, addr := range []sdk.AccAddress{proxyAddr, delAddr} {
+		require.True(t, handler(ctx, types.NewMsgDelegate(addr, amount)).IsOK())
+	}
+	require.True(t, handler(ctx, types.NewMsgRegProxy(proxyAddr, true)).IsOK())
+	require.True(t, handler(ctx, types.NewMsgBindProxy(delAddr, proxyAddr)).IsOK())
+
+	// a delegator bound to a proxy can't register as a proxy itself
+	response := handler(ctx, types.NewMsgRegProxy(delAddr, true))
+	require.False(t, response.IsOK())
+	require.Equal(t, types.ErrDoubleProxy(types.DefaultCodespace, delAddr.String()).Result().Log, response.Log)
+	delegator, found := keeper.GetDelegator(ctx, delAddr)
+	require.True(t, found)
+	require.False(t, delegator.IsProxy)
+	require.Equal(t, proxyAddr, delegator.ProxyAddress)
+
+	// it registers once it unbinds the proxy
+	require.True(t, handler(ctx, types.NewMsgUnbindProxy(delAddr)).IsOK())
+	require.True(t, handler(ctx, types.NewMsgRegProxy(delAddr, true)).IsOK())
+	delegator, found = keeper.GetDelegator(ctx, delAddr)
+	require.True(t, found)
+	require.True(t, delegator.IsProxy)
+	require.False(t, delegator.HasProxy())
+}
+
+func TestHandlerProxyUndelegateAll(t *testing.T) {
+	ctx, _, mockKeeper := CreateTestInput(t, false, SufficientInitPower)
+	keeper := mockKeeper.Keeper
+	_ = setInstantUnbondPeriod(keeper, ctx)
+	handler := NewHandler(keeper)
+	valAddr, proxyAddr, delAddr := sdk.ValAddress(Addrs[0]), Addrs[1], Addrs[2]
+	amount := sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(100))
+
+	require.True(t, handler(ctx, NewTestMsgCreateValidator(valAddr, PKs[0], DefaultValidInitMsd)).IsOK())
+	validator, found := keeper.GetValidator(ctx, valAddr)
+	require.True(t, found)
+	votesBefore := validator.DelegatorShares
+	for _, addr := range []sdk.AccAddress{proxyAddr, delAddr} {
+		require.True(t, handler(ctx, types.NewMsgDelegate(addr, amount)).IsOK())
+	}
+	require.True(t, handler(ctx, types.NewMsgRegProxy(proxyAddr, true)).IsOK())
+	require.True(t, handler(ctx, types.NewMsgBindProxy(delAddr, proxyAddr)).IsOK())
+	require.True(t, handler(ctx, types.NewMsgVote(proxyAddr, []sdk.ValAddress{valAddr})).IsOK())
+
+	// the proxy undelegating all its tokens leaves, releasing the delegators bound to it as the unregistration does
+	require.True(t, handler(ctx, types.NewMsgUndelegate(proxyAddr, amount)).IsOK())
+	_, found = keeper.GetDelegator(ctx, proxyAddr)
+	require.False(t, found)
+	require.Empty(t, keeper.GetDelegatorsByProxy(ctx, proxyAddr))
+	delegator, found := keeper.GetDelegator(ctx, delAddr)
+	require.True(t, found)
+	require.False(t, delegator.HasProxy())
+	validator, found = keeper.GetValidator(ctx, valAddr)
+	require.True(t, found)
+	require.True(t, votesBefore.Equal(validator.DelegatorShares))
+
+	// the delegator released votes by itself
+	require.True(t, handler(ctx, types.NewMsgVote(delAddr, []sdk.ValAddress{valAddr})).IsOK())
+}
@@ -16,10 +16,11 @@ func (k Keeper) IterateValidators(ctx sdk.Context, fn func(index int64, validato
 	store := ctx.KVStore(k.storeKey)
 	iterator := sdk.KVStorePrefixIterator(store, types.ValidatorsKey)
 	defer iterator.Close()
+	powerReduction := k.GetPowerReduction(ctx)
 	i := int64(0)
 	for ; iterator.Valid(); iterator.Next() {
 		validator := types.MustUnmarshalValidator(k.cdc, iterator.Value())
-		stop := fn(i, validator) // XXX is this safe will the validator unexposed fields be able to get written to?
+		stop := fn(i, types.NewValidatorWithPowerReduction(validator, powerReduction))
 		if stop {
 			break
 		}
@@ -36,13 +37,14 @@ func (k Keeper) IterateBondedValidatorsByPower(ctx sdk.Context,
 	iterator := sdk.KVStoreReversePrefixIterator(store, types.ValidatorsByPowerIndexKey)
 	defer iterator.Close()
 
+	powerReduction := k.GetPowerReduction(ctx)
 	i := int64(0)
 	for ; iterator.Valid() && i < int64(maxValidators); iterator.Next() {
 		address := iterator.Value()
 		validator := k.mustGetValidator(ctx, address)
 
 		if validator.IsBonded() {
-			stop := fn(i, validator) // XXX is this safe will the validator unexposed fields be able to get written to?
+			stop := fn(i, types.NewValidatorWithPowerReduction(validator, powerReduction))
 			if stop {
 				break
 			}
@@ -56,6 +58,7 @@ func (k Keeper) IterateLastValidators(ctx sdk.Context,
 	fn func(index int64, validator exported.ValidatorI) (stop bool)) {
 	iterator := k.LastValidatorsIterator(ctx)
 	defer iterator.Close()
+	powerReduction := k.GetPowerReduction(ctx)
 	i := int64(0)
 	for ; iterator.Valid(); iterator.Next() {
 		address := types.AddressFromLastValidatorPowerKey(iterator.Key())
@@ -64,7 +67,7 @@ func (k Keeper) IterateLastValidators(ctx sdk.Context,
 			panic(fmt.Sprintf("validator record not found for address: %v\n", address))
 		}
 
-		stop := fn(i, validator) // XXX is this safe will the validator unexposed fields be able to get written to?
+		stop := fn(i, types.NewValidatorWithPowerReduction(validator, powerReduction))
 		if stop {
 			break
 		}
@@ -78,7 +81,7 @@ func (k Keeper) Validator(ctx sdk.Context, address sdk.ValAddress) exported.Vali
 	if !found {
 		return nil
 	}
-	return val
+	return types.NewValidatorWithPowerReduction(val, k.GetPowerReduction(ctx))
 }
 
 // ValidatorByConsAddr gets the validator interface for a particular pubkey
@@ -87,7 +90,7 @@ func (k Keeper) ValidatorByConsAddr(ctx sdk.Context, addr sdk.ConsAddress) expor
 	if !found {
 		return nil
 	}
-	return val
+	return types.NewValidatorWithPowerReduction(val, k.GetPowerReduction(ctx))
 }
 
 // Delegator gets the DelegatorI interface for other module
@@ -1,6 +1,8 @@
 package keeper
 
 import (
+	"bytes"
+	"fmt"
 	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -27,94 +29,290 @@ func (k Keeper) UpdateProxy(ctx sdk.Context, delegator types.Delegator, tokens s
 	return sdk.ErrInvalidAddress(delegator.ProxyAddress.String())
 }
 
+// meetsMinDelegation checks whether the quantity of tokens to delegate reaches the param MinDelegation, allowing it to
+// fall short by no more than the param MinDelegationTolerance, which absorbs the rounding of the amounts converted from
+// the display units. The undelegations are checked against MinDelegation exactly. The min delegation limit is returned
+// for the error messages
+func (k Keeper) meetsMinDelegation(ctx sdk.Context, quantity sdk.Dec) (ok bool, minDelLimit sdk.Dec) {
+	minDelLimit = k.ParamsMinDelegation(ctx)
+	return quantity.Add(k.ParamsMinDelegationTolerance(ctx)).GTE(minDelLimit), minDelLimit
+}
+
 // Delegate handles the process of delegating
+// The coins in any bondable denom are converted into the tokens of the delegator by the weight of the denom. A change
+// of the weights applies to all the delegators at the end of the epoch, see ApplyBondDenoms
 func (k Keeper) Delegate(ctx sdk.Context, delAddr sdk.AccAddress, token sdk.DecCoin) sdk.Error {
+	return k.delegate(ctx, delAddr, token, false)
+}
 
-	delQuantity, minDelLimit := token.Amount, k.ParamsMinDelegation(ctx)
-	if delQuantity.LT(minDelLimit) {
-		return types.ErrInsufficientQuantity(types.DefaultCodespace, delQuantity.String(), minDelLimit.String())
+// delegate handles the process of delegating, taking the coins from the not bonded pool if they have been escrowed
+// there by DeferDelegation, or from the account of the delegator otherwise
+func (k Keeper) delegate(ctx sdk.Context, delAddr sdk.AccAddress, token sdk.DecCoin, escrowed bool) sdk.Error {
+	bondDenoms := k.ParamsBondDenoms(ctx)
+	weight, found := bondDenoms.Weight(token.Denom)
+	if !found {
+		return types.ErrBadDenom(types.DefaultCodespace)
 	}
 
-	// 1.transfer account's okt into bondPool
-	coins := token.ToCoins()
-	if err := k.supplyKeeper.DelegateCoinsFromAccountToModule(ctx, delAddr, types.BondedPoolName, coins); err != nil {
-		return err
+	delQuantity := token.Amount.Mul(weight)
+	if ok, minDelLimit := k.meetsMinDelegation(ctx, delQuantity); !ok {
+		return types.ErrInsufficientQuantity(types.DefaultCodespace, delQuantity.String(), minDelLimit.String())
 	}
 
-	// 2.get
+	// 1.get, a new delegator is rejected once the number of delegators reaches the limit
 	delegator, found := k.GetDelegator(ctx, delAddr)
 	if !found {
+		if maxDelegations := k.ParamsMaxDelegations(ctx); maxDelegations > 0 &&
+			k.GetDelegatorCount(ctx) >= maxDelegations {
+			return types.ErrMaxDelegationsReached(types.DefaultCodespace, maxDelegations)
+		}
 		delegator = types.NewDelegator(delAddr)
 	}
 
+	// 2.transfer account's or escrowed coins into bondPool
+	coins := token.ToCoins()
+	if escrowed {
+		k.notBondedTokensToBonded(ctx, coins)
+	} else if err := k.delegateToBondedPool(ctx, delAddr, coins); err != nil {
+		return err
+	}
+
 	// 3.update delegator
-	delegator.Tokens = delegator.Tokens.Add(delQuantity)
+	lastTokens := delegator.Tokens
+	delegator.DelegatedCoins = delegator.GetDelegatedCoins(bondDenoms.Primary()).Add(coins)
+	delegator.Tokens = bondDenoms.WeightedAmount(delegator.DelegatedCoins)
 	k.SetDelegator(ctx, delegator)
 
 	if delegator.HasProxy() {
 		//delegator have binded with some proxy, need update proxy's votes
-		return k.UpdateProxy(ctx, delegator, delQuantity)
+		return k.UpdateProxy(ctx, delegator, delegator.Tokens.Sub(lastTokens))
 
 	}
 	// 4.update votes when delAddr have voted already
 	return k.UpdateVotes(ctx, delegator.DelegatorAddress, delegator.Tokens)
 }
 
-// Undelegate handles the process of undelegating
-func (k Keeper) Undelegate(ctx sdk.Context, delAddr sdk.AccAddress, token sdk.DecCoin) (time.Time, sdk.Error) {
+// IsWithinEpochBoundaryGrace tells whether the current block is one of the last EpochBoundaryGrace blocks of the epoch
+func (k Keeper) IsWithinEpochBoundaryGrace(ctx sdk.Context) bool {
+	grace := k.ParamsEpochBoundaryGrace(ctx)
+	if grace == 0 {
+		return false
+	}
+	blocksLeft := k.GetTheEndOfLastEpoch(ctx) + int64(k.GetEpoch(ctx)) - ctx.BlockHeight()
+	return blocksLeft < int64(grace)
+}
+
+// DeferDelegation checks the delegation against the current state, escrows the coins into the not bonded pool, and
+// defers the delegation to the end of the epoch, so that it's applied after the validator set of the next epoch is
+// decided
+func (k Keeper) DeferDelegation(ctx sdk.Context, delAddr sdk.AccAddress, token sdk.DecCoin) sdk.Error {
+	cacheCtx, _ := ctx.CacheContext()
+	if err := k.Delegate(cacheCtx, delAddr, token); err != nil {
+		return err
+	}
+
+	if err := k.delegateToNotBondedPool(ctx, delAddr, token.ToCoins()); err != nil {
+		return err
+	}
+	k.SetPendingDelegations(ctx, append(k.GetPendingDelegations(ctx), types.NewPendingDelegation(delAddr, token)))
+	return nil
+}
+
+// GetPendingDelegations returns the delegations deferred to the end of the current epoch in the order received
+func (k Keeper) GetPendingDelegations(ctx sdk.Context) (pending []types.PendingDelegation) {
+	b := ctx.KVStore(k.storeKey).Get(types.PendingDelegationsKey)
+	if b == nil {
+		return
+	}
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(b, &pending)
+	return
+}
+
+// SetPendingDelegations sets the delegations deferred to the end of the current epoch into store
+func (k Keeper) SetPendingDelegations(ctx sdk.Context, pending []types.PendingDelegation) {
+	ctx.KVStore(k.storeKey).Set(types.PendingDelegationsKey, k.cdc.MustMarshalBinaryLengthPrefixed(pending))
+}
+
+// ApplyPendingDelegations applies the delegations deferred within the epoch boundary grace from the coins escrowed,
+// which is called once an epoch ends. A delegation no longer valid, e.g. for the delegator count reaching the limit
+// after it was received, is dropped and its coins are returned to the delegator
+func (k Keeper) ApplyPendingDelegations(ctx sdk.Context) {
+	for _, pending := range k.GetPendingDelegations(ctx) {
+		cacheCtx, write := ctx.CacheContext()
+		if err := k.delegate(cacheCtx, pending.DelegatorAddress, pending.Amount, true); err != nil {
+			ctx.Logger().Error(fmt.Sprintf("apply deferred delegation of %s failed and the coins are returned: %s",
+				pending.DelegatorAddress, err.Result().Log))
+			if err := k.undelegateFromNotBondedPool(ctx, pending.DelegatorAddress, pending.Amount.ToCoins()); err != nil {
+				panic(err)
+			}
+			k.RecordDelegatorAction(ctx, pending.DelegatorAddress, types.DelegatorActionDelegateDropped, pending.Amount,
+				nil)
+			continue
+		}
+		write()
+		k.RecordDelegatorAction(ctx, pending.DelegatorAddress, types.DelegatorActionDelegate, pending.Amount, nil)
+		ctx.EventManager().EmitEvent(
+			sdk.NewEvent(
+				types.EventTypeDelegate,
+				sdk.NewAttribute(types.AttributeKeyValidator, pending.DelegatorAddress.String()),
+				sdk.NewAttribute(sdk.AttributeKeyAmount, pending.Amount.String()),
+				sdk.NewAttribute(types.AttributeKeyMode, types.AttributeValueDeferred),
+			),
+		)
+	}
+	ctx.KVStore(k.storeKey).Delete(types.PendingDelegationsKey)
+}
+
+// ApplyBondDenoms converts the tokens of all the delegators by the bond denom weights changed within the epoch and
+// updates their votes, which is called once an epoch ends. A delegator whose votes can't be updated, e.g. for the vote
+// caps of the validators, keeps its last votes until its next delegation or undelegation
+func (k Keeper) ApplyBondDenoms(ctx sdk.Context) {
+	bondDenoms, appliedBondDenoms := k.ParamsBondDenoms(ctx), k.GetAppliedBondDenoms(ctx)
+	if bytes.Equal(k.cdc.MustMarshalBinaryLengthPrefixed(bondDenoms),
+		k.cdc.MustMarshalBinaryLengthPrefixed(appliedBondDenoms)) {
+		return
+	}
+	k.SetAppliedBondDenoms(ctx, bondDenoms)
+	// the weights applied are unknown to the store written by the earlier software, which converted the tokens by the
+	// current ones
+	if appliedBondDenoms == nil {
+		return
+	}
+
+	var delegators []types.Delegator
+	k.IterateDelegator(ctx, func(_ int64, delegator types.Delegator) (stop bool) {
+		delegators = append(delegators, delegator)
+		return false
+	})
+	for _, delegator := range delegators {
+		// the delegator might have been updated by its delegators converted earlier as a proxy
+		delegator, _ = k.GetDelegator(ctx, delegator.DelegatorAddress)
+		tokens := bondDenoms.WeightedAmount(delegator.GetDelegatedCoins(bondDenoms.Primary()))
+		if tokens.Equal(delegator.Tokens) {
+			continue
+		}
+
+		cacheCtx, write := ctx.CacheContext()
+		if err := k.updateDelegatorTokens(cacheCtx, delegator, tokens); err != nil {
+			ctx.Logger().Error(fmt.Sprintf("update votes of %s by the new bond denom weights failed: %s",
+				delegator.DelegatorAddress, err.Result().Log))
+			k.setDelegatorTokens(ctx, delegator, tokens)
+			continue
+		}
+		write()
+	}
+}
+
+// setDelegatorTokens sets the tokens of a delegator and the total delegated tokens of its proxy without updating any
+// votes
+func (k Keeper) setDelegatorTokens(ctx sdk.Context, delegator types.Delegator, tokens sdk.Dec) {
+	if delegator.HasProxy() {
+		if proxy, found := k.GetDelegator(ctx, delegator.ProxyAddress); found {
+			proxy.TotalDelegatedTokens = proxy.TotalDelegatedTokens.Add(tokens.Sub(delegator.Tokens))
+			k.SetDelegator(ctx, proxy)
+		}
+	}
+	delegator.Tokens = tokens
+	k.SetDelegator(ctx, delegator)
+}
+
+// updateDelegatorTokens sets the tokens of a delegator and updates the votes of it or its proxy
+func (k Keeper) updateDelegatorTokens(ctx sdk.Context, delegator types.Delegator, tokens sdk.Dec) sdk.Error {
+	lastTokens := delegator.Tokens
+	delegator.Tokens = tokens
+	k.SetDelegator(ctx, delegator)
+	if delegator.HasProxy() {
+		return k.UpdateProxy(ctx, delegator, tokens.Sub(lastTokens))
+	}
+	if delegator.IsProxy {
+		tokens = tokens.Add(delegator.TotalDelegatedTokens)
+	}
+	return k.UpdateVotes(ctx, delegator.DelegatorAddress, tokens)
+}
+
+// BeginUnbonding handles the process of undelegating and returns the undelegation info of the delegator,
+// whose completion time is the block time plus the current UnbondingTime. The completion time is stored as an absolute
+// time, so that a later change of UnbondingTime never reschedules the undelegations in flight. A further undelegation
+// of the delegator is added as a new entry, while the coins already unbonding keep completing at their own time
+func (k Keeper) BeginUnbonding(ctx sdk.Context, delAddr sdk.AccAddress, token sdk.DecCoin) (
+	undelegation types.UndelegationInfo, err sdk.Error) {
 	delegator, found := k.GetDelegator(ctx, delAddr)
 	if !found {
-		return time.Time{}, types.ErrNoDelegationVote(types.DefaultCodespace, delAddr.String())
+		return undelegation, types.ErrNoDelegationVote(types.DefaultCodespace, delAddr.String())
 	}
-	quantity, minDelLimit := token.Amount, k.ParamsMinDelegation(ctx)
-	if quantity.LT(minDelLimit) {
-		return time.Time{}, types.ErrInsufficientQuantity(types.DefaultCodespace, quantity.String(), minDelLimit.String())
-	} else if delegator.Tokens.LT(quantity) {
-		return time.Time{}, types.ErrInsufficientDelegation(types.DefaultCodespace, quantity.String(), delegator.Tokens.String())
+	bondDenoms := k.ParamsBondDenoms(ctx)
+	delegatedCoins := delegator.GetDelegatedCoins(bondDenoms.Primary())
+	// the coins in the denom which isn't bondable any more are weighted zero, but still able to be undelegated
+	weight, found := bondDenoms.Weight(token.Denom)
+	if !found && delegatedCoins.AmountOf(token.Denom).IsZero() {
+		return undelegation, types.ErrBadDenom(types.DefaultCodespace)
 	}
+	quantity := token.Amount.Mul(weight)
+	minDelLimit := k.ParamsMinDelegation(ctx)
+	if found && quantity.LT(minDelLimit) {
+		return undelegation, types.ErrInsufficientQuantity(types.DefaultCodespace, quantity.String(), minDelLimit.String())
+	} else if delegated := delegatedCoins.AmountOf(token.Denom); delegated.LT(token.Amount) {
+		return undelegation, types.ErrInsufficientDelegation(types.DefaultCodespace, token.Amount.String(), delegated.String())
+	}
+	// the tokens left must be either zero or no less than the min delegation limit
+	leftCoins := delegatedCoins.Sub(token.ToCoins())
+	leftTokens := bondDenoms.WeightedAmount(leftCoins)
+	if leftTokens.IsPositive() && leftTokens.LT(minDelLimit) {
+		return undelegation, types.ErrInsufficientRemainder(types.DefaultCodespace, leftTokens.String(), minDelLimit.String())
+	}
+
+	// the validators whose votes will be withdrawn, before the votes are changed
+	votedValAddrs := k.getVotedValidatorAddrs(ctx, delegator)
 
-	// 1.some okt transfer bondPool into unbondPool
+	// 1.some coins transfer bondPool into unbondPool
 	k.bondedTokensToNotBonded(ctx, token)
 
 	// 2.delete delegator in store, or set back
-	leftTokens := delegator.Tokens.Sub(quantity)
 	if delegator.HasProxy() {
-		if sdkErr := k.UpdateProxy(ctx, delegator, quantity.Mul(sdk.NewDec(-1))); sdkErr != nil {
-			return time.Time{}, sdkErr
+		if sdkErr := k.UpdateProxy(ctx, delegator, leftTokens.Sub(delegator.Tokens)); sdkErr != nil {
+			return undelegation, sdkErr
 		}
 	}
-	if leftTokens.IsZero() {
+	if leftCoins.IsZero() {
 		//withdraw all votes
 		lastVals, lastVotes := k.GetLastValsVotedExisted(ctx, delAddr)
+		for _, val := range lastVals {
+			k.BeforeDelegationRemoved(ctx, delAddr, val.OperatorAddress)
+		}
 		k.WithdrawLastVotes(ctx, delAddr, lastVals, lastVotes)
 		if delegator.HasProxy() {
 			k.SetProxyBinding(ctx, delegator.ProxyAddress, delAddr, true)
 		}
+		// a proxy leaving releases all the delegators bound to it, as the unregistration does
+		if delegator.IsProxy {
+			k.ClearProxy(ctx, delAddr)
+		}
 		k.DeleteDelegator(ctx, delAddr)
 	} else {
 		delegator.Tokens = leftTokens
+		delegator.DelegatedCoins = leftCoins
 		k.SetDelegator(ctx, delegator)
 		if !delegator.HasProxy() {
-			if err := k.UpdateVotes(ctx, delegator.DelegatorAddress, delegator.Tokens); err != nil {
-				return time.Time{}, err
+			if err = k.UpdateVotes(ctx, delegator.DelegatorAddress, delegator.Tokens); err != nil {
+				return undelegation, err
 			}
 		}
 	}
 
 	// 3.set undelegation and into store
 	completionTime := ctx.BlockHeader().Time.Add(k.UnbondingTime(ctx))
-	undelegation, found := k.GetUndelegating(ctx, delAddr)
+	undelegation, found = k.GetUndelegating(ctx, delAddr)
 	if !found {
-		undelegation = types.NewUndelegationInfo(delAddr, quantity, completionTime)
+		undelegation = types.NewUndelegationInfo(delAddr, quantity, token.ToCoins(), completionTime)
 	} else {
-		k.DeleteAddrByTimeKey(ctx, undelegation.CompletionTime, delAddr)
-		undelegation.Quantity = undelegation.Quantity.Add(quantity)
-		undelegation.CompletionTime = completionTime
+		// the coins already unbonding keep completing at their own time
+		undelegation.AddEntry(bondDenoms.Primary(), quantity, token.ToCoins(), completionTime)
 	}
+	undelegation.AddValidatorAddresses(votedValAddrs)
 	k.SetUndelegating(ctx, undelegation)
 	k.SetAddrByTimeKeyWithNilValue(ctx, completionTime, delAddr)
 
-	return completionTime, nil
+	return undelegation, nil
 }
 
 // GetUndelegating gets UndelegationInfo entity from store
@@ -129,34 +327,143 @@ func (k Keeper) GetUndelegating(ctx sdk.Context, delAddr sdk.AccAddress) (undele
 	return undelegationInfo, true
 }
 
-// SetUndelegating sets UndelegationInfo entity to store
+// SetUndelegating sets UndelegationInfo entity to store, and indexes it by the validators it withdrew votes from
 func (k Keeper) SetUndelegating(ctx sdk.Context, undelegationInfo types.UndelegationInfo) {
+	store := ctx.KVStore(k.storeKey)
 	key := types.GetUndelegationInfoKey(undelegationInfo.DelegatorAddress)
 	bytes := k.cdc.MustMarshalBinaryLengthPrefixed(undelegationInfo)
-	ctx.KVStore(k.storeKey).Set(key, bytes)
+	store.Set(key, bytes)
+	for _, valAddr := range undelegationInfo.ValidatorAddresses {
+		store.Set(types.GetUndelegationByValIndexKey(valAddr, undelegationInfo.DelegatorAddress), []byte{})
+	}
 }
 
-// DeleteUndelegating deletes UndelegationInfo from store
+// DeleteUndelegating deletes UndelegationInfo and its index from store
 func (k Keeper) DeleteUndelegating(ctx sdk.Context, delAddr sdk.AccAddress) {
-	ctx.KVStore(k.storeKey).Delete(types.GetUndelegationInfoKey(delAddr))
+	store := ctx.KVStore(k.storeKey)
+	if undelegationInfo, found := k.GetUndelegating(ctx, delAddr); found {
+		for _, valAddr := range undelegationInfo.ValidatorAddresses {
+			store.Delete(types.GetUndelegationByValIndexKey(valAddr, delAddr))
+		}
+	}
+	store.Delete(types.GetUndelegationInfoKey(delAddr))
+}
+
+// GetLastSelfUndelegationTime gets the time of the last self-undelegation of a validator
+func (k Keeper) GetLastSelfUndelegationTime(ctx sdk.Context, valAddr sdk.ValAddress) (lastTime time.Time, found bool) {
+	bytes := ctx.KVStore(k.storeKey).Get(types.GetLastSelfUndelegationKey(valAddr))
+	if bytes == nil {
+		return lastTime, false
+	}
+
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(bytes, &lastTime)
+	return lastTime, true
+}
+
+// SetLastSelfUndelegationTime sets the time of the last self-undelegation of a validator
+func (k Keeper) SetLastSelfUndelegationTime(ctx sdk.Context, valAddr sdk.ValAddress, lastTime time.Time) {
+	ctx.KVStore(k.storeKey).Set(types.GetLastSelfUndelegationKey(valAddr), k.cdc.MustMarshalBinaryLengthPrefixed(lastTime))
+}
+
+// RecordSelfUndelegation records the time of an undelegation of the operator of a validator, which is a
+// self-undelegation starting the cooldown. It does nothing to the delegators who aren't validators
+func (k Keeper) RecordSelfUndelegation(ctx sdk.Context, delAddr sdk.AccAddress) {
+	valAddr := sdk.ValAddress(delAddr)
+	if _, found := k.GetValidator(ctx, valAddr); found {
+		k.SetLastSelfUndelegationTime(ctx, valAddr, ctx.BlockTime())
+	}
+}
+
+// ValidateSelfUndelegateCooldown checks that the operator of a validator doesn't undelegate within the cooldown since
+// its last self-undelegation recorded by RecordSelfUndelegation. It does nothing to the delegators who aren't validators
+func (k Keeper) ValidateSelfUndelegateCooldown(ctx sdk.Context, delAddr sdk.AccAddress) sdk.Error {
+	cooldown := k.ParamsValidatorSelfUndelegateCooldown(ctx)
+	if cooldown <= 0 {
+		return nil
+	}
+
+	valAddr := sdk.ValAddress(delAddr)
+	if _, found := k.GetValidator(ctx, valAddr); !found {
+		return nil
+	}
+
+	lastTime, found := k.GetLastSelfUndelegationTime(ctx, valAddr)
+	if !found {
+		return nil
+	}
+
+	if nextTime := lastTime.Add(cooldown); ctx.BlockTime().Before(nextTime) {
+		return types.ErrSelfUndelegateCooldown(types.DefaultCodespace, valAddr.String(), nextTime)
+	}
+	return nil
+}
+
+// GetUnbondingDelegationsFromValidator returns all the pending undelegations which withdrew votes from a validator
+func (k Keeper) GetUnbondingDelegationsFromValidator(ctx sdk.Context, valAddr sdk.ValAddress) (
+	undelegationInfos []types.UndelegationInfo) {
+	store := ctx.KVStore(k.storeKey)
+	prefix := types.GetUndelegationsByValKey(valAddr)
+	iterator := sdk.KVStorePrefixIterator(store, prefix)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		delAddr := sdk.AccAddress(iterator.Key()[len(prefix):])
+		if undelegationInfo, found := k.GetUndelegating(ctx, delAddr); found {
+			undelegationInfos = append(undelegationInfos, undelegationInfo)
+		}
+	}
+	return
+}
+
+// getVotedValidatorAddrs returns the validators that the votes of a delegator go to, through its proxy if bound
+func (k Keeper) getVotedValidatorAddrs(ctx sdk.Context, delegator types.Delegator) []sdk.ValAddress {
+	if delegator.HasProxy() {
+		proxy, found := k.GetDelegator(ctx, delegator.ProxyAddress)
+		if !found {
+			return nil
+		}
+		return proxy.ValidatorAddresses
+	}
+	return delegator.ValidatorAddresses
 }
 
 // CompleteUndelegation handles the final process when the undelegation is completed
-func (k Keeper) CompleteUndelegation(ctx sdk.Context, delAddr sdk.AccAddress) (sdk.Dec, sdk.Error) {
+func (k Keeper) CompleteUndelegation(ctx sdk.Context, delAddr sdk.AccAddress) (sdk.DecCoins, sdk.Error) {
 	ud, found := k.GetUndelegating(ctx, delAddr)
 	if !found {
-		return sdk.NewDec(0), types.ErrNotInDelegating(k.Codespace(), delAddr.String())
+		return nil, types.ErrNotInDelegating(k.Codespace(), delAddr.String())
 	}
 
-	coin := sdk.NewDecCoinsFromDec(k.GetParams(ctx).BondDenom, ud.Quantity)
+	coins := ud.GetCoins(k.GetParamsCached(ctx).BondDenom)
 
-	err := k.supplyKeeper.UndelegateCoinsFromModuleToAccount(ctx, types.NotBondedPoolName, ud.DelegatorAddress, coin)
-	if err != nil {
-		return sdk.NewDec(0), err
+	if err := k.undelegateFromNotBondedPool(ctx, ud.DelegatorAddress, coins); err != nil {
+		return nil, err
 	}
 
 	k.DeleteUndelegating(ctx, delAddr)
-	return ud.Quantity, nil
+	return coins, nil
+}
+
+// CompleteMatureUndelegation completes the parts of the undelegation of a delegator which complete no later than the
+// completion time popped from the unbonding queue, and returns their coins
+func (k Keeper) CompleteMatureUndelegation(ctx sdk.Context, delAddr sdk.AccAddress, completionTime time.Time) (
+	sdk.DecCoins, sdk.Error) {
+	ud, found := k.GetUndelegating(ctx, delAddr)
+	if !found {
+		return nil, types.ErrNotInDelegating(k.Codespace(), delAddr.String())
+	}
+
+	coins, completed := ud.RemoveMatureEntries(k.GetParamsCached(ctx).BondDenom, completionTime)
+	if err := k.undelegateFromNotBondedPool(ctx, ud.DelegatorAddress, coins); err != nil {
+		return nil, err
+	}
+
+	if completed {
+		k.DeleteUndelegating(ctx, delAddr)
+	} else {
+		k.SetUndelegating(ctx, ud)
+	}
+	return coins, nil
 }
 
 // IterateUndelegationInfo iterates through all of the undelegation info
@@ -202,9 +509,182 @@ func (k Keeper) IterateKeysBeforeCurrentTime(ctx sdk.Context, currentTime time.T
 	}
 }
 
+// IterateUnbondingQueue iterates through the whole unbonding queue in the order of the completion time
+func (k Keeper) IterateUnbondingQueue(ctx sdk.Context,
+	fn func(completionTime time.Time, delAddr sdk.AccAddress) (stop bool)) {
+	iterator := sdk.KVStorePrefixIterator(ctx.KVStore(k.storeKey), types.UnDelegateQueueKey)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		if stop := fn(types.SplitCompleteTimeWithAddrKey(iterator.Key())); stop {
+			break
+		}
+	}
+}
+
+// GetUnbondingStats sums up the pending undelegations in the unbonding queue
+func (k Keeper) GetUnbondingStats(ctx sdk.Context) (stats types.UnbondingStats) {
+	primaryDenom := k.ParamsBondDenoms(ctx).Primary()
+	stats.TotalQuantity, stats.TotalCoins = sdk.ZeroDec(), sdk.DecCoins{}
+	k.IterateUnbondingQueue(ctx, func(completionTime time.Time, delAddr sdk.AccAddress) (stop bool) {
+		undelegation, found := k.GetUndelegating(ctx, delAddr)
+		if !found {
+			return false
+		}
+		// each part of the undelegation is queued at its own completion time
+		for _, entry := range undelegation.GetEntries(primaryDenom) {
+			if !entry.CompletionTime.Equal(completionTime) {
+				continue
+			}
+			if stats.Count == 0 {
+				stats.EarliestCompletionTime = completionTime
+			}
+			stats.LatestCompletionTime = completionTime
+			stats.Count++
+			stats.TotalQuantity = stats.TotalQuantity.Add(entry.Quantity)
+			stats.TotalCoins = stats.TotalCoins.Add(entry.Coins)
+		}
+		return false
+	})
+	return
+}
+
 // getAddrByTimeKeyIterator gets the iterator of keys from time 0 until endTime
 func (k Keeper) getAddrByTimeKeyIterator(ctx sdk.Context, endTime time.Time) sdk.Iterator {
 	store := ctx.KVStore(k.storeKey)
 	key := types.GetCompleteTimeKey(endTime)
 	return store.Iterator(types.UnDelegateQueueKey, sdk.PrefixEndBytes(key))
 }
+
+// ValidateSelfDelegationOnly checks whether all the target validators are operated by the voter in the
+// self-delegation-only mode
+func (k Keeper) ValidateSelfDelegationOnly(ctx sdk.Context, voterAddr sdk.AccAddress, vals types.Validators,
+) sdk.Error {
+	if !k.ParamsSelfDelegationOnly(ctx) {
+		return nil
+	}
+
+	for _, val := range vals {
+		if !val.OperatorAddress.Equals(sdk.ValAddress(voterAddr)) {
+			return types.ErrSelfDelegationOnly(types.DefaultCodespace, val.OperatorAddress.String())
+		}
+	}
+
+	return nil
+}
+
+// ValidateDelegationPolicy checks whether the target validators accept the voter with the tokens if it's new to them.
+// The voters voted last time are always allowed to keep on voting
+func (k Keeper) ValidateDelegationPolicy(ctx sdk.Context, vals, lastVals types.Validators, tokens sdk.Dec) sdk.Error {
+	lastValAddrs := make(map[string]bool, len(lastVals))
+	for _, val := range lastVals {
+		lastValAddrs[val.OperatorAddress.String()] = true
+	}
+
+	for _, val := range vals {
+		if lastValAddrs[val.OperatorAddress.String()] {
+			continue
+		}
+		if val.ClosedToDelegations {
+			return types.ErrValidatorNotAcceptingDelegations(types.DefaultCodespace, val.OperatorAddress.String())
+		}
+		if val.MaxDelegatorCount > 0 && k.GetValidatorVoterCount(ctx, val.OperatorAddress) >= val.MaxDelegatorCount {
+			return types.ErrValidatorDelegatorCountReached(types.DefaultCodespace, val.OperatorAddress.String(),
+				val.MaxDelegatorCount)
+		}
+		// the param MinDelegation has been met on delegating, so the validator's one binds only if it's higher
+		if !val.MinDelegation.IsNil() && tokens.LT(val.MinDelegation) {
+			return types.ErrBelowValidatorMinDelegation(types.DefaultCodespace, val.OperatorAddress.String(), tokens,
+				val.MinDelegation)
+		}
+	}
+
+	return nil
+}
+
+// CanDelegate checks whether a delegation of the amount followed by a vote to the validator would be accepted by all
+// the constraints on delegating and voting, without changing any state. It returns the first constraint violated
+func (k Keeper) CanDelegate(ctx sdk.Context, delAddr sdk.AccAddress, valAddr sdk.ValAddress, token sdk.DecCoin,
+) sdk.Error {
+	// constraints on the delegation
+	weight, found := k.ParamsBondDenoms(ctx).Weight(token.Denom)
+	if !found {
+		return types.ErrBadDenom(types.DefaultCodespace)
+	}
+	delQuantity := token.Amount.Mul(weight)
+	if ok, minDelLimit := k.meetsMinDelegation(ctx, delQuantity); !ok {
+		return types.ErrInsufficientQuantity(types.DefaultCodespace, delQuantity.String(), minDelLimit.String())
+	}
+	delegator, found := k.GetDelegator(ctx, delAddr)
+	if !found {
+		if maxDelegations := k.ParamsMaxDelegations(ctx); maxDelegations > 0 &&
+			k.GetDelegatorCount(ctx) >= maxDelegations {
+			return types.ErrMaxDelegationsReached(types.DefaultCodespace, maxDelegations)
+		}
+		delegator = types.NewDelegator(delAddr)
+	}
+
+	// constraints on the vote
+	if !k.HasMinValidators(ctx) {
+		return types.ErrNotEnoughValidators(types.DefaultCodespace, k.ParamsMinValidators(ctx))
+	}
+	if delegator.HasProxy() {
+		return types.ErrVoteDuringProxy(types.DefaultCodespace, delAddr.String(), delegator.ProxyAddress.String())
+	}
+	val, found := k.GetValidator(ctx, valAddr)
+	if !found {
+		return types.ErrNoValidatorFound(types.DefaultCodespace, valAddr.String())
+	}
+	if val.MinSelfDelegation.IsZero() {
+		return types.ErrVoteDismission(types.DefaultCodespace, valAddr.String())
+	}
+	lastVals, lastVotes := k.GetLastValsVotedExisted(ctx, delAddr)
+	votedAlready := k.HasDelegation(ctx, delAddr, valAddr)
+	if maxValsToVote := int(k.ParamsMaxValsToVote(ctx)); !votedAlready && len(lastVals)+1 > maxValsToVote {
+		return types.ErrExceedValidatorAddrs(types.DefaultCodespace, maxValsToVote)
+	}
+	if err := k.ValidateSelfDelegationOnly(ctx, delAddr, types.Validators{val}); err != nil {
+		return err
+	}
+	totalTokens := delegator.Tokens.Add(delegator.TotalDelegatedTokens).Add(delQuantity)
+	if err := k.ValidateDelegationPolicy(ctx, types.Validators{val}, lastVals, totalTokens); err != nil {
+		return err
+	}
+
+	// the votes of the validator grow by the votes of the delegator after the delegation, and so do the ones of all
+	// the validators voted together
+	votes, err := calculateWeight(ctx.BlockTime().Unix(), totalTokens)
+	if err != nil {
+		return err
+	}
+	valsToVote, extraVotes := len(lastVals)+1, votes
+	if votedAlready {
+		valsToVote, extraVotes = len(lastVals), votes.Sub(lastVotes)
+	}
+	if err := k.checkValidatorVotesCap(ctx, val, extraVotes); err != nil {
+		return err
+	}
+	totalExtraVotes := votes.MulInt64(int64(valsToVote)).Sub(lastVotes.MulInt64(int64(len(lastVals))))
+	return k.checkValidatorVoteShareCap(ctx, types.Validators{val}, extraVotes, totalExtraVotes)
+}
+
+// SharesFromTokens calculates the shares that a delegation of the amount would vote to the validator at the current
+// block time, without changing any state. The shares only depend on the tokens weighted by the time, so they're the
+// same for all the validators
+func (k Keeper) SharesFromTokens(ctx sdk.Context, valAddr sdk.ValAddress, token sdk.DecCoin,
+) (types.SharesFromTokensResponse, sdk.Error) {
+	weight, found := k.ParamsBondDenoms(ctx).Weight(token.Denom)
+	if !found {
+		return types.SharesFromTokensResponse{}, types.ErrBadDenom(types.DefaultCodespace)
+	}
+	if _, found := k.GetValidator(ctx, valAddr); !found {
+		return types.SharesFromTokensResponse{}, types.ErrNoValidatorFound(types.DefaultCodespace, valAddr.String())
+	}
+
+	tokens := token.Amount.Mul(weight)
+	shares, err := calculateWeight(ctx.BlockTime().Unix(), tokens)
+	if err != nil {
+		return types.SharesFromTokensResponse{}, err
+	}
+	return types.NewSharesFromTokensResponse(valAddr, tokens, shares), nil
+}
@@ -0,0 +1,644 @@
+package keeper
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/okex/okchain/x/staking/types"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+func TestBeginUnbonding(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mkeeper.Keeper
+	blockTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx = ctx.WithBlockTime(blockTime)
+	delAddr := addrDels[0]
+	minDelegation := keeper.ParamsMinDelegation(ctx)
+
+	// no delegation
+	_, err := keeper.BeginUnbonding(ctx, delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.OneDec()))
+	require.NotNil(t, err)
+
+	require.Nil(t, keeper.Delegate(ctx, delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(100))))
+
+	// quantity below the min delegation
+	_, err = keeper.BeginUnbonding(ctx, delAddr,
+		sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, minDelegation.QuoInt64(2)))
+	require.NotNil(t, err)
+
+	// remainder below the min delegation
+	_, err = keeper.BeginUnbonding(ctx, delAddr,
+		sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(100).Sub(minDelegation.QuoInt64(2))))
+	require.NotNil(t, err)
+
+	// more than the delegation
+	_, err = keeper.BeginUnbonding(ctx, delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(101)))
+	require.NotNil(t, err)
+
+	// completion time is the block time plus the unbonding time
+	undelegation, err := keeper.BeginUnbonding(ctx, delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(40)))
+	require.Nil(t, err)
+	require.Equal(t, blockTime.Add(keeper.UnbondingTime(ctx)), undelegation.CompletionTime)
+	require.True(t, undelegation.Quantity.Equal(sdk.NewDec(40)))
+	delegator, found := keeper.GetDelegator(ctx, delAddr)
+	require.True(t, found)
+	require.True(t, delegator.Tokens.Equal(sdk.NewDec(60)))
+
+	// the following unbonding postpones the completion time and accumulates the quantity
+	ctx = ctx.WithBlockTime(blockTime.Add(time.Hour))
+	undelegation, err = keeper.BeginUnbonding(ctx, delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(60)))
+	require.Nil(t, err)
+	require.Equal(t, blockTime.Add(time.Hour).Add(keeper.UnbondingTime(ctx)), undelegation.CompletionTime)
+	require.True(t, undelegation.Quantity.Equal(sdk.NewDec(100)))
+	stored, found := keeper.GetUndelegating(ctx, delAddr)
+	require.True(t, found)
+	require.Equal(t, undelegation.CompletionTime, stored.CompletionTime)
+
+	// unbonding all deletes the delegator
+	_, found = keeper.GetDelegator(ctx, delAddr)
+	require.False(t, found)
+}
+
+func TestDelegateMultiDenom(t *testing.T) {
+	ctx, accKeeper, mkeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mkeeper.Keeper
+	params := keeper.GetParams(ctx)
+	params.BondDenoms = append(params.BondDenoms, types.NewWeightedDenom("xxb", sdk.NewDecWithPrec(5, 1)))
+	keeper.SetParams(ctx, params)
+	vals := createVals(ctx, 2, keeper)
+	for i := range vals {
+		// no msd in the bonded pool
+		vals[i].MinSelfDelegation = sdk.ZeroDec()
+		keeper.SetValidator(ctx, vals[i])
+	}
+	delAddr, refAddr := addrDels[0], addrDels[1]
+	acc := accKeeper.GetAccount(ctx, delAddr)
+	require.NoError(t, acc.SetCoins(acc.GetCoins().Add(sdk.NewDecCoinsFromDec("xxb", sdk.NewDec(1000)))))
+	accKeeper.SetAccount(ctx, acc)
+
+	// the denom isn't bondable
+	require.NotNil(t, keeper.Delegate(ctx, delAddr, sdk.NewDecCoinFromDec("abc", sdk.NewDec(100))))
+
+	// the coins in each denom are weighted into the tokens
+	require.Nil(t, keeper.Delegate(ctx, delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(100))))
+	require.Nil(t, keeper.Delegate(ctx, delAddr, sdk.NewDecCoinFromDec("xxb", sdk.NewDec(100))))
+	delegator, found := keeper.GetDelegator(ctx, delAddr)
+	require.True(t, found)
+	require.True(t, delegator.Tokens.Equal(sdk.NewDec(150)))
+	require.True(t, delegator.DelegatedCoins.AmountOf(sdk.DefaultBondDenom).Equal(sdk.NewDec(100)))
+	require.True(t, delegator.DelegatedCoins.AmountOf("xxb").Equal(sdk.NewDec(100)))
+	_, broken := ModuleAccountInvariantsCustom(keeper)(ctx)
+	require.False(t, broken)
+
+	// the votes are the same as the ones by the equivalent tokens in the primary denom
+	require.Nil(t, keeper.Delegate(ctx, refAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(150))))
+	votes, err := keeper.VoteValidators(ctx, delAddr, vals[:1], delegator.Tokens)
+	require.Nil(t, err)
+	refDelegator, found := keeper.GetDelegator(ctx, refAddr)
+	require.True(t, found)
+	refVotes, err := keeper.VoteValidators(ctx, refAddr, getVals(ctx, vals[1:], keeper, t), refDelegator.Tokens)
+	require.Nil(t, err)
+	require.True(t, votes.Equal(refVotes))
+	vals = getVals(ctx, vals, keeper, t)
+	powerReduction := keeper.GetPowerReduction(ctx)
+	require.Equal(t, vals[1].PotentialConsensusPowerByVotes(powerReduction),
+		vals[0].PotentialConsensusPowerByVotes(powerReduction))
+
+	// undelegate in the secondary denom
+	_, err = keeper.BeginUnbonding(ctx, delAddr, sdk.NewDecCoinFromDec("xxb", sdk.NewDec(101)))
+	require.NotNil(t, err)
+	undelegation, err := keeper.BeginUnbonding(ctx, delAddr, sdk.NewDecCoinFromDec("xxb", sdk.NewDec(100)))
+	require.Nil(t, err)
+	require.True(t, undelegation.Quantity.Equal(sdk.NewDec(50)))
+	require.True(t, undelegation.Coins.IsEqual(sdk.NewDecCoinsFromDec("xxb", sdk.NewDec(100))))
+	delegator, found = keeper.GetDelegator(ctx, delAddr)
+	require.True(t, found)
+	require.True(t, delegator.Tokens.Equal(sdk.NewDec(100)))
+	_, broken = ModuleAccountInvariantsCustom(keeper)(ctx)
+	require.False(t, broken)
+
+	// the coins in each denom are returned
+	coins, err := keeper.CompleteUndelegation(ctx, delAddr)
+	require.Nil(t, err)
+	require.True(t, coins.IsEqual(undelegation.Coins))
+	require.True(t, accKeeper.GetAccount(ctx, delAddr).GetCoins().AmountOf("xxb").Equal(sdk.NewDec(1000)))
+	_, broken = ModuleAccountInvariantsCustom(keeper)(ctx)
+	require.False(t, broken)
+
+	// the coins in the denom which isn't bondable any more are weighted zero but still able to be undelegated
+	require.Nil(t, keeper.Delegate(ctx, delAddr, sdk.NewDecCoinFromDec("xxb", sdk.NewDec(100))))
+	params.BondDenoms = params.BondDenoms[:1]
+	keeper.SetParams(ctx, params)
+	require.NotNil(t, keeper.Delegate(ctx, delAddr, sdk.NewDecCoinFromDec("xxb", sdk.NewDec(100))))
+	undelegation, err = keeper.BeginUnbonding(ctx, delAddr, sdk.NewDecCoinFromDec("xxb", sdk.NewDec(100)))
+	require.Nil(t, err)
+	require.True(t, undelegation.Quantity.IsZero())
+	delegator, found = keeper.GetDelegator(ctx, delAddr)
+	require.True(t, found)
+	require.True(t, delegator.Tokens.Equal(sdk.NewDec(100)))
+	_, broken = ModuleAccountInvariantsCustom(keeper)(ctx)
+	require.False(t, broken)
+}
+
+func TestApplyBondDenoms(t *testing.T) {
+	ctx, accKeeper, mkeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mkeeper.Keeper
+	params := keeper.GetParams(ctx)
+	params.BondDenoms = append(params.BondDenoms, types.NewWeightedDenom("xxb", sdk.NewDecWithPrec(5, 1)))
+	keeper.SetParams(ctx, params)
+	keeper.SetAppliedBondDenoms(ctx, params.BondDenoms)
+	vals := createVals(ctx, 2, keeper)
+	delAddr, refAddr := addrDels[0], addrDels[1]
+	acc := accKeeper.GetAccount(ctx, delAddr)
+	require.NoError(t, acc.SetCoins(acc.GetCoins().Add(sdk.NewDecCoinsFromDec("xxb", sdk.NewDec(1000)))))
+	accKeeper.SetAccount(ctx, acc)
+	require.Nil(t, keeper.Delegate(ctx, delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(100))))
+	require.Nil(t, keeper.Delegate(ctx, delAddr, sdk.NewDecCoinFromDec("xxb", sdk.NewDec(100))))
+	votes, err := keeper.VoteValidators(ctx, delAddr, vals[:1], sdk.NewDec(150))
+	require.Nil(t, err)
+	delegator, found := keeper.GetDelegator(ctx, delAddr)
+	require.True(t, found)
+	delegator.ValidatorAddresses = []sdk.ValAddress{vals[0].OperatorAddress}
+	delegator.Shares = votes
+	keeper.SetDelegator(ctx, delegator)
+
+	// nothing changes until the weights change
+	keeper.ApplyBondDenoms(ctx)
+	delegator, found = keeper.GetDelegator(ctx, delAddr)
+	require.True(t, found)
+	require.True(t, delegator.Tokens.Equal(sdk.NewDec(150)))
+
+	// the tokens and the votes of the delegator who doesn't act again are converted by the new weights
+	params.BondDenoms[1].Weight = sdk.OneDec()
+	keeper.SetParams(ctx, params)
+	keeper.ApplyBondDenoms(ctx)
+	delegator, found = keeper.GetDelegator(ctx, delAddr)
+	require.True(t, found)
+	require.True(t, delegator.Tokens.Equal(sdk.NewDec(200)))
+	require.Equal(t, params.BondDenoms, keeper.GetAppliedBondDenoms(ctx))
+
+	// the votes are the same as the ones by the equivalent tokens in the primary denom
+	require.Nil(t, keeper.Delegate(ctx, refAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(200))))
+	refVotes, err := keeper.VoteValidators(ctx, refAddr, getVals(ctx, vals[1:], keeper, t), sdk.NewDec(200))
+	require.Nil(t, err)
+	require.True(t, refVotes.Equal(delegator.Shares))
+	vals = getVals(ctx, vals, keeper, t)
+	require.True(t, vals[0].DelegatorShares.Equal(vals[1].DelegatorShares))
+}
+
+func TestUnbondingTimeChangeMidFlight(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mkeeper.Keeper
+	blockTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx = ctx.WithBlockTime(blockTime)
+	params := keeper.GetParams(ctx)
+	params.UnbondingTime = time.Hour
+	keeper.SetParams(ctx, params)
+	oldAddr, newAddr := addrDels[0], addrDels[1]
+	for _, delAddr := range []sdk.AccAddress{oldAddr, newAddr} {
+		require.Nil(t, keeper.Delegate(ctx, delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(100))))
+	}
+	_, err := keeper.BeginUnbonding(ctx, oldAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(100)))
+	require.Nil(t, err)
+
+	// governance extends the unbonding time
+	params.UnbondingTime = 10 * time.Hour
+	keeper.SetParams(ctx, params)
+	_, err = keeper.BeginUnbonding(ctx, newAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(100)))
+	require.Nil(t, err)
+
+	// the undelegation in flight keeps its original completion time while the new one takes the new unbonding time
+	undelegation, found := keeper.GetUndelegating(ctx, oldAddr)
+	require.True(t, found)
+	require.Equal(t, blockTime.Add(time.Hour), undelegation.CompletionTime)
+	undelegation, found = keeper.GetUndelegating(ctx, newAddr)
+	require.True(t, found)
+	require.Equal(t, blockTime.Add(10*time.Hour), undelegation.CompletionTime)
+
+	// only the undelegation in flight matures on its original schedule
+	maturedAddrs := func(currentTime time.Time) (addrs []sdk.AccAddress) {
+		keeper.IterateKeysBeforeCurrentTime(ctx, currentTime, func(_ int64, key []byte) (stop bool) {
+			_, delAddr := types.SplitCompleteTimeWithAddrKey(key)
+			addrs = append(addrs, delAddr)
+			return false
+		})
+		return
+	}
+	require.Empty(t, maturedAddrs(blockTime.Add(time.Hour).Add(-time.Second)))
+	require.Equal(t, []sdk.AccAddress{oldAddr}, maturedAddrs(blockTime.Add(time.Hour)))
+	require.Equal(t, []sdk.AccAddress{oldAddr, newAddr}, maturedAddrs(blockTime.Add(10*time.Hour)))
+
+	// shortening the unbonding time doesn't bring forward the undelegation in flight either
+	params.UnbondingTime = time.Minute
+	keeper.SetParams(ctx, params)
+	require.Equal(t, []sdk.AccAddress{oldAddr}, maturedAddrs(blockTime.Add(time.Hour)))
+
+	// a delegator undelegating again under the new unbonding time keeps the original schedule of the part in flight
+	delAddr := addrDels[2]
+	params.UnbondingTime = time.Hour
+	keeper.SetParams(ctx, params)
+	require.Nil(t, keeper.Delegate(ctx, delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(100))))
+	_, err = keeper.BeginUnbonding(ctx, delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(40)))
+	require.Nil(t, err)
+	params.UnbondingTime = 10 * time.Hour
+	keeper.SetParams(ctx, params)
+	_, err = keeper.BeginUnbonding(ctx, delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(60)))
+	require.Nil(t, err)
+	undelegation, found = keeper.GetUndelegating(ctx, delAddr)
+	require.True(t, found)
+	require.Equal(t, blockTime.Add(10*time.Hour), undelegation.CompletionTime)
+	require.Equal(t, sdk.NewDec(100), undelegation.Quantity)
+
+	completeMatured := func(currentTime time.Time) (coins sdk.DecCoins) {
+		keeper.IterateKeysBeforeCurrentTime(ctx, currentTime, func(_ int64, key []byte) (stop bool) {
+			completionTime, addr := types.SplitCompleteTimeWithAddrKey(key)
+			if !addr.Equals(delAddr) {
+				return false
+			}
+			keeper.DeleteAddrByTimeKey(ctx, completionTime, addr)
+			completed, err := keeper.CompleteMatureUndelegation(ctx, addr, completionTime)
+			require.Nil(t, err)
+			coins = coins.Add(completed)
+			return false
+		})
+		return
+	}
+	require.True(t, completeMatured(blockTime.Add(time.Hour).Add(-time.Second)).IsZero())
+	require.Equal(t, sdk.NewDecCoinsFromDec(sdk.DefaultBondDenom, sdk.NewDec(40)), completeMatured(blockTime.Add(time.Hour)))
+	undelegation, found = keeper.GetUndelegating(ctx, delAddr)
+	require.True(t, found)
+	require.Equal(t, sdk.NewDec(60), undelegation.Quantity)
+	require.Equal(t, blockTime.Add(10*time.Hour), undelegation.CompletionTime)
+	require.Empty(t, undelegation.Entries)
+
+	require.Equal(t, sdk.NewDecCoinsFromDec(sdk.DefaultBondDenom, sdk.NewDec(60)), completeMatured(blockTime.Add(10*time.Hour)))
+	_, found = keeper.GetUndelegating(ctx, delAddr)
+	require.False(t, found)
+}
+
+func TestMaxDelegations(t *testing.T) {
+	ctx, accKeeper, mkeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mkeeper.Keeper
+	params := keeper.GetParams(ctx)
+	params.MaxDelegations = 2
+	keeper.SetParams(ctx, params)
+	token := sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(100))
+
+	for _, delAddr := range addrDels[:2] {
+		require.Nil(t, keeper.Delegate(ctx, delAddr, token))
+	}
+	require.Equal(t, uint64(2), keeper.GetDelegatorCount(ctx))
+
+	// a new delegator is rejected without any coins transferred
+	balance := accKeeper.GetAccount(ctx, addrDels[2]).GetCoins()
+	err := keeper.Delegate(ctx, addrDels[2], token)
+	require.NotNil(t, err)
+	require.Equal(t, types.CodeInvalidDelegation, err.Code())
+	require.True(t, balance.IsEqual(accKeeper.GetAccount(ctx, addrDels[2]).GetCoins()))
+	require.Equal(t, uint64(2), keeper.GetDelegatorCount(ctx))
+
+	// the existing delegators are still able to add to their delegations
+	require.Nil(t, keeper.Delegate(ctx, addrDels[0], token))
+	require.Equal(t, uint64(2), keeper.GetDelegatorCount(ctx))
+
+	// a delegator unbonding all leaves the room for a new one
+	_, err = keeper.BeginUnbonding(ctx, addrDels[1], token)
+	require.Nil(t, err)
+	require.Equal(t, uint64(1), keeper.GetDelegatorCount(ctx))
+	require.Nil(t, keeper.Delegate(ctx, addrDels[2], token))
+	require.Equal(t, uint64(2), keeper.GetDelegatorCount(ctx))
+
+	// no limit
+	params.MaxDelegations = 0
+	keeper.SetParams(ctx, params)
+	require.Nil(t, keeper.Delegate(ctx, addrDels[1], token))
+	require.Equal(t, uint64(3), keeper.GetDelegatorCount(ctx))
+}
+
+// delegationHooksRecorder records the delegations removed through the staking hooks
+type delegationHooksRecorder struct {
+	mockDistributionKeeper
+	removed map[string]int
+}
+
+func (h delegationHooksRecorder) BeforeDelegationRemoved(_ sdk.Context, delAddr sdk.AccAddress,
+	valAddr sdk.ValAddress) {
+	h.removed[delAddr.String()+"/"+valAddr.String()]++
+}
+
+func TestMinDelegationTolerance(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mkeeper.Keeper
+	delAddr := addrDels[0]
+	minDelegation := keeper.ParamsMinDelegation(ctx)
+	delegate := func(amount sdk.Dec) sdk.Error {
+		return keeper.Delegate(ctx, delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, amount))
+	}
+
+	// the check is exact without the tolerance
+	require.Nil(t, delegate(minDelegation))
+	require.NotNil(t, delegate(minDelegation.Sub(sdk.NewDecWithPrec(1, sdk.Precision))))
+
+	tolerance := sdk.NewDecWithPrec(1, 6)
+	params := keeper.GetParams(ctx)
+	params.MinDelegationTolerance = tolerance
+	keeper.SetParams(ctx, params)
+
+	// exactly at the floor and within the tolerance
+	require.Nil(t, delegate(minDelegation))
+	require.Nil(t, delegate(minDelegation.Sub(sdk.NewDecWithPrec(1, sdk.Precision))))
+	require.Nil(t, delegate(minDelegation.Sub(tolerance)))
+
+	// just under the floor beyond the tolerance
+	require.NotNil(t, delegate(minDelegation.Sub(tolerance).Sub(sdk.NewDecWithPrec(1, sdk.Precision))))
+
+	// the tolerance doesn't apply to the undelegations
+	_, err := keeper.BeginUnbonding(ctx, delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom,
+		minDelegation.Sub(tolerance)))
+	require.NotNil(t, err)
+	_, err = keeper.BeginUnbonding(ctx, delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, minDelegation))
+	require.Nil(t, err)
+}
+
+func TestBeforeDelegationRemovedHook(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mkeeper.Keeper
+	recorder := delegationHooksRecorder{removed: make(map[string]int)}
+	keeper.hooks = types.NewMultiStakingHooks(recorder)
+	vals := createVals(ctx, 2, keeper)
+	delAddr := addrDels[0]
+
+	// vote for the validators
+	require.Nil(t, keeper.Delegate(ctx, delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(100))))
+	delegator, found := keeper.GetDelegator(ctx, delAddr)
+	require.True(t, found)
+	votes, err := keeper.VoteValidators(ctx, delAddr, vals, delegator.Tokens)
+	require.Nil(t, err)
+	delegator.ValidatorAddresses = []sdk.ValAddress{vals[0].OperatorAddress, vals[1].OperatorAddress}
+	delegator.Shares = votes
+	keeper.SetDelegator(ctx, delegator)
+
+	// partial undelegation doesn't fire the hook
+	_, err = keeper.BeginUnbonding(ctx, delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(40)))
+	require.Nil(t, err)
+	require.Empty(t, recorder.removed)
+
+	// full undelegation fires the hook exactly once for each validator voted
+	_, err = keeper.BeginUnbonding(ctx, delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(60)))
+	require.Nil(t, err)
+	require.Equal(t, map[string]int{
+		delAddr.String() + "/" + vals[0].OperatorAddress.String(): 1,
+		delAddr.String() + "/" + vals[1].OperatorAddress.String(): 1,
+	}, recorder.removed)
+
+	// destroying a validator removes the self delegation
+	for k := range recorder.removed {
+		delete(recorder.removed, k)
+	}
+	ownerAddr := sdk.AccAddress(vals[0].OperatorAddress)
+	validator := keeper.mustGetValidator(ctx, vals[0].OperatorAddress)
+	require.Nil(t, keeper.VoteMinSelfDelegation(ctx, ownerAddr, &validator,
+		sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, validator.MinSelfDelegation)))
+	_, err = keeper.UndelegateMinSelfDelegation(ctx, ownerAddr, validator)
+	require.Nil(t, err)
+	require.Equal(t, map[string]int{ownerAddr.String() + "/" + validator.OperatorAddress.String(): 1},
+		recorder.removed)
+}
+
+// validatorModifiedRecorder records the votes of the validators seen by the hook BeforeValidatorModified
+type validatorModifiedRecorder struct {
+	mockDistributionKeeper
+	keeper Keeper
+	shares map[string][]sdk.Dec
+}
+
+func (h validatorModifiedRecorder) BeforeValidatorModified(ctx sdk.Context, valAddr sdk.ValAddress) {
+	validator := h.keeper.mustGetValidator(ctx, valAddr)
+	h.shares[valAddr.String()] = append(h.shares[valAddr.String()], validator.DelegatorShares)
+}
+
+func TestBeforeValidatorModifiedHook(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mkeeper.Keeper
+	recorder := validatorModifiedRecorder{keeper: keeper, shares: make(map[string][]sdk.Dec)}
+	keeper.hooks = types.NewMultiStakingHooks(recorder)
+	vals := createVals(ctx, 1, keeper)
+	valAddr := vals[0].OperatorAddress
+	delAddr := addrDels[0]
+	// the hook must have seen the votes before each change, which are the ones after the previous change
+	requireSeenBefore := func(expected ...sdk.Dec) {
+		seen := recorder.shares[valAddr.String()]
+		require.Equal(t, len(expected), len(seen))
+		for i := range expected {
+			require.True(t, expected[i].Equal(seen[i]), "expected %s, got %s", expected[i], seen[i])
+		}
+	}
+
+	// no vote changes without any validator voted
+	require.Nil(t, keeper.Delegate(ctx, delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(100))))
+	requireSeenBefore()
+
+	// vote
+	delegator, found := keeper.GetDelegator(ctx, delAddr)
+	require.True(t, found)
+	votes, err := keeper.VoteValidators(ctx, delAddr, vals, delegator.Tokens)
+	require.Nil(t, err)
+	delegator.ValidatorAddresses = []sdk.ValAddress{valAddr}
+	delegator.Shares = votes
+	keeper.SetDelegator(ctx, delegator)
+	requireSeenBefore(sdk.ZeroDec())
+	votedShares := keeper.mustGetValidator(ctx, valAddr).DelegatorShares
+
+	// delegate more
+	require.Nil(t, keeper.Delegate(ctx, delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(100))))
+	requireSeenBefore(sdk.ZeroDec(), votedShares)
+	delegatedShares := keeper.mustGetValidator(ctx, valAddr).DelegatorShares
+	require.True(t, delegatedShares.GT(votedShares))
+
+	// undelegate all
+	_, err = keeper.BeginUnbonding(ctx, delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(200)))
+	require.Nil(t, err)
+	requireSeenBefore(sdk.ZeroDec(), votedShares, delegatedShares)
+}
+
+func TestGetUnbondingDelegationsFromValidator(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mkeeper.Keeper
+	vals := createVals(ctx, 2, keeper)
+	ownerAddr := sdk.AccAddress(vals[0].OperatorAddress)
+
+	delegateAndVote := func(delAddr sdk.AccAddress, vals types.Validators) {
+		require.Nil(t, keeper.Delegate(ctx, delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(100))))
+		delegator, found := keeper.GetDelegator(ctx, delAddr)
+		require.True(t, found)
+		for i, val := range vals {
+			vals[i] = keeper.mustGetValidator(ctx, val.OperatorAddress)
+			delegator.ValidatorAddresses = append(delegator.ValidatorAddresses, val.OperatorAddress)
+		}
+		votes, err := keeper.VoteValidators(ctx, delAddr, vals, delegator.Tokens)
+		require.Nil(t, err)
+		delegator.Shares = votes
+		keeper.SetDelegator(ctx, delegator)
+	}
+	delegateAndVote(addrDels[0], vals[:1])
+	delegateAndVote(addrDels[1], vals[:1])
+	delegateAndVote(addrDels[2], vals[1:])
+	delegateAndVote(ownerAddr, vals[:1])
+
+	// pending unbondings of multiple delegators
+	_, err := keeper.BeginUnbonding(ctx, addrDels[0], sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(40)))
+	require.Nil(t, err)
+	_, err = keeper.BeginUnbonding(ctx, addrDels[1], sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(100)))
+	require.Nil(t, err)
+	_, err = keeper.BeginUnbonding(ctx, addrDels[2], sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(40)))
+	require.Nil(t, err)
+	_, err = keeper.BeginUnbonding(ctx, ownerAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(50)))
+	require.Nil(t, err)
+
+	getDelAddrs := func(valAddr sdk.ValAddress) (delAddrs []sdk.AccAddress) {
+		for _, ud := range keeper.GetUnbondingDelegationsFromValidator(ctx, valAddr) {
+			delAddrs = append(delAddrs, ud.DelegatorAddress)
+		}
+		return
+	}
+	require.ElementsMatch(t, []sdk.AccAddress{addrDels[0], addrDels[1], ownerAddr}, getDelAddrs(vals[0].OperatorAddress))
+	require.ElementsMatch(t, []sdk.AccAddress{addrDels[2]}, getDelAddrs(vals[1].OperatorAddress))
+
+	// destroying the validator merges the msd into the pending unbonding of the owner
+	validator := keeper.mustGetValidator(ctx, vals[0].OperatorAddress)
+	msd := validator.MinSelfDelegation
+	require.Nil(t, keeper.VoteMinSelfDelegation(ctx, ownerAddr, &validator,
+		sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, msd)))
+	_, err = keeper.UndelegateMinSelfDelegation(ctx, ownerAddr, validator)
+	require.Nil(t, err)
+	undelegation, found := keeper.GetUndelegating(ctx, ownerAddr)
+	require.True(t, found)
+	require.True(t, undelegation.Quantity.Equal(sdk.NewDec(50).Add(msd)))
+	require.Equal(t, []sdk.ValAddress{vals[0].OperatorAddress}, undelegation.ValidatorAddresses)
+	require.ElementsMatch(t, []sdk.AccAddress{addrDels[0], addrDels[1], ownerAddr}, getDelAddrs(vals[0].OperatorAddress))
+
+	// the completed unbonding leaves the index
+	_, err = keeper.CompleteUndelegation(ctx, addrDels[1])
+	require.Nil(t, err)
+	require.ElementsMatch(t, []sdk.AccAddress{addrDels[0], ownerAddr}, getDelAddrs(vals[0].OperatorAddress))
+	_, err = keeper.CompleteUndelegation(ctx, ownerAddr)
+	require.Nil(t, err)
+	require.ElementsMatch(t, []sdk.AccAddress{addrDels[0]}, getDelAddrs(vals[0].OperatorAddress))
+}
+
+func TestGetUnbondingStats(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mkeeper.Keeper
+	unbondingTime := keeper.UnbondingTime(ctx)
+	blockTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	unbond := func(delAddr sdk.AccAddress, amount int64, hours int) {
+		ctx = ctx.WithBlockTime(blockTime.Add(time.Duration(hours) * time.Hour))
+		_, err := keeper.BeginUnbonding(ctx, delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(amount)))
+		require.Nil(t, err)
+	}
+
+	// empty queue
+	stats := keeper.GetUnbondingStats(ctx)
+	require.Equal(t, int64(0), stats.Count)
+	require.True(t, stats.TotalQuantity.IsZero())
+	require.True(t, stats.TotalCoins.IsZero())
+
+	delAddrs := Addrs[20:24]
+	for _, delAddr := range delAddrs {
+		require.Nil(t, keeper.Delegate(ctx, delAddr, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(100))))
+	}
+
+	// staggered completions, not in the order of the delegators
+	unbond(delAddrs[2], 10, 0)
+	unbond(delAddrs[0], 20, 1)
+	unbond(delAddrs[3], 30, 2)
+	stats = keeper.GetUnbondingStats(ctx)
+	require.Equal(t, int64(3), stats.Count)
+	require.Equal(t, sdk.NewDec(60), stats.TotalQuantity)
+	require.Equal(t, sdk.NewDecCoinsFromDec(sdk.DefaultBondDenom, sdk.NewDec(60)), stats.TotalCoins)
+	require.Equal(t, blockTime.Add(unbondingTime), stats.EarliestCompletionTime)
+	require.Equal(t, blockTime.Add(2*time.Hour).Add(unbondingTime), stats.LatestCompletionTime)
+
+	// a further undelegation of the delegator is queued as a new entry
+	unbond(delAddrs[2], 15, 3)
+	stats = keeper.GetUnbondingStats(ctx)
+	require.Equal(t, int64(4), stats.Count)
+	require.Equal(t, sdk.NewDec(75), stats.TotalQuantity)
+	require.Equal(t, blockTime.Add(unbondingTime), stats.EarliestCompletionTime)
+	require.Equal(t, blockTime.Add(3*time.Hour).Add(unbondingTime), stats.LatestCompletionTime)
+
+	// the completed entries leave the queue
+	ctx = ctx.WithBlockTime(blockTime.Add(time.Hour).Add(unbondingTime))
+	keeper.IterateKeysBeforeCurrentTime(ctx, ctx.BlockHeader().Time, func(_ int64, key []byte) (stop bool) {
+		completionTime, delAddr := types.SplitCompleteTimeWithAddrKey(key)
+		keeper.DeleteAddrByTimeKey(ctx, completionTime, delAddr)
+		_, err := keeper.CompleteMatureUndelegation(ctx, delAddr, completionTime)
+		require.Nil(t, err)
+		return false
+	})
+	stats = keeper.GetUnbondingStats(ctx)
+	require.Equal(t, int64(2), stats.Count)
+	require.Equal(t, sdk.NewDec(45), stats.TotalQuantity)
+	require.Equal(t, blockTime.Add(2*time.Hour).Add(unbondingTime), stats.EarliestCompletionTime)
+
+	// query
+	data, err := NewQuerier(keeper)(ctx, []string{types.QueryUnbondingStats}, abci.RequestQuery{})
+	require.Nil(t, err)
+	var queried types.UnbondingStats
+	require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &queried))
+	require.Equal(t, stats.Count, queried.Count)
+	require.True(t, stats.TotalQuantity.Equal(queried.TotalQuantity))
+	require.True(t, stats.LatestCompletionTime.Equal(queried.LatestCompletionTime))
+}
+
+func TestMigrateDelegatedCoins(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, 0)
+	keeper := mkeeper.Keeper
+	bondDenom := keeper.BondDenom(ctx)
+
+	// store the delegators out of the address order, the even ones in the legacy form without the coins
+	var expMigrated []sdk.AccAddress
+	for _, i := range []int{7, 2, 9, 0, 4, 1, 8, 3, 6, 5} {
+		delegator := types.NewDelegator(Addrs[i])
+		delegator.Tokens = sdk.NewDec(int64(i + 1))
+		if i%2 == 1 {
+			delegator.DelegatedCoins = sdk.NewDecCoinsFromDec(bondDenom, delegator.Tokens)
+		} else {
+			expMigrated = append(expMigrated, Addrs[i])
+		}
+		keeper.SetDelegator(ctx, delegator)
+	}
+	// no tokens, nothing to backfill
+	keeper.SetDelegator(ctx, types.NewDelegator(Addrs[10]))
+	sortAddrs := func(addrs []sdk.AccAddress) {
+		sort.Slice(addrs, func(i, j int) bool { return bytes.Compare(addrs[i], addrs[j]) < 0 })
+	}
+	sortAddrs(expMigrated)
+
+	// all the delegators are visited in the order of their addresses
+	var visited []sdk.AccAddress
+	keeper.IterateDelegator(ctx, func(_ int64, delegator types.Delegator) (stop bool) {
+		visited = append(visited, delegator.DelegatorAddress)
+		return false
+	})
+	require.Equal(t, 11, len(visited))
+	require.True(t, sort.SliceIsSorted(visited, func(i, j int) bool { return bytes.Compare(visited[i], visited[j]) < 0 }))
+
+	require.Equal(t, expMigrated, keeper.MigrateDelegatedCoins(ctx))
+	for i := 0; i < 10; i++ {
+		delegator, found := keeper.GetDelegator(ctx, Addrs[i])
+		require.True(t, found)
+		require.Equal(t, sdk.NewDecCoinsFromDec(bondDenom, sdk.NewDec(int64(i+1))), delegator.DelegatedCoins)
+	}
+	delegator, _ := keeper.GetDelegator(ctx, Addrs[10])
+	require.True(t, delegator.DelegatedCoins.Empty())
+	require.Equal(t, uint64(11), keeper.GetDelegatorCount(ctx))
+
+	// running it again changes nothing
+	require.Empty(t, keeper.MigrateDelegatedCoins(ctx))
+}
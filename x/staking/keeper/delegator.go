@@ -1,7 +1,9 @@
 package keeper
 
 import (
+	"github.com/cosmos/cosmos-sdk/client"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/okex/okchain/x/staking/exported"
 	"github.com/okex/okchain/x/staking/types"
 )
 
@@ -18,14 +20,51 @@ func (k Keeper) GetDelegator(ctx sdk.Context, delAddr sdk.AccAddress) (delegator
 
 // SetDelegator sets Delegator info to store
 func (k Keeper) SetDelegator(ctx sdk.Context, delegator types.Delegator) {
+	store := ctx.KVStore(k.storeKey)
 	key := types.GetDelegatorKey(delegator.DelegatorAddress)
+	if !store.Has(key) {
+		k.setDelegatorCount(ctx, k.GetDelegatorCount(ctx)+1)
+	}
 	bytes := k.cdc.MustMarshalBinaryLengthPrefixed(delegator)
-	ctx.KVStore(k.storeKey).Set(key, bytes)
+	store.Set(key, bytes)
 }
 
 // DeleteDelegator deletes Delegator info from store
 func (k Keeper) DeleteDelegator(ctx sdk.Context, delAddr sdk.AccAddress) {
-	ctx.KVStore(k.storeKey).Delete(types.GetDelegatorKey(delAddr))
+	store := ctx.KVStore(k.storeKey)
+	key := types.GetDelegatorKey(delAddr)
+	// the count never goes below zero, even if it's out of sync with the delegators in store
+	if count := k.GetDelegatorCount(ctx); store.Has(key) && count > 0 {
+		k.setDelegatorCount(ctx, count-1)
+	}
+	store.Delete(key)
+}
+
+// GetDelegatorCount gets the total number of delegators on the chain
+func (k Keeper) GetDelegatorCount(ctx sdk.Context) (count uint64) {
+	bytes := ctx.KVStore(k.storeKey).Get(types.DelegatorCountKey)
+	if bytes == nil {
+		return 0
+	}
+
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(bytes, &count)
+	return
+}
+
+// setDelegatorCount sets the total number of delegators to store
+func (k Keeper) setDelegatorCount(ctx sdk.Context, count uint64) {
+	ctx.KVStore(k.storeKey).Set(types.DelegatorCountKey, k.cdc.MustMarshalBinaryLengthPrefixed(count))
+}
+
+// countDelegators counts the delegators in store and sets the total number of delegators with it, which was never
+// maintained by the earlier software
+func (k Keeper) countDelegators(ctx sdk.Context) (count uint64) {
+	k.IterateDelegator(ctx, func(_ int64, _ types.Delegator) (stop bool) {
+		count++
+		return false
+	})
+	k.setDelegatorCount(ctx, count)
+	return
 }
 
 // IterateDelegator iterates through all of the delegators info from the store
@@ -43,3 +82,217 @@ func (k Keeper) IterateDelegator(ctx sdk.Context, fn func(index int64, delegator
 		i++
 	}
 }
+
+// MigrateDelegatedCoins backfills the delegated coins of the delegators stored by the earlier versions, which only
+// delegated in the primary bondable denom, so that every delegator carries its coins explicitly afterwards. It visits
+// all the delegators in the order of their addresses and returns the addresses of the ones migrated
+func (k Keeper) MigrateDelegatedCoins(ctx sdk.Context) (migrated []sdk.AccAddress) {
+	primaryDenom := k.BondDenom(ctx)
+	var delegators []types.Delegator
+	k.IterateDelegator(ctx, func(_ int64, delegator types.Delegator) (stop bool) {
+		if delegator.DelegatedCoins.Empty() && delegator.Tokens.IsPositive() {
+			delegators = append(delegators, delegator)
+		}
+		return false
+	})
+
+	// write after the iteration is closed
+	for _, delegator := range delegators {
+		delegator.DelegatedCoins = delegator.GetDelegatedCoins(primaryDenom)
+		k.SetDelegator(ctx, delegator)
+		migrated = append(migrated, delegator.DelegatorAddress)
+	}
+	return
+}
+
+// GetDelegatorPortfolio gets the delegation, the votes and the undelegation of a delegator altogether
+func (k Keeper) GetDelegatorPortfolio(ctx sdk.Context, delAddr sdk.AccAddress) (portfolio types.DelegatorPortfolio,
+	found bool) {
+	delegator, delegatorFound := k.GetDelegator(ctx, delAddr)
+	undelegation, undelegationFound := k.GetUndelegating(ctx, delAddr)
+	if !delegatorFound && !undelegationFound {
+		return portfolio, false
+	}
+
+	if !delegatorFound {
+		delegator = types.NewDelegator(delAddr)
+	}
+	if !undelegationFound {
+		undelegation = types.DefaultUndelegation()
+	}
+
+	votes := make([]types.VoteToValidator, 0, len(delegator.ValidatorAddresses))
+	for _, valAddr := range delegator.ValidatorAddresses {
+		if vote, voteFound := k.GetVote(ctx, delAddr, valAddr); voteFound {
+			votes = append(votes, types.NewVoteToValidator(valAddr, vote))
+		}
+	}
+
+	return types.NewDelegatorPortfolio(delegator, votes, undelegation), true
+}
+
+// GetDelegatorDelegations gets a page of the votes made by a delegator together with the number of all the validators
+// it votes for. Only the votes on the page are read from the store
+func (k Keeper) GetDelegatorDelegations(ctx sdk.Context, delAddr sdk.AccAddress, page, limit int) (
+	votes []types.VoteToValidator, total int) {
+	delegator, found := k.GetDelegator(ctx, delAddr)
+	if !found {
+		return []types.VoteToValidator{}, 0
+	}
+
+	total = len(delegator.ValidatorAddresses)
+	start, end := client.Paginate(total, page, limit, int(k.ParamsMaxValsToVote(ctx)))
+	if start < 0 || end < 0 {
+		return []types.VoteToValidator{}, total
+	}
+
+	votes = make([]types.VoteToValidator, 0, end-start)
+	for _, valAddr := range delegator.ValidatorAddresses[start:end] {
+		if vote, voteFound := k.GetVote(ctx, delAddr, valAddr); voteFound {
+			votes = append(votes, types.NewVoteToValidator(valAddr, vote))
+		}
+	}
+	return votes, total
+}
+
+// GetDelegatorValidators gets the validators that a delegator votes for in the order of its votes, up to maxRetrieve
+func (k Keeper) GetDelegatorValidators(ctx sdk.Context, delAddr sdk.AccAddress, maxRetrieve uint16) types.Validators {
+	delegator, found := k.GetDelegator(ctx, delAddr)
+	if !found {
+		return types.Validators{}
+	}
+
+	validators := make(types.Validators, 0, len(delegator.ValidatorAddresses))
+	for _, valAddr := range delegator.ValidatorAddresses {
+		if len(validators) >= int(maxRetrieve) {
+			break
+		}
+		if validator, valFound := k.GetValidator(ctx, valAddr); valFound {
+			validators = append(validators, validator)
+		}
+	}
+	return validators
+}
+
+// GetEffectiveVotes gets the votes of a delegator split into the ones counted under the current param MaxValsToVote and
+// the ones in excess of it. The votes in excess are withdrawn once the votes of the delegator are updated
+func (k Keeper) GetEffectiveVotes(ctx sdk.Context, delAddr sdk.AccAddress) (effectiveVotes types.EffectiveVotes,
+	found bool) {
+	delegator, found := k.GetDelegator(ctx, delAddr)
+	if !found {
+		return effectiveVotes, false
+	}
+
+	votes := make([]types.VoteToValidator, 0, len(delegator.ValidatorAddresses))
+	for _, valAddr := range delegator.ValidatorAddresses {
+		if vote, voteFound := k.GetVote(ctx, delAddr, valAddr); voteFound {
+			votes = append(votes, types.NewVoteToValidator(valAddr, vote))
+		}
+	}
+	return types.NewEffectiveVotes(delAddr, k.ParamsMaxValsToVote(ctx), votes), true
+}
+
+// GetDelegatorBonded gets the total bonded tokens of a delegator, including both the delegated tokens measured in the
+// primary bond denom and the msd if the delegator is also the operator of a validator
+// NOTE: the bonded tokens are never slashed in okchain's staking, so they always equal to what the delegator delegated
+func (k Keeper) GetDelegatorBonded(ctx sdk.Context, delAddr sdk.AccAddress) sdk.Dec {
+	bonded := sdk.ZeroDec()
+	if delegator, found := k.GetDelegator(ctx, delAddr); found {
+		bonded = bonded.Add(delegator.Tokens)
+	}
+	if validator, found := k.GetValidator(ctx, sdk.ValAddress(delAddr)); found {
+		bonded = bonded.Add(validator.MinSelfDelegation)
+	}
+	return bonded
+}
+
+// GetDelegatorVotePower gets the votes that a delegator is able to make by its bonded tokens together with the ones
+// delegated to it as a proxy at the current block time, and how many of them have been committed to its validators.
+// All the votes of a delegator bound to a proxy are committed through the proxy
+func (k Keeper) GetDelegatorVotePower(ctx sdk.Context, delAddr sdk.AccAddress) (types.DelegatorVotePower, sdk.Error) {
+	delegator, found := k.GetDelegator(ctx, delAddr)
+	if !found {
+		return types.DelegatorVotePower{}, types.ErrNoDelegatorExisted(types.DefaultCodespace, delAddr.String())
+	}
+
+	totalShares, err := calculateWeight(ctx.BlockTime().Unix(), delegator.Tokens.Add(delegator.TotalDelegatedTokens))
+	if err != nil {
+		return types.DelegatorVotePower{}, err
+	}
+
+	committedShares := sdk.ZeroDec()
+	if delegator.HasProxy() {
+		committedShares = totalShares
+	} else if len(delegator.ValidatorAddresses) != 0 {
+		committedShares = delegator.Shares
+	}
+	return types.NewDelegatorVotePower(delAddr, totalShares, committedShares), nil
+}
+
+// GetUnvotedValidators returns the bonded validators that a delegator isn't voting for, together with how many more
+// validators it's able to vote for under the param MaxValsToVote. There's no room left for a delegator bound to a proxy
+func (k Keeper) GetUnvotedValidators(ctx sdk.Context, delAddr sdk.AccAddress) (validators types.Validators,
+	remaining uint16) {
+	delegator, found := k.GetDelegator(ctx, delAddr)
+	if found && delegator.HasProxy() {
+		return
+	}
+
+	maxValsToVote := k.ParamsMaxValsToVote(ctx)
+	if len(delegator.ValidatorAddresses) >= int(maxValsToVote) {
+		return
+	}
+	remaining = maxValsToVote - uint16(len(delegator.ValidatorAddresses))
+
+	voted := make(map[string]bool, len(delegator.ValidatorAddresses))
+	for _, valAddr := range delegator.ValidatorAddresses {
+		voted[valAddr.String()] = true
+	}
+	k.IterateBondedValidatorsByPower(ctx, func(_ int64, validator exported.ValidatorI) (stop bool) {
+		if !voted[validator.GetOperator().String()] {
+			validators = append(validators, validator.(types.ValidatorWithPowerReduction).Validator)
+		}
+		return false
+	})
+	return
+}
+
+// RecordDelegatorAction logs a staking action of a delegator at the current height when RecordDelegatorHistory is on,
+// and prunes the action which falls out of the retention window
+func (k Keeper) RecordDelegatorAction(ctx sdk.Context, delAddr sdk.AccAddress, action string, amount sdk.DecCoin,
+	valAddrs []sdk.ValAddress) {
+	if !k.ParamsRecordDelegatorHistory(ctx) {
+		return
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	// the sequence goes on from the latest action of the delegator
+	var sequence uint64
+	iterator := sdk.KVStoreReversePrefixIterator(store, types.GetDelegatorHistoryKey(delAddr))
+	if iterator.Valid() {
+		var last types.DelegatorAction
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &last)
+		sequence = last.Sequence + 1
+	}
+	iterator.Close()
+
+	store.Set(types.GetDelegatorActionKey(delAddr, sequence), k.cdc.MustMarshalBinaryLengthPrefixed(
+		types.NewDelegatorAction(sequence, action, ctx.BlockHeight(), amount, valAddrs)))
+	if sequence >= types.DelegatorHistoryRetention {
+		store.Delete(types.GetDelegatorActionKey(delAddr, sequence-types.DelegatorHistoryRetention))
+	}
+}
+
+// GetDelegatorHistory returns the staking actions of a delegator kept in store, in the order they took place
+func (k Keeper) GetDelegatorHistory(ctx sdk.Context, delAddr sdk.AccAddress) types.DelegatorHistory {
+	history := types.DelegatorHistory{}
+	iterator := sdk.KVStorePrefixIterator(ctx.KVStore(k.storeKey), types.GetDelegatorHistoryKey(delAddr))
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		var action types.DelegatorAction
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &action)
+		history = append(history, action)
+	}
+	return history
+}
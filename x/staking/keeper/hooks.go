@@ -49,3 +49,10 @@ func (k Keeper) AfterValidatorDestroyed(ctx sdk.Context, consAddr sdk.ConsAddres
 		k.hooks.AfterValidatorDestroyed(ctx, consAddr, valAddr)
 	}
 }
+
+// BeforeDelegationRemoved - call hook if registered
+func (k Keeper) BeforeDelegationRemoved(ctx sdk.Context, delAddr sdk.AccAddress, valAddr sdk.ValAddress) {
+	if k.hooks != nil {
+		k.hooks.BeforeDelegationRemoved(ctx, delAddr, valAddr)
+	}
+}
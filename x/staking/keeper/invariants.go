@@ -21,6 +21,30 @@ func RegisterInvariantsCustom(ir sdk.InvariantRegistry, k Keeper) {
 		PositiveDelegatorInvariant(k))
 	ir.RegisterRoute(types.ModuleName, "delegator-votes",
 		DelegatorVotesInvariant(k))
+	ir.RegisterRoute(types.ModuleName, "validator-tokens",
+		ValidatorTokensInvariant(k))
+}
+
+// ValidatorTokensInvariant checks that no tokens are recorded on the validators, because the voted tokens are kept
+// in the bonded pool on behalf of the delegators. The broken validators are able to be fixed by RefreshValidatorTokens
+func ValidatorTokensInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		var msg string
+		var count int
+
+		k.IterateValidators(ctx, func(index int64, validator exported.ValidatorI) bool {
+			if !validator.GetTokens().IsZero() {
+				count++
+				msg += fmt.Sprintf("	validator %s with tokens: %v\n", validator.GetOperator(), validator.GetTokens())
+			}
+			return false
+		})
+
+		broken := count != 0
+
+		return sdk.FormatInvariant(types.ModuleName, "zero tokens of validator", fmt.Sprintf(
+			"%d validators with tokens found\n%s", count, msg)), broken
+	}
 }
 
 // DelegatorVotesInvariant checks whether all the votes which persist
@@ -34,21 +58,15 @@ func DelegatorVotesInvariant(k Keeper) sdk.Invariant {
 
 		validators := k.GetAllValidators(ctx)
 		for _, validator := range validators {
-
 			valTotalVotes := validator.GetDelegatorShares()
-
-			totalVotes := validator.MinSelfDelegation
-
-			votes := k.GetValidatorVotes(ctx, validator.GetOperator())
-			for _, vote := range votes {
-				totalVotes = totalVotes.Add(vote.Votes)
-			}
-
+			totalVotes := k.GetValidatorTotalShares(ctx, validator.GetOperator())
 			if !valTotalVotes.Equal(totalVotes) {
 				broken = true
 				msg += fmt.Sprintf("broken delegator votes invariance:\n"+
+					"\tvalidator: %s\n"+
 					"\tvalidator.DelegatorShares: %v\n"+
-					"\tsum of Vote.Votes and min self delegation: %v\n", valTotalVotes, totalVotes)
+					"\tsum of Vote.Votes and min self delegation: %v\n",
+					validator.GetOperator(), valTotalVotes, totalVotes)
 			}
 		}
 		return sdk.FormatInvariant(types.ModuleName, "delegator votes", msg), broken
@@ -89,6 +107,7 @@ func NonNegativePowerInvariantCustom(k Keeper) sdk.Invariant {
 		var broken bool
 
 		iterator := k.ValidatorsPowerStoreIterator(ctx)
+		powerReduction := k.GetPowerReduction(ctx)
 
 		for ; iterator.Valid(); iterator.Next() {
 			validator, found := k.GetValidator(ctx, iterator.Value())
@@ -96,13 +115,13 @@ func NonNegativePowerInvariantCustom(k Keeper) sdk.Invariant {
 				panic(fmt.Sprintf("validator record not found for address: %X\n", iterator.Value()))
 			}
 
-			powerKey := types.GetValidatorsByPowerIndexKey(validator)
+			powerKey := k.getValidatorPowerIndexKey(ctx, validator)
 
 			if !bytes.Equal(iterator.Key(), powerKey) {
 				broken = true
 				msg += fmt.Sprintf("power store invariance:\n\tvalidator.Power: %v"+
 					"\n\tkey should be: %v\n\tkey in store: %v\n",
-					validator.ConsensusPowerByVotes(), powerKey, iterator.Key())
+					validator.ConsensusPowerByVotes(powerReduction), powerKey, iterator.Key())
 			}
 
 			if validator.DelegatorShares.IsNegative() {
@@ -119,42 +138,48 @@ func NonNegativePowerInvariantCustom(k Keeper) sdk.Invariant {
 // ModuleAccountInvariantsCustom check invariants for module account
 func ModuleAccountInvariantsCustom(k Keeper) sdk.Invariant {
 	return func(ctx sdk.Context) (string, bool) {
-		bonded := sdk.ZeroDec()
-		notBonded := sdk.ZeroDec()
+		bonded := sdk.DecCoins{}
+		notBonded := sdk.DecCoins{}
 		bondedPool := k.GetBondedPool(ctx)
 		notBondedPool := k.GetNotBondedPool(ctx)
 		bondDenom := k.BondDenom(ctx)
 
 		k.IterateValidators(ctx, func(index int64, validator exported.ValidatorI) bool {
-			bonded = bonded.Add(validator.GetMinSelfDelegation())
+			bonded = bonded.Add(sdk.NewDecCoinsFromDec(bondDenom, validator.GetMinSelfDelegation()))
 			return false
 		})
 
 		k.IterateDelegator(ctx, func(index int64, delegator types.Delegator) bool {
-			bonded = bonded.Add(delegator.Tokens)
+			bonded = bonded.Add(delegator.GetDelegatedCoins(bondDenom))
 			return false
 		})
 
 		k.IterateUndelegationInfo(ctx, func(_ int64, undelegationInfo types.UndelegationInfo) bool {
-			notBonded = notBonded.Add(undelegationInfo.Quantity)
+			notBonded = notBonded.Add(undelegationInfo.GetCoins(bondDenom))
 			return false
 		})
 
-		poolBonded := bondedPool.GetCoins().AmountOf(bondDenom)
-		poolNotBonded := notBondedPool.GetCoins().AmountOf(bondDenom)
-		broken := !poolBonded.Equal(bonded) || !poolNotBonded.Equal(notBonded)
+		for _, pending := range k.GetPendingDelegations(ctx) {
+			notBonded = notBonded.Add(pending.Amount.ToCoins())
+		}
+
+		poolBonded := bondedPool.GetCoins()
+		poolNotBonded := notBondedPool.GetCoins()
+		bondedDiff, _ := poolBonded.SafeSub(bonded)
+		notBondedDiff, _ := poolNotBonded.SafeSub(notBonded)
+		broken := !bondedDiff.IsZero() || !notBondedDiff.IsZero()
 
-		// Bonded tokens should be equal to the sum of delegators' tokens
-		// Not-bonded tokens should be equal to the sum of undelegation infos' tokens
+		// Bonded coins should be equal to the sum of delegators' coins in each denom
+		// Not-bonded coins should be equal to the sum of undelegation infos' and pending delegations' coins in each denom
 		return sdk.FormatInvariant(types.ModuleName, "bonded and not bonded module account coins", fmt.Sprintf(
-			"\tPool's bonded tokens: %v\n"+
-				"\tsum of bonded tokens: %v\n"+
-				"not bonded token invariance:\n"+
-				"\tPool's not bonded tokens: %v\n"+
-				"\tsum of not bonded tokens: %v\n"+
+			"\tPool's bonded coins: %v\n"+
+				"\tsum of bonded coins: %v\n"+
+				"not bonded coin invariance:\n"+
+				"\tPool's not bonded coins: %v\n"+
+				"\tsum of not bonded coins: %v\n"+
 				"module accounts total (bonded + not bonded):\n"+
-				"\tModule Accounts' tokens: %v\n"+
-				"\tsum tokens:              %v\n",
+				"\tModule Accounts' coins: %v\n"+
+				"\tsum coins:              %v\n",
 			poolBonded, bonded, poolNotBonded, notBonded, poolBonded.Add(poolNotBonded), bonded.Add(notBonded))), broken
 	}
 }
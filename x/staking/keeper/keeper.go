@@ -2,6 +2,7 @@ package keeper
 
 import (
 	"container/list"
+	"encoding/hex"
 	"fmt"
 	"strings"
 
@@ -25,6 +26,7 @@ type Keeper struct {
 	storeTKey          sdk.StoreKey
 	cdc                *codec.Codec
 	supplyKeeper       types.SupplyKeeper
+	mintKeeper         types.MintKeeper
 	hooks              types.StakingHooks
 	paramstore         params.Subspace
 	validatorCache     map[string]cachedValidator
@@ -74,6 +76,15 @@ func (k *Keeper) SetHooks(sh types.StakingHooks) *Keeper {
 	return k
 }
 
+// SetMintKeeper sets the mint keeper which the block reward is derived from
+func (k *Keeper) SetMintKeeper(mk types.MintKeeper) *Keeper {
+	if k.mintKeeper != nil {
+		panic("cannot set mint keeper twice")
+	}
+	k.mintKeeper = mk
+	return k
+}
+
 // Codespace returns the codespace
 func (k Keeper) Codespace() sdk.CodespaceType {
 	return k.codespace
@@ -115,6 +126,12 @@ func (k Keeper) IsValidator(ctx sdk.Context, addr sdk.AccAddress) bool {
 	return false
 }
 
+// IsValidatorOperator tells whether an account operates a validator which isn't destroyed, whatever its status is
+func (k Keeper) IsValidatorOperator(ctx sdk.Context, addr sdk.AccAddress) bool {
+	validator, found := k.GetValidator(ctx, sdk.ValAddress(addr))
+	return found && validator.MinSelfDelegation.IsPositive()
+}
+
 // GetOperAddrFromValidatorAddr returns the validator address according to the consensus pubkey
 // the validator has to be existed
 func (k Keeper) GetOperAddrFromValidatorAddr(ctx sdk.Context, va string) (sdk.ValAddress, bool) {
@@ -139,3 +156,33 @@ func (k Keeper) GetOperAndValidatorAddr(ctx sdk.Context) types.OVPairs {
 	}
 	return ovPairs
 }
+
+// storeStatsPrefixes are the prefixes of the staking store counted in the store stats, which are the ones growing
+// with the number of the validators and the delegators
+var storeStatsPrefixes = []struct {
+	name   string
+	prefix []byte
+}{
+	{"validators", types.ValidatorsKey},
+	{"delegators", types.DelegatorKey},
+	{"votes", types.VoteKey},
+	{"undelegations", types.UnDelegationInfoKey},
+	{"power_index", types.ValidatorsByPowerIndexKey},
+	{"delegator_history", types.DelegatorHistoryKey},
+}
+
+// GetStoreStats counts the entries under each prefix of the staking store by iterating over them, for the monitoring
+// of the state bloat. It's expensive on a large state so that it's only supposed to be queried
+func (k Keeper) GetStoreStats(ctx sdk.Context) types.StoreStats {
+	store := ctx.KVStore(k.storeKey)
+	stats := make(types.StoreStats, len(storeStatsPrefixes))
+	for i, p := range storeStatsPrefixes {
+		stats[i] = types.StorePrefixStats{Name: p.name, Prefix: hex.EncodeToString(p.prefix)}
+		iterator := sdk.KVStorePrefixIterator(store, p.prefix)
+		for ; iterator.Valid(); iterator.Next() {
+			stats[i].Entries++
+		}
+		iterator.Close()
+	}
+	return stats
+}
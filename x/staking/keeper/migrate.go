@@ -0,0 +1,128 @@
+package keeper
+
+import (
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/okex/okchain/x/staking/types"
+)
+
+// MigrateStore upgrades the staking store written by the earlier software to types.StoreVersion. It's called at the
+// beginning of every block before any module reads the staking store, and does nothing once the store is up to date.
+// The slashing keeper tells when the existing validators were first bonded
+func (k Keeper) MigrateStore(ctx sdk.Context, sk types.SlashingKeeper) {
+	version := k.GetStoreVersion(ctx)
+	if version >= types.StoreVersion {
+		return
+	}
+
+	setKeys := k.setMissingParams(ctx)
+	normalizedKeys := k.NormalizeDecParams(ctx)
+	delegatorCount := k.countDelegators(ctx)
+	validatorCount := k.backfillValidatorBonds(ctx, sk)
+	indexedCount := k.indexValidatorDescriptions(ctx)
+	minDelegationCount := k.setMissingValidatorMinDelegations(ctx)
+	totalVotes := k.sumTotalVotes(ctx)
+	k.SetStoreVersion(ctx, types.StoreVersion)
+	k.Logger(ctx).Info(fmt.Sprintf("staking store migrated from version %d to %d, %d params set to default, "+
+		"%d params normalized, %d delegators counted, %d validator bonds backfilled, %d validators indexed by "+
+		"description, %d validator min delegations set to zero, total votes %s summed up", version,
+		types.StoreVersion, len(setKeys), len(normalizedKeys), delegatorCount, validatorCount, indexedCount,
+		minDelegationCount, totalVotes))
+}
+
+// GetStoreVersion returns the version of the staking store, which is 0 for the stores written by the earlier software
+func (k Keeper) GetStoreVersion(ctx sdk.Context) (version uint64) {
+	b := ctx.KVStore(k.storeKey).Get(types.StoreVersionKey)
+	if b == nil {
+		return 0
+	}
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(b, &version)
+	return
+}
+
+// SetStoreVersion sets the version of the staking store
+func (k Keeper) SetStoreVersion(ctx sdk.Context, version uint64) {
+	ctx.KVStore(k.storeKey).Set(types.StoreVersionKey, k.cdc.MustMarshalBinaryLengthPrefixed(version))
+}
+
+// setMissingParams sets the params which the earlier software never stored to their default values, since the
+// paramstore panics on reading a missing param. It returns the keys of the params set
+func (k Keeper) setMissingParams(ctx sdk.Context) (setKeys [][]byte) {
+	defaultParams := types.DefaultParams()
+	for _, pair := range defaultParams.ParamSetPairs() {
+		if k.paramstore.Has(ctx, pair.Key) {
+			continue
+		}
+
+		k.paramstore.Set(ctx, pair.Key, pair.Value)
+		setKeys = append(setKeys, pair.Key)
+	}
+
+	if len(setKeys) != 0 {
+		ctx.TransientStore(k.storeTKey).Delete(types.ParamsCacheKey)
+	}
+	return
+}
+
+// backfillValidatorBonds sets the bond height and the bonded-since time of the validators written by the earlier
+// software, which never stored them. The bond height is the start height of the signing info, which is created when the
+// validator is first bonded, or the current height for the validators never bonded. The tenure of a bonded validator
+// starts at the upgrade, since the earlier software never recorded when it joined the bonded set. It returns the
+// number of the validators backfilled
+func (k Keeper) backfillValidatorBonds(ctx sdk.Context, sk types.SlashingKeeper) (count int) {
+	store := ctx.KVStore(k.storeKey)
+	for _, validator := range k.GetAllValidators(ctx) {
+		// the bond height is a part of the power index key with the oldest-first tie-break
+		oldKey := k.getValidatorPowerIndexKey(ctx, validator)
+
+		validator.BondHeight = ctx.BlockHeight()
+		if info, found := sk.GetValidatorSigningInfo(ctx, validator.ConsAddress()); found {
+			validator.BondHeight = info.StartHeight
+		}
+		validator.BondedSince = time.Unix(0, 0).UTC()
+		if validator.IsBonded() {
+			validator.BondedSince = ctx.BlockHeader().Time
+		}
+		k.SetValidator(ctx, validator)
+
+		if store.Has(oldKey) {
+			store.Delete(oldKey)
+			store.Set(k.getValidatorPowerIndexKey(ctx, validator), validator.OperatorAddress)
+		}
+		count++
+	}
+	return
+}
+
+// indexValidatorDescriptions builds the moniker and the identity indexes of the validators written by the earlier
+// software, which never indexed them. A moniker shared by several validators is kept by the first one in the order of
+// the operator addresses, and the others can only be found by their addresses. It returns the number of the
+// validators indexed
+func (k Keeper) indexValidatorDescriptions(ctx sdk.Context) (count int) {
+	for _, validator := range k.GetAllValidators(ctx) {
+		if !k.IsMonikerTaken(ctx, validator.Description.Moniker, validator.OperatorAddress) {
+			k.SetValidatorByMoniker(ctx, validator)
+		}
+		k.SetValidatorByIdentity(ctx, validator)
+		count++
+	}
+	return
+}
+
+// setMissingValidatorMinDelegations sets the min delegation of the validators written by the earlier software, which
+// never stored it and are decoded with a nil one, to zero, which means no more than the param MinDelegation. It
+// returns the number of the validators set
+func (k Keeper) setMissingValidatorMinDelegations(ctx sdk.Context) (count int) {
+	for _, validator := range k.GetAllValidators(ctx) {
+		if !validator.MinDelegation.IsNil() {
+			continue
+		}
+
+		validator.MinDelegation = sdk.ZeroDec()
+		k.SetValidator(ctx, validator)
+		count++
+	}
+	return
+}
@@ -0,0 +1,245 @@
+package keeper
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/store/prefix"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+	"github.com/okex/okchain/x/staking/types"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto"
+)
+
+type mockSlashingKeeper struct {
+	signingInfos map[string]slashingtypes.ValidatorSigningInfo
+}
+
+func (sk mockSlashingKeeper) GetValidatorSigningInfo(_ sdk.Context, address sdk.ConsAddress) (
+	info slashingtypes.ValidatorSigningInfo, found bool) {
+	info, found = sk.signingInfos[address.String()]
+	return
+}
+
+func (sk mockSlashingKeeper) SetValidatorSigningInfo(_ sdk.Context, address sdk.ConsAddress,
+	info slashingtypes.ValidatorSigningInfo) {
+	sk.signingInfos[address.String()] = info
+}
+
+// downgradeStore turns the staking store back into the one written by the earlier software, which never stored the
+// given params
+func downgradeStore(ctx sdk.Context, mkeeper MockStakingKeeper, missingKeys ...[]byte) {
+	paramStore := prefix.NewStore(ctx.KVStore(mkeeper.ParamsKey), append([]byte(DefaultParamspace), '/'))
+	for _, key := range missingKeys {
+		paramStore.Delete(key)
+	}
+	ctx.KVStore(mkeeper.StoreKey).Delete(types.StoreVersionKey)
+	commitBlock(ctx)
+}
+
+func TestMigrateStoreParams(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, 0)
+	keeper := mkeeper.Keeper
+	params := keeper.GetParams(ctx)
+	params.MaxValidators = 7
+	keeper.SetParams(ctx, params)
+
+	downgradeStore(ctx, mkeeper, types.KeyMaxDelegations, types.KeyEpochBoundaryGrace, types.KeyPowerReduction)
+	require.Equal(t, uint64(0), keeper.GetStoreVersion(ctx))
+	require.Panics(t, func() { keeper.GetParams(ctx) })
+
+	keeper.MigrateStore(ctx, mockSlashingKeeper{})
+	require.Equal(t, types.StoreVersion, keeper.GetStoreVersion(ctx))
+	migrated := keeper.GetParams(ctx)
+	defaultParams := types.DefaultParams()
+	require.Equal(t, defaultParams.MaxDelegations, migrated.MaxDelegations)
+	require.Equal(t, defaultParams.EpochBoundaryGrace, migrated.EpochBoundaryGrace)
+	require.True(t, defaultParams.PowerReduction.Equal(migrated.PowerReduction))
+	// the params stored are kept
+	require.Equal(t, uint16(7), migrated.MaxValidators)
+	require.NoError(t, migrated.Validate())
+
+	// the migration is run only once
+	params.MaxDelegations = 10
+	keeper.SetParams(ctx, params)
+	keeper.MigrateStore(ctx, mockSlashingKeeper{})
+	require.Equal(t, uint64(10), keeper.ParamsMaxDelegations(ctx))
+}
+
+func TestMigrateStoreDelegatorCount(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, 0)
+	keeper := mkeeper.Keeper
+	for _, delAddr := range addrDels {
+		keeper.SetDelegator(ctx, types.NewDelegator(delAddr))
+	}
+
+	// the earlier software never counted the delegators
+	ctx.KVStore(mkeeper.StoreKey).Delete(types.DelegatorCountKey)
+	downgradeStore(ctx, mkeeper)
+	require.Equal(t, uint64(0), keeper.GetDelegatorCount(ctx))
+
+	// removing a delegator doesn't underflow the count
+	keeper.DeleteDelegator(ctx, addrDels[0])
+	require.Equal(t, uint64(0), keeper.GetDelegatorCount(ctx))
+
+	keeper.MigrateStore(ctx, mockSlashingKeeper{})
+	require.Equal(t, uint64(len(addrDels)-1), keeper.GetDelegatorCount(ctx))
+	keeper.DeleteDelegator(ctx, addrDels[1])
+	require.Equal(t, uint64(len(addrDels)-2), keeper.GetDelegatorCount(ctx))
+}
+
+func TestMigrateStoreNormalizeDecParams(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, 0)
+	keeper := mkeeper.Keeper
+	params := keeper.GetParams(ctx)
+	canonical := keeper.cdc.MustMarshalJSON(params.MinSelfDelegationLimit)
+
+	// the param stored in a non-canonical encoding by the earlier software
+	paramStore := prefix.NewStore(ctx.KVStore(mkeeper.ParamsKey), append([]byte(DefaultParamspace), '/'))
+	paramStore.Set(types.KeyMinSelfDelegationLimit, []byte(`"0.001"`))
+	downgradeStore(ctx, mkeeper)
+	require.NotEqual(t, canonical, keeper.paramstore.GetRaw(ctx, types.KeyMinSelfDelegationLimit))
+
+	keeper.MigrateStore(ctx, mockSlashingKeeper{})
+	require.Equal(t, canonical, keeper.paramstore.GetRaw(ctx, types.KeyMinSelfDelegationLimit))
+	require.True(t, keeper.GetParams(ctx).Equal(params))
+}
+
+func TestMigrateStoreValidatorBonds(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, 0)
+	keeper := mkeeper.Keeper
+	keeper.SetPowerTieBreak(ctx, types.TieBreakOldestFirst)
+	vals := createVals(ctx, 3, keeper)
+	for i := range vals {
+		// the earlier software never stored the bond heights and the bonded-since times
+		vals[i].BondedSince = time.Time{}
+		vals[i].DelegatorShares = sdk.NewDec(10)
+		if i == 0 {
+			vals[i].Status = sdk.Bonded
+		}
+		keeper.SetValidator(ctx, vals[i])
+		keeper.SetNewValidatorByPowerIndex(ctx, vals[i])
+	}
+	require.Equal(t, []sdk.ValAddress{vals[0].OperatorAddress, vals[1].OperatorAddress, vals[2].OperatorAddress},
+		getPowerIndexOrder(ctx, keeper))
+	downgradeStore(ctx, mkeeper)
+
+	// the validators 0 and 2 have been bonded, while the validator 1 never
+	sk := mockSlashingKeeper{map[string]slashingtypes.ValidatorSigningInfo{}}
+	for i, startHeight := range map[int]int64{0: 20, 2: 5} {
+		consAddr := vals[i].ConsAddress()
+		sk.SetValidatorSigningInfo(ctx, consAddr, slashingtypes.NewValidatorSigningInfo(consAddr, startHeight, 0,
+			time.Unix(0, 0), false, 0, slashingtypes.Created))
+	}
+	ctx = ctx.WithBlockHeight(50).WithBlockTime(time.Unix(1000, 0).UTC())
+	keeper.MigrateStore(ctx, sk)
+
+	for i, bondHeight := range []int64{20, 50, 5} {
+		val := keeper.mustGetValidator(ctx, vals[i].OperatorAddress)
+		require.Equal(t, bondHeight, val.BondHeight)
+		if i == 0 {
+			require.Equal(t, ctx.BlockHeader().Time, val.BondedSince)
+		} else {
+			require.Equal(t, time.Unix(0, 0).UTC(), val.BondedSince)
+		}
+	}
+	// the power index is rebuilt with the bond heights backfilled
+	require.Equal(t, []sdk.ValAddress{vals[2].OperatorAddress, vals[0].OperatorAddress, vals[1].OperatorAddress},
+		getPowerIndexOrder(ctx, keeper))
+	_, broken := NonNegativePowerInvariantCustom(keeper)(ctx)
+	require.False(t, broken)
+}
+
+func TestMigrateStoreValidatorDescriptionIndexes(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, 0)
+	keeper := mkeeper.Keeper
+	// the earlier software never indexed the validators by their monikers and identities
+	vals := createVals(ctx, 3, keeper)
+	for i, description := range []types.Description{
+		{Moniker: "alice", Identity: "shared"},
+		{Moniker: "Alice", Identity: "shared"},
+		{Moniker: "bob"},
+	} {
+		vals[i].Description = description
+		keeper.SetValidator(ctx, vals[i])
+	}
+	downgradeStore(ctx, mkeeper)
+	_, found := keeper.GetValidatorByMoniker(ctx, "bob")
+	require.False(t, found)
+	require.Empty(t, keeper.GetValidatorsByIdentity(ctx, "shared"))
+
+	keeper.MigrateStore(ctx, mockSlashingKeeper{})
+
+	// the moniker shared is kept by the first validator in the order of the operator addresses
+	first := vals[0]
+	if bytes.Compare(vals[1].OperatorAddress, first.OperatorAddress) < 0 {
+		first = vals[1]
+	}
+	validator, found := keeper.GetValidatorByMoniker(ctx, "ALICE")
+	require.True(t, found)
+	require.Equal(t, first.OperatorAddress, validator.OperatorAddress)
+	validator, found = keeper.GetValidatorByMoniker(ctx, "bob")
+	require.True(t, found)
+	require.Equal(t, vals[2].OperatorAddress, validator.OperatorAddress)
+
+	shared := keeper.GetValidatorsByIdentity(ctx, "shared")
+	require.Len(t, shared, 2)
+	for _, val := range shared {
+		require.False(t, val.OperatorAddress.Equals(vals[2].OperatorAddress))
+	}
+}
+
+func TestMigrateStoreTotalVotes(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, 0)
+	keeper := mkeeper.Keeper
+	vals := createVals(ctx, 2, keeper)
+	for i := range vals {
+		vals[i].DelegatorShares = sdk.NewDec(int64(10 * (i + 1)))
+		keeper.SetValidator(ctx, vals[i])
+	}
+
+	// the earlier software never kept the total votes
+	ctx.KVStore(mkeeper.StoreKey).Delete(types.TotalVotesKey)
+	downgradeStore(ctx, mkeeper)
+	require.True(t, keeper.GetTotalVotes(ctx).IsZero())
+
+	keeper.MigrateStore(ctx, mockSlashingKeeper{})
+	require.True(t, sdk.NewDec(30).Equal(keeper.GetTotalVotes(ctx)))
+}
+
+// legacyValidator is the validator written by the earlier software, which lacks the fields added since
+type legacyValidator struct {
+	OperatorAddress         sdk.ValAddress
+	ConsPubKey              crypto.PubKey
+	Jailed                  bool
+	Status                  sdk.BondStatus
+	Tokens                  sdk.Int
+	DelegatorShares         sdk.Dec
+	Description             types.Description
+	UnbondingHeight         int64
+	UnbondingCompletionTime time.Time
+	Commission              types.Commission
+	MinSelfDelegation       sdk.Dec
+}
+
+func TestMigrateStoreValidatorMinDelegations(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, 0)
+	keeper := mkeeper.Keeper
+	vals := createVals(ctx, 2, keeper)
+	vals[1].MinDelegation = sdk.NewDec(5)
+	keeper.SetValidator(ctx, vals[1])
+	// the earlier software never stored the min delegation of a validator, which is decoded as nil
+	val := vals[0]
+	ctx.KVStore(mkeeper.StoreKey).Set(types.GetValidatorKey(val.OperatorAddress),
+		keeper.cdc.MustMarshalBinaryLengthPrefixed(legacyValidator{val.OperatorAddress, val.ConsPubKey, val.Jailed,
+			val.Status, val.Tokens, val.DelegatorShares, val.Description, val.UnbondingHeight,
+			val.UnbondingCompletionTime, val.Commission, val.MinSelfDelegation}))
+	downgradeStore(ctx, mkeeper)
+	require.True(t, keeper.mustGetValidator(ctx, val.OperatorAddress).MinDelegation.IsNil())
+
+	keeper.MigrateStore(ctx, mockSlashingKeeper{})
+	require.True(t, keeper.mustGetValidator(ctx, val.OperatorAddress).MinDelegation.IsZero())
+	require.True(t, sdk.NewDec(5).Equal(keeper.mustGetValidator(ctx, vals[1].OperatorAddress).MinDelegation))
+}
@@ -22,13 +22,24 @@ func (k Keeper) UndelegateMinSelfDelegation(ctx sdk.Context, delAddr sdk.AccAddr
 	}
 
 	// 2.unbond msd
-	k.bondedTokensToNotBonded(ctx, sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, validator.MinSelfDelegation))
+	bondDenom := k.ParamsBondDenoms(ctx).Primary()
+	k.bondedTokensToNotBonded(ctx, sdk.NewDecCoinFromDec(bondDenom, validator.MinSelfDelegation))
 	completionTime = ctx.BlockHeader().Time.Add(k.UnbondingTime(ctx))
-	minSelfUndelegation := types.NewUndelegationInfo(delAddr, validator.MinSelfDelegation, completionTime)
+	msdCoins := sdk.NewDecCoinsFromDec(bondDenom, validator.MinSelfDelegation)
+	// merge into the pending undelegation of the operator, which mustn't be overwritten
+	minSelfUndelegation, found := k.GetUndelegating(ctx, delAddr)
+	if !found {
+		minSelfUndelegation = types.NewUndelegationInfo(delAddr, validator.MinSelfDelegation, msdCoins, completionTime)
+	} else {
+		minSelfUndelegation.AddEntry(bondDenom, validator.MinSelfDelegation, msdCoins, completionTime)
+	}
+	minSelfUndelegation.AddValidatorAddresses([]sdk.ValAddress{validator.OperatorAddress})
 	k.SetUndelegating(ctx, minSelfUndelegation)
-	k.SetAddrByTimeKeyWithNilValue(ctx, minSelfUndelegation.CompletionTime, minSelfUndelegation.DelegatorAddress)
+	k.SetAddrByTimeKeyWithNilValue(ctx, completionTime, delAddr)
 
 	// 3.clear the msd
+	k.BeforeValidatorModified(ctx, validator.OperatorAddress)
+	k.BeforeDelegationRemoved(ctx, delAddr, validator.OperatorAddress)
 	validator.MinSelfDelegation = sdk.ZeroDec()
 
 	// 4.jail the validator
@@ -64,8 +75,7 @@ func (k Keeper) VoteMinSelfDelegation(ctx sdk.Context, delAddr sdk.AccAddress, v
 	msdToken sdk.DecCoin) (err sdk.Error) {
 	// 0. transfer account's okt into bondPool
 	coins := msdToken.ToCoins()
-	err = k.supplyKeeper.DelegateCoinsFromAccountToModule(ctx, delAddr, types.BondedPoolName, coins)
-	if err != nil {
+	if err = k.delegateToBondedPool(ctx, delAddr, coins); err != nil {
 		return err
 	}
 
@@ -1,6 +1,9 @@
 package keeper
 
 import (
+	"bytes"
+	"fmt"
+	"reflect"
 	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -24,12 +27,118 @@ func (k Keeper) UnbondingTime(ctx sdk.Context) (res time.Duration) {
 	return
 }
 
+// SetUnbondingTime schedules a new unbonding time to take effect at the end of the current epoch, so that all the
+// undelegations within an epoch are matured by the same unbonding time. The unbonding time must be longer than the
+// CommissionChangeWindow and no more than MaxUnbondingTime
+func (k Keeper) SetUnbondingTime(ctx sdk.Context, unbondingTime time.Duration) sdk.Error {
+	commissionChangeWindow := k.ParamsCommissionChangeWindow(ctx)
+	if unbondingTime <= commissionChangeWindow || unbondingTime > types.MaxUnbondingTime {
+		return types.ErrInvalidUnbondingTime(k.Codespace(), unbondingTime, commissionChangeWindow,
+			types.MaxUnbondingTime)
+	}
+
+	ctx.KVStore(k.storeKey).Set(types.PendingUnbondingTimeKey, k.cdc.MustMarshalBinaryLengthPrefixed(unbondingTime))
+	effectiveHeight := k.GetTheEndOfLastEpoch(ctx) + int64(k.GetEpoch(ctx))
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeSetUnbondingTime,
+			sdk.NewAttribute(types.AttributeKeyUnbondingTime, unbondingTime.String()),
+			sdk.NewAttribute(types.AttributeKeyEffectiveHeight, fmt.Sprintf("%d", effectiveHeight)),
+		),
+	)
+	return nil
+}
+
+// GetPendingUnbondingTime returns the unbonding time scheduled by SetUnbondingTime which hasn't taken effect yet
+func (k Keeper) GetPendingUnbondingTime(ctx sdk.Context) (unbondingTime time.Duration, found bool) {
+	b := ctx.KVStore(k.storeKey).Get(types.PendingUnbondingTimeKey)
+	if b == nil {
+		return
+	}
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(b, &unbondingTime)
+	return unbondingTime, true
+}
+
+// ApplyPendingUnbondingTime updates the param UnbondingTime with the scheduled one, which is called once an epoch ends
+func (k Keeper) ApplyPendingUnbondingTime(ctx sdk.Context) {
+	unbondingTime, found := k.GetPendingUnbondingTime(ctx)
+	if !found {
+		return
+	}
+
+	k.paramstore.Set(ctx, types.KeyUnbondingTime, unbondingTime)
+	ctx.TransientStore(k.storeTKey).Delete(types.ParamsCacheKey)
+	ctx.KVStore(k.storeKey).Delete(types.PendingUnbondingTimeKey)
+}
+
 // MaxValidators returns the param Maximum number of validators
 func (k Keeper) MaxValidators(ctx sdk.Context) (res uint16) {
 	k.paramstore.Get(ctx, types.KeyMaxValidators, &res)
 	return
 }
 
+// SetMaxValidators updates the param MaxValidators, which must be positive, no more than MaxMaxValidators and no less
+// than MinValidators. If
+// immediate, the validator set is recomputed at the end of the current block, which may drop validators within an
+// epoch. Otherwise it's scheduled to take effect at the end of the current epoch
+func (k Keeper) SetMaxValidators(ctx sdk.Context, maxValidators uint16, immediate bool) sdk.Error {
+	minValidators := k.ParamsMinValidators(ctx)
+	if maxValidators == 0 || maxValidators > types.MaxMaxValidators || maxValidators < minValidators {
+		return types.ErrInvalidMaxValidators(k.Codespace(), maxValidators, minValidators)
+	}
+
+	mode, effectiveHeight := types.AttributeValueDeferred, k.GetTheEndOfLastEpoch(ctx)+int64(k.GetEpoch(ctx))
+	if immediate {
+		mode, effectiveHeight = types.AttributeValueImmediate, ctx.BlockHeight()
+		k.applyMaxValidators(ctx, maxValidators)
+		ctx.TransientStore(k.storeTKey).Set(types.ValidatorSetRecomputeKey, []byte{})
+	} else {
+		ctx.KVStore(k.storeKey).Set(types.PendingMaxValidatorsKey, k.cdc.MustMarshalBinaryLengthPrefixed(maxValidators))
+	}
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeSetMaxValidators,
+			sdk.NewAttribute(types.AttributeKeyMaxValidators, fmt.Sprintf("%d", maxValidators)),
+			sdk.NewAttribute(types.AttributeKeyMode, mode),
+			sdk.NewAttribute(types.AttributeKeyEffectiveHeight, fmt.Sprintf("%d", effectiveHeight)),
+		),
+	)
+	return nil
+}
+
+// GetPendingMaxValidators returns the max validators scheduled by SetMaxValidators which hasn't taken effect yet
+func (k Keeper) GetPendingMaxValidators(ctx sdk.Context) (maxValidators uint16, found bool) {
+	b := ctx.KVStore(k.storeKey).Get(types.PendingMaxValidatorsKey)
+	if b == nil {
+		return
+	}
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(b, &maxValidators)
+	return maxValidators, true
+}
+
+// ApplyPendingMaxValidators updates the param MaxValidators with the scheduled one, which is called once an epoch ends
+func (k Keeper) ApplyPendingMaxValidators(ctx sdk.Context) {
+	maxValidators, found := k.GetPendingMaxValidators(ctx)
+	if !found {
+		return
+	}
+
+	k.applyMaxValidators(ctx, maxValidators)
+}
+
+// IsValidatorSetRecomputed tells whether the validator set is to be recomputed at the end of the current block
+func (k Keeper) IsValidatorSetRecomputed(ctx sdk.Context) bool {
+	return ctx.TransientStore(k.storeTKey).Has(types.ValidatorSetRecomputeKey)
+}
+
+// applyMaxValidators sets the param MaxValidators and drops the one scheduled, since the latest setting wins
+func (k Keeper) applyMaxValidators(ctx sdk.Context, maxValidators uint16) {
+	k.paramstore.Set(ctx, types.KeyMaxValidators, maxValidators)
+	ctx.TransientStore(k.storeTKey).Delete(types.ParamsCacheKey)
+	ctx.KVStore(k.storeKey).Delete(types.PendingMaxValidatorsKey)
+}
+
 // BondDenom renturns  the param Bondable coin denomination
 func (k Keeper) BondDenom(ctx sdk.Context) (res string) {
 	k.paramstore.Get(ctx, types.KeyBondDenom, &res)
@@ -46,12 +155,107 @@ func (k Keeper) GetParams(ctx sdk.Context) types.Params {
 		k.ParamsMaxValsToVote(ctx),
 		k.ParamsMinSelfDelegationLimited(ctx),
 		k.ParamsMinDelegation(ctx),
+		k.ParamsEnforceUniqueMoniker(ctx),
+		k.ParamsPowerReduction(ctx),
+		k.ParamsBondDenoms(ctx),
+		k.ParamsPowerAlertThreshold(ctx),
+		k.ParamsMaxDelegations(ctx),
+		k.ParamsPowerTieBreak(ctx),
+		k.ParamsBondDenomDecimals(ctx),
+		k.ParamsSelfDelegationOnly(ctx),
+		k.ParamsBondDenomMigration(ctx),
+		k.ParamsMinValidators(ctx),
+		k.ParamsUnjailMaxDepositPeriod(ctx),
+		k.ParamsUnjailMinDeposit(ctx),
+		k.ParamsUnjailVotingPeriod(ctx),
+		k.ParamsCommissionChangeWindow(ctx),
+		k.ParamsMaxValidatorVotes(ctx),
+		k.ParamsValidatorSelfUndelegateCooldown(ctx),
+		k.ParamsMaxValidatorVoteShare(ctx),
+		k.ParamsMinDelegationTolerance(ctx),
+		k.ParamsRecordDelegatorHistory(ctx),
+		k.ParamsEpochBoundaryGrace(ctx),
 	)
 }
 
+// GetParamsSubset reads only the params of the given keys, leaving the others zero in the returned types.Params, for
+// the callers that need several params but not the whole set
+func (k Keeper) GetParamsSubset(ctx sdk.Context, keys [][]byte) (subset types.Params, err sdk.Error) {
+	pairs := subset.ParamSetPairs()
+	for _, key := range keys {
+		found := false
+		for _, pair := range pairs {
+			if bytes.Equal(pair.Key, key) {
+				k.paramstore.Get(ctx, pair.Key, pair.Value)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return subset, types.ErrUnknownParamKey(types.DefaultCodespace, string(key))
+		}
+	}
+	return
+}
+
 // SetParams sets the params
 func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
 	k.paramstore.SetParamSet(ctx, &params)
+	ctx.TransientStore(k.storeTKey).Delete(types.ParamsCacheKey)
+}
+
+// GetParamsCached gets all params as types.Params, which are loaded from the paramstore once and cached in the
+// transient store within a block. The cache is skipped once any param has been modified in the block, e.g. by gov
+func (k Keeper) GetParamsCached(ctx sdk.Context) (params types.Params) {
+	if k.isParamsModified(ctx) {
+		return k.GetParams(ctx)
+	}
+
+	store := ctx.TransientStore(k.storeTKey)
+	if bz := store.Get(types.ParamsCacheKey); bz != nil {
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &params)
+		return
+	}
+
+	params = k.GetParams(ctx)
+	store.Set(types.ParamsCacheKey, k.cdc.MustMarshalBinaryLengthPrefixed(params))
+	return
+}
+
+// NormalizeDecParams rewrites the Dec params which were stored in a non-canonical encoding by the earlier versions,
+// so that Equal and the JSON round trips of the params are stable afterwards. It's run once by MigrateStore on the
+// upgrade but is harmless to run again, and returns the keys of the params rewritten
+func (k Keeper) NormalizeDecParams(ctx sdk.Context) (normalizedKeys [][]byte) {
+	for _, key := range [][]byte{types.KeyMinSelfDelegationLimit, types.KeyMinDelegation, types.KeyPowerAlertThreshold} {
+		if !k.paramstore.Has(ctx, key) {
+			continue
+		}
+
+		var dec sdk.Dec
+		k.paramstore.Get(ctx, key, &dec)
+		if bytes.Equal(k.paramstore.GetRaw(ctx, key), k.cdc.MustMarshalJSON(dec)) {
+			continue
+		}
+
+		k.paramstore.Set(ctx, key, dec)
+		normalizedKeys = append(normalizedKeys, key)
+	}
+
+	if len(normalizedKeys) != 0 {
+		ctx.TransientStore(k.storeTKey).Delete(types.ParamsCacheKey)
+	}
+	return
+}
+
+// isParamsModified tells whether any param of staking has been modified in the current block
+func (k Keeper) isParamsModified(ctx sdk.Context) bool {
+	var params types.Params
+	for _, pair := range params.ParamSetPairs() {
+		if k.paramstore.Modified(ctx, pair.Key) {
+			return true
+		}
+	}
+	return false
 }
 
 // ParamsEpoch returns epoch from paramstore, only update the KeyEpoch after last epoch ends
@@ -102,6 +306,26 @@ func (k Keeper) SetTheEndOfLastEpoch(ctx sdk.Context) {
 	store.Set(types.KeyTheEndOfLastEpoch, b)
 }
 
+// CurrentEpochNumber returns the number of the current epoch, which is the count of the epochs ended since genesis
+func (k Keeper) CurrentEpochNumber(ctx sdk.Context) (number uint64) {
+	b := ctx.KVStore(k.storeKey).Get(types.EpochNumberKey)
+	if b == nil {
+		return 0
+	}
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(b, &number)
+	return
+}
+
+// SetEpochNumber sets the number of the current epoch into keystore
+func (k Keeper) SetEpochNumber(ctx sdk.Context, number uint64) {
+	ctx.KVStore(k.storeKey).Set(types.EpochNumberKey, k.cdc.MustMarshalBinaryLengthPrefixed(number))
+}
+
+// GetEpochInfo returns the number and the range of heights of the current epoch
+func (k Keeper) GetEpochInfo(ctx sdk.Context) types.EpochInfo {
+	return types.NewEpochInfo(k.CurrentEpochNumber(ctx), k.GetEpoch(ctx), k.GetTheEndOfLastEpoch(ctx))
+}
+
 // ParamsMaxValsToVote returns the param MaxValsToVote
 func (k Keeper) ParamsMaxValsToVote(ctx sdk.Context) (num uint16) {
 	k.paramstore.Get(ctx, types.KeyMaxValsToVote, &num)
@@ -119,3 +343,265 @@ func (k Keeper) ParamsMinDelegation(ctx sdk.Context) (num sdk.Dec) {
 	k.paramstore.Get(ctx, types.KeyMinDelegation, &num)
 	return
 }
+
+// ParamsEnforceUniqueMoniker returns the param EnforceUniqueMoniker
+func (k Keeper) ParamsEnforceUniqueMoniker(ctx sdk.Context) (enforced bool) {
+	k.paramstore.Get(ctx, types.KeyEnforceUniqueMoniker, &enforced)
+	return
+}
+
+// ParamsPowerReduction returns the param PowerReduction, only update the KeyPowerReduction in store after last epoch ends
+func (k Keeper) ParamsPowerReduction(ctx sdk.Context) (res sdk.Int) {
+	k.paramstore.Get(ctx, types.KeyPowerReduction, &res)
+	return
+}
+
+// ParamsBondDenoms returns the param BondDenoms, the bondable coin denominations with the weights
+func (k Keeper) ParamsBondDenoms(ctx sdk.Context) (res types.WeightedDenoms) {
+	k.paramstore.Get(ctx, types.KeyBondDenoms, &res)
+	return
+}
+
+// ParamsPowerAlertThreshold returns the param PowerAlertThreshold
+func (k Keeper) ParamsPowerAlertThreshold(ctx sdk.Context) (res sdk.Dec) {
+	k.paramstore.Get(ctx, types.KeyPowerAlertThreshold, &res)
+	return
+}
+
+// ParamsMaxDelegations returns the param MaxDelegations
+func (k Keeper) ParamsMaxDelegations(ctx sdk.Context) (res uint64) {
+	k.paramstore.Get(ctx, types.KeyMaxDelegations, &res)
+	return
+}
+
+// GetPowerReduction returns the power reduction which is taking effect on the power index and the validator set
+func (k Keeper) GetPowerReduction(ctx sdk.Context) (powerReduction sdk.Int) {
+	store := ctx.KVStore(k.storeKey)
+	b := store.Get(types.KeyPowerReduction)
+	if b == nil {
+		return types.DefaultPowerReduction
+	}
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(b, &powerReduction)
+	return
+}
+
+// ParamsPowerTieBreak returns the param PowerTieBreak, only update the KeyPowerTieBreak in store after last epoch ends
+func (k Keeper) ParamsPowerTieBreak(ctx sdk.Context) (res string) {
+	k.paramstore.Get(ctx, types.KeyPowerTieBreak, &res)
+	return
+}
+
+// ParamsBondDenomDecimals returns the param BondDenomDecimals
+func (k Keeper) ParamsBondDenomDecimals(ctx sdk.Context) (res uint16) {
+	k.paramstore.Get(ctx, types.KeyBondDenomDecimals, &res)
+	return
+}
+
+// ParamsSelfDelegationOnly returns the param SelfDelegationOnly
+func (k Keeper) ParamsSelfDelegationOnly(ctx sdk.Context) (res bool) {
+	k.paramstore.Get(ctx, types.KeySelfDelegationOnly, &res)
+	return
+}
+
+// ParamsBondDenomMigration returns the param BondDenomMigration
+func (k Keeper) ParamsBondDenomMigration(ctx sdk.Context) (res bool) {
+	k.paramstore.Get(ctx, types.KeyBondDenomMigration, &res)
+	return
+}
+
+// ParamsMinValidators returns the param MinValidators
+func (k Keeper) ParamsMinValidators(ctx sdk.Context) (res uint16) {
+	k.paramstore.Get(ctx, types.KeyMinValidators, &res)
+	return
+}
+
+// ParamsUnjailMaxDepositPeriod returns the param UnjailMaxDepositPeriod
+func (k Keeper) ParamsUnjailMaxDepositPeriod(ctx sdk.Context) (res time.Duration) {
+	k.paramstore.Get(ctx, types.KeyUnjailMaxDepositPeriod, &res)
+	return
+}
+
+// ParamsUnjailMinDeposit returns the param UnjailMinDeposit
+func (k Keeper) ParamsUnjailMinDeposit(ctx sdk.Context) (res sdk.DecCoins) {
+	k.paramstore.Get(ctx, types.KeyUnjailMinDeposit, &res)
+	return
+}
+
+// ParamsUnjailVotingPeriod returns the param UnjailVotingPeriod
+func (k Keeper) ParamsUnjailVotingPeriod(ctx sdk.Context) (res time.Duration) {
+	k.paramstore.Get(ctx, types.KeyUnjailVotingPeriod, &res)
+	return
+}
+
+// ParamsCommissionChangeWindow returns the param CommissionChangeWindow
+func (k Keeper) ParamsCommissionChangeWindow(ctx sdk.Context) (res time.Duration) {
+	k.paramstore.Get(ctx, types.KeyCommissionChangeWindow, &res)
+	return
+}
+
+// ParamsMaxValidatorVotes returns the param MaxValidatorVotes
+func (k Keeper) ParamsMaxValidatorVotes(ctx sdk.Context) (res sdk.Int) {
+	k.paramstore.Get(ctx, types.KeyMaxValidatorVotes, &res)
+	return
+}
+
+// ParamsValidatorSelfUndelegateCooldown returns the param ValidatorSelfUndelegateCooldown
+func (k Keeper) ParamsValidatorSelfUndelegateCooldown(ctx sdk.Context) (res time.Duration) {
+	k.paramstore.Get(ctx, types.KeyValidatorSelfUndelegateCooldown, &res)
+	return
+}
+
+// ParamsMaxValidatorVoteShare returns the param MaxValidatorVoteShare
+func (k Keeper) ParamsMaxValidatorVoteShare(ctx sdk.Context) (res sdk.Dec) {
+	k.paramstore.Get(ctx, types.KeyMaxValidatorVoteShare, &res)
+	return
+}
+
+// ParamsMinDelegationTolerance returns the param MinDelegationTolerance
+func (k Keeper) ParamsMinDelegationTolerance(ctx sdk.Context) (res sdk.Dec) {
+	k.paramstore.Get(ctx, types.KeyMinDelegationTolerance, &res)
+	return
+}
+
+// ParamsRecordDelegatorHistory returns the param RecordDelegatorHistory
+func (k Keeper) ParamsRecordDelegatorHistory(ctx sdk.Context) (res bool) {
+	k.paramstore.Get(ctx, types.KeyRecordDelegatorHistory, &res)
+	return
+}
+
+// ParamsEpochBoundaryGrace returns the param EpochBoundaryGrace
+func (k Keeper) ParamsEpochBoundaryGrace(ctx sdk.Context) (res uint16) {
+	k.paramstore.Get(ctx, types.KeyEpochBoundaryGrace, &res)
+	return
+}
+
+// SetPowerReduction sets the power reduction into keystore and rebuilds the power index with it
+func (k Keeper) SetPowerReduction(ctx sdk.Context, powerReduction sdk.Int) {
+	k.rebuildPowerIndex(ctx, func(store sdk.KVStore) {
+		store.Set(types.KeyPowerReduction, k.cdc.MustMarshalBinaryLengthPrefixed(powerReduction))
+	})
+}
+
+// GetAppliedBondDenoms returns the bond denom weights which the tokens of the delegators are converted by, nil if they
+// have never been set
+func (k Keeper) GetAppliedBondDenoms(ctx sdk.Context) (bondDenoms types.WeightedDenoms) {
+	b := ctx.KVStore(k.storeKey).Get(types.AppliedBondDenomsKey)
+	if b == nil {
+		return nil
+	}
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(b, &bondDenoms)
+	return
+}
+
+// SetAppliedBondDenoms sets the bond denom weights which the tokens of the delegators are converted by into keystore
+func (k Keeper) SetAppliedBondDenoms(ctx sdk.Context, bondDenoms types.WeightedDenoms) {
+	ctx.KVStore(k.storeKey).Set(types.AppliedBondDenomsKey, k.cdc.MustMarshalBinaryLengthPrefixed(bondDenoms))
+}
+
+// GetPowerTieBreak returns the rule which is taking effect on ordering the validators with equal power in power index
+func (k Keeper) GetPowerTieBreak(ctx sdk.Context) string {
+	b := ctx.KVStore(k.storeKey).Get(types.KeyPowerTieBreak)
+	if b == nil {
+		return types.TieBreakByAddress
+	}
+	return string(b)
+}
+
+// SetPowerTieBreak sets the tie-break rule into keystore and rebuilds the power index with it
+func (k Keeper) SetPowerTieBreak(ctx sdk.Context, tieBreak string) {
+	k.rebuildPowerIndex(ctx, func(store sdk.KVStore) {
+		store.Set(types.KeyPowerTieBreak, []byte(tieBreak))
+	})
+}
+
+// rebuildPowerIndex rebuilds the power index after the settings that the power index keys depend on are updated
+func (k Keeper) rebuildPowerIndex(ctx sdk.Context, update func(store sdk.KVStore)) {
+	store := ctx.KVStore(k.storeKey)
+
+	// the keys in power index are computed by the old settings
+	iterator := k.ValidatorsPowerStoreIterator(ctx)
+	var valAddrs []sdk.ValAddress
+	var oldKeys [][]byte
+	for ; iterator.Valid(); iterator.Next() {
+		oldKeys = append(oldKeys, iterator.Key())
+		valAddrs = append(valAddrs, iterator.Value())
+	}
+	iterator.Close()
+	for _, key := range oldKeys {
+		store.Delete(key)
+	}
+
+	update(store)
+	for _, valAddr := range valAddrs {
+		store.Set(k.getValidatorPowerIndexKey(ctx, k.mustGetValidator(ctx, valAddr)), valAddr)
+	}
+}
+
+// DryRunParams applies the param changes to a copy of the current params without writing anything into store and
+// returns the merged params, which are not validated yet
+func (k Keeper) DryRunParams(ctx sdk.Context, changes []params.ParamChange) (types.Params, sdk.Error) {
+	merged := k.GetParams(ctx)
+	pairs := merged.ParamSetPairs()
+	for _, c := range changes {
+		if c.Subspace != DefaultParamspace {
+			return merged, params.ErrUnknownSubspace(params.DefaultCodespace, c.Subspace)
+		}
+		if len(c.Subkey) != 0 {
+			return merged, params.ErrSettingParameter(params.DefaultCodespace, c.Key, c.Subkey, c.Value,
+				"subkey is not supported by staking params")
+		}
+
+		var pair *params.ParamSetPair
+		for i := range pairs {
+			if bytes.Equal(pairs[i].Key, []byte(c.Key)) {
+				pair = &pairs[i]
+				break
+			}
+		}
+		if pair == nil {
+			return merged, params.ErrSettingParameter(params.DefaultCodespace, c.Key, c.Subkey, c.Value,
+				fmt.Sprintf("unknown key %s in staking params", c.Key))
+		}
+
+		// decode the value into a new instance as the subspace does, so that nothing of the old value remains
+		dest := reflect.New(reflect.TypeOf(pair.Value).Elem())
+		if err := k.cdc.UnmarshalJSON([]byte(c.Value), dest.Interface()); err != nil {
+			return merged, params.ErrSettingParameter(params.DefaultCodespace, c.Key, c.Subkey, c.Value, err.Error())
+		}
+		reflect.ValueOf(pair.Value).Elem().Set(dest.Elem())
+	}
+
+	return merged, nil
+}
+
+// ValidateParamChange checks a param change of staking against the current state before it's applied. It rejects the
+// change of the primary bond denom while there are tokens bonded, which would orphan all the existing votes, unless
+// the param BondDenomMigration is turned on for a deliberate migration
+func (k Keeper) ValidateParamChange(ctx sdk.Context, change params.ParamChange) sdk.Error {
+	if change.Subspace != DefaultParamspace {
+		return nil
+	}
+
+	var newDenom string
+	switch key := []byte(change.Key); {
+	case bytes.Equal(key, types.KeyBondDenom):
+		if err := k.cdc.UnmarshalJSON([]byte(change.Value), &newDenom); err != nil {
+			return params.ErrSettingParameter(params.DefaultCodespace, change.Key, change.Subkey, change.Value,
+				err.Error())
+		}
+	case bytes.Equal(key, types.KeyBondDenoms):
+		var bondDenoms types.WeightedDenoms
+		if err := k.cdc.UnmarshalJSON([]byte(change.Value), &bondDenoms); err != nil {
+			return params.ErrSettingParameter(params.DefaultCodespace, change.Key, change.Subkey, change.Value,
+				err.Error())
+		}
+		newDenom = bondDenoms.Primary()
+	default:
+		return nil
+	}
+
+	if newDenom == k.BondDenom(ctx) || k.ParamsBondDenomMigration(ctx) || k.GetBondedPool(ctx).GetCoins().IsZero() {
+		return nil
+	}
+	return types.ErrBondDenomChange(types.DefaultCodespace, k.BondDenom(ctx), newDenom)
+}
@@ -0,0 +1,202 @@
+package keeper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/store/prefix"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/params"
+	"github.com/okex/okchain/x/staking/types"
+	"github.com/stretchr/testify/require"
+)
+
+// commitBlock commits the multistore to clear the transient stores as a new block begins
+func commitBlock(ctx sdk.Context) {
+	ctx.MultiStore().(sdk.CommitMultiStore).Commit()
+}
+
+func TestGetParamsCached(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, 0)
+	keeper := mkeeper.Keeper
+	commitBlock(ctx)
+
+	// load once and cache it
+	params := keeper.GetParamsCached(ctx)
+	require.True(t, params.Equal(keeper.GetParams(ctx)))
+	require.NotNil(t, ctx.TransientStore(keeper.storeTKey).Get(types.ParamsCacheKey))
+	require.True(t, keeper.GetParamsCached(ctx).Equal(params))
+
+	// invalidated by SetParams
+	params.MaxValidators++
+	keeper.SetParams(ctx, params)
+	require.Nil(t, ctx.TransientStore(keeper.storeTKey).Get(types.ParamsCacheKey))
+	require.Equal(t, params.MaxValidators, keeper.GetParamsCached(ctx).MaxValidators)
+
+	// a param changed by gov directly through the subspace in the middle of a block
+	ctx, _, mkeeper = CreateTestInput(t, false, 0)
+	keeper = mkeeper.Keeper
+	commitBlock(ctx)
+	params = keeper.GetParamsCached(ctx)
+	require.NotNil(t, ctx.TransientStore(keeper.storeTKey).Get(types.ParamsCacheKey))
+	keeper.paramstore.Set(ctx, types.KeyMaxValsToVote, params.MaxValsToVote+1)
+	require.Equal(t, params.MaxValsToVote+1, keeper.GetParamsCached(ctx).MaxValsToVote)
+}
+
+func TestSetUnbondingTime(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, 0)
+	keeper := mkeeper.Keeper
+	oldUnbondingTime := keeper.UnbondingTime(ctx)
+	window := keeper.ParamsCommissionChangeWindow(ctx)
+
+	// out of the range allowed
+	for _, invalid := range []time.Duration{0, -time.Hour, window, types.MaxUnbondingTime + time.Nanosecond} {
+		require.NotNil(t, keeper.SetUnbondingTime(ctx, invalid), invalid)
+	}
+	_, found := keeper.GetPendingUnbondingTime(ctx)
+	require.False(t, found)
+
+	// scheduled but not applied yet
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	require.Nil(t, keeper.SetUnbondingTime(ctx, types.MaxUnbondingTime))
+	pending, found := keeper.GetPendingUnbondingTime(ctx)
+	require.True(t, found)
+	require.Equal(t, types.MaxUnbondingTime, pending)
+	require.Equal(t, oldUnbondingTime, keeper.UnbondingTime(ctx))
+	events := ctx.EventManager().Events()
+	require.Equal(t, 1, len(events))
+	require.Equal(t, types.EventTypeSetUnbondingTime, events[0].Type)
+
+	// the later one overrides the earlier one in the same epoch
+	require.Nil(t, keeper.SetUnbondingTime(ctx, window+time.Hour))
+	keeper.ApplyPendingUnbondingTime(ctx)
+	require.Equal(t, window+time.Hour, keeper.UnbondingTime(ctx))
+	require.True(t, keeper.GetParams(ctx).Validate() == nil)
+	_, found = keeper.GetPendingUnbondingTime(ctx)
+	require.False(t, found)
+}
+
+func TestNormalizeDecParams(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, 0)
+	keeper := mkeeper.Keeper
+	params := keeper.GetParams(ctx)
+	canonical := keeper.cdc.MustMarshalJSON(params.MinDelegation)
+
+	// nothing to normalize
+	require.Empty(t, keeper.NormalizeDecParams(ctx))
+
+	// the param stored in a non-canonical encoding by an earlier version
+	paramStore := prefix.NewStore(ctx.KVStore(mkeeper.ParamsKey), append([]byte(DefaultParamspace), '/'))
+	paramStore.Set(types.KeyMinDelegation, []byte(`"0.0001"`))
+	require.NotEqual(t, canonical, keeper.paramstore.GetRaw(ctx, types.KeyMinDelegation))
+	require.True(t, keeper.ParamsMinDelegation(ctx).Equal(params.MinDelegation))
+
+	normalizedKeys := keeper.NormalizeDecParams(ctx)
+	require.Equal(t, [][]byte{types.KeyMinDelegation}, normalizedKeys)
+	require.Equal(t, canonical, keeper.paramstore.GetRaw(ctx, types.KeyMinDelegation))
+	require.True(t, keeper.GetParams(ctx).Equal(params))
+
+	// the JSON round trip is stable afterwards
+	bz := keeper.cdc.MustMarshalJSON(keeper.GetParams(ctx))
+	var decoded types.Params
+	keeper.cdc.MustUnmarshalJSON(bz, &decoded)
+	require.Equal(t, bz, keeper.cdc.MustMarshalJSON(decoded))
+
+	// running it again changes nothing
+	require.Empty(t, keeper.NormalizeDecParams(ctx))
+}
+
+func TestGetParamsSubset(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, 0)
+	keeper := mkeeper.Keeper
+	params := keeper.GetParams(ctx)
+	params.MaxValsToVote = 7
+	params.PowerTieBreak = types.TieBreakOldestFirst
+	keeper.SetParams(ctx, params)
+
+	subset, err := keeper.GetParamsSubset(ctx, [][]byte{types.KeyMaxValsToVote, types.KeyPowerTieBreak,
+		types.KeyMinDelegation})
+	require.Nil(t, err)
+	require.Equal(t, uint16(7), subset.MaxValsToVote)
+	require.Equal(t, types.TieBreakOldestFirst, subset.PowerTieBreak)
+	require.Equal(t, params.MinDelegation, subset.MinDelegation)
+	// the others aren't read
+	require.Equal(t, uint16(0), subset.MaxValidators)
+	require.Empty(t, subset.BondDenom)
+	require.True(t, subset.MinSelfDelegationLimit.IsNil())
+
+	// all the keys make up the whole params
+	var keys [][]byte
+	for _, pair := range params.ParamSetPairs() {
+		keys = append(keys, pair.Key)
+	}
+	subset, err = keeper.GetParamsSubset(ctx, keys)
+	require.Nil(t, err)
+	require.True(t, subset.Equal(params))
+
+	// unknown key
+	_, err = keeper.GetParamsSubset(ctx, [][]byte{types.KeyMaxValsToVote, []byte("Unknown")})
+	require.NotNil(t, err)
+}
+
+func TestValidateParamChange(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mkeeper.Keeper
+	bondDenom := keeper.BondDenom(ctx)
+	changeDenom := params.NewParamChange(DefaultParamspace, string(types.KeyBondDenom), `"xxb"`)
+	changeDenoms := params.NewParamChange(DefaultParamspace, string(types.KeyBondDenoms),
+		`[{"denom":"xxb","weight":"1.00000000"}]`)
+	sameDenom := params.NewParamChange(DefaultParamspace, string(types.KeyBondDenom), `"`+bondDenom+`"`)
+	otherKey := params.NewParamChange(DefaultParamspace, string(types.KeyMaxValsToVote), `10`)
+	otherSubspace := params.NewParamChange("gov", string(types.KeyBondDenom), `"xxb"`)
+
+	// no tokens bonded
+	require.Nil(t, keeper.ValidateParamChange(ctx, changeDenom))
+	require.Nil(t, keeper.ValidateParamChange(ctx, changeDenoms))
+
+	// tokens bonded
+	require.Nil(t, keeper.Delegate(ctx, addrDels[0], sdk.NewDecCoinFromDec(bondDenom, sdk.NewDec(100))))
+	require.NotNil(t, keeper.ValidateParamChange(ctx, changeDenom))
+	require.NotNil(t, keeper.ValidateParamChange(ctx, changeDenoms))
+	require.Nil(t, keeper.ValidateParamChange(ctx, sameDenom))
+	require.Nil(t, keeper.ValidateParamChange(ctx, otherKey))
+	require.Nil(t, keeper.ValidateParamChange(ctx, otherSubspace))
+	require.NotNil(t, keeper.ValidateParamChange(ctx,
+		params.NewParamChange(DefaultParamspace, string(types.KeyBondDenom), `xxb`)))
+
+	// the migration is enabled explicitly
+	p := keeper.GetParams(ctx)
+	p.BondDenomMigration = true
+	keeper.SetParams(ctx, p)
+	require.Nil(t, keeper.ValidateParamChange(ctx, changeDenom))
+	require.Nil(t, keeper.ValidateParamChange(ctx, changeDenoms))
+}
+
+// BenchmarkGetParams compares reading the params from the paramstore, where every param is read from the iavl store
+// and decoded from JSON, with reading them from the transient cache and reading only a subset of them
+func BenchmarkGetParams(b *testing.B) {
+	ctx, _, mkeeper := CreateTestInput(&testing.T{}, false, 0)
+	keeper := mkeeper.Keeper
+	commitBlock(ctx)
+
+	b.Run("uncached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			keeper.GetParams(ctx)
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			keeper.GetParamsCached(ctx)
+		}
+	})
+
+	b.Run("subset", func(b *testing.B) {
+		keys := [][]byte{types.KeyMaxValsToVote, types.KeyMinDelegation}
+		for i := 0; i < b.N; i++ {
+			if _, err := keeper.GetParamsSubset(ctx, keys); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -16,6 +16,10 @@ func (k Keeper) GetNotBondedPool(ctx sdk.Context) (notBondedPool exported.Module
 	return k.supplyKeeper.GetModuleAccount(ctx, types.NotBondedPoolName)
 }
 
+// NOTE: the helpers below are the only places where the staking tokens are moved in or out of the pools, and between
+// the pools. The bonded pool keeps all the delegated tokens and the msds, while the not bonded pool keeps the tokens
+// undelegating and the ones escrowed for the deferred delegations
+
 // bondedTokensToNotBonded transfers coins from the bonded to the not bonded pool within staking
 func (k Keeper) bondedTokensToNotBonded(ctx sdk.Context, tokens sdk.DecCoin) {
 
@@ -26,6 +30,29 @@ func (k Keeper) bondedTokensToNotBonded(ctx sdk.Context, tokens sdk.DecCoin) {
 	}
 }
 
+// notBondedTokensToBonded transfers the coins escrowed from the not bonded to the bonded pool within staking
+func (k Keeper) notBondedTokensToBonded(ctx sdk.Context, coins sdk.DecCoins) {
+	err := k.supplyKeeper.SendCoinsFromModuleToModule(ctx, types.NotBondedPoolName, types.BondedPoolName, coins)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// delegateToNotBondedPool escrows coins from the account of a delegator into the not bonded pool
+func (k Keeper) delegateToNotBondedPool(ctx sdk.Context, delAddr sdk.AccAddress, coins sdk.DecCoins) sdk.Error {
+	return k.supplyKeeper.DelegateCoinsFromAccountToModule(ctx, delAddr, types.NotBondedPoolName, coins)
+}
+
+// delegateToBondedPool transfers coins from the account of a delegator into the bonded pool
+func (k Keeper) delegateToBondedPool(ctx sdk.Context, delAddr sdk.AccAddress, coins sdk.DecCoins) sdk.Error {
+	return k.supplyKeeper.DelegateCoinsFromAccountToModule(ctx, delAddr, types.BondedPoolName, coins)
+}
+
+// undelegateFromNotBondedPool returns coins from the not bonded pool to the account of a delegator
+func (k Keeper) undelegateFromNotBondedPool(ctx sdk.Context, delAddr sdk.AccAddress, coins sdk.DecCoins) sdk.Error {
+	return k.supplyKeeper.UndelegateCoinsFromModuleToAccount(ctx, types.NotBondedPoolName, delAddr, coins)
+}
+
 // TotalBondedTokens total staking tokens supply which is bonded
 // TODO:No usages found in project files,remove it later
 func (k Keeper) TotalBondedTokens(ctx sdk.Context) sdk.Dec {
@@ -48,3 +75,51 @@ func (k Keeper) BondedRatio(ctx sdk.Context) sdk.Dec {
 	}
 	return sdk.ZeroDec()
 }
+
+// GetBlockReward gets the reward minted per block by the minter of the mint module, which is recalculated from the
+// inflation rate over the staking token supply once a year, together with the share of each bonded token
+func (k Keeper) GetBlockReward(ctx sdk.Context) (types.BlockReward, sdk.Error) {
+	if k.mintKeeper == nil {
+		return types.BlockReward{}, sdk.ErrInternal("mint keeper hasn't been set")
+	}
+	if k.GetBondedPool(ctx) == nil {
+		return types.BlockReward{}, sdk.ErrInternal("pool accounts haven't been set")
+	}
+
+	mintParams := k.mintKeeper.GetParams(ctx)
+	mintedPerBlock := k.mintKeeper.GetMinterCustom(ctx).MintedPerBlock.AmountOf(mintParams.MintDenom)
+	return types.NewBlockReward(sdk.NewDecCoinFromDec(mintParams.MintDenom, mintedPerBlock), k.BondDenom(ctx),
+		mintParams.InflationRate, mintParams.BlocksPerYear, k.StakingTokenSupply(ctx), k.TotalBondedTokens(ctx)), nil
+}
+
+// SetBondedSnapshot sets the bonded snapshot of an epoch into store
+func (k Keeper) SetBondedSnapshot(ctx sdk.Context, snapshot types.BondedSnapshot) {
+	ctx.KVStore(k.storeKey).Set(types.GetBondedSnapshotKey(snapshot.EpochNumber),
+		k.cdc.MustMarshalBinaryLengthPrefixed(snapshot))
+}
+
+// RecordBondedSnapshot snapshots the bonded pool for the epoch ending at the current block and prunes the snapshot
+// which falls out of the retention window. It's called before the epoch number is increased
+func (k Keeper) RecordBondedSnapshot(ctx sdk.Context) {
+	epochNumber := k.CurrentEpochNumber(ctx)
+	k.SetBondedSnapshot(ctx, types.NewBondedSnapshot(epochNumber, ctx.BlockHeight(), k.GetBondedPool(ctx).GetCoins()))
+	if epochNumber >= types.BondedHistoryRetention {
+		ctx.KVStore(k.storeKey).Delete(types.GetBondedSnapshotKey(epochNumber - types.BondedHistoryRetention))
+	}
+}
+
+// GetBondedHistory returns the bonded snapshots of the epochs from startEpoch to endEpoch, both ends included. The
+// epochs pruned or not ended yet are absent from the history
+func (k Keeper) GetBondedHistory(ctx sdk.Context, startEpoch, endEpoch uint64) types.BondedHistory {
+	history := types.BondedHistory{}
+	iterator := ctx.KVStore(k.storeKey).Iterator(types.GetBondedSnapshotKey(startEpoch),
+		sdk.PrefixEndBytes(types.GetBondedSnapshotKey(endEpoch)))
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		var snapshot types.BondedSnapshot
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &snapshot)
+		history = append(history, snapshot)
+	}
+	return history
+}
@@ -0,0 +1,124 @@
+package keeper
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/okex/okchain/x/staking/types"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+func TestPoolBalances(t *testing.T) {
+	ctx, accKeeper, mkeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mkeeper.Keeper
+	vals := createVals(ctx, 1, keeper)
+	delAddr := addrDels[0]
+	bondDenom := sdk.DefaultBondDenom
+	checkPools := func(expBonded, expNotBonded int64) {
+		require.True(t, keeper.GetBondedPool(ctx).GetCoins().AmountOf(bondDenom).Equal(sdk.NewDec(expBonded)))
+		require.True(t, keeper.GetNotBondedPool(ctx).GetCoins().AmountOf(bondDenom).Equal(sdk.NewDec(expNotBonded)))
+	}
+	balance := func(addr sdk.AccAddress) sdk.Dec {
+		return accKeeper.GetAccount(ctx, addr).GetCoins().AmountOf(bondDenom)
+	}
+	checkPools(0, 0)
+
+	// delegate
+	initBalance := balance(delAddr)
+	require.Nil(t, keeper.Delegate(ctx, delAddr, sdk.NewDecCoinFromDec(bondDenom, sdk.NewDec(100))))
+	checkPools(100, 0)
+	require.True(t, balance(delAddr).Equal(initBalance.Sub(sdk.NewDec(100))))
+
+	// failed delegation moves nothing
+	require.NotNil(t, keeper.Delegate(ctx, delAddr, sdk.NewDecCoinFromDec(bondDenom, initBalance)))
+	checkPools(100, 0)
+
+	// unbond
+	_, err := keeper.BeginUnbonding(ctx, delAddr, sdk.NewDecCoinFromDec(bondDenom, sdk.NewDec(40)))
+	require.Nil(t, err)
+	checkPools(60, 40)
+	_, err = keeper.BeginUnbonding(ctx, delAddr, sdk.NewDecCoinFromDec(bondDenom, sdk.NewDec(60)))
+	require.Nil(t, err)
+	checkPools(0, 100)
+
+	// complete the undelegation
+	_, err = keeper.CompleteUndelegation(ctx, delAddr)
+	require.Nil(t, err)
+	checkPools(0, 0)
+	require.True(t, balance(delAddr).Equal(initBalance))
+
+	// vote and unbond the msd
+	ownerAddr := sdk.AccAddress(vals[0].OperatorAddress)
+	validator := keeper.mustGetValidator(ctx, vals[0].OperatorAddress)
+	require.Nil(t, keeper.VoteMinSelfDelegation(ctx, ownerAddr, &validator,
+		sdk.NewDecCoinFromDec(bondDenom, validator.MinSelfDelegation)))
+	checkPools(1, 0)
+	_, err = keeper.UndelegateMinSelfDelegation(ctx, ownerAddr, validator)
+	require.Nil(t, err)
+	checkPools(0, 1)
+
+	// the transfer between the pools panics without the tokens enough
+	require.Panics(t, func() {
+		keeper.bondedTokensToNotBonded(ctx, sdk.NewDecCoinFromDec(bondDenom, sdk.OneDec()))
+	})
+}
+
+func TestBondedHistory(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mkeeper.Keeper
+	querier := NewQuerier(keeper)
+	bondDenom := sdk.DefaultBondDenom
+	queryHistory := func(startEpoch, endEpoch uint64) (history types.BondedHistory, err sdk.Error) {
+		bz, jsonErr := types.ModuleCdc.MarshalJSON(types.NewQueryBondedHistoryParams(startEpoch, endEpoch))
+		require.NoError(t, jsonErr)
+		data, err := querier(ctx, []string{types.QueryBondedHistory}, abci.RequestQuery{Data: bz})
+		if err != nil {
+			return
+		}
+		require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &history))
+		return
+	}
+	endEpoch := func() {
+		ctx = ctx.WithBlockHeight(ctx.BlockHeight() + int64(keeper.GetEpoch(ctx)))
+		keeper.RecordBondedSnapshot(ctx)
+		keeper.SetEpochNumber(ctx, keeper.CurrentEpochNumber(ctx)+1)
+	}
+
+	// the bonded pool grows by 100 every epoch
+	for i := 0; i < 4; i++ {
+		require.Nil(t, keeper.Delegate(ctx, addrDels[0], sdk.NewDecCoinFromDec(bondDenom, sdk.NewDec(100))))
+		endEpoch()
+	}
+	history, err := queryHistory(0, 10)
+	require.Nil(t, err)
+	require.Equal(t, 4, len(history))
+	for i, snapshot := range history {
+		require.Equal(t, uint64(i), snapshot.EpochNumber)
+		require.Equal(t, int64(i+1)*int64(keeper.GetEpoch(ctx)), snapshot.Height)
+		require.True(t, snapshot.BondedTokens.AmountOf(bondDenom).Equal(sdk.NewDec(int64(i+1)*100)))
+	}
+
+	// a sub range
+	history, err = queryHistory(1, 2)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(history))
+	require.Equal(t, uint64(1), history[0].EpochNumber)
+	require.Equal(t, uint64(2), history[1].EpochNumber)
+
+	// invalid ranges
+	_, err = queryHistory(2, 1)
+	require.NotNil(t, err)
+	_, err = queryHistory(0, types.BondedHistoryRetention)
+	require.NotNil(t, err)
+
+	// the snapshot out of the retention window is pruned
+	keeper.SetEpochNumber(ctx, types.BondedHistoryRetention)
+	endEpoch()
+	history, err = queryHistory(0, 10)
+	require.Nil(t, err)
+	require.Equal(t, 3, len(history))
+	require.Equal(t, uint64(1), history[0].EpochNumber)
+	history = keeper.GetBondedHistory(ctx, types.BondedHistoryRetention, types.BondedHistoryRetention)
+	require.Equal(t, 1, len(history))
+}
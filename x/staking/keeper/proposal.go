@@ -0,0 +1,192 @@
+package keeper
+
+import (
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/supply"
+	"github.com/okex/okchain/x/common"
+	govtypes "github.com/okex/okchain/x/gov/types"
+	"github.com/okex/okchain/x/staking/types"
+)
+
+// GetMinDeposit implements ProposalHandler interface. The corrections of the validator states share the deposit and
+// the periods with the unjail proposals, both of which are the rare rescues of a validator
+func (k Keeper) GetMinDeposit(ctx sdk.Context, content govtypes.Content) (minDeposit sdk.DecCoins) {
+	switch content.(type) {
+	case types.UnjailValidatorProposal, types.ForceUpdateValidatorStateProposal:
+		minDeposit = k.ParamsUnjailMinDeposit(ctx)
+	}
+
+	return
+}
+
+// GetMaxDepositPeriod implements ProposalHandler interface
+func (k Keeper) GetMaxDepositPeriod(ctx sdk.Context, content govtypes.Content) (maxDepositPeriod time.Duration) {
+	switch content.(type) {
+	case types.UnjailValidatorProposal, types.ForceUpdateValidatorStateProposal:
+		maxDepositPeriod = k.ParamsUnjailMaxDepositPeriod(ctx)
+	}
+
+	return
+}
+
+// GetVotingPeriod implements ProposalHandler interface
+func (k Keeper) GetVotingPeriod(ctx sdk.Context, content govtypes.Content) (votingPeriod time.Duration) {
+	switch content.(type) {
+	case types.UnjailValidatorProposal, types.ForceUpdateValidatorStateProposal:
+		votingPeriod = k.ParamsUnjailVotingPeriod(ctx)
+	}
+
+	return
+}
+
+// CheckMsgSubmitProposal implements ProposalHandler interface
+func (k Keeper) CheckMsgSubmitProposal(ctx sdk.Context, msg govtypes.MsgSubmitProposal) sdk.Error {
+	switch content := msg.Content.(type) {
+	case types.UnjailValidatorProposal:
+		if err := k.checkProposerAndInitialDeposit(ctx, msg); err != nil {
+			return err
+		}
+		return k.ValidateUnjailValidator(ctx, content.ValidatorAddress)
+	case types.ForceUpdateValidatorStateProposal:
+		if err := k.checkProposerAndInitialDeposit(ctx, msg); err != nil {
+			return err
+		}
+		return k.ValidateForceUpdateValidatorState(ctx, content.ValidatorAddress, content.Status,
+			content.DelegatorShares)
+	default:
+		return sdk.ErrUnknownRequest(fmt.Sprintf("unrecognized staking proposal content type: %T", content))
+	}
+}
+
+// checkProposerAndInitialDeposit checks that the proposer of a staking proposal is a validator and that the initial
+// deposit is no less than a ratio of the min deposit
+func (k Keeper) checkProposerAndInitialDeposit(ctx sdk.Context, msg govtypes.MsgSubmitProposal) sdk.Error {
+	// check message sender is current validator
+	if !k.IsValidator(ctx, msg.Proposer) {
+		return govtypes.ErrInvalidProposer(types.DefaultCodespace,
+			fmt.Sprintf("proposer of %s proposal must be validator", msg.Content.ProposalType()))
+	}
+	// check initial deposit more than or equal to ratio of MinDeposit
+	initDeposit := k.ParamsUnjailMinDeposit(ctx).MulDec(sdk.NewDecWithPrec(1, 1))
+	if err := common.HasSufficientCoins(msg.Proposer, msg.InitialDeposit, initDeposit); err != nil {
+		return sdk.ErrInvalidCoins(fmt.Sprintf("InitialDeposit must not be less than %s", initDeposit.String()))
+	}
+	return nil
+}
+
+// ValidateUnjailValidator checks whether a validator is able to be unjailed by governance, that is, it's jailed but
+// not destroyed
+func (k Keeper) ValidateUnjailValidator(ctx sdk.Context, valAddr sdk.ValAddress) sdk.Error {
+	validator, found := k.GetValidator(ctx, valAddr)
+	if !found {
+		return types.ErrNoValidatorFound(types.DefaultCodespace, valAddr.String())
+	}
+	if validator.MinSelfDelegation.IsZero() {
+		return types.ErrNoMinSelfDelegation(types.DefaultCodespace, valAddr.String())
+	}
+	if !validator.Jailed {
+		return types.ErrValidatorNotJailed(types.DefaultCodespace, valAddr.String())
+	}
+	return nil
+}
+
+// ValidateForceUpdateValidatorState checks whether the state of a validator is able to be corrected to the given
+// status and delegator shares. The status only moves along the legal transitions of a validator which the recompute of
+// the validator set is able to carry on with
+func (k Keeper) ValidateForceUpdateValidatorState(ctx sdk.Context, valAddr sdk.ValAddress, status sdk.BondStatus,
+	delegatorShares sdk.Dec) sdk.Error {
+	validator, found := k.GetValidator(ctx, valAddr)
+	if !found {
+		return types.ErrNoValidatorFound(types.DefaultCodespace, valAddr.String())
+	}
+	if (status != sdk.Unbonded && status != sdk.Unbonding && status != sdk.Bonded) ||
+		delegatorShares.IsNil() || delegatorShares.IsNegative() {
+		return types.ErrInvalidStateCorrection(types.DefaultCodespace, status, delegatorShares)
+	}
+	if status != validator.Status && types.ValidateStatusTransition(validator.Status, status) != nil {
+		return types.ErrInvalidStateCorrection(types.DefaultCodespace, status, delegatorShares)
+	}
+
+	// only the corrections absorbed by the recompute of the validator set are allowed
+	switch {
+	case status == sdk.Bonded && validator.Status != sdk.Bonded:
+		// the recompute bonds the validator only if it ranks within the validator set with the shares corrected
+		cacheCtx, _ := ctx.CacheContext()
+		k.correctValidatorShares(cacheCtx, validator, delegatorShares)
+		if !k.isWithinValidatorSet(cacheCtx, valAddr) {
+			return types.ErrInvalidStateCorrection(types.DefaultCodespace, status, delegatorShares)
+		}
+	case status == sdk.Unbonded && k.hasLastValidatorPower(ctx, valAddr):
+		// the validator which has just begun unbonding has to leave the last validator set first
+		return types.ErrInvalidStateCorrection(types.DefaultCodespace, status, delegatorShares)
+	}
+	return nil
+}
+
+// ForceUpdateValidatorState corrects the status and the delegator shares of a validator for the rare recovery of a
+// corrupted state, e.g. the repair after a bug. It's executed by a passed ForceUpdateValidatorStateProposal and only
+// allowed to the gov module account. The tokens recorded on the validator are dropped as usual. A change of the status
+// goes through the recompute of the validator set at the end of the block, which updates the last validator set and
+// Tendermint: a validator corrected to bonded is bonded by the recompute, and a bonded one corrected to unbonding
+// begins unbonding at once and leaves the set with a zero power update. An event with the state before and after is
+// emitted for the audit
+func (k Keeper) ForceUpdateValidatorState(ctx sdk.Context, authority sdk.AccAddress, valAddr sdk.ValAddress,
+	status sdk.BondStatus, delegatorShares sdk.Dec) sdk.Error {
+	if authority.Empty() || !authority.Equals(supply.NewModuleAddress(govtypes.ModuleName)) {
+		return types.ErrUnauthorizedStateCorrection(types.DefaultCodespace, authority.String())
+	}
+	if err := k.ValidateForceUpdateValidatorState(ctx, valAddr, status, delegatorShares); err != nil {
+		return err
+	}
+
+	oldValidator := k.mustGetValidator(ctx, valAddr)
+	validator := k.correctValidatorShares(ctx, oldValidator, delegatorShares)
+	if status != validator.Status {
+		switch status {
+		case sdk.Unbonding:
+			validator = k.beginUnbondingValidator(ctx, validator)
+		case sdk.Unbonded:
+			// the unbonding validator completes ahead of the queue
+			k.DeleteValidatorQueue(ctx, validator)
+			validator = k.completeUnbondingValidator(ctx, validator)
+		}
+		ctx.TransientStore(k.storeTKey).Set(types.ValidatorSetRecomputeKey, []byte{})
+	}
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeCorrectValidator,
+			sdk.NewAttribute(types.AttributeKeyValidator, valAddr.String()),
+			sdk.NewAttribute(types.AttributeKeyAuthority, authority.String()),
+			sdk.NewAttribute(types.AttributeKeyOldStatus, oldValidator.Status.String()),
+			sdk.NewAttribute(types.AttributeKeyNewStatus, status.String()),
+			sdk.NewAttribute(types.AttributeKeyOldShares, oldValidator.DelegatorShares.String()),
+			sdk.NewAttribute(types.AttributeKeyNewShares, validator.DelegatorShares.String()),
+			sdk.NewAttribute(types.AttributeKeyOldTokens, oldValidator.Tokens.String()),
+		),
+	)
+	return nil
+}
+
+// correctValidatorShares sets the delegator shares of a validator and drops its tokens, refreshing the power index
+func (k Keeper) correctValidatorShares(ctx sdk.Context, validator types.Validator, delegatorShares sdk.Dec,
+) types.Validator {
+	// ATTENTION:update DelegatorShares must go after DeleteValidatorByPowerIndex
+	k.DeleteValidatorByPowerIndex(ctx, validator)
+	validator.DelegatorShares = delegatorShares
+	validator.Tokens = sdk.ZeroInt()
+	k.SetValidator(ctx, validator)
+	k.SetValidatorByPowerIndex(ctx, validator)
+	return validator
+}
+
+// nolint
+func (Keeper) AfterSubmitProposalHandler(_ sdk.Context, _ govtypes.Proposal) {}
+func (Keeper) VoteHandler(_ sdk.Context, _ govtypes.Proposal, _ govtypes.Vote) (string, sdk.Error) {
+	return "", nil
+}
+func (Keeper) AfterDepositPeriodPassed(_ sdk.Context, _ govtypes.Proposal) {}
+func (Keeper) RejectedHandler(_ sdk.Context, _ govtypes.Content)           {}
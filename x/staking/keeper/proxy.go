@@ -58,7 +58,6 @@ func (k Keeper) UpdateVotes(ctx sdk.Context, delAddr sdk.AccAddress, tokens sdk.
 		return nil
 	}
 
-	lenVals := len(vals)
 	votes, sdkErr := calculateWeight(ctx.BlockTime().Unix(), tokens)
 	if sdkErr != nil {
 		return sdkErr
@@ -69,12 +68,37 @@ func (k Keeper) UpdateVotes(ctx sdk.Context, delAddr sdk.AccAddress, tokens sdk.
 		return types.ErrNoDelegatorExisted(types.DefaultCodespace, delAddr.String())
 	}
 
+	// only the votes to the first MaxValsToVote validators are counted, as GetEffectiveVotes tells, so the ones in
+	// excess of the cap lowered after the delegator voted are withdrawn
+	if maxValsToVote := int(k.ParamsMaxValsToVote(ctx)); len(vals) > maxValsToVote {
+		k.WithdrawLastVotes(ctx, delAddr, vals[maxValsToVote:], lastVotes)
+		vals = vals[:maxValsToVote]
+		delegator.ValidatorAddresses = make([]sdk.ValAddress, len(vals))
+		for i, val := range vals {
+			delegator.ValidatorAddresses[i] = val.OperatorAddress
+		}
+	}
+	lenVals := len(vals)
+
+	if votes.GT(lastVotes) {
+		extraVotes := votes.Sub(lastVotes)
+		if sdkErr = k.checkValidatorVoteShareCap(ctx, vals, extraVotes, extraVotes.MulInt64(int64(lenVals))); sdkErr != nil {
+			return sdkErr
+		}
+	}
+
 	for i := 0; i < lenVals; i++ {
 		if vals[i].MinSelfDelegation.IsZero() {
 			return types.ErrVoteDismission(types.DefaultCodespace, vals[i].OperatorAddress.String())
 		}
+		if votes.GT(lastVotes) {
+			if sdkErr = k.checkValidatorVotesCap(ctx, vals[i], votes.Sub(lastVotes)); sdkErr != nil {
+				return sdkErr
+			}
+		}
 
 		// 1.delete related store
+		k.BeforeValidatorModified(ctx, vals[i].OperatorAddress)
 		k.DeleteValidatorByPowerIndex(ctx, vals[i])
 
 		// 2.update vote
@@ -101,12 +125,51 @@ func (k Keeper) VoteValidators(ctx sdk.Context, delAddr sdk.AccAddress, vals typ
 	if sdkErr != nil {
 		return sdk.Dec{}, sdkErr
 	}
+	for i := 0; i < lenVals; i++ {
+		if sdkErr = k.checkValidatorVotesCap(ctx, vals[i], votes); sdkErr != nil {
+			return sdk.Dec{}, sdkErr
+		}
+	}
+	if sdkErr = k.checkValidatorVoteShareCap(ctx, vals, votes, votes.MulInt64(int64(lenVals))); sdkErr != nil {
+		return sdk.Dec{}, sdkErr
+	}
 	for i := 0; i < lenVals; i++ {
 		k.vote(ctx, delAddr, vals[i], votes)
 	}
 	return votes, nil
 }
 
+// checkValidatorVotesCap checks whether the votes of a validator would exceed the param MaxValidatorVotes after
+// the extra votes are added. Zero MaxValidatorVotes means no limit
+func (k Keeper) checkValidatorVotesCap(ctx sdk.Context, val types.Validator, extraVotes sdk.Dec) sdk.Error {
+	maxValidatorVotes := k.ParamsMaxValidatorVotes(ctx)
+	if maxValidatorVotes.IsZero() || val.GetDelegatorShares().Add(extraVotes).LTE(maxValidatorVotes.ToDec()) {
+		return nil
+	}
+
+	return types.ErrValidatorVotesCapReached(types.DefaultCodespace, val.OperatorAddress.String(), maxValidatorVotes)
+}
+
+// checkValidatorVoteShareCap checks whether the votes of any of the validators would exceed the param
+// MaxValidatorVoteShare of the total votes of all the validators, after the extra votes are added to each of them and
+// the total votes grow by totalExtraVotes. Zero MaxValidatorVoteShare means no limit
+func (k Keeper) checkValidatorVoteShareCap(ctx sdk.Context, vals types.Validators, extraVotes,
+	totalExtraVotes sdk.Dec) sdk.Error {
+	maxVoteShare := k.ParamsMaxValidatorVoteShare(ctx)
+	if maxVoteShare.IsZero() || len(vals) == 0 {
+		return nil
+	}
+
+	maxVotes := k.GetTotalVotes(ctx).Add(totalExtraVotes).Mul(maxVoteShare)
+	for _, val := range vals {
+		if val.GetDelegatorShares().Add(extraVotes).GT(maxVotes) {
+			return types.ErrValidatorVoteShareCapReached(types.DefaultCodespace, val.OperatorAddress.String(),
+				maxVoteShare)
+		}
+	}
+	return nil
+}
+
 // WithdrawLastVotes withdraws the vote last time from the validators
 func (k Keeper) WithdrawLastVotes(ctx sdk.Context, delAddr sdk.AccAddress, lastValsVoted types.Validators,
 	lastVotes sdk.Dec) {
@@ -118,26 +181,44 @@ func (k Keeper) WithdrawLastVotes(ctx sdk.Context, delAddr sdk.AccAddress, lastV
 
 func (k Keeper) withdrawVote(ctx sdk.Context, voterAddr sdk.AccAddress, val types.Validator, votes sdk.Dec) {
 	// 1.delete vote entity
+	k.BeforeValidatorModified(ctx, val.OperatorAddress)
 	k.DeleteVote(ctx, val.OperatorAddress, voterAddr)
 
-	// 2.update validator entity
-	k.DeleteValidatorByPowerIndex(ctx, val)
-
-	// 3.update validator's votes
-	val.DelegatorShares = val.GetDelegatorShares().Sub(votes)
+	// 2.update validator's votes
+	val, _ = k.RemoveValidatorTokensAndShares(ctx, val, votes)
 
 	// 3.check whether the validator should be removed
 	if val.IsUnbonded() && val.GetMinSelfDelegation().IsZero() && val.GetDelegatorShares().IsZero() {
 		k.RemoveValidator(ctx, val.OperatorAddress)
-		return
+	}
+}
+
+// RemoveValidatorTokensAndShares removes the shares from a validator together with the tokens they are worth, and
+// returns the updated validator and the tokens removed. The validator is stored with its power index updated
+// NOTE: the tokens recorded on a validator are always zero in okchain's staking because the voted tokens are kept by the
+// delegators, so that the tokens removed are only positive for a validator adjusted by other modules
+func (k Keeper) RemoveValidatorTokensAndShares(ctx sdk.Context, validator types.Validator, shares sdk.Dec,
+) (types.Validator, sdk.Int) {
+	removedTokens := sdk.ZeroInt()
+	if shares.Equal(validator.DelegatorShares) {
+		// all the tokens are removed together with all the shares, leaving no remainder of the rounding
+		removedTokens = validator.Tokens
+	} else if validator.Tokens.IsPositive() {
+		removedTokens = validator.TokensFromSharesTruncated(shares).TruncateInt()
 	}
 
-	k.SetValidator(ctx, val)
-	k.SetValidatorByPowerIndex(ctx, val)
+	// ATTENTION:update DelegatorShares must go after DeleteValidatorByPowerIndex
+	k.DeleteValidatorByPowerIndex(ctx, validator)
+	validator.Tokens = validator.Tokens.Sub(removedTokens)
+	validator.DelegatorShares = validator.DelegatorShares.Sub(shares)
+	k.SetValidator(ctx, validator)
+	k.SetValidatorByPowerIndex(ctx, validator)
+	return validator, removedTokens
 }
 
 func (k Keeper) vote(ctx sdk.Context, voterAddr sdk.AccAddress, val types.Validator, votes types.Votes) {
 	// 1.update vote entity
+	k.BeforeValidatorModified(ctx, val.OperatorAddress)
 	k.SetVote(ctx, voterAddr, val.OperatorAddress, votes)
 
 	// 2.update validator entity
@@ -41,6 +41,74 @@ func NewQuerier(k Keeper) sdk.Querier {
 			return queryProxy(ctx, req, k)
 		case types.QueryDelegator:
 			return queryDelegator(ctx, req, k)
+		case types.QueryValidatorShares:
+			return queryValidatorShares(ctx, req, k)
+		case types.QueryValidatorsByAddrs:
+			return queryValidatorsByAddrs(ctx, req, k)
+		case types.QueryDelegatorPortfolio:
+			return queryDelegatorPortfolio(ctx, req, k)
+		case types.QueryProjectedValidatorSet:
+			return queryProjectedValidatorSet(ctx, k)
+		case types.QueryDelegatorBonded:
+			return queryDelegatorBonded(ctx, req, k)
+		case types.QueryEpochInfo:
+			return queryEpochInfo(ctx, k)
+		case types.QueryStakingRatio:
+			return queryStakingRatio(ctx, k)
+		case types.QueryDryRunParams:
+			return queryDryRunParams(ctx, req, k)
+		case types.QueryUnvotedValidators:
+			return queryUnvotedValidators(ctx, req, k)
+		case types.QueryMinDelegationDisplay:
+			return queryMinDelegationDisplay(ctx, k)
+		case types.QueryDelegatorDelegations:
+			return queryDelegatorDelegations(ctx, req, k)
+		case types.QueryValidatorsByRank:
+			return queryValidatorsByRank(ctx, req, k)
+		case types.QueryUnbondingStats:
+			return queryUnbondingStats(ctx, k)
+		case types.QueryValidatorTenure:
+			return queryValidatorTenure(ctx, req, k)
+		case types.QueryMedianCommission:
+			return queryMedianCommission(ctx, k)
+		case types.QueryIsValidatorOperator:
+			return queryIsValidatorOperator(ctx, req, k)
+		case types.QueryPowerIndex:
+			return queryPowerIndex(ctx, k)
+		case types.QueryCommissionCooldown:
+			return queryCommissionCooldown(ctx, req, k)
+		case types.QueryValidatorDelegations:
+			return queryValidatorDelegations(ctx, req, k)
+		case types.QueryPowerDistribution:
+			return queryPowerDistribution(ctx, k)
+		case types.QueryStoreStats:
+			return queryStoreStats(ctx, k)
+		case types.QueryBlockReward:
+			return queryBlockReward(ctx, k)
+		case types.QueryDelegatorHistory:
+			return queryDelegatorHistory(ctx, req, k)
+		case types.QueryDelegatorValidators:
+			return queryDelegatorValidators(ctx, req, k)
+		case types.QueryEffectiveVotes:
+			return queryEffectiveVotes(ctx, req, k)
+		case types.QueryDelegatorVotePower:
+			return queryDelegatorVotePower(ctx, req, k)
+		case types.QueryAddressConversion:
+			return queryAddressConversion(ctx, req, k)
+		case types.QueryValidatorsByIdentity:
+			return queryValidatorsByIdentity(ctx, req, k)
+		case types.QueryCanDelegate:
+			return queryCanDelegate(ctx, req, k)
+		case types.QuerySharesFromTokens:
+			return querySharesFromTokens(ctx, req, k)
+		case types.QueryBondedHistory:
+			return queryBondedHistory(ctx, req, k)
+		case types.QueryMarginalValidator:
+			return queryMarginalValidator(ctx, k)
+		case types.QueryCandidateValidators:
+			return queryCandidateValidators(ctx, k)
+		case types.QueryUnbondingValidators:
+			return queryUnbondingValidators(ctx, k)
 		default:
 			return nil, sdk.ErrUnknownRequest("unknown staking query endpoint")
 		}
@@ -67,6 +135,449 @@ func queryDelegator(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, s
 	return res, nil
 }
 
+func queryValidatorShares(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QueryValidatorParams
+
+	if err := types.ModuleCdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, defaultQueryErrParseParams(err)
+	}
+
+	validator, found := k.GetValidator(ctx, params.ValidatorAddr)
+	if !found {
+		return nil, types.ErrNoValidatorFound(types.DefaultCodespace, params.ValidatorAddr.String())
+	}
+
+	sharesResp := types.NewValidatorSharesResponse(validator.OperatorAddress, validator.DelegatorShares,
+		k.GetValidatorTotalShares(ctx, validator.OperatorAddress))
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, sharesResp)
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryDelegatorPortfolio(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QueryDelegatorParams
+
+	if err := types.ModuleCdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, defaultQueryErrParseParams(err)
+	}
+
+	portfolio, found := k.GetDelegatorPortfolio(ctx, params.DelegatorAddr)
+	if !found {
+		return nil, types.ErrNoDelegatorExisted(types.DefaultCodespace, params.DelegatorAddr.String())
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, portfolio)
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryDelegatorDelegations(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QueryDelegatorDelegationsParams
+
+	if err := types.ModuleCdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, defaultQueryErrParseParams(err)
+	}
+
+	votes, total := k.GetDelegatorDelegations(ctx, params.DelegatorAddr, params.Page, params.Limit)
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, types.DelegatorDelegationsResult{Total: total, Votes: votes})
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryValidatorDelegations(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QueryValidatorDelegationsParams
+
+	if err := types.ModuleCdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, defaultQueryErrParseParams(err)
+	}
+
+	votes, total := k.GetValidatorDelegations(ctx, params.ValidatorAddr, params.Page, params.Limit)
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, types.ValidatorDelegationsResult{Total: total, Votes: votes})
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryDelegatorBonded(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QueryDelegatorParams
+
+	if err := types.ModuleCdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, defaultQueryErrParseParams(err)
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, k.GetDelegatorBonded(ctx, params.DelegatorAddr))
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryDelegatorValidators(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QueryDelegatorParams
+
+	if err := types.ModuleCdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, defaultQueryErrParseParams(err)
+	}
+
+	validators := k.GetDelegatorValidators(ctx, params.DelegatorAddr, k.ParamsMaxValsToVote(ctx))
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, validators)
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryDelegatorHistory(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QueryDelegatorParams
+
+	if err := types.ModuleCdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, defaultQueryErrParseParams(err)
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, k.GetDelegatorHistory(ctx, params.DelegatorAddr))
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryEffectiveVotes(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QueryDelegatorParams
+
+	if err := types.ModuleCdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, defaultQueryErrParseParams(err)
+	}
+
+	effectiveVotes, found := k.GetEffectiveVotes(ctx, params.DelegatorAddr)
+	if !found {
+		return nil, types.ErrNoDelegatorExisted(types.DefaultCodespace, params.DelegatorAddr.String())
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, effectiveVotes)
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryDelegatorVotePower(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QueryDelegatorParams
+
+	if err := types.ModuleCdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, defaultQueryErrParseParams(err)
+	}
+
+	votePower, sdkErr := k.GetDelegatorVotePower(ctx, params.DelegatorAddr)
+	if sdkErr != nil {
+		return nil, sdkErr
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, votePower)
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryEpochInfo(ctx sdk.Context, k Keeper) ([]byte, sdk.Error) {
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, k.GetEpochInfo(ctx))
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryStakingRatio(ctx sdk.Context, k Keeper) ([]byte, sdk.Error) {
+	if k.GetBondedPool(ctx) == nil {
+		return nil, sdk.ErrInternal("pool accounts haven't been set")
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, k.BondedRatio(ctx))
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryDryRunParams(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QueryDryRunParamsParams
+
+	if err := types.ModuleCdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, defaultQueryErrParseParams(err)
+	}
+
+	merged, sdkErr := k.DryRunParams(ctx, params.Changes)
+	if sdkErr != nil {
+		return nil, sdkErr
+	}
+
+	result := types.DryRunParamsResult{Params: merged}
+	if err := merged.Validate(); err != nil {
+		result.Error = err.Error()
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, result)
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryValidatorTenure(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QueryValidatorParams
+	if err := types.ModuleCdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, defaultQueryErrParseParams(err)
+	}
+
+	tenure, sdkErr := k.GetValidatorTenure(ctx, params.ValidatorAddr)
+	if sdkErr != nil {
+		return nil, sdkErr
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, tenure)
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryCommissionCooldown(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QueryValidatorParams
+	if err := types.ModuleCdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, defaultQueryErrParseParams(err)
+	}
+
+	cooldown, sdkErr := k.GetValidatorCommissionCooldown(ctx, params.ValidatorAddr)
+	if sdkErr != nil {
+		return nil, sdkErr
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, cooldown)
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryUnbondingStats(ctx sdk.Context, k Keeper) ([]byte, sdk.Error) {
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, k.GetUnbondingStats(ctx))
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryStoreStats(ctx sdk.Context, k Keeper) ([]byte, sdk.Error) {
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, k.GetStoreStats(ctx))
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryMedianCommission(ctx sdk.Context, k Keeper) ([]byte, sdk.Error) {
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, k.GetMedianCommission(ctx))
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryPowerIndex(ctx sdk.Context, k Keeper) ([]byte, sdk.Error) {
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, k.GetPowerIndexEntries(ctx))
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryIsValidatorOperator(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QueryIsValidatorOperatorParams
+	if err := types.ModuleCdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, defaultQueryErrParseParams(err)
+	}
+
+	// accept both the account address and the validator address of the operator
+	accAddr, err := sdk.AccAddressFromBech32(params.Address)
+	if err != nil {
+		valAddr, errVal := sdk.ValAddressFromBech32(params.Address)
+		if errVal != nil {
+			return nil, sdk.ErrInvalidAddress(fmt.Sprintf("invalid account or validator address %s", params.Address))
+		}
+		accAddr = sdk.AccAddress(valAddr)
+	}
+
+	result := types.IsValidatorOperatorResult{
+		AccAddress: accAddr,
+		ValAddress: sdk.ValAddress(accAddr),
+		IsOperator: k.IsValidatorOperator(ctx, accAddr),
+	}
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, result)
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryMinDelegationDisplay(ctx sdk.Context, k Keeper) ([]byte, sdk.Error) {
+	minDelegationDisplay := types.NewMinDelegationDisplay(k.BondDenom(ctx), k.ParamsBondDenomDecimals(ctx),
+		k.ParamsMinDelegation(ctx))
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, minDelegationDisplay)
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryProjectedValidatorSet(ctx sdk.Context, k Keeper) ([]byte, sdk.Error) {
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, k.GetProjectedValidatorSet(ctx))
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryPowerDistribution(ctx sdk.Context, k Keeper) ([]byte, sdk.Error) {
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, k.GetPowerDistribution(ctx))
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryBlockReward(ctx sdk.Context, k Keeper) ([]byte, sdk.Error) {
+	reward, sdkErr := k.GetBlockReward(ctx)
+	if sdkErr != nil {
+		return nil, sdkErr
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, reward)
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryCanDelegate(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QueryCanDelegateParams
+
+	if err := types.ModuleCdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, defaultQueryErrParseParams(err)
+	}
+
+	canDelegate := types.NewCanDelegateResponse(
+		k.CanDelegate(ctx, params.DelegatorAddr, params.ValidatorAddr, params.Amount))
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, canDelegate)
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func querySharesFromTokens(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QuerySharesFromTokensParams
+
+	if err := types.ModuleCdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, defaultQueryErrParseParams(err)
+	}
+
+	shares, sdkErr := k.SharesFromTokens(ctx, params.ValidatorAddr, params.Amount)
+	if sdkErr != nil {
+		return nil, sdkErr
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, shares)
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryBondedHistory(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QueryBondedHistoryParams
+
+	if err := types.ModuleCdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, defaultQueryErrParseParams(err)
+	}
+
+	if params.EndEpoch < params.StartEpoch || params.EndEpoch-params.StartEpoch >= types.BondedHistoryRetention {
+		return nil, types.ErrInvalidEpochRange(types.DefaultCodespace, params.StartEpoch, params.EndEpoch,
+			types.BondedHistoryRetention)
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, k.GetBondedHistory(ctx, params.StartEpoch, params.EndEpoch))
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryMarginalValidator(ctx sdk.Context, k Keeper) ([]byte, sdk.Error) {
+	marginal, found := k.GetMarginalValidator(ctx)
+	if !found {
+		return nil, types.ErrValidatorSetNotFull(types.DefaultCodespace, k.MaxValidators(ctx))
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, marginal)
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryUnbondingValidators(ctx sdk.Context, k Keeper) ([]byte, sdk.Error) {
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, k.GetUnbondingValidators(ctx))
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryCandidateValidators(ctx sdk.Context, k Keeper) ([]byte, sdk.Error) {
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, k.GetCandidateValidators(ctx))
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
 func queryValidators(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
 	var params types.QueryValidatorsParams
 
@@ -98,6 +609,129 @@ func queryValidators(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte,
 	return res, nil
 }
 
+func queryUnvotedValidators(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QueryUnvotedValidatorsParams
+
+	if err := types.ModuleCdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, defaultQueryErrParseParams(err)
+	}
+
+	validators, remaining := k.GetUnvotedValidators(ctx, params.DelegatorAddr)
+	start, end := client.Paginate(len(validators), params.Page, params.Limit, int(k.MaxValidators(ctx)))
+	if start < 0 || end < 0 {
+		validators = types.Validators{}
+	} else {
+		validators = validators[start:end]
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc,
+		types.UnvotedValidatorsResult{RemainingValsToVote: remaining, Validators: validators})
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryValidatorsByAddrs(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QueryValidatorsByAddrsParams
+
+	if err := types.ModuleCdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, defaultQueryErrParseParams(err)
+	}
+
+	if len(params.ValidatorAddrs) > types.MaxValidatorsByAddrsQuery {
+		return nil, types.ErrExceedValidatorAddrs(types.DefaultCodespace, types.MaxValidatorsByAddrsQuery)
+	}
+
+	var validators types.Validators
+	var notFound []sdk.ValAddress
+	for _, valAddr := range params.ValidatorAddrs {
+		validator, found := k.GetValidator(ctx, valAddr)
+		if !found {
+			notFound = append(notFound, valAddr)
+			continue
+		}
+		validators = append(validators, validator)
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, types.NewValidatorsByAddrsResponse(validators, notFound))
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryValidatorsByIdentity(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QueryValidatorsByIdentityParams
+
+	if err := types.ModuleCdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, defaultQueryErrParseParams(err)
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, k.GetValidatorsByIdentity(ctx, params.Identity))
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryAddressConversion(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QueryAddressConversionParams
+
+	if err := types.ModuleCdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, defaultQueryErrParseParams(err)
+	}
+
+	// an empty address is parsed as a valid one by bech32
+	if len(strings.TrimSpace(params.Address)) == 0 {
+		return nil, types.ErrBadValidatorAddr(types.DefaultCodespace)
+	}
+
+	var validator types.Validator
+	var found bool
+	if valAddr, err := sdk.ValAddressFromBech32(params.Address); err == nil {
+		validator, found = k.GetValidator(ctx, valAddr)
+	} else if consAddr, err := sdk.ConsAddressFromBech32(params.Address); err == nil {
+		validator, found = k.GetValidatorByConsAddr(ctx, consAddr)
+	} else {
+		return nil, types.ErrBadValidatorAddr(types.DefaultCodespace)
+	}
+	if !found {
+		return nil, types.ErrNoValidatorFound(types.DefaultCodespace, params.Address)
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, types.NewAddressConversion(validator))
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
+func queryValidatorsByRank(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QueryValidatorsByRankParams
+
+	if err := types.ModuleCdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, defaultQueryErrParseParams(err)
+	}
+
+	if params.StartRank < 1 || params.EndRank < params.StartRank ||
+		params.EndRank-params.StartRank >= types.MaxValidatorsByRankQuery {
+		return nil, types.ErrInvalidRankRange(types.DefaultCodespace, params.StartRank, params.EndRank,
+			types.MaxValidatorsByRankQuery)
+	}
+
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, k.GetValidatorsByRank(ctx, params.StartRank, params.EndRank))
+	if err != nil {
+		return nil, defaultQueryErrJSONMarshal(err)
+	}
+
+	return res, nil
+}
+
 func queryValidator(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
 	var params types.QueryValidatorParams
 
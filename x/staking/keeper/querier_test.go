@@ -2,8 +2,12 @@ package keeper
 
 import (
 	"testing"
+	"time"
 
 	types2 "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/mint"
+	"github.com/cosmos/cosmos-sdk/x/params"
+	"github.com/cosmos/cosmos-sdk/x/supply"
 	"github.com/okex/okchain/x/staking/types"
 	"github.com/stretchr/testify/require"
 	"github.com/tendermint/go-amino"
@@ -210,3 +214,1232 @@ func TestParams(t *testing.T) {
 	resParams = keeper.GetParams(ctx)
 	require.True(t, expParams.Equal(resParams))
 }
+
+func TestQueryValidatorsByAddrs(t *testing.T) {
+	ctx, _, mockKeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mockKeeper.Keeper
+	createVals(ctx, 2, keeper)
+	querior := NewQuerier(keeper)
+
+	// mixed known and unknown addresses
+	params := types.NewQueryValidatorsByAddrsParams([]types2.ValAddress{addrVals[0], addrVals[2], addrVals[1]})
+	bz, _ := types.ModuleCdc.MarshalJSON(params)
+	data, err := querior(ctx, []string{types.QueryValidatorsByAddrs}, abci.RequestQuery{Data: bz})
+	require.Nil(t, err)
+
+	var resp types.ValidatorsByAddrsResponse
+	require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &resp))
+	require.Equal(t, 2, len(resp.Validators))
+	require.True(t, resp.Validators[0].OperatorAddress.Equals(addrVals[0]))
+	require.True(t, resp.Validators[1].OperatorAddress.Equals(addrVals[1]))
+	require.Equal(t, 1, len(resp.NotFound))
+	require.True(t, resp.NotFound[0].Equals(addrVals[2]))
+
+	// over the cap
+	valAddrs := make([]types2.ValAddress, types.MaxValidatorsByAddrsQuery+1)
+	for i := range valAddrs {
+		valAddrs[i] = addrVals[0]
+	}
+	bz, _ = types.ModuleCdc.MarshalJSON(types.NewQueryValidatorsByAddrsParams(valAddrs))
+	data, err = querior(ctx, []string{types.QueryValidatorsByAddrs}, abci.RequestQuery{Data: bz})
+	require.NotNil(t, err)
+	require.Nil(t, data)
+
+	// bad params
+	data, err = querior(ctx, []string{types.QueryValidatorsByAddrs}, abci.RequestQuery{Data: nil})
+	require.NotNil(t, err)
+	require.Nil(t, data)
+}
+
+func TestQueryDelegatorPortfolio(t *testing.T) {
+	ctx, _, mockKeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mockKeeper.Keeper
+	vals := createVals(ctx, 2, keeper)
+	querior := NewQuerier(keeper)
+	delAddr := addrDels[0]
+	bz, _ := types.ModuleCdc.MarshalJSON(types.NewQueryDelegatorParams(delAddr))
+
+	// nothing staked
+	_, err := querior(ctx, []string{types.QueryDelegatorPortfolio}, abci.RequestQuery{Data: bz})
+	require.NotNil(t, err)
+
+	// delegate, vote and undelegate partly
+	require.Nil(t, keeper.Delegate(ctx, delAddr, types2.NewDecCoinFromDec(types2.DefaultBondDenom, types2.NewDec(100))))
+	votes, err := keeper.VoteValidators(ctx, delAddr, vals, types2.NewDec(100))
+	require.Nil(t, err)
+	delegator, found := keeper.GetDelegator(ctx, delAddr)
+	require.True(t, found)
+	delegator.Shares = votes
+	delegator.ValidatorAddresses = []types2.ValAddress{vals[0].OperatorAddress, vals[1].OperatorAddress}
+	keeper.SetDelegator(ctx, delegator)
+	undelegation, err := keeper.BeginUnbonding(ctx, delAddr,
+		types2.NewDecCoinFromDec(types2.DefaultBondDenom, types2.NewDec(40)))
+	require.Nil(t, err)
+
+	data, err := querior(ctx, []string{types.QueryDelegatorPortfolio}, abci.RequestQuery{Data: bz})
+	require.Nil(t, err)
+	var portfolio types.DelegatorPortfolio
+	require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &portfolio))
+	require.True(t, portfolio.DelegatorAddress.Equals(delAddr))
+	require.True(t, portfolio.Tokens.Equal(types2.NewDec(60)))
+	require.Equal(t, 2, len(portfolio.Votes))
+	for i, vote := range portfolio.Votes {
+		require.True(t, vote.ValidatorAddress.Equals(vals[i].OperatorAddress))
+		require.True(t, vote.Votes.IsPositive())
+	}
+	require.True(t, portfolio.Undelegation.Quantity.Equal(types2.NewDec(40)))
+	require.Equal(t, undelegation.CompletionTime, portfolio.Undelegation.CompletionTime)
+	require.Contains(t, portfolio.String(), delAddr.String())
+
+	// only the undelegation left
+	_, err = keeper.BeginUnbonding(ctx, delAddr, types2.NewDecCoinFromDec(types2.DefaultBondDenom, types2.NewDec(60)))
+	require.Nil(t, err)
+	data, err = querior(ctx, []string{types.QueryDelegatorPortfolio}, abci.RequestQuery{Data: bz})
+	require.Nil(t, err)
+	require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &portfolio))
+	require.True(t, portfolio.Tokens.IsZero())
+	require.Equal(t, 0, len(portfolio.Votes))
+	require.True(t, portfolio.Undelegation.Quantity.Equal(types2.NewDec(100)))
+}
+
+func TestQueryDelegatorBonded(t *testing.T) {
+	ctx, _, mockKeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mockKeeper.Keeper
+	vals := createVals(ctx, 2, keeper)
+	querior := NewQuerier(keeper)
+	delAddr := addrDels[0]
+	queryBonded := func(addr types2.AccAddress) (bonded types2.Dec) {
+		bz, _ := types.ModuleCdc.MarshalJSON(types.NewQueryDelegatorParams(addr))
+		data, err := querior(ctx, []string{types.QueryDelegatorBonded}, abci.RequestQuery{Data: bz})
+		require.Nil(t, err)
+		require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &bonded))
+		return
+	}
+
+	// nothing staked
+	require.True(t, queryBonded(delAddr).IsZero())
+
+	// delegate and vote
+	require.Nil(t, keeper.Delegate(ctx, delAddr, types2.NewDecCoinFromDec(types2.DefaultBondDenom, types2.NewDec(100))))
+	_, err := keeper.VoteValidators(ctx, delAddr, vals, types2.NewDec(100))
+	require.Nil(t, err)
+	require.True(t, queryBonded(delAddr).Equal(types2.NewDec(100)))
+
+	// the bonded tokens stay the same after the validator voted is jailed since no token is slashed
+	keeper.jailValidator(ctx, vals[0])
+	require.True(t, keeper.GetDelegatorBonded(ctx, delAddr).Equal(types2.NewDec(100)))
+
+	// the undelegating tokens aren't bonded
+	_, err = keeper.BeginUnbonding(ctx, delAddr, types2.NewDecCoinFromDec(types2.DefaultBondDenom, types2.NewDec(40)))
+	require.Nil(t, err)
+	require.True(t, queryBonded(delAddr).Equal(types2.NewDec(60)))
+
+	// the msd of the validator operator is bonded
+	operatorAddr := types2.AccAddress(vals[1].OperatorAddress)
+	require.True(t, queryBonded(operatorAddr).Equal(vals[1].MinSelfDelegation))
+	require.Nil(t, keeper.Delegate(ctx, operatorAddr, types2.NewDecCoinFromDec(types2.DefaultBondDenom, types2.NewDec(10))))
+	require.True(t, queryBonded(operatorAddr).Equal(vals[1].MinSelfDelegation.Add(types2.NewDec(10))))
+}
+
+func TestQueryDelegatorVotePower(t *testing.T) {
+	ctx, _, mockKeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mockKeeper.Keeper
+	ctx = ctx.WithBlockTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	vals := createVals(ctx, 2, keeper)
+	querior := NewQuerier(keeper)
+	queryVotePower := func(addr types2.AccAddress) (votePower types.DelegatorVotePower, err types2.Error) {
+		bz, _ := types.ModuleCdc.MarshalJSON(types.NewQueryDelegatorParams(addr))
+		data, err := querior(ctx, []string{types.QueryDelegatorVotePower}, abci.RequestQuery{Data: bz})
+		if err == nil {
+			require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &votePower))
+		}
+		return
+	}
+	// delegates and votes as the handler does
+	delegateAndVote := func(delAddr types2.AccAddress, vote bool) {
+		require.Nil(t, keeper.Delegate(ctx, delAddr, types2.NewDecCoinFromDec(types2.DefaultBondDenom, types2.NewDec(100))))
+		if !vote {
+			return
+		}
+		delegator, found := keeper.GetDelegator(ctx, delAddr)
+		require.True(t, found)
+		votes, err := keeper.VoteValidators(ctx, delAddr, getVals(ctx, vals, keeper, t), delegator.Tokens)
+		require.Nil(t, err)
+		delegator.ValidatorAddresses = []types2.ValAddress{vals[0].OperatorAddress, vals[1].OperatorAddress}
+		delegator.Shares = votes
+		keeper.SetDelegator(ctx, delegator)
+	}
+
+	// nothing staked
+	_, err := queryVotePower(addrDels[0])
+	require.NotNil(t, err)
+
+	// uncommitted
+	delegateAndVote(addrDels[0], false)
+	uncommitted, err := queryVotePower(addrDels[0])
+	require.Nil(t, err)
+	require.True(t, uncommitted.DelegatorAddress.Equals(addrDels[0]))
+	require.True(t, uncommitted.TotalShares.IsPositive())
+	require.True(t, uncommitted.CommittedShares.IsZero())
+	require.True(t, uncommitted.AvailableShares.Equal(uncommitted.TotalShares))
+
+	// fully committed
+	delegateAndVote(addrDels[1], true)
+	committed, err := queryVotePower(addrDels[1])
+	require.Nil(t, err)
+	require.True(t, committed.TotalShares.Equal(uncommitted.TotalShares))
+	require.True(t, committed.CommittedShares.Equal(committed.TotalShares))
+	require.True(t, committed.AvailableShares.IsZero())
+
+	// partially committed once the weight of the tokens grows by a year
+	ctx = ctx.WithBlockTime(ctx.BlockTime().Add(52 * 7 * 24 * time.Hour))
+	partial, err := queryVotePower(addrDels[1])
+	require.Nil(t, err)
+	require.True(t, partial.TotalShares.Equal(committed.TotalShares.MulInt64(2)))
+	require.True(t, partial.CommittedShares.Equal(committed.CommittedShares))
+	require.True(t, partial.AvailableShares.Equal(committed.TotalShares))
+}
+
+func TestQueryEffectiveVotes(t *testing.T) {
+	ctx, _, mockKeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mockKeeper.Keeper
+	vals := createVals(ctx, 3, keeper)
+	querior := NewQuerier(keeper)
+	delAddr := addrDels[0]
+	queryEffectiveVotes := func() (effectiveVotes types.EffectiveVotes, err types2.Error) {
+		bz, _ := types.ModuleCdc.MarshalJSON(types.NewQueryDelegatorParams(delAddr))
+		data, err := querior(ctx, []string{types.QueryEffectiveVotes}, abci.RequestQuery{Data: bz})
+		if err == nil {
+			require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &effectiveVotes))
+		}
+		return
+	}
+	requireVotedTo := func(votes []types.VoteToValidator, vals types.Validators) {
+		require.Equal(t, len(vals), len(votes))
+		for i, vote := range votes {
+			require.True(t, vote.ValidatorAddress.Equals(vals[i].OperatorAddress))
+			require.True(t, vote.Votes.IsPositive())
+		}
+	}
+
+	// nothing staked
+	_, err := queryEffectiveVotes()
+	require.NotNil(t, err)
+
+	// vote for all the validators in the order of the addresses
+	require.Nil(t, keeper.Delegate(ctx, delAddr, types2.NewDecCoinFromDec(types2.DefaultBondDenom, types2.NewDec(100))))
+	votes, err := keeper.VoteValidators(ctx, delAddr, vals, types2.NewDec(100))
+	require.Nil(t, err)
+	delegator, found := keeper.GetDelegator(ctx, delAddr)
+	require.True(t, found)
+	delegator.Shares = votes
+	for _, val := range vals {
+		delegator.ValidatorAddresses = append(delegator.ValidatorAddresses, val.OperatorAddress)
+	}
+	keeper.SetDelegator(ctx, delegator)
+
+	// all counted under the cap
+	effectiveVotes, err := queryEffectiveVotes()
+	require.Nil(t, err)
+	require.True(t, effectiveVotes.DelegatorAddress.Equals(delAddr))
+	requireVotedTo(effectiveVotes.Counted, vals)
+	require.Empty(t, effectiveVotes.Excess)
+
+	// the cap lowered after the vote
+	params := keeper.GetParams(ctx)
+	params.MaxValsToVote = 2
+	keeper.SetParams(ctx, params)
+	effectiveVotes, err = queryEffectiveVotes()
+	require.Nil(t, err)
+	require.Equal(t, uint16(2), effectiveVotes.MaxValsToVote)
+	requireVotedTo(effectiveVotes.Counted, vals[:2])
+	requireVotedTo(effectiveVotes.Excess, vals[2:])
+
+	// the votes in excess are withdrawn once the votes of the delegator are updated
+	require.Nil(t, keeper.Delegate(ctx, delAddr, types2.NewDecCoinFromDec(types2.DefaultBondDenom, types2.NewDec(100))))
+	effectiveVotes, err = queryEffectiveVotes()
+	require.Nil(t, err)
+	requireVotedTo(effectiveVotes.Counted, vals[:2])
+	require.Empty(t, effectiveVotes.Excess)
+	_, found = keeper.GetVote(ctx, delAddr, vals[2].OperatorAddress)
+	require.False(t, found)
+	for _, val := range vals[:2] {
+		vote, _ := keeper.GetVote(ctx, delAddr, val.OperatorAddress)
+		require.True(t, keeper.mustGetValidator(ctx, val.OperatorAddress).DelegatorShares.Equal(vote))
+	}
+}
+
+func TestQueryEpochInfo(t *testing.T) {
+	ctx, _, mockKeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mockKeeper.Keeper
+	querior := NewQuerier(keeper)
+	keeper.SetEpochNumber(ctx, 7)
+	keeper.SetTheEndOfLastEpoch(ctx.WithBlockHeight(100))
+
+	data, err := querior(ctx, []string{types.QueryEpochInfo}, abci.RequestQuery{})
+	require.Nil(t, err)
+	var epochInfo types.EpochInfo
+	require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &epochInfo))
+	require.Equal(t, types.NewEpochInfo(7, keeper.GetEpoch(ctx), 100), epochInfo)
+	require.Equal(t, int64(100)+int64(keeper.GetEpoch(ctx)), epochInfo.EpochEndHeight)
+	require.Contains(t, epochInfo.String(), "Epoch Number")
+}
+
+func TestQueryStakingRatio(t *testing.T) {
+	ctx, _, mockKeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mockKeeper.Keeper
+	supplyKeeper := mockKeeper.SupplyKeeper
+	querior := NewQuerier(keeper)
+	queryRatio := func() (ratio types2.Dec) {
+		data, err := querior(ctx, []string{types.QueryStakingRatio}, abci.RequestQuery{})
+		require.Nil(t, err)
+		require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &ratio))
+		return
+	}
+
+	// 1000 of the total supply 10000 are bonded
+	supplyKeeper.SetSupply(ctx, supply.NewSupply(types2.NewDecCoinsFromDec(types2.DefaultBondDenom,
+		types2.NewDec(10000))))
+	bondedPool := keeper.GetBondedPool(ctx)
+	require.NoError(t, bondedPool.SetCoins(types2.NewDecCoinsFromDec(types2.DefaultBondDenom, types2.NewDec(1000))))
+	supplyKeeper.SetModuleAccount(ctx, bondedPool)
+	require.True(t, queryRatio().Equal(types2.NewDecWithPrec(1, 1)))
+
+	// zero supply
+	supplyKeeper.SetSupply(ctx, supply.NewSupply(nil))
+	require.True(t, queryRatio().IsZero())
+}
+
+func TestQueryDryRunParams(t *testing.T) {
+	ctx, _, mockKeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mockKeeper.Keeper
+	querior := NewQuerier(keeper)
+	oldParams := keeper.GetParams(ctx)
+	dryRun := func(changes ...params.ParamChange) (result types.DryRunParamsResult, err types2.Error) {
+		bz := types.ModuleCdc.MustMarshalJSON(types.NewQueryDryRunParamsParams(changes))
+		data, err := querior(ctx, []string{types.QueryDryRunParams}, abci.RequestQuery{Data: bz})
+		if err == nil {
+			require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &result))
+		}
+		return
+	}
+
+	// valid changes
+	result, err := dryRun(
+		params.NewParamChange(DefaultParamspace, string(types.KeyMaxValidators), `50`),
+		params.NewParamChange(DefaultParamspace, string(types.KeyMinDelegation), `"0.5"`),
+		params.NewParamChange(DefaultParamspace, string(types.KeyPowerTieBreak), `"oldest-first"`),
+	)
+	require.Nil(t, err)
+	require.Empty(t, result.Error)
+	require.Equal(t, uint16(50), result.Params.MaxValidators)
+	require.True(t, result.Params.MinDelegation.Equal(types2.NewDecWithPrec(5, 1)))
+	require.Equal(t, types.TieBreakOldestFirst, result.Params.PowerTieBreak)
+	require.Equal(t, oldParams.UnbondingTime, result.Params.UnbondingTime)
+
+	// the merged params fail the validation
+	result, err = dryRun(
+		params.NewParamChange(DefaultParamspace, string(types.KeyMaxValidators), `0`),
+	)
+	require.Nil(t, err)
+	require.NotEmpty(t, result.Error)
+	require.Equal(t, uint16(0), result.Params.MaxValidators)
+	result, err = dryRun(
+		params.NewParamChange(DefaultParamspace, string(types.KeyPowerTieBreak), `"newest-first"`),
+	)
+	require.Nil(t, err)
+	require.NotEmpty(t, result.Error)
+
+	// malformed changes
+	_, err = dryRun(params.NewParamChange("gov", string(types.KeyMaxValidators), `50`))
+	require.NotNil(t, err)
+	_, err = dryRun(params.NewParamChange(DefaultParamspace, "UnknownKey", `50`))
+	require.NotNil(t, err)
+	_, err = dryRun(params.NewParamChange(DefaultParamspace, string(types.KeyMaxValidators), `"fifty"`))
+	require.NotNil(t, err)
+	_, err = dryRun(params.NewParamChangeWithSubkey(DefaultParamspace, string(types.KeyMaxValidators), "sub", `50`))
+	require.NotNil(t, err)
+
+	// nothing is written into store
+	require.Equal(t, oldParams, keeper.GetParams(ctx))
+}
+
+func TestQueryProjectedValidatorSet(t *testing.T) {
+	ctx, _, mockKeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mockKeeper.Keeper
+	params := keeper.GetParams(ctx)
+	params.MaxValidators = 2
+	keeper.SetParams(ctx, params)
+	vals := createVals(ctx, 3, keeper)
+	querior := NewQuerier(keeper)
+	tokens := types2.NewDec(1000000000)
+
+	// vals[0] and vals[1] are bonded
+	_, err := keeper.VoteValidators(ctx, addrDels[0], vals[:2], tokens)
+	require.Nil(t, err)
+	_, err = keeper.VoteValidators(ctx, addrDels[1], getVals(ctx, vals[:1], keeper, t), tokens)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(keeper.ApplyAndReturnValidatorSetUpdates(ctx)))
+
+	projection := keeper.GetProjectedValidatorSet(ctx)
+	require.Equal(t, 2, len(projection.Validators))
+	require.True(t, projection.Validators[0].OperatorAddress.Equals(vals[0].OperatorAddress))
+	require.True(t, projection.Validators[1].OperatorAddress.Equals(vals[1].OperatorAddress))
+	require.Equal(t, 0, len(projection.Entering))
+	require.Equal(t, 0, len(projection.Leaving))
+	require.Equal(t, ctx.BlockHeight(), projection.ProjectedAtHeight)
+	require.Equal(t, keeper.GetTheEndOfLastEpoch(ctx)+int64(keeper.GetEpoch(ctx)), projection.EpochEndHeight)
+
+	// the pending votes to vals[2] push vals[1] out of the projected set
+	_, err = keeper.VoteValidators(ctx, addrDels[2], getVals(ctx, vals[2:], keeper, t), tokens.MulInt64(3))
+	require.Nil(t, err)
+
+	data, qErr := querior(ctx, []string{types.QueryProjectedValidatorSet}, abci.RequestQuery{})
+	require.Nil(t, qErr)
+	require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &projection))
+	require.Equal(t, 2, len(projection.Validators))
+	require.True(t, projection.Validators[0].OperatorAddress.Equals(vals[2].OperatorAddress))
+	require.True(t, projection.Validators[1].OperatorAddress.Equals(vals[0].OperatorAddress))
+	require.True(t, projection.Validators[0].Power > projection.Validators[1].Power)
+	require.Equal(t, []types2.ValAddress{vals[2].OperatorAddress}, projection.Entering)
+	require.Equal(t, []types2.ValAddress{vals[1].OperatorAddress}, projection.Leaving)
+	require.Contains(t, projection.String(), "may change")
+
+	// the current bonded set is untouched by the projection
+	require.Equal(t, int64(0), keeper.GetLastValidatorPower(ctx, vals[2].OperatorAddress))
+	require.True(t, keeper.GetLastValidatorPower(ctx, vals[1].OperatorAddress) > 0)
+
+	// a pending decrease of the max validators shrinks the projection
+	params.MaxValidators = 1
+	keeper.SetParams(ctx, params)
+	projection = keeper.GetProjectedValidatorSet(ctx)
+	require.Equal(t, 1, len(projection.Validators))
+	require.Equal(t, 2, len(projection.Leaving))
+}
+
+func TestQueryCandidateValidators(t *testing.T) {
+	ctx, _, mockKeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mockKeeper.Keeper
+	params := keeper.GetParams(ctx)
+	params.MaxValidators = 2
+	keeper.SetParams(ctx, params)
+	vals := createVals(ctx, 4, keeper)
+	querior := NewQuerier(keeper)
+	tokens := types2.NewDec(1000000000)
+	queryCandidates := func() (candidates []types2.ValAddress) {
+		data, err := querior(ctx, []string{types.QueryCandidateValidators}, abci.RequestQuery{})
+		require.Nil(t, err)
+		var validators types.Validators
+		require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &validators))
+		for _, validator := range validators {
+			candidates = append(candidates, validator.OperatorAddress)
+		}
+		return
+	}
+	setMinSelfDelegation := func(i int, msd types2.Dec) {
+		validator := keeper.mustGetValidator(ctx, vals[i].OperatorAddress)
+		validator.MinSelfDelegation = msd
+		keeper.SetValidator(ctx, validator)
+	}
+
+	// no candidate
+	require.Empty(t, queryCandidates())
+
+	// the more votes the earlier the validator is, vals[2] sits just below the cutoff and vals[3] has no msd
+	setMinSelfDelegation(3, types2.ZeroDec())
+	for i := range vals {
+		_, err := keeper.VoteValidators(ctx, addrDels[0], getVals(ctx, vals[i:i+1], keeper, t),
+			tokens.MulInt64(int64(len(vals)-i)))
+		require.Nil(t, err)
+	}
+	require.Equal(t, 2, len(keeper.ApplyAndReturnValidatorSetUpdates(ctx)))
+	require.Equal(t, []types2.ValAddress{vals[2].OperatorAddress}, queryCandidates())
+
+	// sorted by power
+	setMinSelfDelegation(3, types2.OneDec())
+	require.Equal(t, []types2.ValAddress{vals[2].OperatorAddress, vals[3].OperatorAddress}, queryCandidates())
+
+	// vals[2] climbs above the cutoff, while the bonded vals[1] falls below it but isn't a candidate
+	_, err := keeper.VoteValidators(ctx, addrDels[1], getVals(ctx, vals[2:3], keeper, t), tokens.MulInt64(5))
+	require.Nil(t, err)
+	require.Equal(t, []types2.ValAddress{vals[3].OperatorAddress}, queryCandidates())
+
+	// no candidate when all the validators fit in the set
+	params.MaxValidators = 4
+	keeper.SetParams(ctx, params)
+	require.Empty(t, queryCandidates())
+}
+
+func TestQueryMarginalValidator(t *testing.T) {
+	ctx, _, mockKeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mockKeeper.Keeper
+	params := keeper.GetParams(ctx)
+	params.MaxValidators = 3
+	keeper.SetParams(ctx, params)
+	vals := createVals(ctx, 4, keeper)
+	querior := NewQuerier(keeper)
+	tokens := types2.NewDec(1000000000)
+	powerReduction := keeper.ParamsPowerReduction(ctx)
+	powerOf := func(i int) int64 {
+		return keeper.mustGetValidator(ctx, vals[i].OperatorAddress).PotentialConsensusPowerByVotes(powerReduction)
+	}
+	queryMarginal := func() (marginal types.MarginalValidator, err error) {
+		data, err := querior(ctx, []string{types.QueryMarginalValidator}, abci.RequestQuery{})
+		if err != nil {
+			return
+		}
+		require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &marginal))
+		return
+	}
+
+	// the set isn't full without votes
+	_, err := queryMarginal()
+	require.NotNil(t, err)
+
+	// the set is just full, so there's no candidate and the gap is the whole power of the marginal validator
+	for i := 0; i < 3; i++ {
+		_, err := keeper.VoteValidators(ctx, addrDels[0], getVals(ctx, vals[i:i+1], keeper, t),
+			tokens.MulInt64(int64(len(vals)-i)))
+		require.Nil(t, err)
+	}
+	marginal, err := queryMarginal()
+	require.Nil(t, err)
+	require.Equal(t, vals[2].OperatorAddress, marginal.OperatorAddress)
+	require.Equal(t, powerOf(2), marginal.Power)
+	require.Empty(t, marginal.CandidateAddress)
+	require.Equal(t, int64(0), marginal.CandidatePower)
+	require.Equal(t, powerOf(2), marginal.PowerGap)
+
+	// vals[3] sits just below the cutoff
+	_, err = keeper.VoteValidators(ctx, addrDels[0], getVals(ctx, vals[3:4], keeper, t), tokens)
+	require.Nil(t, err)
+	marginal, err = queryMarginal()
+	require.Nil(t, err)
+	require.Equal(t, vals[2].OperatorAddress, marginal.OperatorAddress)
+	require.Equal(t, vals[3].OperatorAddress, marginal.CandidateAddress)
+	require.Equal(t, powerOf(3), marginal.CandidatePower)
+	require.Equal(t, powerOf(2)-powerOf(3), marginal.PowerGap)
+	require.True(t, marginal.PowerGap > 0)
+
+	// vals[3] climbs to the top, so that vals[1] becomes the marginal one and vals[2] becomes the candidate
+	_, err = keeper.VoteValidators(ctx, addrDels[1], getVals(ctx, vals[3:4], keeper, t), tokens.MulInt64(5))
+	require.Nil(t, err)
+	marginal, err = queryMarginal()
+	require.Nil(t, err)
+	require.Equal(t, vals[1].OperatorAddress, marginal.OperatorAddress)
+	require.Equal(t, vals[2].OperatorAddress, marginal.CandidateAddress)
+	require.Equal(t, powerOf(1)-powerOf(2), marginal.PowerGap)
+
+	// no validator to evict once the set is enlarged
+	params.MaxValidators = 5
+	keeper.SetParams(ctx, params)
+	_, err = queryMarginal()
+	require.NotNil(t, err)
+}
+
+func TestQueryCanDelegate(t *testing.T) {
+	ctx, _, mockKeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mockKeeper.Keeper
+	vals := createVals(ctx, 3, keeper)
+	querior := NewQuerier(keeper)
+	bondDenom := keeper.BondDenom(ctx)
+	amount := types2.NewDecCoinFromDec(bondDenom, types2.NewDec(100))
+	queryCanDelegate := func(delAddr types2.AccAddress, valAddr types2.ValAddress, token types2.DecCoin,
+	) types.CanDelegateResponse {
+		bz, err := types.ModuleCdc.MarshalJSON(types.NewQueryCanDelegateParams(delAddr, valAddr, token))
+		require.NoError(t, err)
+		data, sdkErr := querior(ctx, []string{types.QueryCanDelegate}, abci.RequestQuery{Data: bz})
+		require.Nil(t, sdkErr)
+		var resp types.CanDelegateResponse
+		require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &resp))
+		return resp
+	}
+	requireCannot := func(resp types.CanDelegateResponse) {
+		require.False(t, resp.CanDelegate)
+		require.NotEmpty(t, resp.Reason)
+	}
+	// delegates and votes as the handler does
+	delegateAndVote := func(delAddr types2.AccAddress, valIndexes ...int) {
+		require.Nil(t, keeper.Delegate(ctx, delAddr, amount))
+		delegator, found := keeper.GetDelegator(ctx, delAddr)
+		require.True(t, found)
+		var valsToVote types.Validators
+		for _, i := range valIndexes {
+			valsToVote = append(valsToVote, getVals(ctx, vals[i:i+1], keeper, t)...)
+			delegator.ValidatorAddresses = append(delegator.ValidatorAddresses, vals[i].OperatorAddress)
+		}
+		votes, err := keeper.VoteValidators(ctx, delAddr, valsToVote, delegator.Tokens)
+		require.Nil(t, err)
+		delegator.Shares = votes
+		keeper.SetDelegator(ctx, delegator)
+	}
+	updateParams := func(update func(params *types.Params)) (restore func()) {
+		params := keeper.GetParams(ctx)
+		origin := params
+		update(&params)
+		keeper.SetParams(ctx, params)
+		return func() { keeper.SetParams(ctx, origin) }
+	}
+	updateValidator := func(i int, update func(val *types.Validator)) (restore func()) {
+		val := keeper.mustGetValidator(ctx, vals[i].OperatorAddress)
+		origin := val
+		update(&val)
+		keeper.SetValidator(ctx, val)
+		return func() { keeper.SetValidator(ctx, origin) }
+	}
+
+	resp := queryCanDelegate(addrDels[0], vals[0].OperatorAddress, amount)
+	require.True(t, resp.CanDelegate)
+	require.Empty(t, resp.Reason)
+	delegateAndVote(addrDels[0], 0)
+
+	// bad denom and min delegation
+	requireCannot(queryCanDelegate(addrDels[1], vals[0].OperatorAddress,
+		types2.NewDecCoinFromDec("unknown", types2.NewDec(100))))
+	requireCannot(queryCanDelegate(addrDels[1], vals[0].OperatorAddress,
+		types2.NewDecCoinFromDec(bondDenom, keeper.ParamsMinDelegation(ctx).QuoInt64(2))))
+
+	// max delegations blocks the new delegators only
+	restore := updateParams(func(params *types.Params) { params.MaxDelegations = keeper.GetDelegatorCount(ctx) })
+	requireCannot(queryCanDelegate(addrDels[1], vals[0].OperatorAddress, amount))
+	require.True(t, queryCanDelegate(addrDels[0], vals[0].OperatorAddress, amount).CanDelegate)
+	restore()
+
+	// min validators
+	restore = updateParams(func(params *types.Params) {
+		params.MinValidators = uint16(len(vals) + 1)
+		params.MaxValidators = params.MinValidators
+	})
+	requireCannot(queryCanDelegate(addrDels[1], vals[0].OperatorAddress, amount))
+	restore()
+
+	// unknown or dismissed validator
+	requireCannot(queryCanDelegate(addrDels[1], addrVals[len(vals)], amount))
+	restore = updateValidator(1, func(val *types.Validator) { val.MinSelfDelegation = types2.ZeroDec() })
+	requireCannot(queryCanDelegate(addrDels[1], vals[1].OperatorAddress, amount))
+	restore()
+
+	// max validators to vote counts the validators voted already
+	restore = updateParams(func(params *types.Params) { params.MaxValsToVote = 1 })
+	requireCannot(queryCanDelegate(addrDels[0], vals[1].OperatorAddress, amount))
+	require.True(t, queryCanDelegate(addrDels[0], vals[0].OperatorAddress, amount).CanDelegate)
+	restore()
+
+	// self-delegation-only mode
+	restore = updateParams(func(params *types.Params) { params.SelfDelegationOnly = true })
+	requireCannot(queryCanDelegate(addrDels[1], vals[1].OperatorAddress, amount))
+	restore()
+
+	// acceptance policy of the validator applies to the new voters only
+	restore = updateValidator(0, func(val *types.Validator) { val.ClosedToDelegations = true })
+	requireCannot(queryCanDelegate(addrDels[1], vals[0].OperatorAddress, amount))
+	require.True(t, queryCanDelegate(addrDels[0], vals[0].OperatorAddress, amount).CanDelegate)
+	restore()
+	restore = updateValidator(0, func(val *types.Validator) { val.MaxDelegatorCount = 1 })
+	requireCannot(queryCanDelegate(addrDels[1], vals[0].OperatorAddress, amount))
+	restore()
+
+	// the votes of the validator would exceed the cap
+	shares := keeper.mustGetValidator(ctx, vals[0].OperatorAddress).DelegatorShares
+	restore = updateParams(func(params *types.Params) { params.MaxValidatorVotes = shares.Ceil().TruncateInt() })
+	requireCannot(queryCanDelegate(addrDels[0], vals[0].OperatorAddress, amount))
+	requireCannot(queryCanDelegate(addrDels[1], vals[0].OperatorAddress, amount))
+	require.True(t, queryCanDelegate(addrDels[1], vals[1].OperatorAddress, amount).CanDelegate)
+	restore()
+
+	// the votes of the validator would exceed the cap of the share of the total votes
+	restore = updateParams(func(params *types.Params) { params.MaxValidatorVoteShare = types2.NewDecWithPrec(5, 1) })
+	requireCannot(queryCanDelegate(addrDels[1], vals[0].OperatorAddress, amount))
+	require.True(t, queryCanDelegate(addrDels[1], vals[1].OperatorAddress, amount).CanDelegate)
+	restore()
+}
+
+func TestQueryUnvotedValidators(t *testing.T) {
+	ctx, _, mockKeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mockKeeper.Keeper
+	params := keeper.GetParams(ctx)
+	params.MaxValidators = 3
+	params.MaxValsToVote = 2
+	keeper.SetParams(ctx, params)
+	vals := createVals(ctx, 4, keeper)
+	querior := NewQuerier(keeper)
+	delAddr := addrDels[0]
+	tokens := types2.NewDec(100)
+	queryUnvoted := func(delAddr types2.AccAddress, page, limit int) (result types.UnvotedValidatorsResult) {
+		bz := types.ModuleCdc.MustMarshalJSON(types.NewQueryUnvotedValidatorsParams(delAddr, page, limit))
+		data, err := querior(ctx, []string{types.QueryUnvotedValidators}, abci.RequestQuery{Data: bz})
+		require.Nil(t, err)
+		require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &result))
+		return
+	}
+	getValAddrs := func(validators types.Validators) (valAddrs []types2.ValAddress) {
+		for _, val := range validators {
+			valAddrs = append(valAddrs, val.OperatorAddress)
+		}
+		return
+	}
+	vote := func(delAddr types2.AccAddress, vals types.Validators) {
+		delegator, found := keeper.GetDelegator(ctx, delAddr)
+		require.True(t, found)
+		votes, err := keeper.VoteValidators(ctx, delAddr, getVals(ctx, vals, keeper, t), delegator.Tokens)
+		require.Nil(t, err)
+		delegator.ValidatorAddresses = getValAddrs(vals)
+		delegator.Shares = votes
+		keeper.SetDelegator(ctx, delegator)
+	}
+
+	// vals[0], vals[1] and vals[2] are bonded, the more votes the higher
+	for i, dAddr := range Addrs[10:13] {
+		require.Nil(t, keeper.Delegate(ctx, dAddr,
+			types2.NewDecCoinFromDec(types2.DefaultBondDenom, tokens.MulInt64(int64(4-i)))))
+		vote(dAddr, vals[i:i+1])
+	}
+	require.Equal(t, 3, len(keeper.ApplyAndReturnValidatorSetUpdates(ctx)))
+
+	// no delegation yet, all bonded validators by power
+	result := queryUnvoted(delAddr, 1, 0)
+	require.Equal(t, uint16(2), result.RemainingValsToVote)
+	require.Equal(t, getValAddrs(vals[:3]), getValAddrs(result.Validators))
+
+	// pagination
+	result = queryUnvoted(delAddr, 2, 2)
+	require.Equal(t, getValAddrs(vals[2:3]), getValAddrs(result.Validators))
+	result = queryUnvoted(delAddr, 3, 2)
+	require.Empty(t, result.Validators)
+
+	// below the vote cap
+	require.Nil(t, keeper.Delegate(ctx, delAddr, types2.NewDecCoinFromDec(types2.DefaultBondDenom, tokens)))
+	vote(delAddr, vals[1:2])
+	result = queryUnvoted(delAddr, 1, 0)
+	require.Equal(t, uint16(1), result.RemainingValsToVote)
+	require.Equal(t, []types2.ValAddress{vals[0].OperatorAddress, vals[2].OperatorAddress},
+		getValAddrs(result.Validators))
+
+	// at the vote cap
+	vote(delAddr, vals[1:3])
+	result = queryUnvoted(delAddr, 1, 0)
+	require.Equal(t, uint16(0), result.RemainingValsToVote)
+	require.Empty(t, result.Validators)
+
+	// bound to a proxy
+	require.Nil(t, keeper.Delegate(ctx, addrDels[1], types2.NewDecCoinFromDec(types2.DefaultBondDenom, tokens)))
+	delegator, found := keeper.GetDelegator(ctx, addrDels[1])
+	require.True(t, found)
+	delegator.BindProxy(Addrs[10])
+	keeper.SetDelegator(ctx, delegator)
+	result = queryUnvoted(addrDels[1], 1, 0)
+	require.Equal(t, uint16(0), result.RemainingValsToVote)
+	require.Empty(t, result.Validators)
+
+	// bad params
+	_, err := querior(ctx, []string{types.QueryUnvotedValidators}, abci.RequestQuery{Data: []byte("invalid")})
+	require.NotNil(t, err)
+}
+
+func TestQueryMinDelegationDisplay(t *testing.T) {
+	ctx, _, mockKeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mockKeeper.Keeper
+	querior := NewQuerier(keeper)
+
+	tests := []struct {
+		decimals      uint16
+		minDelegation types2.Dec
+		display       types2.Dec
+	}{
+		{0, types2.NewDecWithPrec(1, 4), types2.NewDecWithPrec(1, 4)},
+		{2, types2.NewDec(150), types2.NewDecWithPrec(15, 1)},
+		{6, types2.NewDec(1000000), types2.OneDec()},
+		{8, types2.NewDec(1), types2.NewDecWithPrec(1, 8)},
+	}
+	for _, tc := range tests {
+		params := keeper.GetParams(ctx)
+		params.BondDenomDecimals = tc.decimals
+		params.MinDelegation = tc.minDelegation
+		keeper.SetParams(ctx, params)
+
+		data, err := querior(ctx, []string{types.QueryMinDelegationDisplay}, abci.RequestQuery{})
+		require.Nil(t, err)
+		var result types.MinDelegationDisplay
+		require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &result))
+		require.Equal(t, keeper.BondDenom(ctx), result.Denom)
+		require.Equal(t, tc.decimals, result.Decimals)
+		require.True(t, tc.minDelegation.Equal(result.Raw))
+		require.True(t, tc.display.Equal(result.Display), "decimals %d: %s", tc.decimals, result.Display)
+	}
+}
+
+func TestQueryDelegatorDelegations(t *testing.T) {
+	ctx, _, mockKeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mockKeeper.Keeper
+	vals := createVals(ctx, 5, keeper)
+	querior := NewQuerier(keeper)
+	delAddr := addrDels[0]
+	queryDelegations := func(page, limit int) (result types.DelegatorDelegationsResult) {
+		bz := types.ModuleCdc.MustMarshalJSON(types.NewQueryDelegatorDelegationsParams(delAddr, page, limit))
+		data, err := querior(ctx, []string{types.QueryDelegatorDelegations}, abci.RequestQuery{Data: bz})
+		require.Nil(t, err)
+		require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &result))
+		return
+	}
+	getValAddrs := func(votes []types.VoteToValidator) (valAddrs []types2.ValAddress) {
+		for _, vote := range votes {
+			valAddrs = append(valAddrs, vote.ValidatorAddress)
+		}
+		return
+	}
+
+	// no delegator
+	result := queryDelegations(1, 0)
+	require.Equal(t, 0, result.Total)
+	require.Empty(t, result.Votes)
+
+	// vote for all the validators
+	require.Nil(t, keeper.Delegate(ctx, delAddr, types2.NewDecCoinFromDec(types2.DefaultBondDenom, types2.NewDec(100))))
+	delegator, found := keeper.GetDelegator(ctx, delAddr)
+	require.True(t, found)
+	votes, err := keeper.VoteValidators(ctx, delAddr, getVals(ctx, vals, keeper, t), delegator.Tokens)
+	require.Nil(t, err)
+	for _, val := range vals {
+		delegator.ValidatorAddresses = append(delegator.ValidatorAddresses, val.OperatorAddress)
+	}
+	delegator.Shares = votes
+	keeper.SetDelegator(ctx, delegator)
+
+	// all on one page by default
+	result = queryDelegations(1, 0)
+	require.Equal(t, 5, result.Total)
+	require.Equal(t, delegator.ValidatorAddresses, getValAddrs(result.Votes))
+	for _, vote := range result.Votes {
+		require.True(t, votes.Equal(vote.Votes))
+	}
+
+	// multiple pages
+	var paged []types2.ValAddress
+	for page := 1; page <= 3; page++ {
+		result = queryDelegations(page, 2)
+		require.Equal(t, 5, result.Total)
+		paged = append(paged, getValAddrs(result.Votes)...)
+	}
+	require.Equal(t, delegator.ValidatorAddresses, paged)
+	require.Equal(t, delegator.ValidatorAddresses[4:], getValAddrs(queryDelegations(3, 2).Votes))
+
+	// beyond the last page
+	result = queryDelegations(4, 2)
+	require.Equal(t, 5, result.Total)
+	require.Empty(t, result.Votes)
+}
+
+func TestQueryValidatorsByRank(t *testing.T) {
+	ctx, _, mockKeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mockKeeper.Keeper
+	querior := NewQuerier(keeper)
+
+	// 15 validators, the later the more powerful
+	var valAddrsByRank []types2.ValAddress
+	for i := 0; i < 15; i++ {
+		valAddr := types2.ValAddress(Addrs[100+i])
+		validator := types.NewValidator(valAddr, PKs[100+i], types.Description{})
+		validator.DelegatorShares = types2.NewDec(int64(i+1) * 10000)
+		keeper.SetValidator(ctx, validator)
+		keeper.SetValidatorByPowerIndex(ctx, validator)
+		valAddrsByRank = append([]types2.ValAddress{valAddr}, valAddrsByRank...)
+	}
+	queryByRank := func(startRank, endRank int) (validators types.Validators, err types2.Error) {
+		bz := types.ModuleCdc.MustMarshalJSON(types.NewQueryValidatorsByRankParams(startRank, endRank))
+		data, err := querior(ctx, []string{types.QueryValidatorsByRank}, abci.RequestQuery{Data: bz})
+		if err != nil {
+			return nil, err
+		}
+		require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &validators))
+		return validators, nil
+	}
+	getValAddrs := func(validators types.Validators) (valAddrs []types2.ValAddress) {
+		for _, val := range validators {
+			valAddrs = append(valAddrs, val.OperatorAddress)
+		}
+		return
+	}
+
+	// top 10
+	validators, err := queryByRank(1, 10)
+	require.Nil(t, err)
+	require.Equal(t, valAddrsByRank[:10], getValAddrs(validators))
+
+	// a middle slice
+	validators, err = queryByRank(6, 8)
+	require.Nil(t, err)
+	require.Equal(t, valAddrsByRank[5:8], getValAddrs(validators))
+
+	// partly out of range
+	validators, err = queryByRank(11, 20)
+	require.Nil(t, err)
+	require.Equal(t, valAddrsByRank[10:], getValAddrs(validators))
+
+	// out of range
+	validators, err = queryByRank(16, 20)
+	require.Nil(t, err)
+	require.Empty(t, validators)
+
+	// invalid ranges
+	_, err = queryByRank(0, 10)
+	require.NotNil(t, err)
+	_, err = queryByRank(5, 4)
+	require.NotNil(t, err)
+	_, err = queryByRank(1, types.MaxValidatorsByRankQuery+1)
+	require.NotNil(t, err)
+}
+
+func TestQueryIsValidatorOperator(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, 0)
+	keeper := mkeeper.Keeper
+	querier := NewQuerier(keeper)
+	vals := createVals(ctx, 2, keeper)
+	for _, val := range vals {
+		val.MinSelfDelegation = keeper.ParamsMinSelfDelegationLimited(ctx)
+		keeper.SetValidator(ctx, val)
+	}
+	queryIsOperator := func(address string) (result types.IsValidatorOperatorResult) {
+		bz := types.ModuleCdc.MustMarshalJSON(types.NewQueryIsValidatorOperatorParams(address))
+		data, err := querier(ctx, []string{types.QueryIsValidatorOperator}, abci.RequestQuery{Data: bz})
+		require.Nil(t, err)
+		require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &result))
+		return
+	}
+
+	// operator, by either the account address or the validator address
+	operator := types2.AccAddress(vals[0].OperatorAddress)
+	require.True(t, keeper.IsValidatorOperator(ctx, operator))
+	for _, address := range []string{operator.String(), vals[0].OperatorAddress.String()} {
+		result := queryIsOperator(address)
+		require.True(t, result.IsOperator)
+		require.Equal(t, operator, result.AccAddress)
+		require.Equal(t, vals[0].OperatorAddress, result.ValAddress)
+	}
+
+	// non-operator
+	require.False(t, keeper.IsValidatorOperator(ctx, addrDels[0]))
+	require.False(t, queryIsOperator(addrDels[0].String()).IsOperator)
+
+	// destroyed validator still kept in store with votes left
+	destroyed := keeper.mustGetValidator(ctx, vals[1].OperatorAddress)
+	destroyed.MinSelfDelegation = types2.ZeroDec()
+	destroyed.DelegatorShares = types2.OneDec()
+	keeper.SetValidator(ctx, destroyed)
+	require.False(t, keeper.IsValidatorOperator(ctx, types2.AccAddress(vals[1].OperatorAddress)))
+	require.False(t, queryIsOperator(vals[1].OperatorAddress.String()).IsOperator)
+
+	// destroyed validator removed from store
+	keeper.RemoveValidator(ctx, vals[1].OperatorAddress)
+	require.False(t, queryIsOperator(vals[1].OperatorAddress.String()).IsOperator)
+
+	// invalid address
+	bz := types.ModuleCdc.MustMarshalJSON(types.NewQueryIsValidatorOperatorParams("okchain1xxx"))
+	_, err := querier(ctx, []string{types.QueryIsValidatorOperator}, abci.RequestQuery{Data: bz})
+	require.NotNil(t, err)
+}
+
+func TestQueryAddressConversion(t *testing.T) {
+	ctx, _, mockKeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mockKeeper.Keeper
+	validator := types.NewValidator(addrVals[0], PKs[0], types.Description{})
+	keeper.SetValidator(ctx, validator)
+	keeper.SetValidatorByConsAddr(ctx, validator)
+	querior := NewQuerier(keeper)
+	queryAddressConversion := func(address string) (types.AddressConversion, types2.Error) {
+		bz, err := types.ModuleCdc.MarshalJSON(types.NewQueryAddressConversionParams(address))
+		require.NoError(t, err)
+		data, sdkErr := querior(ctx, []string{types.QueryAddressConversion}, abci.RequestQuery{Data: bz})
+		var conversion types.AddressConversion
+		if sdkErr == nil {
+			require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &conversion))
+		}
+		return conversion, sdkErr
+	}
+	expected := types.AddressConversion{OperatorAddress: addrVals[0], ConsAddress: validator.ConsAddress()}
+
+	// operator address to consensus address
+	conversion, err := queryAddressConversion(addrVals[0].String())
+	require.Nil(t, err)
+	require.Equal(t, expected, conversion)
+
+	// consensus address to operator address
+	conversion, err = queryAddressConversion(validator.ConsAddress().String())
+	require.Nil(t, err)
+	require.Equal(t, expected, conversion)
+
+	// unknown validators
+	_, err = queryAddressConversion(addrVals[1].String())
+	require.NotNil(t, err)
+	require.Equal(t, types.CodeInvalidValidator, err.Code())
+	_, err = queryAddressConversion(types2.ConsAddress(PKs[1].Address()).String())
+	require.NotNil(t, err)
+	require.Equal(t, types.CodeInvalidValidator, err.Code())
+
+	// neither an operator address nor a consensus address
+	for _, address := range []string{"", "invalid", addrDels[0].String()} {
+		_, err = queryAddressConversion(address)
+		require.NotNil(t, err)
+		require.Equal(t, types.CodeInvalidAddress, err.Code())
+	}
+}
+
+func TestQueryDelegatorValidators(t *testing.T) {
+	ctx, _, mockKeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mockKeeper.Keeper
+	vals := createVals(ctx, 3, keeper)
+	querior := NewQuerier(keeper)
+	delAddr := addrDels[0]
+
+	// no validators voted
+	require.Empty(t, keeper.GetDelegatorValidators(ctx, delAddr, 3))
+	bz, _ := types.ModuleCdc.MarshalJSON(types.NewQueryDelegatorParams(delAddr))
+	data, err := querior(ctx, []string{types.QueryDelegatorValidators}, abci.RequestQuery{Data: bz})
+	require.Nil(t, err)
+	var validators types.Validators
+	require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &validators))
+	require.Empty(t, validators)
+
+	// vote for all the validators
+	require.Nil(t, keeper.Delegate(ctx, delAddr, types2.NewDecCoinFromDec(types2.DefaultBondDenom, types2.NewDec(100))))
+	votes, err := keeper.VoteValidators(ctx, delAddr, vals, types2.NewDec(100))
+	require.Nil(t, err)
+	delegator, found := keeper.GetDelegator(ctx, delAddr)
+	require.True(t, found)
+	delegator.Shares = votes
+	for _, val := range vals {
+		delegator.ValidatorAddresses = append(delegator.ValidatorAddresses, val.OperatorAddress)
+	}
+	keeper.SetDelegator(ctx, delegator)
+
+	data, err = querior(ctx, []string{types.QueryDelegatorValidators}, abci.RequestQuery{Data: bz})
+	require.Nil(t, err)
+	require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &validators))
+	require.Equal(t, len(vals), len(validators))
+	for i, val := range validators {
+		require.True(t, val.OperatorAddress.Equals(vals[i].OperatorAddress))
+		require.True(t, val.DelegatorShares.IsPositive())
+	}
+
+	// more validators voted than the max to retrieve
+	validators = keeper.GetDelegatorValidators(ctx, delAddr, 2)
+	require.Equal(t, 2, len(validators))
+	for i, val := range validators {
+		require.True(t, val.OperatorAddress.Equals(vals[i].OperatorAddress))
+	}
+}
+
+func TestQueryStoreStats(t *testing.T) {
+	ctx, _, mockKeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mockKeeper.Keeper
+	querior := NewQuerier(keeper)
+	queryStoreStats := func() (stats types.StoreStats) {
+		data, err := querior(ctx, []string{types.QueryStoreStats}, abci.RequestQuery{})
+		require.Nil(t, err)
+		require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &stats))
+		return
+	}
+	requireEntries := func(stats types.StoreStats, expected map[string]int64) {
+		require.Equal(t, len(expected), len(stats))
+		for _, s := range stats {
+			require.Equal(t, expected[s.Name], s.Entries, s.Name)
+		}
+	}
+
+	// empty store
+	requireEntries(queryStoreStats(), map[string]int64{
+		"validators": 0, "delegators": 0, "votes": 0, "undelegations": 0, "power_index": 0,
+		"delegator_history": 0})
+
+	vals := createVals(ctx, 3, keeper)
+	for _, val := range vals {
+		keeper.SetValidatorByPowerIndex(ctx, val)
+	}
+	for _, delAddr := range addrDels[:2] {
+		require.Nil(t, keeper.Delegate(ctx, delAddr, types2.NewDecCoinFromDec(types2.DefaultBondDenom, types2.NewDec(100))))
+	}
+	_, err := keeper.VoteValidators(ctx, addrDels[0], vals[:2], types2.NewDec(100))
+	require.Nil(t, err)
+	_, err = keeper.BeginUnbonding(ctx, addrDels[1], types2.NewDecCoinFromDec(types2.DefaultBondDenom, types2.NewDec(50)))
+	require.Nil(t, err)
+
+	stats := queryStoreStats()
+	requireEntries(stats, map[string]int64{
+		"validators": 3, "delegators": 2, "votes": 2, "undelegations": 1, "power_index": 3,
+		"delegator_history": 0})
+	require.Equal(t, "21", stats[0].Prefix)
+	require.Contains(t, stats.String(), "votes (0x51):    2")
+}
+
+func TestQueryPowerDistribution(t *testing.T) {
+	ctx, _, mockKeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mockKeeper.Keeper
+	vals := createVals(ctx, 3, keeper)
+	querior := NewQuerier(keeper)
+	queryPowerDistribution := func() (distribution types.PowerDistribution) {
+		data, err := querior(ctx, []string{types.QueryPowerDistribution}, abci.RequestQuery{})
+		require.Nil(t, err)
+		require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &distribution))
+		return
+	}
+
+	// no validator with power
+	distribution := queryPowerDistribution()
+	require.Equal(t, int64(0), distribution.TotalPower)
+	require.Equal(t, int64(0), distribution.NakamotoCoefficient)
+	require.Empty(t, distribution.Validators)
+
+	// powers of 2:1:1, where the first validator alone controls more than 1/3
+	tokens := types2.NewDec(1000)
+	_, err := keeper.VoteValidators(ctx, addrDels[0], vals, tokens)
+	require.Nil(t, err)
+	_, err = keeper.VoteValidators(ctx, addrDels[1], getVals(ctx, vals[:1], keeper, t), tokens)
+	require.Nil(t, err)
+	distribution = queryPowerDistribution()
+	require.Equal(t, 3, len(distribution.Validators))
+	require.True(t, distribution.Validators[0].OperatorAddress.Equals(vals[0].OperatorAddress))
+	require.True(t, types2.NewDec(50).Equal(distribution.Validators[0].Percentage))
+	for _, val := range distribution.Validators {
+		require.True(t, types2.NewDec(val.Power*100).QuoInt64(distribution.TotalPower).Equal(val.Percentage))
+	}
+	require.Equal(t, int64(1), distribution.NakamotoCoefficient)
+
+	// equal powers, where any single validator controls exactly 1/3 which isn't more than 1/3
+	_, err = keeper.VoteValidators(ctx, addrDels[2], getVals(ctx, vals[1:2], keeper, t), tokens)
+	require.Nil(t, err)
+	_, err = keeper.VoteValidators(ctx, addrDels[1], getVals(ctx, vals[2:], keeper, t), tokens)
+	require.Nil(t, err)
+	distribution = queryPowerDistribution()
+	require.Equal(t, distribution.Validators[0].Power*3, distribution.TotalPower)
+	require.Equal(t, int64(2), distribution.NakamotoCoefficient)
+}
+
+type mockMintKeeper struct {
+	params mint.Params
+	minter mint.MinterCustom
+}
+
+func (mk mockMintKeeper) GetParams(_ types2.Context) mint.Params {
+	return mk.params
+}
+
+func (mk mockMintKeeper) GetMinterCustom(_ types2.Context) mint.MinterCustom {
+	return mk.minter
+}
+
+func TestQueryBlockReward(t *testing.T) {
+	ctx, _, mockKeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mockKeeper.Keeper
+	querior := NewQuerier(keeper)
+
+	// no mint keeper to derive the reward from
+	_, err := querior(ctx, []string{types.QueryBlockReward}, abci.RequestQuery{})
+	require.NotNil(t, err)
+
+	// the reward is what the minter mints per block, rather than recalculated from the current supply
+	mintParams := mint.DefaultParams()
+	mintParams.InflationRate = types2.NewDecWithPrec(1, 2)
+	mintParams.BlocksPerYear = 10000
+	mintedPerBlock := types2.NewDecCoinsFromDec(mintParams.MintDenom, types2.NewDec(3))
+	mintKeeper := &mockMintKeeper{mintParams, mint.MinterCustom{MintedPerBlock: mintedPerBlock}}
+	keeper.SetMintKeeper(mintKeeper)
+	querior = NewQuerier(keeper)
+
+	data, err := querior(ctx, []string{types.QueryBlockReward}, abci.RequestQuery{})
+	require.Nil(t, err)
+	var reward types.BlockReward
+	require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &reward))
+	require.Equal(t, mintParams.MintDenom, reward.Amount.Denom)
+	require.True(t, types2.NewDec(3).Equal(reward.Amount.Amount))
+	require.True(t, keeper.BondedRatio(ctx).Equal(reward.BondedRatio))
+	require.Equal(t, uint64(10000), reward.BlocksPerYear)
+
+	// nothing minted before the minter is updated
+	mintKeeper.minter = mint.MinterCustom{}
+	data, err = querior(ctx, []string{types.QueryBlockReward}, abci.RequestQuery{})
+	require.Nil(t, err)
+	require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &reward))
+	require.Equal(t, mintParams.MintDenom, reward.Amount.Denom)
+	require.True(t, reward.Amount.Amount.IsZero())
+}
+
+func TestQueryDelegatorHistory(t *testing.T) {
+	ctx, _, mockKeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mockKeeper.Keeper
+	querior := NewQuerier(keeper)
+	delAddr := addrDels[0]
+	amount := types2.NewDecCoinFromDec(types2.DefaultBondDenom, types2.NewDec(100))
+	params := keeper.GetParams(ctx)
+	params.RecordDelegatorHistory = true
+	keeper.SetParams(ctx, params)
+
+	// one action more than the retention window, the first one is pruned
+	for i := uint64(0); i <= types.DelegatorHistoryRetention; i++ {
+		keeper.RecordDelegatorAction(ctx.WithBlockHeight(int64(i)), delAddr, types.DelegatorActionDelegate, amount, nil)
+	}
+	// the history of another delegator is kept apart
+	keeper.RecordDelegatorAction(ctx, addrDels[1], types.DelegatorActionUndelegate, amount, nil)
+
+	bz, _ := types.ModuleCdc.MarshalJSON(types.NewQueryDelegatorParams(delAddr))
+	data, err := querior(ctx, []string{types.QueryDelegatorHistory}, abci.RequestQuery{Data: bz})
+	require.Nil(t, err)
+	var history types.DelegatorHistory
+	require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &history))
+	require.Equal(t, int(types.DelegatorHistoryRetention), len(history))
+	require.Equal(t, uint64(1), history[0].Sequence)
+	require.Equal(t, int64(1), history[0].Height)
+	require.Equal(t, types.DelegatorHistoryRetention, history[len(history)-1].Sequence)
+	require.Equal(t, 1, len(keeper.GetDelegatorHistory(ctx, addrDels[1])))
+
+	// nothing recorded
+	bz, _ = types.ModuleCdc.MarshalJSON(types.NewQueryDelegatorParams(addrDels[2]))
+	data, err = querior(ctx, []string{types.QueryDelegatorHistory}, abci.RequestQuery{Data: bz})
+	require.Nil(t, err)
+	require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &history))
+	require.Empty(t, history)
+}
+
+func TestQuerySharesFromTokens(t *testing.T) {
+	ctx, _, mockKeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mockKeeper.Keeper
+	vals := createVals(ctx, 2, keeper)
+	querior := NewQuerier(keeper)
+	amount := types2.NewDecCoinFromDec(types2.DefaultBondDenom, types2.NewDec(100))
+	querySharesFromTokens := func(ctx types2.Context, valAddr types2.ValAddress, amount types2.DecCoin) (
+		shares types.SharesFromTokensResponse, err types2.Error) {
+		bz, _ := types.ModuleCdc.MarshalJSON(types.NewQuerySharesFromTokensParams(valAddr, amount))
+		data, err := querior(ctx, []string{types.QuerySharesFromTokens}, abci.RequestQuery{Data: bz})
+		if err == nil {
+			require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &shares))
+		}
+		return
+	}
+
+	// one share per token at the epoch of the weight
+	ctx = ctx.WithBlockTime(time.Unix(blockTimestampEpoch, 0))
+	shares, err := querySharesFromTokens(ctx, vals[0].OperatorAddress, amount)
+	require.Nil(t, err)
+	require.True(t, types2.NewDec(100).Equal(shares.Tokens))
+	require.True(t, types2.NewDec(100).Equal(shares.Shares))
+
+	// two shares per token a year later, which are the ones voted by a real delegation
+	ctx = ctx.WithBlockTime(time.Unix(blockTimestampEpoch+52*secondsPerWeek, 0))
+	shares, err = querySharesFromTokens(ctx, vals[0].OperatorAddress, amount)
+	require.Nil(t, err)
+	require.True(t, types2.NewDec(200).Equal(shares.Shares))
+	require.Nil(t, keeper.Delegate(ctx, addrDels[0], amount))
+	votes, err := keeper.VoteValidators(ctx, addrDels[0], vals[:1], types2.NewDec(100))
+	require.Nil(t, err)
+	require.True(t, shares.Shares.Equal(votes))
+
+	// the shares don't depend on the validator, even a jailed one
+	keeper.jailValidator(ctx, keeper.mustGetValidator(ctx, vals[1].OperatorAddress))
+	jailedShares, err := querySharesFromTokens(ctx, vals[1].OperatorAddress, amount)
+	require.Nil(t, err)
+	require.True(t, shares.Shares.Equal(jailedShares.Shares))
+
+	// unknown validator or denom
+	_, err = querySharesFromTokens(ctx, addrVals[2], amount)
+	require.NotNil(t, err)
+	_, err = querySharesFromTokens(ctx, vals[0].OperatorAddress, types2.NewDecCoinFromDec("xxb", types2.NewDec(100)))
+	require.NotNil(t, err)
+}
@@ -60,10 +60,11 @@ type MockStakingKeeper struct {
 	SupplyKeeper supply.Keeper
 	MountedStore store.MultiStore
 	AccKeeper    auth.AccountKeeper
+	ParamsKey    sdk.StoreKey
 }
 
 func NewMockStakingKeeper(k Keeper, keyStoreKey, tkeyStoreKey sdk.StoreKey, sKeeper supply.Keeper,
-	ms store.MultiStore, accKeeper auth.AccountKeeper) MockStakingKeeper {
+	ms store.MultiStore, accKeeper auth.AccountKeeper, keyParams sdk.StoreKey) MockStakingKeeper {
 	return MockStakingKeeper{
 		k,
 		keyStoreKey,
@@ -71,6 +72,7 @@ func NewMockStakingKeeper(k Keeper, keyStoreKey, tkeyStoreKey sdk.StoreKey, sKee
 		sKeeper,
 		ms,
 		accKeeper,
+		keyParams,
 	}
 }
 
@@ -192,7 +194,7 @@ func CreateTestInput(t *testing.T, isCheckTx bool, initBalance int64) (sdk.Conte
 	keeper.SetHooks(hooks)
 
 	mockKeeper := NewMockStakingKeeper(keeper, keyStaking, tkeyStaking,
-		supplyKeeper, ms, accountKeeper)
+		supplyKeeper, ms, accountKeeper, keyParams)
 
 	return ctx, accountKeeper, mockKeeper
 }
@@ -315,3 +317,5 @@ func (dk mockDistributionKeeper) AfterValidatorBeginUnbonding(ctx sdk.Context, c
 }
 func (dk mockDistributionKeeper) AfterValidatorDestroyed(ctx sdk.Context, consAddr sdk.ConsAddress, valAddr sdk.ValAddress) {
 }
+func (dk mockDistributionKeeper) BeforeDelegationRemoved(ctx sdk.Context, delAddr sdk.AccAddress, valAddr sdk.ValAddress) {
+}
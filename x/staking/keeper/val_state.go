@@ -20,6 +20,7 @@ func (k Keeper) KickOutAndReturnValidatorSetUpdates(ctx sdk.Context) (updates []
 
 	store := ctx.KVStore(k.storeKey)
 	totalPower := k.GetLastTotalPower(ctx)
+	powerReduction := k.GetPowerReduction(ctx)
 
 	// 3.look for the ahead candidate and promote it
 	iterator := sdk.KVStoreReversePrefixIterator(store, types.ValidatorsByPowerIndexKey)
@@ -38,7 +39,7 @@ func (k Keeper) KickOutAndReturnValidatorSetUpdates(ctx sdk.Context) (updates []
 		// promote the candidate
 		validator := k.mustGetValidator(ctx, valAddr)
 		// if we get to a zero-power validator without votes, just pass
-		if validator.PotentialConsensusPowerByVotes() == 0 {
+		if validator.PotentialConsensusPowerByVotes(powerReduction) == 0 {
 			continue
 		}
 
@@ -54,9 +55,9 @@ func (k Keeper) KickOutAndReturnValidatorSetUpdates(ctx sdk.Context) (updates []
 		}
 
 		// calculate the new power of candidate validator
-		newPower := validator.ConsensusPowerByVotes()
+		newPower := validator.ConsensusPowerByVotes(powerReduction)
 		// update the validator to tendermint
-		updates = append(updates, validator.ABCIValidatorUpdateByVotes())
+		updates = append(updates, validator.ABCIValidatorUpdateByVotes(powerReduction))
 		// set validator power on lookup index
 		k.SetLastValidatorPower(ctx, valAddr, newPower)
 		// cumsum the total power
@@ -88,6 +89,7 @@ func (k Keeper) KickOutAndReturnValidatorSetUpdates(ctx sdk.Context) (updates []
 	// 5. update the total power of this block to store
 	k.SetLastTotalPower(ctx, totalPower)
 
+	k.mustNotExceedMaxValidators(ctx)
 	return updates
 }
 
@@ -4,12 +4,49 @@ import (
 	"bytes"
 	"fmt"
 	"sort"
+	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/okex/okchain/x/staking/types"
 	abci "github.com/tendermint/tendermint/abci/types"
 )
 
+// ProcessEpochEnd does all the work at the end of an epoch and returns the validator updates to Tendermint:
+// * Applies the epoch, power reduction and tie-break params changed within the epoch.
+// * Applies the unbonding time and the max validators scheduled within the epoch.
+// * Converts the tokens of the delegators by the bond denom weights changed within the epoch.
+// * Snapshots the bonded tokens of the epoch.
+// * Moves on to the next epoch.
+// * Recomputes the validator set, which covers the validators abandoned but not kicked out within the epoch yet.
+// * Applies the delegations deferred within the epoch boundary grace, which count from the next epoch on.
+// The commission of the validators is distributed by the distribution module rather than here, every block or at the
+// beginning of the next epoch according to its reward_cadence param.
+// CONTRACT: it's only called by the EndBlocker once IsEndOfEpoch
+func (k Keeper) ProcessEpochEnd(ctx sdk.Context) []abci.ValidatorUpdate {
+	if oldEpoch, newEpoch := k.GetEpoch(ctx), k.ParamsEpoch(ctx); oldEpoch != newEpoch {
+		k.SetEpoch(ctx, newEpoch)
+	}
+	oldPowerReduction, newPowerReduction := k.GetPowerReduction(ctx), k.ParamsPowerReduction(ctx)
+	if !oldPowerReduction.Equal(newPowerReduction) {
+		k.SetPowerReduction(ctx, newPowerReduction)
+	}
+	if oldTieBreak, newTieBreak := k.GetPowerTieBreak(ctx), k.ParamsPowerTieBreak(ctx); oldTieBreak != newTieBreak {
+		k.SetPowerTieBreak(ctx, newTieBreak)
+	}
+	k.ApplyPendingUnbondingTime(ctx)
+	k.ApplyPendingMaxValidators(ctx)
+	k.ApplyBondDenoms(ctx)
+	k.RecordBondedSnapshot(ctx)
+	k.SetTheEndOfLastEpoch(ctx)
+	k.SetEpochNumber(ctx, k.CurrentEpochNumber(ctx)+1)
+
+	validatorUpdates := k.ApplyAndReturnValidatorSetUpdates(ctx)
+	// dont forget to delete in case that some validator need to kick out when an epoch ends
+	k.DeleteAbandonedValidatorAddrs(ctx)
+	k.ApplyPendingDelegations(ctx)
+	return validatorUpdates
+}
+
 // ApplyAndReturnValidatorSetUpdates applies and returns accumulated updates to the bonded validator set. Also,
 // * Updates the active valset as keyed by LastValidatorPowerKey.
 // * Updates the total power as keyed by LastTotalPowerKey.
@@ -25,7 +62,8 @@ import (
 func (k Keeper) ApplyAndReturnValidatorSetUpdates(ctx sdk.Context) (updates []abci.ValidatorUpdate) {
 
 	store := ctx.KVStore(k.storeKey)
-	maxValidators := k.GetParams(ctx).MaxValidators
+	maxValidators := k.GetParamsCached(ctx).MaxValidators
+	powerReduction := k.GetPowerReduction(ctx)
 	totalPower := sdk.ZeroInt()
 
 	// Retrieve the last validator set. The persistent set is updated later in this function (see LastValidatorPowerKey)
@@ -42,15 +80,25 @@ func (k Keeper) ApplyAndReturnValidatorSetUpdates(ctx sdk.Context) (updates []ab
 			panic("should never retrieve a jailed validator from the power store")
 		}
 
-		// if we get to a zero-power validator (which we don't vote), there are no more possible elected validators
-		if validator.PotentialConsensusPowerByVotes() == 0 {
+		// if we get to a zero-power validator (which we don't vote), there are no more possible elected validators.
+		// It's never bonded even though it's still within the max validators, because its votes are below one power unit
+		if validator.PotentialConsensusPowerByVotes(powerReduction) == 0 {
 			break
 		}
 
+		// fetch the old power bytes
+		var valAddrBytes [sdk.AddrLen]byte
+		copy(valAddrBytes[:], valAddr[:])
+		oldPowerBytes, found := last[valAddrBytes]
+
 		// apply the appropriate state change if necessary
 		switch {
 		case validator.IsUnbonded():
 			validator = k.unbondedToBonded(ctx, validator)
+		case validator.IsUnbonding() && found:
+			// a bonded validator corrected to unbonding by ForceUpdateValidatorState leaves the set before it could be
+			// bonded again
+			continue
 		case validator.IsUnbonding():
 			validator = k.unbondingToBonded(ctx, validator)
 		case validator.IsBonded():
@@ -59,18 +107,18 @@ func (k Keeper) ApplyAndReturnValidatorSetUpdates(ctx sdk.Context) (updates []ab
 			panic("unexpected validator status")
 		}
 
-		// fetch the old power bytes
-		var valAddrBytes [sdk.AddrLen]byte
-		copy(valAddrBytes[:], valAddr[:])
-		oldPowerBytes, found := last[valAddrBytes]
-
 		// calculate the new power bytes
-		newPower := validator.ConsensusPowerByVotes()
+		newPower := validator.ConsensusPowerByVotes(powerReduction)
 		newPowerBytes := k.cdc.MustMarshalBinaryLengthPrefixed(newPower)
 
+		// the validator enters the validator set
+		if !found {
+			emitValidatorSetChangeEvent(ctx, types.EventTypeValidatorBonded, valAddr, 0, newPower)
+		}
+
 		// update the validator set if power has changed
 		if !found || !bytes.Equal(oldPowerBytes, newPowerBytes) {
-			updates = append(updates, validator.ABCIValidatorUpdateByVotes())
+			updates = append(updates, validator.ABCIValidatorUpdateByVotes(powerReduction))
 
 			// set validator power on lookup index
 			k.SetLastValidatorPower(ctx, valAddr, newPower)
@@ -93,14 +141,20 @@ func (k Keeper) ApplyAndReturnValidatorSetUpdates(ctx sdk.Context) (updates []ab
 		// fetch the validator
 		validator := k.mustGetValidator(ctx, sdk.ValAddress(valAddrBytes))
 
-		// bonded to unbonding
-		validator = k.bondedToUnbonding(ctx, validator)
+		// bonded to unbonding, unless it has begun unbonding by ForceUpdateValidatorState
+		if validator.IsBonded() {
+			validator = k.bondedToUnbonding(ctx, validator)
+		}
 
 		// delete from the bonded validator index
 		k.DeleteLastValidatorPower(ctx, validator.GetOperator())
 
 		// update the validator set
 		updates = append(updates, validator.ABCIValidatorUpdateZero())
+
+		var oldPower int64
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(last[getLastValidatorsMapKey(valAddrBytes)], &oldPower)
+		emitValidatorSetChangeEvent(ctx, types.EventTypeValidatorUnbonded, validator.OperatorAddress, oldPower, 0)
 	}
 
 	// set total power on lookup index if there are any updates
@@ -108,9 +162,184 @@ func (k Keeper) ApplyAndReturnValidatorSetUpdates(ctx sdk.Context) (updates []ab
 		k.SetLastTotalPower(ctx, totalPower)
 	}
 
+	k.mustNotExceedMaxValidators(ctx)
 	return updates
 }
 
+// emitValidatorSetChangeEvent emits the event of a validator entering or leaving the validator set with its power
+// before and after
+func emitValidatorSetChangeEvent(ctx sdk.Context, eventType string, valAddr sdk.ValAddress, oldPower, newPower int64) {
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			eventType,
+			sdk.NewAttribute(types.AttributeKeyValidator, valAddr.String()),
+			sdk.NewAttribute(types.AttributeKeyOldPower, fmt.Sprintf("%d", oldPower)),
+			sdk.NewAttribute(types.AttributeKeyNewPower, fmt.Sprintf("%d", newPower)),
+		),
+	)
+}
+
+// mustNotExceedMaxValidators panics if the last validator set is larger than the param MaxValidators once it's updated,
+// which a bug in the power index or a stale entry of the last validator set must never lead to
+func (k Keeper) mustNotExceedMaxValidators(ctx sdk.Context) {
+	var bondedCount int
+	k.IterateLastValidatorPowers(ctx, func(sdk.ValAddress, int64) (stop bool) {
+		bondedCount++
+		return false
+	})
+	if maxValidators := k.GetParamsCached(ctx).MaxValidators; bondedCount > int(maxValidators) {
+		panic(fmt.Sprintf("the bonded validator set has %d validators, more than the max validators %d",
+			bondedCount, maxValidators))
+	}
+}
+
+// isWithinValidatorSet tells whether a validator ranks within the validator set which the recompute by
+// ApplyAndReturnValidatorSetUpdates bonds at the end of the current block
+func (k Keeper) isWithinValidatorSet(ctx sdk.Context, valAddr sdk.ValAddress) bool {
+	maxValidators := k.GetParamsCached(ctx).MaxValidators
+	powerReduction := k.GetPowerReduction(ctx)
+
+	iterator := k.ValidatorsPowerStoreIterator(ctx)
+	defer iterator.Close()
+	for count := 0; iterator.Valid() && count < int(maxValidators); iterator.Next() {
+		validator := k.mustGetValidator(ctx, iterator.Value())
+		if validator.PotentialConsensusPowerByVotes(powerReduction) == 0 {
+			return false
+		}
+		if validator.OperatorAddress.Equals(valAddr) {
+			return true
+		}
+		// the validator leaving the set by a correction doesn't take a seat
+		if !validator.IsUnbonding() || !k.hasLastValidatorPower(ctx, validator.OperatorAddress) {
+			count++
+		}
+	}
+	return false
+}
+
+// GetProjectedValidatorSet returns the validator set which would be bonded if the current epoch ended now, with
+// the validators entering and leaving compared to the current bonded set. It's read-only and only a projection,
+// the actual set is decided by ApplyAndReturnValidatorSetUpdates at the end of the epoch
+func (k Keeper) GetProjectedValidatorSet(ctx sdk.Context) types.ProjectedValidatorSet {
+	// the params pending to take effect are applied at the end of the epoch before the validator set updates
+	maxValidators := k.MaxValidators(ctx)
+	powerReduction := k.ParamsPowerReduction(ctx)
+	last := k.getLastValidatorsByAddr(ctx)
+
+	var projected []types.ProjectedValidator
+	var entering []sdk.ValAddress
+	iterator := k.ValidatorsPowerStoreIterator(ctx)
+	defer iterator.Close()
+	for count := 0; iterator.Valid() && count < int(maxValidators); iterator.Next() {
+		valAddr := sdk.ValAddress(iterator.Value())
+		validator := k.mustGetValidator(ctx, valAddr)
+
+		power := validator.PotentialConsensusPowerByVotes(powerReduction)
+		if power == 0 {
+			break
+		}
+
+		projected = append(projected, types.NewProjectedValidator(valAddr, power))
+		valAddrKey := getLastValidatorsMapKey(valAddr)
+		if _, found := last[valAddrKey]; found {
+			delete(last, valAddrKey)
+		} else {
+			entering = append(entering, valAddr)
+		}
+		count++
+	}
+
+	noLongerBonded := sortNoLongerBonded(last)
+	leaving := make([]sdk.ValAddress, len(noLongerBonded))
+	for i, valAddrBytes := range noLongerBonded {
+		leaving[i] = valAddrBytes
+	}
+
+	epochEndHeight := k.GetTheEndOfLastEpoch(ctx) + int64(k.GetEpoch(ctx))
+	return types.NewProjectedValidatorSet(ctx.BlockHeight(), epochEndHeight, projected, entering, leaving)
+}
+
+// GetPowerDistribution gets the distribution of the consensus power over the validators in the projected validator set,
+// so that it's only a prediction of the next validator set as well
+func (k Keeper) GetPowerDistribution(ctx sdk.Context) types.PowerDistribution {
+	return types.NewPowerDistribution(k.GetProjectedValidatorSet(ctx).Validators)
+}
+
+// GetCandidateValidators returns the unbonded validators with min self delegation that rank below the cutoff of
+// MaxValidators by the current votes, sorted by power. They're the next ones to be promoted once any validator above
+// the cutoff leaves or loses votes
+func (k Keeper) GetCandidateValidators(ctx sdk.Context) (candidates types.Validators) {
+	candidates = types.Validators{}
+	maxValidators := int(k.MaxValidators(ctx))
+	powerReduction := k.ParamsPowerReduction(ctx)
+	iterator := k.ValidatorsPowerStoreIterator(ctx)
+	defer iterator.Close()
+	// the same cutoff as ApplyAndReturnValidatorSetUpdates
+	for rank := 0; iterator.Valid(); iterator.Next() {
+		validator := k.mustGetValidator(ctx, sdk.ValAddress(iterator.Value()))
+		if validator.PotentialConsensusPowerByVotes(powerReduction) == 0 {
+			break
+		}
+
+		rank++
+		if rank > maxValidators && !validator.IsBonded() && validator.MinSelfDelegation.IsPositive() {
+			candidates = append(candidates, validator)
+		}
+	}
+	return
+}
+
+// GetMarginalValidator returns the validator ranked at the cutoff of MaxValidators by the current votes, which drops
+// out once a validator below the cutoff climbs above it, and the power gap to the first validator below the cutoff.
+// found is false if the validators with votes don't fill the set, when nobody would be evicted
+func (k Keeper) GetMarginalValidator(ctx sdk.Context) (marginal types.MarginalValidator, found bool) {
+	maxValidators := int(k.MaxValidators(ctx))
+	powerReduction := k.ParamsPowerReduction(ctx)
+	iterator := k.ValidatorsPowerStoreIterator(ctx)
+	defer iterator.Close()
+	// the same cutoff as ApplyAndReturnValidatorSetUpdates
+	for rank := 0; iterator.Valid() && rank <= maxValidators; iterator.Next() {
+		valAddr := sdk.ValAddress(iterator.Value())
+		power := k.mustGetValidator(ctx, valAddr).PotentialConsensusPowerByVotes(powerReduction)
+		if power == 0 {
+			break
+		}
+
+		rank++
+		if rank == maxValidators {
+			marginal, found = types.NewMarginalValidator(valAddr, power, nil, 0), true
+		} else if rank > maxValidators {
+			marginal = types.NewMarginalValidator(marginal.OperatorAddress, marginal.Power, valAddr, power)
+		}
+	}
+	return
+}
+
+// ValidatorPowerDelta returns the power of a validator in the validator set updated at the end of last epoch and its
+// projected power by the current votes, which is zero if the validator is jailed or removed
+func (k Keeper) ValidatorPowerDelta(ctx sdk.Context, valAddr sdk.ValAddress) (lastPower, projectedPower int64) {
+	lastPower = k.GetLastValidatorPower(ctx, valAddr)
+	validator, found := k.GetValidator(ctx, valAddr)
+	if !found || validator.Jailed {
+		return lastPower, 0
+	}
+	return lastPower, validator.PotentialConsensusPowerByVotes(k.ParamsPowerReduction(ctx))
+}
+
+// IsLargePowerChange tells whether the absolute power delta exceeds the PowerAlertThreshold fraction of the last
+// total power
+func (k Keeper) IsLargePowerChange(ctx sdk.Context, lastPower, projectedPower int64) bool {
+	threshold := k.ParamsPowerAlertThreshold(ctx)
+	if threshold.IsZero() {
+		return false
+	}
+	delta := projectedPower - lastPower
+	if delta < 0 {
+		delta = -delta
+	}
+	return sdk.NewDec(delta).GT(threshold.MulInt(k.GetLastTotalPower(ctx)))
+}
+
 // Validator state transitions
 // bondedToUnbonding switches a validator from bonded state to unbonding state
 func (k Keeper) bondedToUnbonding(ctx sdk.Context, validator types.Validator) types.Validator {
@@ -168,12 +397,16 @@ func (k Keeper) unjailValidator(ctx sdk.Context, validator types.Validator) {
 
 // bondValidator performs all the store operations for when a validator status becomes bonded
 func (k Keeper) bondValidator(ctx sdk.Context, validator types.Validator) types.Validator {
+	if err := types.ValidateStatusTransition(validator.Status, sdk.Bonded); err != nil {
+		panic(fmt.Sprintf("%s, validator: %v\n", err, validator))
+	}
 
 	// delete the validator by power index, as the key will change
 	k.DeleteValidatorByPowerIndex(ctx, validator)
 
-	// set the status
+	// set the status and start the tenure of the validator
 	validator = validator.UpdateStatus(sdk.Bonded)
+	validator.BondedSince = ctx.BlockHeader().Time
 
 	// save the now bonded validator record to the two referenced stores
 	k.SetValidator(ctx, validator)
@@ -190,23 +423,24 @@ func (k Keeper) bondValidator(ctx sdk.Context, validator types.Validator) types.
 
 // beginUnbondingValidator performs all the store operations for when a validator begins unbonding
 func (k Keeper) beginUnbondingValidator(ctx sdk.Context, validator types.Validator) types.Validator {
+	// sanity check before any store operation
+	if err := types.ValidateStatusTransition(validator.Status, sdk.Unbonding); err != nil {
+		panic(fmt.Sprintf("%s, validator: %v\n", err, validator))
+	}
 
 	params := k.GetParams(ctx)
 
 	// delete the validator by power index, as the key will change
 	k.DeleteValidatorByPowerIndex(ctx, validator)
 
-	// sanity check
-	if validator.Status != sdk.Bonded {
-		panic(fmt.Sprintf("should not already be unbonded or unbonding, validator: %v\n", validator))
-	}
-
 	// set the status
 	validator = validator.UpdateStatus(sdk.Unbonding)
 
 	// set the unbonding completion time and completion height appropriately
 	validator.UnbondingCompletionTime = ctx.BlockHeader().Time.Add(params.UnbondingTime)
 	validator.UnbondingHeight = ctx.BlockHeader().Height
+	// the tenure ends once the validator leaves the bonded set
+	validator.BondedSince = time.Unix(0, 0).UTC()
 
 	// save the now unbonded validator record and power index
 	k.SetValidator(ctx, validator)
@@ -223,6 +457,9 @@ func (k Keeper) beginUnbondingValidator(ctx sdk.Context, validator types.Validat
 
 // completeUnbondingValidator performs all the store operations for when a validator status becomes unbonded
 func (k Keeper) completeUnbondingValidator(ctx sdk.Context, validator types.Validator) types.Validator {
+	if err := types.ValidateStatusTransition(validator.Status, sdk.Unbonded); err != nil {
+		panic(fmt.Sprintf("%s, validator: %v\n", err, validator))
+	}
 	validator = validator.UpdateStatus(sdk.Unbonded)
 	k.SetValidator(ctx, validator)
 	return validator
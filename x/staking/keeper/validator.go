@@ -2,11 +2,16 @@ package keeper
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
+	"sort"
 	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/okex/okchain/x/staking/exported"
 	"github.com/okex/okchain/x/staking/types"
+	"github.com/tendermint/tendermint/libs/common"
+	tmtypes "github.com/tendermint/tendermint/types"
 )
 
 // Cache the amino decoding of validators, as it can be the case that repeated slashing calls
@@ -66,6 +71,33 @@ func (k Keeper) mustGetValidator(ctx sdk.Context, addr sdk.ValAddress) types.Val
 	return validator
 }
 
+// GetValidatorStatus gets the status of a validator, which is Jailed for a jailed validator whatever its bond status
+func (k Keeper) GetValidatorStatus(ctx sdk.Context, valAddr sdk.ValAddress) (status types.ValidatorStatus, found bool) {
+	validator, found := k.GetValidator(ctx, valAddr)
+	if !found {
+		return status, false
+	}
+	return validator.GetValidatorStatus(), true
+}
+
+// GetValidatorByOperatorString gets a single validator by the bech32 string of its operator address, which is
+// convenient for the entries receiving the address from users
+func (k Keeper) GetValidatorByOperatorString(ctx sdk.Context, bech32Addr string) (types.Validator, sdk.Error) {
+	valAddr, err := sdk.ValAddressFromBech32(bech32Addr)
+	if err != nil {
+		return types.Validator{}, types.ErrBadValidatorAddr(k.Codespace())
+	}
+	if valAddr.Empty() {
+		return types.Validator{}, types.ErrNilValidatorAddr(k.Codespace())
+	}
+
+	validator, found := k.GetValidator(ctx, valAddr)
+	if !found {
+		return types.Validator{}, types.ErrNoValidatorFound(k.Codespace(), bech32Addr)
+	}
+	return validator, nil
+}
+
 // GetValidatorByConsAddr gets a single validator by consensus address
 func (k Keeper) GetValidatorByConsAddr(ctx sdk.Context, consAddr sdk.ConsAddress) (validator types.Validator,
 	found bool) {
@@ -88,8 +120,53 @@ func (k Keeper) mustGetValidatorByConsAddr(ctx sdk.Context, consAddr sdk.ConsAdd
 // SetValidator sets the main record holding validator details
 func (k Keeper) SetValidator(ctx sdk.Context, validator types.Validator) {
 	store := ctx.KVStore(k.storeKey)
+	key := types.GetValidatorKey(validator.OperatorAddress)
+	// the total votes follow the votes of the validator
+	lastVotes := sdk.ZeroDec()
+	if bz := store.Get(key); bz != nil {
+		lastVotes = votesOf(types.MustUnmarshalValidator(k.cdc, bz))
+	}
+	if votes := votesOf(validator); !votes.Equal(lastVotes) {
+		k.setTotalVotes(ctx, k.GetTotalVotes(ctx).Sub(lastVotes).Add(votes))
+	}
+
 	bz := types.MustMarshalValidator(k.cdc, validator)
-	store.Set(types.GetValidatorKey(validator.OperatorAddress), bz)
+	store.Set(key, bz)
+}
+
+// GetTotalVotes gets the total votes of all the validators
+func (k Keeper) GetTotalVotes(ctx sdk.Context) (totalVotes sdk.Dec) {
+	bytes := ctx.KVStore(k.storeKey).Get(types.TotalVotesKey)
+	if bytes == nil {
+		return sdk.ZeroDec()
+	}
+
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(bytes, &totalVotes)
+	return
+}
+
+// setTotalVotes sets the total votes of all the validators to store
+func (k Keeper) setTotalVotes(ctx sdk.Context, totalVotes sdk.Dec) {
+	ctx.KVStore(k.storeKey).Set(types.TotalVotesKey, k.cdc.MustMarshalBinaryLengthPrefixed(totalVotes))
+}
+
+// sumTotalVotes sums up the votes of the validators in store and sets the total votes with it, which was never
+// maintained by the earlier software
+func (k Keeper) sumTotalVotes(ctx sdk.Context) sdk.Dec {
+	totalVotes := sdk.ZeroDec()
+	for _, validator := range k.GetAllValidators(ctx) {
+		totalVotes = totalVotes.Add(votesOf(validator))
+	}
+	k.setTotalVotes(ctx, totalVotes)
+	return totalVotes
+}
+
+// votesOf returns the votes of a validator, which are zero for the validator never voted in the earlier software
+func votesOf(validator types.Validator) sdk.Dec {
+	if validator.DelegatorShares.IsNil() {
+		return sdk.ZeroDec()
+	}
+	return validator.DelegatorShares
 }
 
 // SetValidatorByConsAddr sets the operator address with the key of validator consensus pubkey
@@ -99,6 +176,68 @@ func (k Keeper) SetValidatorByConsAddr(ctx sdk.Context, validator types.Validato
 	store.Set(types.GetValidatorByConsAddrKey(consAddr), validator.OperatorAddress)
 }
 
+// GetValidatorByMoniker gets a single validator by its moniker, which is case-insensitive
+func (k Keeper) GetValidatorByMoniker(ctx sdk.Context, moniker string) (validator types.Validator, found bool) {
+	store := ctx.KVStore(k.storeKey)
+	opAddr := store.Get(types.GetValidatorByMonikerKey(moniker))
+	if opAddr == nil {
+		return validator, false
+	}
+	return k.GetValidator(ctx, opAddr)
+}
+
+// SetValidatorByMoniker sets the operator address with the key of validator moniker
+func (k Keeper) SetValidatorByMoniker(ctx sdk.Context, validator types.Validator) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.GetValidatorByMonikerKey(validator.Description.Moniker), validator.OperatorAddress)
+}
+
+// DeleteValidatorByMoniker deletes the moniker index of a validator only when it still points to the validator
+func (k Keeper) DeleteValidatorByMoniker(ctx sdk.Context, validator types.Validator) {
+	store := ctx.KVStore(k.storeKey)
+	key := types.GetValidatorByMonikerKey(validator.Description.Moniker)
+	if bytes.Equal(store.Get(key), validator.OperatorAddress) {
+		store.Delete(key)
+	}
+}
+
+// IsMonikerTaken checks whether the moniker has been used by a validator other than the given one
+func (k Keeper) IsMonikerTaken(ctx sdk.Context, moniker string, valAddr sdk.ValAddress) bool {
+	validator, found := k.GetValidatorByMoniker(ctx, moniker)
+	return found && !validator.OperatorAddress.Equals(valAddr)
+}
+
+// GetValidatorsByIdentity gets all the validators sharing an identity in the order of their operator addresses
+func (k Keeper) GetValidatorsByIdentity(ctx sdk.Context, identity string) types.Validators {
+	validators := types.Validators{}
+	if len(identity) == 0 {
+		return validators
+	}
+
+	prefixKey := types.GetValidatorsByIdentityKey(identity)
+	iterator := sdk.KVStorePrefixIterator(ctx.KVStore(k.storeKey), prefixKey)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		validators = append(validators, k.mustGetValidator(ctx, iterator.Key()[len(prefixKey):]))
+	}
+	return validators
+}
+
+// SetValidatorByIdentity sets the identity index of a validator. The validators without identity aren't indexed
+func (k Keeper) SetValidatorByIdentity(ctx sdk.Context, validator types.Validator) {
+	if len(validator.Description.Identity) == 0 {
+		return
+	}
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.GetValidatorByIdentityKey(validator.Description.Identity, validator.OperatorAddress), []byte{})
+}
+
+// DeleteValidatorByIdentity deletes the identity index of a validator
+func (k Keeper) DeleteValidatorByIdentity(ctx sdk.Context, validator types.Validator) {
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(types.GetValidatorByIdentityKey(validator.Description.Identity, validator.OperatorAddress))
+}
+
 // SetValidatorByPowerIndex sets the power index key of an unjailed validator
 func (k Keeper) SetValidatorByPowerIndex(ctx sdk.Context, validator types.Validator) {
 	// jailed validators are not kept in the power index
@@ -106,19 +245,134 @@ func (k Keeper) SetValidatorByPowerIndex(ctx sdk.Context, validator types.Valida
 		return
 	}
 	store := ctx.KVStore(k.storeKey)
-	store.Set(types.GetValidatorsByPowerIndexKey(validator), validator.OperatorAddress)
+	store.Set(k.getValidatorPowerIndexKey(ctx, validator), validator.OperatorAddress)
+	k.setPowerChanged(ctx, validator.OperatorAddress)
 }
 
 // DeleteValidatorByPowerIndex deletes the power index key
 func (k Keeper) DeleteValidatorByPowerIndex(ctx sdk.Context, validator types.Validator) {
 	store := ctx.KVStore(k.storeKey)
-	store.Delete(types.GetValidatorsByPowerIndexKey(validator))
+	store.Delete(k.getValidatorPowerIndexKey(ctx, validator))
+	k.setPowerChanged(ctx, validator.OperatorAddress)
 }
 
 // SetNewValidatorByPowerIndex sets the power index key of a validator
 func (k Keeper) SetNewValidatorByPowerIndex(ctx sdk.Context, validator types.Validator) {
 	store := ctx.KVStore(k.storeKey)
-	store.Set(types.GetValidatorsByPowerIndexKey(validator), validator.OperatorAddress)
+	store.Set(k.getValidatorPowerIndexKey(ctx, validator), validator.OperatorAddress)
+	k.setPowerChanged(ctx, validator.OperatorAddress)
+}
+
+// ValidateMsgCreateValidator checks a MsgCreateValidator against the current state without changing anything
+func (k Keeper) ValidateMsgCreateValidator(ctx sdk.Context, msg types.MsgCreateValidator) sdk.Error {
+	if _, found := k.GetValidator(ctx, msg.ValidatorAddress); found {
+		return types.ErrValidatorOwnerExists(k.Codespace())
+	}
+	if _, found := k.GetValidatorByConsAddr(ctx, sdk.GetConsAddress(msg.PubKey)); found {
+		return types.ErrValidatorPubKeyExists(k.Codespace())
+	}
+	if msg.MinSelfDelegation.Denom != k.BondDenom(ctx) {
+		return types.ErrBadDenom(k.Codespace())
+	}
+	if msdLimit := k.ParamsMinSelfDelegationLimited(ctx); msg.MinSelfDelegation.Amount.LT(msdLimit) {
+		return types.ErrInsufficientMinSelfDelegation(k.Codespace(), msdLimit)
+	}
+	if _, err := msg.Description.EnsureLength(); err != nil {
+		return err
+	}
+	if k.ParamsEnforceUniqueMoniker(ctx) && k.IsMonikerTaken(ctx, msg.Description.Moniker, msg.ValidatorAddress) {
+		return types.ErrValidatorMonikerExists(k.Codespace(), msg.Description.Moniker)
+	}
+	if ctx.ConsensusParams() != nil {
+		tmPubKey := tmtypes.TM2PB.PubKey(msg.PubKey)
+		if !common.StringInSlice(tmPubKey.Type, ctx.ConsensusParams().Validator.PubKeyTypes) {
+			return types.ErrValidatorPubKeyTypeNotSupported(k.Codespace(), tmPubKey.Type,
+				ctx.ConsensusParams().Validator.PubKeyTypes)
+		}
+	}
+	return nil
+}
+
+// SetValidatorBondHeight sets the bond height of a validator and refreshes its key in power index
+func (k Keeper) SetValidatorBondHeight(ctx sdk.Context, valAddr sdk.ValAddress, height int64) sdk.Error {
+	validator, found := k.GetValidator(ctx, valAddr)
+	if !found {
+		return types.ErrNoValidatorFound(k.Codespace(), valAddr.String())
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	oldKey := k.getValidatorPowerIndexKey(ctx, validator)
+	validator.BondHeight = height
+	k.SetValidator(ctx, validator)
+	if store.Has(oldKey) {
+		store.Delete(oldKey)
+		store.Set(k.getValidatorPowerIndexKey(ctx, validator), validator.OperatorAddress)
+	}
+	return nil
+}
+
+// ConsensusPower returns the consensus power of a validator from its votes and the power reduction taking effect, zero
+// if it isn't bonded
+func (k Keeper) ConsensusPower(ctx sdk.Context, validator exported.ValidatorI) int64 {
+	if !validator.IsBonded() {
+		return 0
+	}
+	return types.VotesToConsensusPower(validator.GetDelegatorShares(), k.GetPowerReduction(ctx))
+}
+
+// GetValidatorTenure returns how long the validator has been in the bonded set continuously, zero if it isn't bonded
+func (k Keeper) GetValidatorTenure(ctx sdk.Context, valAddr sdk.ValAddress) (types.ValidatorTenure, sdk.Error) {
+	validator, found := k.GetValidator(ctx, valAddr)
+	if !found {
+		return types.ValidatorTenure{}, types.ErrNoValidatorFound(k.Codespace(), valAddr.String())
+	}
+
+	var tenure time.Duration
+	if validator.IsBonded() {
+		tenure = ctx.BlockHeader().Time.Sub(validator.BondedSince)
+	}
+	return types.NewValidatorTenure(valAddr, validator.BondedSince, tenure), nil
+}
+
+// GetValidatorCommissionCooldown returns when the validator is allowed to change its commission rate next time and
+// how long it remains until then
+func (k Keeper) GetValidatorCommissionCooldown(ctx sdk.Context, valAddr sdk.ValAddress) (types.CommissionCooldown,
+	sdk.Error) {
+	validator, found := k.GetValidator(ctx, valAddr)
+	if !found {
+		return types.CommissionCooldown{}, types.ErrNoValidatorFound(k.Codespace(), valAddr.String())
+	}
+
+	nextUpdateTime := validator.Commission.NextUpdateTime(k.ParamsCommissionChangeWindow(ctx))
+	remaining := nextUpdateTime.Sub(ctx.BlockHeader().Time)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return types.NewCommissionCooldown(valAddr, validator.Commission.UpdateTime, nextUpdateTime, remaining), nil
+}
+
+// getValidatorPowerIndexKey gets the power index key of a validator with the power reduction and the tie-break rule
+// which are taking effect
+func (k Keeper) getValidatorPowerIndexKey(ctx sdk.Context, validator types.Validator) []byte {
+	return types.GetValidatorsByPowerIndexKeyWithTieBreak(validator, k.GetPowerReduction(ctx), k.GetPowerTieBreak(ctx))
+}
+
+// setPowerChanged marks the validator whose power changed in the current block
+func (k Keeper) setPowerChanged(ctx sdk.Context, valAddr sdk.ValAddress) {
+	ctx.TransientStore(k.storeTKey).Set(types.GetPowerChangedKey(valAddr), []byte{})
+}
+
+// IteratePowerChangedValidators iterates through the validators whose power changed in the current block
+func (k Keeper) IteratePowerChangedValidators(ctx sdk.Context, fn func(index int64, valAddr sdk.ValAddress) (stop bool)) {
+	iterator := sdk.KVStorePrefixIterator(ctx.TransientStore(k.storeTKey), types.PowerChangedKey)
+	defer iterator.Close()
+
+	for i := int64(0); iterator.Valid(); iterator.Next() {
+		if stop := fn(i, iterator.Key()[1:]); stop {
+			break
+		}
+		i++
+	}
 }
 
 // RemoveValidator removes the validator record and associated indexes
@@ -145,13 +399,39 @@ func (k Keeper) RemoveValidator(ctx sdk.Context, address sdk.ValAddress) {
 	// delete the old validator record
 	store := ctx.KVStore(k.storeKey)
 	store.Delete(types.GetValidatorKey(address))
+	k.setTotalVotes(ctx, k.GetTotalVotes(ctx).Sub(votesOf(validator)))
 	store.Delete(types.GetValidatorByConsAddrKey(sdk.ConsAddress(validator.ConsPubKey.Address())))
-	store.Delete(types.GetValidatorsByPowerIndexKey(validator))
+	store.Delete(k.getValidatorPowerIndexKey(ctx, validator))
+	k.DeleteValidatorByMoniker(ctx, validator)
+	k.DeleteValidatorByIdentity(ctx, validator)
+	store.Delete(types.GetLastSelfUndelegationKey(address))
 
 	// call hooks
 	k.AfterValidatorRemoved(ctx, validator.ConsAddress(), validator.OperatorAddress)
 }
 
+// HasMinValidators tells whether there are at least MinValidators validators able to be voted on the chain, that is,
+// the ones not destroyed
+func (k Keeper) HasMinValidators(ctx sdk.Context) bool {
+	minValidators := int(k.ParamsMinValidators(ctx))
+	if minValidators == 0 {
+		return true
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.ValidatorsKey)
+	defer iterator.Close()
+
+	count := 0
+	for ; iterator.Valid() && count < minValidators; iterator.Next() {
+		validator := types.MustUnmarshalValidator(k.cdc, iterator.Value())
+		if validator.MinSelfDelegation.IsPositive() {
+			count++
+		}
+	}
+	return count >= minValidators
+}
+
 // get groups of validators
 
 // GetAllValidators gets the set of all validators with no limits, used during genesis dump
@@ -188,6 +468,11 @@ func (k Keeper) GetLastValidatorPower(ctx sdk.Context, operator sdk.ValAddress)
 	return
 }
 
+// hasLastValidatorPower tells whether the operator is in the last validator set
+func (k Keeper) hasLastValidatorPower(ctx sdk.Context, operator sdk.ValAddress) bool {
+	return ctx.KVStore(k.storeKey).Has(types.GetLastValidatorPowerKey(operator))
+}
+
 // SetLastValidatorPower sets the last validator power
 func (k Keeper) SetLastValidatorPower(ctx sdk.Context, operator sdk.ValAddress, power int64) {
 	store := ctx.KVStore(k.storeKey)
@@ -224,6 +509,80 @@ func (k Keeper) IterateLastValidatorPowers(ctx sdk.Context,
 	}
 }
 
+// GetMedianCommission returns the median commission rate of the bonded validators weighted by their consensus powers
+// in the last block, or zero if there's no bonded validator
+func (k Keeper) GetMedianCommission(ctx sdk.Context) sdk.Dec {
+	var rates []sdk.Dec
+	var powers []int64
+	k.IterateLastValidatorPowers(ctx, func(operator sdk.ValAddress, power int64) (stop bool) {
+		rates = append(rates, k.mustGetValidator(ctx, operator).Commission.Rate)
+		powers = append(powers, power)
+		return false
+	})
+	return weightedMedian(rates, powers)
+}
+
+// weightedMedian returns the value where the cumulative weight of the sorted values reaches the half of the total
+// weight. If the cumulative weight is exactly the half at a value, the median is the mean of it and the next value
+func weightedMedian(values []sdk.Dec, weights []int64) sdk.Dec {
+	type weighted struct {
+		value  sdk.Dec
+		weight int64
+	}
+	var entries []weighted
+	var total int64
+	for i, value := range values {
+		if weights[i] > 0 {
+			entries = append(entries, weighted{value, weights[i]})
+			total += weights[i]
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].value.LT(entries[j].value) })
+
+	var cumulative int64
+	for i, entry := range entries {
+		cumulative += entry.weight
+		switch {
+		case cumulative*2 > total:
+			return entry.value
+		case cumulative*2 == total:
+			return entry.value.Add(entries[i+1].value).QuoInt64(2)
+		}
+	}
+	return sdk.ZeroDec()
+}
+
+// GetValidatorsByRank returns the validators occupying the ranks [startRank, endRank] in the power order, starting
+// from 1. It returns fewer validators or none if the range goes beyond the number of validators in the power index
+func (k Keeper) GetValidatorsByRank(ctx sdk.Context, startRank, endRank int) (validators types.Validators) {
+	validators = types.Validators{}
+	iterator := k.ValidatorsPowerStoreIterator(ctx)
+	defer iterator.Close()
+	for rank := 1; iterator.Valid() && rank <= endRank; iterator.Next() {
+		if rank >= startRank {
+			validators = append(validators, k.mustGetValidator(ctx, sdk.ValAddress(iterator.Value())))
+		}
+		rank++
+	}
+	return
+}
+
+// GetPowerIndexEntries returns the raw entries decoded from the power index in the order of the power store
+// iterator, which is exactly the order to pick the validators for consensus. It's only for diagnosing
+func (k Keeper) GetPowerIndexEntries(ctx sdk.Context) (entries types.PowerIndexEntries) {
+	entries = types.PowerIndexEntries{}
+	iterator := k.ValidatorsPowerStoreIterator(ctx)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		// key is of format prefix || powerbytes || ..., whatever the tie-break is
+		entries = append(entries, types.PowerIndexEntry{
+			Power:    int64(binary.BigEndian.Uint64(iterator.Key()[1:9])),
+			Operator: sdk.ValAddress(iterator.Value()),
+		})
+	}
+	return
+}
+
 //_______________________________________________________________________
 // Validator Queue
 
@@ -286,6 +645,25 @@ func (k Keeper) ValidatorQueueIterator(ctx sdk.Context, endTime time.Time) sdk.I
 	return store.Iterator(types.ValidatorQueueKey, sdk.InclusiveEndBytes(types.GetValidatorQueueTimeKey(endTime)))
 }
 
+// GetUnbondingValidators returns all the validators in the validator queue which are unbonding, in the order of the
+// time they complete unbonding
+func (k Keeper) GetUnbondingValidators(ctx sdk.Context) []types.UnbondingValidator {
+	unbondingValidators := []types.UnbondingValidator{}
+	iterator := sdk.KVStorePrefixIterator(ctx.KVStore(k.storeKey), types.ValidatorQueueKey)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		var timeslice []sdk.ValAddress
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &timeslice)
+		for _, valAddr := range timeslice {
+			if val, found := k.GetValidator(ctx, valAddr); found && val.IsUnbonding() {
+				unbondingValidators = append(unbondingValidators, types.NewUnbondingValidator(val))
+			}
+		}
+	}
+	return unbondingValidators
+}
+
 // UnbondAllMatureValidatorQueue unbonds all the unbonding validators that have finished their unbonding period
 func (k Keeper) UnbondAllMatureValidatorQueue(ctx sdk.Context) {
 	store := ctx.KVStore(k.storeKey)
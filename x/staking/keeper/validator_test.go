@@ -1 +1,740 @@
 package keeper
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/supply"
+	govtypes "github.com/okex/okchain/x/gov/types"
+	"github.com/okex/okchain/x/staking/types"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+func getPowerIndexOrder(ctx sdk.Context, keeper Keeper) (valAddrs []sdk.ValAddress) {
+	iterator := keeper.ValidatorsPowerStoreIterator(ctx)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		valAddrs = append(valAddrs, iterator.Value())
+	}
+	return
+}
+
+func TestPowerTieBreakBySeniority(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, 0)
+	keeper := mkeeper.Keeper
+	vals := createVals(ctx, 3, keeper)
+
+	// equal power, the later the validator is in the address order, the older it is
+	for i, val := range vals {
+		require.Nil(t, keeper.SetValidatorBondHeight(ctx, val.OperatorAddress, int64(100-i*10)))
+		keeper.SetNewValidatorByPowerIndex(ctx, keeper.mustGetValidator(ctx, val.OperatorAddress))
+	}
+
+	// tie-break by address by default
+	require.Equal(t, types.TieBreakByAddress, keeper.GetPowerTieBreak(ctx))
+	byAddress := getPowerIndexOrder(ctx, keeper)
+	require.Equal(t, 3, len(byAddress))
+
+	// the oldest one comes first with the oldest-first rule
+	keeper.SetPowerTieBreak(ctx, types.TieBreakOldestFirst)
+	require.Equal(t, types.TieBreakOldestFirst, keeper.GetPowerTieBreak(ctx))
+	bySeniority := getPowerIndexOrder(ctx, keeper)
+	require.Equal(t, []sdk.ValAddress{vals[2].OperatorAddress, vals[1].OperatorAddress, vals[0].OperatorAddress},
+		bySeniority)
+	_, broken := NonNegativePowerInvariantCustom(keeper)(ctx)
+	require.False(t, broken)
+
+	// refresh the power index when the bond height changes
+	require.Nil(t, keeper.SetValidatorBondHeight(ctx, vals[0].OperatorAddress, 1))
+	require.Equal(t, []sdk.ValAddress{vals[0].OperatorAddress, vals[2].OperatorAddress, vals[1].OperatorAddress},
+		getPowerIndexOrder(ctx, keeper))
+	_, broken = NonNegativePowerInvariantCustom(keeper)(ctx)
+	require.False(t, broken)
+
+	// the higher power still comes first
+	val := keeper.mustGetValidator(ctx, vals[1].OperatorAddress)
+	keeper.DeleteValidatorByPowerIndex(ctx, val)
+	val.DelegatorShares = val.DelegatorShares.Add(sdk.NewDec(10))
+	keeper.SetValidator(ctx, val)
+	keeper.SetValidatorByPowerIndex(ctx, val)
+	require.Equal(t, vals[1].OperatorAddress, getPowerIndexOrder(ctx, keeper)[0])
+
+	// back to the address rule
+	keeper.SetPowerTieBreak(ctx, types.TieBreakByAddress)
+	_, broken = NonNegativePowerInvariantCustom(keeper)(ctx)
+	require.False(t, broken)
+
+	// validator not found
+	require.NotNil(t, keeper.SetValidatorBondHeight(ctx, sdk.ValAddress(addrDels[0]), 1))
+}
+
+func TestValidatorByPowerIndex(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, 0)
+	keeper := mkeeper.Keeper
+	vals := createVals(ctx, 4, keeper)
+	for i, votes := range []int64{10, 20, 10, 0} {
+		val := keeper.mustGetValidator(ctx, vals[i].OperatorAddress)
+		val.DelegatorShares = sdk.NewDec(votes)
+		keeper.SetValidator(ctx, val)
+		keeper.SetValidatorByPowerIndex(ctx, val)
+		vals[i] = val
+	}
+	// the lower address wins the tie
+	require.True(t, bytes.Compare(vals[0].OperatorAddress, vals[2].OperatorAddress) < 0)
+	require.Equal(t, []sdk.ValAddress{vals[1].OperatorAddress, vals[0].OperatorAddress, vals[2].OperatorAddress,
+		vals[3].OperatorAddress}, getPowerIndexOrder(ctx, keeper))
+
+	// the index is maintained by deleting the old key before setting the new power
+	keeper.DeleteValidatorByPowerIndex(ctx, vals[2])
+	vals[2].DelegatorShares = sdk.NewDec(30)
+	keeper.SetValidator(ctx, vals[2])
+	keeper.SetValidatorByPowerIndex(ctx, vals[2])
+	require.Equal(t, []sdk.ValAddress{vals[2].OperatorAddress, vals[1].OperatorAddress, vals[0].OperatorAddress,
+		vals[3].OperatorAddress}, getPowerIndexOrder(ctx, keeper))
+
+	// jailed validators are kept out of the index
+	keeper.DeleteValidatorByPowerIndex(ctx, vals[1])
+	vals[1].Jailed = true
+	keeper.SetValidatorByPowerIndex(ctx, vals[1])
+	require.Equal(t, []sdk.ValAddress{vals[2].OperatorAddress, vals[0].OperatorAddress, vals[3].OperatorAddress},
+		getPowerIndexOrder(ctx, keeper))
+}
+
+func TestGetValidatorByOperatorString(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, 0)
+	keeper := mkeeper.Keeper
+	valAddr := createVals(ctx, 1, keeper)[0].OperatorAddress
+
+	// known
+	validator, err := keeper.GetValidatorByOperatorString(ctx, valAddr.String())
+	require.Nil(t, err)
+	require.Equal(t, valAddr, validator.OperatorAddress)
+
+	// malformed
+	for _, bech32Addr := range []string{"okchainvaloper1", sdk.AccAddress(valAddr).String()} {
+		_, err = keeper.GetValidatorByOperatorString(ctx, bech32Addr)
+		require.Equal(t, types.CodeInvalidAddress, err.Code(), bech32Addr)
+	}
+	_, err = keeper.GetValidatorByOperatorString(ctx, "")
+	require.Equal(t, types.CodeInvalidInput, err.Code())
+
+	// unknown
+	_, err = keeper.GetValidatorByOperatorString(ctx, addrVals[1].String())
+	require.Equal(t, types.CodeInvalidValidator, err.Code())
+}
+
+func TestGetPowerIndexEntries(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, 0)
+	keeper := mkeeper.Keeper
+	require.Equal(t, types.PowerIndexEntries{}, keeper.GetPowerIndexEntries(ctx))
+
+	vals := createVals(ctx, 3, keeper)
+	powerReduction := keeper.GetPowerReduction(ctx)
+	for i, votes := range []int64{20, 30, 20} {
+		val := keeper.mustGetValidator(ctx, vals[i].OperatorAddress)
+		val.DelegatorShares = sdk.NewDec(votes)
+		keeper.SetValidator(ctx, val)
+		require.Nil(t, keeper.SetValidatorBondHeight(ctx, val.OperatorAddress, int64(100-i*10)))
+		vals[i] = keeper.mustGetValidator(ctx, val.OperatorAddress)
+		keeper.SetValidatorByPowerIndex(ctx, vals[i])
+	}
+
+	// the power in the index is the potential consensus power by votes
+	lowPower, highPower := vals[0].PotentialConsensusPowerByVotes(powerReduction),
+		vals[1].PotentialConsensusPowerByVotes(powerReduction)
+	require.True(t, highPower > lowPower)
+	expected := types.PowerIndexEntries{
+		{Power: highPower, Operator: vals[1].OperatorAddress},
+		{Power: lowPower, Operator: vals[0].OperatorAddress},
+		{Power: lowPower, Operator: vals[2].OperatorAddress},
+	}
+	require.True(t, bytes.Compare(vals[0].OperatorAddress, vals[2].OperatorAddress) < 0)
+	require.Equal(t, expected, keeper.GetPowerIndexEntries(ctx))
+
+	// the power is decoded the same way with the bond height in the key
+	keeper.SetPowerTieBreak(ctx, types.TieBreakOldestFirst)
+	expected[1], expected[2] = expected[2], expected[1]
+	require.Equal(t, expected, keeper.GetPowerIndexEntries(ctx))
+
+	// the entries are exactly in the order of the power store iterator
+	order := getPowerIndexOrder(ctx, keeper)
+	for i, entry := range keeper.GetPowerIndexEntries(ctx) {
+		require.Equal(t, order[i], entry.Operator)
+	}
+}
+
+func TestValidatorTenure(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, 0)
+	keeper := mkeeper.Keeper
+	valAddr := createVals(ctx, 1, keeper)[0].OperatorAddress
+	querier := NewQuerier(keeper)
+	queryTenure := func(ctx sdk.Context) (tenure types.ValidatorTenure) {
+		bz := types.ModuleCdc.MustMarshalJSON(types.NewQueryValidatorParams(valAddr))
+		data, err := querier(ctx, []string{types.QueryValidatorTenure}, abci.RequestQuery{Data: bz})
+		require.Nil(t, err)
+		require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &tenure))
+		return
+	}
+	start := time.Unix(1000, 0).UTC()
+	ctx = ctx.WithBlockTime(start)
+
+	// no tenure before the validator is bonded
+	tenure := queryTenure(ctx)
+	require.Equal(t, time.Duration(0), tenure.Tenure)
+	require.Equal(t, valAddr, tenure.OperatorAddress)
+
+	// bond
+	keeper.bondValidator(ctx, keeper.mustGetValidator(ctx, valAddr))
+	require.Equal(t, start, keeper.mustGetValidator(ctx, valAddr).BondedSince)
+	ctx = ctx.WithBlockTime(start.Add(time.Hour))
+	tenure = queryTenure(ctx)
+	require.Equal(t, start, tenure.BondedSince)
+	require.Equal(t, time.Hour, tenure.Tenure)
+
+	// unbond
+	keeper.beginUnbondingValidator(ctx, keeper.mustGetValidator(ctx, valAddr))
+	require.Equal(t, time.Duration(0), queryTenure(ctx.WithBlockTime(start.Add(2*time.Hour))).Tenure)
+	keeper.completeUnbondingValidator(ctx, keeper.mustGetValidator(ctx, valAddr))
+	require.Equal(t, time.Duration(0), queryTenure(ctx.WithBlockTime(start.Add(3*time.Hour))).Tenure)
+
+	// the tenure restarts when the validator rejoins the bonded set
+	rebond := start.Add(4 * time.Hour)
+	keeper.bondValidator(ctx.WithBlockTime(rebond), keeper.mustGetValidator(ctx, valAddr))
+	tenure = queryTenure(ctx.WithBlockTime(rebond.Add(time.Minute)))
+	require.Equal(t, rebond, tenure.BondedSince)
+	require.Equal(t, time.Minute, tenure.Tenure)
+
+	// the tenure survives the genesis export and import
+	validator := keeper.mustGetValidator(ctx, valAddr)
+	require.Equal(t, rebond, validator.Export().Import().BondedSince)
+
+	// validator not found
+	bz := types.ModuleCdc.MustMarshalJSON(types.NewQueryValidatorParams(sdk.ValAddress(addrDels[0])))
+	_, err := querier(ctx, []string{types.QueryValidatorTenure}, abci.RequestQuery{Data: bz})
+	require.NotNil(t, err)
+}
+
+func TestValidatorCommissionCooldown(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, 0)
+	keeper := mkeeper.Keeper
+	valAddr := createVals(ctx, 1, keeper)[0].OperatorAddress
+	querier := NewQuerier(keeper)
+	queryCooldown := func(ctx sdk.Context) (cooldown types.CommissionCooldown) {
+		bz := types.ModuleCdc.MustMarshalJSON(types.NewQueryValidatorParams(valAddr))
+		data, err := querier(ctx, []string{types.QueryCommissionCooldown}, abci.RequestQuery{Data: bz})
+		require.Nil(t, err)
+		require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &cooldown))
+		return
+	}
+
+	// right after a change, the full cooldown remains
+	changedAt := time.Unix(1000, 0).UTC()
+	validator := keeper.mustGetValidator(ctx, valAddr)
+	validator.Commission.UpdateTime = changedAt
+	keeper.SetValidator(ctx, validator)
+	cooldown := queryCooldown(ctx.WithBlockTime(changedAt))
+	require.Equal(t, valAddr, cooldown.OperatorAddress)
+	require.Equal(t, changedAt, cooldown.UpdateTime)
+	require.Equal(t, changedAt.Add(24*time.Hour), cooldown.NextUpdateTime)
+	require.Equal(t, 24*time.Hour, cooldown.Remaining)
+	require.NotNil(t, validator.Commission.ValidateNewRate(sdk.ZeroDec(), changedAt,
+		types.DefaultCommissionChangeWindow))
+
+	// within the window
+	require.Equal(t, time.Hour, queryCooldown(ctx.WithBlockTime(changedAt.Add(23*time.Hour))).Remaining)
+
+	// past the window, the rate is allowed to change right now
+	for _, passed := range []time.Duration{24 * time.Hour, 48 * time.Hour} {
+		cooldown = queryCooldown(ctx.WithBlockTime(changedAt.Add(passed)))
+		require.Equal(t, time.Duration(0), cooldown.Remaining)
+		require.Nil(t, validator.Commission.ValidateNewRate(sdk.ZeroDec(), changedAt.Add(passed),
+			types.DefaultCommissionChangeWindow))
+	}
+
+	// the cooldown follows the change window in the params
+	params := keeper.GetParams(ctx)
+	params.CommissionChangeWindow = time.Hour
+	keeper.SetParams(ctx, params)
+	cooldown = queryCooldown(ctx.WithBlockTime(changedAt.Add(2 * time.Hour)))
+	require.Equal(t, changedAt.Add(time.Hour), cooldown.NextUpdateTime)
+	require.Equal(t, time.Duration(0), cooldown.Remaining)
+
+	// validator not found
+	bz := types.ModuleCdc.MustMarshalJSON(types.NewQueryValidatorParams(sdk.ValAddress(addrDels[0])))
+	_, err := querier(ctx, []string{types.QueryCommissionCooldown}, abci.RequestQuery{Data: bz})
+	require.NotNil(t, err)
+}
+
+func TestZeroPowerValidatorNotBonded(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, 0)
+	keeper := mkeeper.Keeper
+	params := keeper.GetParams(ctx)
+	params.MaxValidators = 3
+	keeper.SetParams(ctx, params)
+	vals := createVals(ctx, 3, keeper)
+
+	// all the validators are within the max validators, while the votes of the last two are below one power unit
+	votes := []sdk.Dec{sdk.NewDec(10), sdk.NewDecWithPrec(5, 1), sdk.ZeroDec()}
+	for i := range vals {
+		vals[i].DelegatorShares = votes[i]
+		keeper.SetValidator(ctx, vals[i])
+		keeper.SetValidatorByConsAddr(ctx, vals[i])
+		keeper.SetValidatorByPowerIndex(ctx, vals[i])
+	}
+	require.Equal(t, int64(0), vals[1].PotentialConsensusPowerByVotes(keeper.ParamsPowerReduction(ctx)))
+
+	updates := keeper.ApplyAndReturnValidatorSetUpdates(ctx)
+	require.Equal(t, 1, len(updates))
+	require.True(t, keeper.mustGetValidator(ctx, vals[0].OperatorAddress).IsBonded())
+	for _, val := range vals[1:] {
+		require.True(t, keeper.mustGetValidator(ctx, val.OperatorAddress).IsUnbonded())
+		require.Equal(t, int64(0), keeper.GetLastValidatorPower(ctx, val.OperatorAddress))
+	}
+}
+
+func TestValidatorSetChangeEvents(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, 0)
+	keeper := mkeeper.Keeper
+	params := keeper.GetParams(ctx)
+	params.MaxValidators = 2
+	keeper.SetParams(ctx, params)
+	vals := createVals(ctx, 3, keeper)
+	setVotes := func(val types.Validator, votes int64) {
+		validator := keeper.mustGetValidator(ctx, val.OperatorAddress)
+		keeper.DeleteValidatorByPowerIndex(ctx, validator)
+		validator.DelegatorShares = sdk.NewDec(votes)
+		keeper.SetValidator(ctx, validator)
+		keeper.SetValidatorByConsAddr(ctx, validator)
+		keeper.SetValidatorByPowerIndex(ctx, validator)
+	}
+	// applies the validator set updates and collects the set changes as validator -> [old power, new power]
+	applyUpdates := func() (bonded, unbonded map[string][2]string) {
+		ctx = ctx.WithEventManager(sdk.NewEventManager())
+		keeper.ApplyAndReturnValidatorSetUpdates(ctx)
+		bonded, unbonded = make(map[string][2]string), make(map[string][2]string)
+		for _, event := range ctx.EventManager().Events() {
+			attrs := make(map[string]string)
+			for _, attr := range event.Attributes {
+				attrs[string(attr.Key)] = string(attr.Value)
+			}
+			change := [2]string{attrs[types.AttributeKeyOldPower], attrs[types.AttributeKeyNewPower]}
+			switch event.Type {
+			case types.EventTypeValidatorBonded:
+				bonded[attrs[types.AttributeKeyValidator]] = change
+			case types.EventTypeValidatorUnbonded:
+				unbonded[attrs[types.AttributeKeyValidator]] = change
+			}
+		}
+		return
+	}
+	setVotes(vals[0], 10)
+	setVotes(vals[1], 5)
+	setVotes(vals[2], 1)
+
+	// the first two validators enter the set
+	bonded, unbonded := applyUpdates()
+	require.Equal(t, map[string][2]string{
+		vals[0].OperatorAddress.String(): {"0", "10"},
+		vals[1].OperatorAddress.String(): {"0", "5"},
+	}, bonded)
+	require.Empty(t, unbonded)
+
+	// no event without any change of the membership, even though the power changes
+	setVotes(vals[0], 12)
+	bonded, unbonded = applyUpdates()
+	require.Empty(t, bonded)
+	require.Empty(t, unbonded)
+
+	// the last validator is promoted and the second one is demoted
+	setVotes(vals[2], 20)
+	setVotes(vals[1], 1)
+	bonded, unbonded = applyUpdates()
+	require.Equal(t, map[string][2]string{vals[2].OperatorAddress.String(): {"0", "20"}}, bonded)
+	require.Equal(t, map[string][2]string{vals[1].OperatorAddress.String(): {"5", "0"}}, unbonded)
+}
+
+func TestValidatorStatusTransition(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, 0)
+	keeper := mkeeper.Keeper
+	valAddr := createVals(ctx, 1, keeper)[0].OperatorAddress
+	requireStatus := func(expected types.ValidatorStatus) {
+		status, found := keeper.GetValidatorStatus(ctx, valAddr)
+		require.True(t, found)
+		require.Equal(t, expected, status)
+	}
+	requireStatus(types.ValidatorStatusUnbonded)
+
+	// an unbonded validator can't begin unbonding, which leaves the power index untouched
+	keeper.SetValidatorByPowerIndex(ctx, keeper.mustGetValidator(ctx, valAddr))
+	require.Panics(t, func() { keeper.beginUnbondingValidator(ctx, keeper.mustGetValidator(ctx, valAddr)) })
+	require.Equal(t, []sdk.ValAddress{valAddr}, getPowerIndexOrder(ctx, keeper))
+	// neither can the correction by governance, which moves the status through the same transitions
+	govAddr := supply.NewModuleAddress(govtypes.ModuleName)
+	require.NotNil(t, keeper.ForceUpdateValidatorState(ctx, govAddr, valAddr, sdk.Unbonding, sdk.ZeroDec()))
+	requireStatus(types.ValidatorStatusUnbonded)
+	keeper.bondValidator(ctx, keeper.mustGetValidator(ctx, valAddr))
+	requireStatus(types.ValidatorStatusBonded)
+	require.NotNil(t, keeper.ForceUpdateValidatorState(ctx, govAddr, valAddr, sdk.Unbonded, sdk.ZeroDec()))
+	requireStatus(types.ValidatorStatusBonded)
+
+	// a bonded validator can be neither bonded again nor unbonded without unbonding first
+	require.Panics(t, func() { keeper.bondValidator(ctx, keeper.mustGetValidator(ctx, valAddr)) })
+	require.Panics(t, func() { keeper.completeUnbondingValidator(ctx, keeper.mustGetValidator(ctx, valAddr)) })
+	keeper.beginUnbondingValidator(ctx, keeper.mustGetValidator(ctx, valAddr))
+	requireStatus(types.ValidatorStatusUnbonding)
+	keeper.completeUnbondingValidator(ctx, keeper.mustGetValidator(ctx, valAddr))
+	requireStatus(types.ValidatorStatusUnbonded)
+
+	keeper.jailValidator(ctx, keeper.mustGetValidator(ctx, valAddr))
+	requireStatus(types.ValidatorStatusJailed)
+
+	// validator not found
+	_, found := keeper.GetValidatorStatus(ctx, addrVals[1])
+	require.False(t, found)
+}
+
+func TestConsensusPower(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, 0)
+	keeper := mkeeper.Keeper
+	validator := types.NewValidator(addrVals[0], PKs[0], types.Description{})
+	validator.Status = sdk.Bonded
+
+	// one power unit is one token with the default power reduction
+	unit := types.DefaultPowerReduction
+	tests := []struct {
+		votes          sdk.Dec
+		powerReduction sdk.Int
+		power          int64
+	}{
+		{sdk.ZeroDec(), unit, 0},
+		{sdk.NewDec(100), unit, 100},
+		{sdk.NewDec(100), unit.MulRaw(10), 10},
+		{sdk.NewDec(105), unit.MulRaw(10), 10},
+		{sdk.NewDec(100), unit.QuoRaw(2), 200},
+		// below one power unit
+		{sdk.NewDec(9), unit.MulRaw(10), 0},
+		{sdk.NewDecWithPrec(5, 1), unit, 0},
+	}
+	for i, tc := range tests {
+		keeper.SetPowerReduction(ctx, tc.powerReduction)
+		validator.DelegatorShares = tc.votes
+		keeper.SetValidator(ctx, validator)
+		// the validator handed out to the other modules reports the same power
+		validatorI := keeper.Validator(ctx, validator.OperatorAddress)
+		require.Equal(t, tc.power, keeper.ConsensusPower(ctx, validatorI), "test case %d", i)
+		require.Equal(t, tc.power, validatorI.GetConsensusPower(), "test case %d", i)
+	}
+
+	// no power if the validator isn't bonded
+	validator.Status = sdk.Unbonding
+	keeper.SetValidator(ctx, validator)
+	require.Equal(t, int64(0), keeper.ConsensusPower(ctx, keeper.Validator(ctx, validator.OperatorAddress)))
+}
+
+func TestValidateMsgCreateValidator(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, 0)
+	keeper := mkeeper.Keeper
+	msd := keeper.ParamsMinSelfDelegationLimited(ctx)
+	existing := types.NewValidator(addrVals[0], PKs[0], types.NewDescription("taken", "", "", ""))
+	keeper.SetValidator(ctx, existing)
+	keeper.SetValidatorByConsAddr(ctx, existing)
+	keeper.SetValidatorByMoniker(ctx, existing)
+
+	// valid
+	msg := NewTestMsgCreateValidator(addrVals[1], PKs[1], msd)
+	require.Nil(t, keeper.ValidateMsgCreateValidator(ctx, msg))
+
+	// duplicate operator
+	msg = NewTestMsgCreateValidator(addrVals[0], PKs[1], msd)
+	require.Equal(t, types.CodeInvalidValidator, keeper.ValidateMsgCreateValidator(ctx, msg).Code())
+
+	// duplicate consensus pubkey
+	msg = NewTestMsgCreateValidator(addrVals[1], PKs[0], msd)
+	require.Equal(t, types.CodeInvalidValidator, keeper.ValidateMsgCreateValidator(ctx, msg).Code())
+
+	// denom mismatch
+	msg = NewTestMsgCreateValidator(addrVals[1], PKs[1], msd)
+	msg.MinSelfDelegation.Denom = "xxb"
+	require.NotNil(t, keeper.ValidateMsgCreateValidator(ctx, msg))
+
+	// min self delegation below the limit
+	msg = NewTestMsgCreateValidator(addrVals[1], PKs[1], msd.Sub(sdk.NewDecWithPrec(1, 8)))
+	require.NotNil(t, keeper.ValidateMsgCreateValidator(ctx, msg))
+
+	// description too long
+	msg = NewTestMsgCreateValidator(addrVals[1], PKs[1], msd)
+	msg.Description.Moniker = strings.Repeat("m", types.MaxMonikerLength+1)
+	require.NotNil(t, keeper.ValidateMsgCreateValidator(ctx, msg))
+
+	// moniker taken, only when the unique moniker is enforced
+	msg = NewTestMsgCreateValidator(addrVals[1], PKs[1], msd)
+	msg.Description.Moniker = "taken"
+	require.Nil(t, keeper.ValidateMsgCreateValidator(ctx, msg))
+	params := keeper.GetParams(ctx)
+	params.EnforceUniqueMoniker = true
+	keeper.SetParams(ctx, params)
+	require.NotNil(t, keeper.ValidateMsgCreateValidator(ctx, msg))
+
+	// pubkey type not supported by the consensus params
+	msg = NewTestMsgCreateValidator(addrVals[1], PKs[1], msd)
+	ctx = ctx.WithConsensusParams(&abci.ConsensusParams{Validator: &abci.ValidatorParams{PubKeyTypes: []string{"sr25519"}}})
+	require.NotNil(t, keeper.ValidateMsgCreateValidator(ctx, msg))
+
+	// nothing is written
+	_, found := keeper.GetValidator(ctx, addrVals[1])
+	require.False(t, found)
+}
+
+func TestWeightedMedian(t *testing.T) {
+	dec := func(s string) sdk.Dec { return sdk.MustNewDecFromStr(s) }
+	tests := []struct {
+		values  []sdk.Dec
+		weights []int64
+		median  sdk.Dec
+	}{
+		{nil, nil, sdk.ZeroDec()},
+		{[]sdk.Dec{dec("0.1")}, []int64{1}, dec("0.1")},
+		// odd number of equal weights
+		{[]sdk.Dec{dec("0.3"), dec("0.1"), dec("0.2")}, []int64{1, 1, 1}, dec("0.2")},
+		// even number of equal weights, the mean of the middle two
+		{[]sdk.Dec{dec("0.2"), dec("0.1")}, []int64{1, 1}, dec("0.15")},
+		{[]sdk.Dec{dec("0.05"), dec("0.5")}, []int64{3, 3}, dec("0.275")},
+		// a heavy one dominates
+		{[]sdk.Dec{dec("0.1"), dec("0.2"), dec("0.3")}, []int64{5, 1, 1}, dec("0.1")},
+		{[]sdk.Dec{dec("0.1"), dec("0.2"), dec("0.3")}, []int64{1, 1, 5}, dec("0.3")},
+		// the cumulative weight is exactly the half at 0.2: (1+1)*2 == 4
+		{[]sdk.Dec{dec("0.3"), dec("0.1"), dec("0.2")}, []int64{2, 1, 1}, dec("0.25")},
+		// 0.1: 2, 0.2: 5, 0.4: 3, the half 5 is crossed at 0.2
+		{[]sdk.Dec{dec("0.4"), dec("0.2"), dec("0.1")}, []int64{3, 5, 2}, dec("0.2")},
+		// no weight, no say
+		{[]sdk.Dec{dec("0.1"), dec("0.9")}, []int64{0, 1}, dec("0.9")},
+		{[]sdk.Dec{dec("0.1")}, []int64{0}, sdk.ZeroDec()},
+	}
+	for i, tc := range tests {
+		require.True(t, tc.median.Equal(weightedMedian(tc.values, tc.weights)), "test case %d: %s", i,
+			weightedMedian(tc.values, tc.weights))
+	}
+}
+
+func TestQueryMedianCommission(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, 0)
+	keeper := mkeeper.Keeper
+	querier := NewQuerier(keeper)
+	queryMedian := func() (median sdk.Dec) {
+		data, err := querier(ctx, []string{types.QueryMedianCommission}, abci.RequestQuery{})
+		require.Nil(t, err)
+		require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &median))
+		return
+	}
+
+	// no bonded validator
+	require.True(t, queryMedian().IsZero())
+
+	vals := createVals(ctx, 4, keeper)
+	for i, rate := range []string{"0.2", "0.1", "0.4", "0.3"} {
+		val := keeper.mustGetValidator(ctx, vals[i].OperatorAddress)
+		val.Commission.Rate = sdk.MustNewDecFromStr(rate)
+		keeper.SetValidator(ctx, val)
+	}
+	// 0.1: 10, 0.2: 10, 0.3: 20, and the unbonded 0.4 isn't counted
+	keeper.SetLastValidatorPower(ctx, vals[0].OperatorAddress, 10)
+	keeper.SetLastValidatorPower(ctx, vals[1].OperatorAddress, 10)
+	keeper.SetLastValidatorPower(ctx, vals[3].OperatorAddress, 20)
+	require.True(t, sdk.MustNewDecFromStr("0.25").Equal(queryMedian()))
+
+	keeper.SetLastValidatorPower(ctx, vals[3].OperatorAddress, 21)
+	require.True(t, sdk.MustNewDecFromStr("0.3").Equal(queryMedian()))
+}
+
+func TestForceUpdateValidatorState(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mkeeper.Keeper
+	epoch := int64(keeper.GetEpoch(ctx))
+	vals := createVals(ctx, 2, keeper)
+	for i := range vals {
+		keeper.SetValidatorByConsAddr(ctx, vals[i])
+		keeper.SetValidatorByPowerIndex(ctx, vals[i])
+	}
+	votes, err := keeper.VoteValidators(ctx, addrDels[0], getVals(ctx, vals[:1], keeper, t),
+		keeper.ParamsPowerReduction(ctx).MulRaw(10).ToDec())
+	require.Nil(t, err)
+	govAddr := supply.NewModuleAddress(govtypes.ModuleName)
+	corruptedShares := votes.MulInt64(2)
+
+	// only the gov module account is authorized
+	for _, authority := range []sdk.AccAddress{nil, addrDels[0], supply.NewModuleAddress(types.BondedPoolName)} {
+		err = keeper.ForceUpdateValidatorState(ctx, authority, vals[1].OperatorAddress, sdk.Bonded, corruptedShares)
+		require.NotNil(t, err)
+		require.Equal(t, types.CodeUnauthorized, err.Code())
+	}
+	require.True(t, keeper.mustGetValidator(ctx, vals[1].OperatorAddress).DelegatorShares.IsZero())
+
+	// invalid corrections
+	require.NotNil(t, keeper.ForceUpdateValidatorState(ctx, govAddr, addrVals[2], sdk.Bonded, corruptedShares))
+	require.NotNil(t, keeper.ForceUpdateValidatorState(ctx, govAddr, vals[1].OperatorAddress, sdk.BondStatus(0x10),
+		corruptedShares))
+	require.NotNil(t, keeper.ForceUpdateValidatorState(ctx, govAddr, vals[1].OperatorAddress, sdk.Bonded,
+		sdk.NewDec(-1)))
+	// an unbonded validator can't begin unbonding
+	require.NotNil(t, keeper.ForceUpdateValidatorState(ctx, govAddr, vals[1].OperatorAddress, sdk.Unbonding,
+		corruptedShares))
+
+	// the correction refreshes the power index and is audited by an event
+	oldPowerIndexKey := keeper.getValidatorPowerIndexKey(ctx, keeper.mustGetValidator(ctx, vals[1].OperatorAddress))
+	require.Equal(t, vals[0].OperatorAddress, getPowerIndexOrder(ctx, keeper)[0])
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	require.Nil(t, keeper.ForceUpdateValidatorState(ctx, govAddr, vals[1].OperatorAddress, sdk.Bonded,
+		corruptedShares))
+	validator := keeper.mustGetValidator(ctx, vals[1].OperatorAddress)
+	require.True(t, validator.DelegatorShares.Equal(corruptedShares))
+	require.False(t, ctx.KVStore(keeper.storeKey).Has(oldPowerIndexKey))
+	require.Equal(t, []sdk.ValAddress{vals[1].OperatorAddress, vals[0].OperatorAddress},
+		getPowerIndexOrder(ctx, keeper))
+	events := ctx.EventManager().Events()
+	require.Equal(t, 1, len(events))
+	require.Equal(t, types.EventTypeCorrectValidator, events[0].Type)
+	attributes := make(map[string]string)
+	for _, attribute := range events[0].Attributes {
+		attributes[string(attribute.Key)] = string(attribute.Value)
+	}
+	require.Equal(t, govAddr.String(), attributes[types.AttributeKeyAuthority])
+	require.Equal(t, sdk.Unbonded.String(), attributes[types.AttributeKeyOldStatus])
+	require.Equal(t, sdk.Bonded.String(), attributes[types.AttributeKeyNewStatus])
+	require.Equal(t, corruptedShares.String(), attributes[types.AttributeKeyNewShares])
+
+	// the validator corrected to bonded is bonded by the recompute of the validator set
+	require.Equal(t, sdk.Unbonded, validator.Status)
+	require.True(t, keeper.IsValidatorSetRecomputed(ctx))
+	ctx = ctx.WithBlockHeight(epoch)
+	updates := keeper.ProcessEpochEnd(ctx)
+	validator = keeper.mustGetValidator(ctx, vals[1].OperatorAddress)
+	require.Equal(t, sdk.Bonded, validator.Status)
+	require.Contains(t, updates, validator.ABCIValidatorUpdateByVotes(keeper.GetPowerReduction(ctx)))
+	require.True(t, keeper.hasLastValidatorPower(ctx, vals[1].OperatorAddress))
+
+	// the bonded validator corrected to unbonding begins unbonding at once, though its votes still rank within the set
+	require.Nil(t, keeper.ForceUpdateValidatorState(ctx, govAddr, vals[1].OperatorAddress, sdk.Unbonding,
+		corruptedShares))
+	validator = keeper.mustGetValidator(ctx, vals[1].OperatorAddress)
+	require.Equal(t, sdk.Unbonding, validator.Status)
+	require.Equal(t, ctx.BlockHeader().Time.Add(keeper.UnbondingTime(ctx)), validator.UnbondingCompletionTime)
+	require.Equal(t, []sdk.ValAddress{vals[1].OperatorAddress},
+		keeper.GetValidatorQueueTimeSlice(ctx, validator.UnbondingCompletionTime))
+	// it can't complete unbonding before it leaves the last validator set
+	require.NotNil(t, keeper.ForceUpdateValidatorState(ctx, govAddr, vals[1].OperatorAddress, sdk.Unbonded,
+		corruptedShares))
+	// and leaves the set with a zero power update by the recompute
+	ctx = ctx.WithBlockHeight(epoch * 2)
+	require.Equal(t, []abci.ValidatorUpdate{validator.ABCIValidatorUpdateZero()}, keeper.ProcessEpochEnd(ctx))
+	require.False(t, keeper.hasLastValidatorPower(ctx, vals[1].OperatorAddress))
+	require.Equal(t, sdk.Unbonding, keeper.mustGetValidator(ctx, vals[1].OperatorAddress).Status)
+
+	// the unbonding validator completes ahead of the queue
+	require.Nil(t, keeper.ForceUpdateValidatorState(ctx, govAddr, vals[1].OperatorAddress, sdk.Unbonded,
+		sdk.ZeroDec()))
+	require.Equal(t, sdk.Unbonded, keeper.mustGetValidator(ctx, vals[1].OperatorAddress).Status)
+	require.Empty(t, keeper.GetValidatorQueueTimeSlice(ctx, validator.UnbondingCompletionTime))
+	ctx = ctx.WithBlockHeight(epoch * 3)
+	require.Empty(t, keeper.ProcessEpochEnd(ctx))
+	require.Equal(t, sdk.Unbonded, keeper.mustGetValidator(ctx, vals[1].OperatorAddress).Status)
+
+	// the validator which doesn't rank within the set can't be corrected to bonded
+	require.NotNil(t, keeper.ForceUpdateValidatorState(ctx, govAddr, vals[1].OperatorAddress, sdk.Bonded,
+		sdk.ZeroDec()))
+}
+
+func TestProcessEpochEnd(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mkeeper.Keeper
+	epoch := int64(keeper.GetEpoch(ctx))
+	vals := createVals(ctx, 3, keeper)
+	for i := range vals {
+		keeper.SetValidatorByConsAddr(ctx, vals[i])
+		keeper.SetValidatorByPowerIndex(ctx, vals[i])
+		_, err := keeper.VoteValidators(ctx, addrDels[i], getVals(ctx, vals[i:i+1], keeper, t),
+			keeper.ParamsPowerReduction(ctx).MulRaw(int64(10*(i+1))).ToDec())
+		require.Nil(t, err)
+	}
+
+	// the first epoch bonds all the validators voted
+	ctx = ctx.WithBlockHeight(epoch)
+	require.True(t, keeper.IsEndOfEpoch(ctx))
+	require.Equal(t, 3, len(keeper.ProcessEpochEnd(ctx)))
+	require.Equal(t, uint64(1), keeper.CurrentEpochNumber(ctx))
+	require.Equal(t, epoch, keeper.GetTheEndOfLastEpoch(ctx))
+	require.Equal(t, 1, len(keeper.GetBondedHistory(ctx, 0, 1)))
+
+	// nothing to update without any change
+	ctx = ctx.WithBlockHeight(epoch * 2)
+	require.Empty(t, keeper.ProcessEpochEnd(ctx))
+	require.Equal(t, uint64(2), keeper.CurrentEpochNumber(ctx))
+
+	// the changes staged within the epoch are completed
+	params := keeper.GetParams(ctx)
+	params.Epoch = uint16(epoch * 2)
+	keeper.SetParams(ctx, params)
+	newUnbondingTime := keeper.UnbondingTime(ctx) * 2
+	require.Nil(t, keeper.SetUnbondingTime(ctx, newUnbondingTime))
+	require.Nil(t, keeper.SetMaxValidators(ctx, 2, false))
+	keeper.AppendAbandonedValidatorAddrs(ctx, vals[2].ConsAddress())
+	require.Equal(t, epoch, int64(keeper.GetEpoch(ctx)))
+
+	ctx = ctx.WithBlockHeight(epoch * 3)
+	updates := keeper.ProcessEpochEnd(ctx)
+	require.Equal(t, []abci.ValidatorUpdate{vals[0].ABCIValidatorUpdateZero()}, updates)
+	require.Equal(t, uint16(epoch*2), keeper.GetEpoch(ctx))
+	require.Equal(t, newUnbondingTime, keeper.UnbondingTime(ctx))
+	require.Equal(t, uint16(2), keeper.MaxValidators(ctx))
+	_, found := keeper.GetPendingUnbondingTime(ctx)
+	require.False(t, found)
+	_, found = keeper.GetPendingMaxValidators(ctx)
+	require.False(t, found)
+	require.False(t, keeper.IsKickedOut(ctx))
+	require.Equal(t, uint64(3), keeper.CurrentEpochNumber(ctx))
+	require.Equal(t, epoch*3, keeper.GetTheEndOfLastEpoch(ctx))
+	require.Equal(t, 3, len(keeper.GetBondedHistory(ctx, 0, 3)))
+
+	// the next boundary follows the new epoch
+	require.False(t, keeper.IsEndOfEpoch(ctx.WithBlockHeight(epoch*4)))
+	require.True(t, keeper.IsEndOfEpoch(ctx.WithBlockHeight(epoch*5)))
+}
+
+func TestTotalVotes(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mkeeper.Keeper
+	sumVotes := func() sdk.Dec {
+		totalVotes := sdk.ZeroDec()
+		for _, validator := range keeper.GetAllValidators(ctx) {
+			totalVotes = totalVotes.Add(validator.DelegatorShares)
+		}
+		return totalVotes
+	}
+	vals := createVals(ctx, 3, keeper)
+	require.True(t, keeper.GetTotalVotes(ctx).IsZero())
+
+	// the total votes follow the votes on the validators
+	tokens := keeper.ParamsPowerReduction(ctx).MulRaw(10).ToDec()
+	votes, err := keeper.VoteValidators(ctx, addrDels[0], getVals(ctx, vals, keeper, t), tokens)
+	require.Nil(t, err)
+	require.True(t, votes.MulInt64(3).Equal(keeper.GetTotalVotes(ctx)))
+	_, err = keeper.VoteValidators(ctx, addrDels[1], getVals(ctx, vals[:1], keeper, t), tokens)
+	require.Nil(t, err)
+	require.True(t, sumVotes().Equal(keeper.GetTotalVotes(ctx)))
+
+	keeper.WithdrawLastVotes(ctx, addrDels[0], getVals(ctx, vals, keeper, t), votes)
+	require.True(t, sumVotes().Equal(keeper.GetTotalVotes(ctx)))
+	require.True(t, keeper.GetTotalVotes(ctx).Equal(
+		keeper.mustGetValidator(ctx, vals[0].OperatorAddress).DelegatorShares))
+
+	// the votes of a removed validator leave the total
+	validator := keeper.mustGetValidator(ctx, vals[0].OperatorAddress)
+	validator.DelegatorShares = sdk.NewDec(5)
+	keeper.SetValidator(ctx, validator)
+	require.True(t, sdk.NewDec(5).Equal(keeper.GetTotalVotes(ctx)))
+	keeper.RemoveValidator(ctx, vals[0].OperatorAddress)
+	require.True(t, keeper.GetTotalVotes(ctx).IsZero())
+}
@@ -1,6 +1,7 @@
 package keeper
 
 import (
+	"github.com/cosmos/cosmos-sdk/client"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/okex/okchain/x/staking/types"
 )
@@ -19,6 +20,11 @@ func (k Keeper) GetVote(ctx sdk.Context, voterAddr sdk.AccAddress, valAddr sdk.V
 	return votes, true
 }
 
+// HasDelegation tells whether the voter is voting to the validator, without unmarshaling the votes
+func (k Keeper) HasDelegation(ctx sdk.Context, voterAddr sdk.AccAddress, valAddr sdk.ValAddress) bool {
+	return ctx.KVStore(k.storeKey).Has(types.GetVoteKey(valAddr, voterAddr))
+}
+
 // SetVote sets votes to store
 func (k Keeper) SetVote(ctx sdk.Context, voterAddr sdk.AccAddress, valAddr sdk.ValAddress, votes types.Votes) {
 	key := types.GetVoteKey(valAddr, voterAddr)
@@ -53,6 +59,85 @@ func (k Keeper) GetValidatorVotes(ctx sdk.Context, valAddr sdk.ValAddress) types
 	return voteResps
 }
 
+// GetValidatorDelegations returns a page of the votes made to a specific validator in the order of the voter
+// addresses, together with the number of all the voters
+func (k Keeper) GetValidatorDelegations(ctx sdk.Context, valAddr sdk.ValAddress, page, limit int) (
+	voteResps types.VoteResponses, total int) {
+	total = int(k.GetValidatorVoterCount(ctx, valAddr))
+	start, end := client.Paginate(total, page, limit, types.DefaultValidatorDelegationsLimit)
+	if start < 0 || end < 0 {
+		return types.VoteResponses{}, total
+	}
+
+	voteResps = make(types.VoteResponses, 0, end-start)
+	iterator := sdk.KVStorePrefixIterator(ctx.KVStore(k.storeKey), types.GetVotesToValidatorsKey(valAddr))
+	defer iterator.Close()
+	for i := 0; iterator.Valid() && i < end; iterator.Next() {
+		if i >= start {
+			voterAddr := sdk.AccAddress(iterator.Key()[1+sdk.AddrLen:])
+			votes := types.MustUnmarshalVote(k.cdc, iterator.Value())
+			voteResps = append(voteResps, types.NewVoteResponse(voterAddr, votes))
+		}
+		i++
+	}
+	return voteResps, total
+}
+
+// GetValidatorVoterCount returns the number of the delegators voting to a specific validator
+func (k Keeper) GetValidatorVoterCount(ctx sdk.Context, valAddr sdk.ValAddress) (count uint64) {
+	iterator := sdk.KVStorePrefixIterator(ctx.KVStore(k.storeKey), types.GetVotesToValidatorsKey(valAddr))
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		count++
+	}
+	return
+}
+
+// GetValidatorTotalShares sums up the min self delegation and all the votes recorded on a specific validator,
+// which is supposed to be equal to the DelegatorShares of the validator
+func (k Keeper) GetValidatorTotalShares(ctx sdk.Context, valAddr sdk.ValAddress) sdk.Dec {
+	validator, found := k.GetValidator(ctx, valAddr)
+	if !found {
+		return sdk.ZeroDec()
+	}
+
+	totalShares := validator.MinSelfDelegation
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.GetVotesToValidatorsKey(valAddr))
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		totalShares = totalShares.Add(types.MustUnmarshalVote(k.cdc, iterator.Value()))
+	}
+
+	return totalShares
+}
+
+// RefreshValidatorTokens reconciles a validator adjusted by other modules with the votes on it. The DelegatorShares
+// are recomputed from the min self delegation and the votes, and the tokens recorded on the validator are dropped
+// because the voted tokens are kept in the bonded pool on behalf of the delegators
+func (k Keeper) RefreshValidatorTokens(ctx sdk.Context, valAddr sdk.ValAddress) (types.Validator, sdk.Error) {
+	validator, found := k.GetValidator(ctx, valAddr)
+	if !found {
+		return types.Validator{}, types.ErrNoValidatorFound(k.Codespace(), valAddr.String())
+	}
+
+	totalShares := k.GetValidatorTotalShares(ctx, valAddr)
+	if validator.DelegatorShares.Equal(totalShares) && validator.Tokens.IsZero() {
+		return validator, nil
+	}
+
+	// ATTENTION:update DelegatorShares must go after DeleteValidatorByPowerIndex
+	k.BeforeValidatorModified(ctx, valAddr)
+	k.DeleteValidatorByPowerIndex(ctx, validator)
+	validator.DelegatorShares = totalShares
+	validator.Tokens = sdk.ZeroInt()
+	k.SetValidator(ctx, validator)
+	k.SetValidatorByPowerIndex(ctx, validator)
+	return validator, nil
+}
+
 // IterateVotes iterates through all of the votes from store
 func (k Keeper) IterateVotes(ctx sdk.Context, fn func(index int64, voterAddr sdk.AccAddress, valAddr sdk.ValAddress,
 	votes types.Votes) (stop bool)) {
@@ -6,6 +6,7 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/okex/okchain/x/staking/types"
 	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
 )
 
 func TestVote(t *testing.T) {
@@ -43,3 +44,175 @@ func TestVote(t *testing.T) {
 	_, found = keeper.GetVote(ctx, addrDels[0], addrVals[0])
 	require.False(t, found)
 }
+
+func TestHasDelegation(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 0)
+	keeper.SetVote(ctx, addrDels[0], addrVals[0], sdk.OneDec())
+
+	// existing and nonexistent delegations
+	require.True(t, keeper.HasDelegation(ctx, addrDels[0], addrVals[0]))
+	require.False(t, keeper.HasDelegation(ctx, addrDels[0], addrVals[1]))
+	require.False(t, keeper.HasDelegation(ctx, addrDels[1], addrVals[0]))
+
+	// gone once the vote is deleted
+	keeper.DeleteVote(ctx, addrVals[0], addrDels[0])
+	require.False(t, keeper.HasDelegation(ctx, addrDels[0], addrVals[0]))
+}
+
+func TestGetValidatorTotalShares(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, 0)
+	keeper := mkeeper.Keeper
+	vals := createVals(ctx, 1, keeper)
+	valAddr := vals[0].OperatorAddress
+	keeper.SetValidatorByConsAddr(ctx, vals[0])
+
+	require.True(t, keeper.GetValidatorTotalShares(ctx, addrVals[1]).IsZero())
+	// min self delegation is counted in
+	require.True(t, keeper.GetValidatorTotalShares(ctx, valAddr).Equal(vals[0].MinSelfDelegation))
+	vals[0].MinSelfDelegation = sdk.ZeroDec()
+	keeper.SetValidator(ctx, vals[0])
+	require.True(t, keeper.GetValidatorTotalShares(ctx, valAddr).IsZero())
+
+	// votes are weighted by time, so the tokens per share drop below 1
+	tokens := sdk.NewDec(10000)
+	votes, err := keeper.VoteValidators(ctx, addrDels[0], vals, tokens)
+	require.Nil(t, err)
+	require.True(t, votes.GT(tokens))
+	_, err = keeper.VoteValidators(ctx, addrDels[1], getVals(ctx, vals, keeper, t), tokens)
+	require.Nil(t, err)
+
+	validator, found := keeper.GetValidator(ctx, valAddr)
+	require.True(t, found)
+	require.True(t, tokens.MulInt64(2).Quo(validator.DelegatorShares).LT(sdk.OneDec()))
+	require.True(t, keeper.GetValidatorTotalShares(ctx, valAddr).Equal(validator.DelegatorShares))
+
+	// still reconciled after the validator is jailed
+	keeper.Jail(ctx, validator.ConsAddress())
+	validator, found = keeper.GetValidator(ctx, valAddr)
+	require.True(t, found)
+	require.True(t, validator.Jailed)
+	require.True(t, keeper.GetValidatorTotalShares(ctx, valAddr).Equal(validator.DelegatorShares))
+	_, broken := DelegatorVotesInvariant(keeper)(ctx)
+	require.False(t, broken)
+
+	// query the reconciliation
+	querior := NewQuerier(keeper)
+	bz, _ := types.ModuleCdc.MarshalJSON(types.NewQueryValidatorParams(valAddr))
+	data, qErr := querior(ctx, []string{types.QueryValidatorShares}, abci.RequestQuery{Data: bz})
+	require.Nil(t, qErr)
+	var sharesResp types.ValidatorSharesResponse
+	require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &sharesResp))
+	require.True(t, sharesResp.IsReconciled(), sharesResp.String())
+
+	// drift is detected by both the query and the invariant
+	validator.DelegatorShares = validator.DelegatorShares.Add(sdk.OneDec())
+	keeper.SetValidator(ctx, validator)
+	data, qErr = querior(ctx, []string{types.QueryValidatorShares}, abci.RequestQuery{Data: bz})
+	require.Nil(t, qErr)
+	require.NoError(t, types.ModuleCdc.UnmarshalJSON(data, &sharesResp))
+	require.True(t, sharesResp.Drift.Equal(sdk.OneDec()), sharesResp.String())
+	_, broken = DelegatorVotesInvariant(keeper)(ctx)
+	require.True(t, broken)
+
+	// unknown validator
+	bz, _ = types.ModuleCdc.MarshalJSON(types.NewQueryValidatorParams(addrVals[1]))
+	_, qErr = querior(ctx, []string{types.QueryValidatorShares}, abci.RequestQuery{Data: bz})
+	require.NotNil(t, qErr)
+}
+
+func TestRefreshValidatorTokens(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mkeeper.Keeper
+	vals := createVals(ctx, 2, keeper)
+	for i := range vals {
+		vals[i].MinSelfDelegation = sdk.ZeroDec()
+		keeper.SetValidator(ctx, vals[i])
+	}
+	valAddr := vals[0].OperatorAddress
+	_, err := keeper.VoteValidators(ctx, addrDels[0], vals, sdk.NewDec(10000))
+	require.Nil(t, err)
+	expected, found := keeper.GetValidator(ctx, valAddr)
+	require.True(t, found)
+
+	// nothing to refresh
+	validator, err := keeper.RefreshValidatorTokens(ctx, valAddr)
+	require.Nil(t, err)
+	require.True(t, expected.TestEquivalent(validator))
+	_, broken := ValidatorTokensInvariant(keeper)(ctx)
+	require.False(t, broken)
+
+	// another module grants tokens to the validator and messes up its shares
+	drifted := expected
+	drifted.Tokens = sdk.NewInt(100)
+	drifted.DelegatorShares = drifted.DelegatorShares.Add(sdk.NewDec(100))
+	keeper.SetValidator(ctx, drifted)
+	_, broken = ValidatorTokensInvariant(keeper)(ctx)
+	require.True(t, broken)
+	_, broken = DelegatorVotesInvariant(keeper)(ctx)
+	require.True(t, broken)
+
+	// reconciled with the votes
+	validator, err = keeper.RefreshValidatorTokens(ctx, valAddr)
+	require.Nil(t, err)
+	require.True(t, validator.Tokens.IsZero())
+	require.True(t, validator.DelegatorShares.Equal(expected.DelegatorShares))
+	_, broken = ValidatorTokensInvariant(keeper)(ctx)
+	require.False(t, broken)
+	_, broken = DelegatorVotesInvariant(keeper)(ctx)
+	require.False(t, broken)
+	_, broken = NonNegativePowerInvariantCustom(keeper)(ctx)
+	require.False(t, broken)
+
+	// unknown validator
+	_, err = keeper.RefreshValidatorTokens(ctx, addrVals[2])
+	require.NotNil(t, err)
+}
+
+func TestRemoveValidatorTokensAndShares(t *testing.T) {
+	ctx, _, mkeeper := CreateTestInput(t, false, SufficientInitBalance)
+	keeper := mkeeper.Keeper
+	store := ctx.KVStore(keeper.storeKey)
+	validator := createVals(ctx, 1, keeper)[0]
+	// the power index is only for the latest state of the validator
+	requirePowerIndexed := func(validator types.Validator) {
+		require.True(t, store.Has(keeper.getValidatorPowerIndexKey(ctx, validator)))
+		iterator := sdk.KVStorePrefixIterator(store, types.ValidatorsByPowerIndexKey)
+		defer iterator.Close()
+		count := 0
+		for ; iterator.Valid(); iterator.Next() {
+			count++
+		}
+		require.Equal(t, 1, count)
+	}
+
+	// no tokens on a validator voted only
+	validator.DelegatorShares = sdk.NewDec(200)
+	keeper.SetValidator(ctx, validator)
+	keeper.SetValidatorByPowerIndex(ctx, validator)
+	validator, removed := keeper.RemoveValidatorTokensAndShares(ctx, validator, sdk.NewDec(50))
+	require.True(t, removed.IsZero())
+	require.True(t, validator.Tokens.IsZero())
+	require.True(t, validator.DelegatorShares.Equal(sdk.NewDec(150)))
+	require.True(t, validator.TestEquivalent(keeper.mustGetValidator(ctx, validator.OperatorAddress)))
+	requirePowerIndexed(validator)
+
+	// partial removal from a validator adjusted by other modules keeps the rate between the tokens and the shares
+	validator.Tokens = sdk.NewInt(100)
+	keeper.SetValidator(ctx, validator)
+	validator, removed = keeper.RemoveValidatorTokensAndShares(ctx, validator, sdk.NewDec(30))
+	require.Equal(t, sdk.NewInt(20), removed)
+	require.Equal(t, sdk.NewInt(80), validator.Tokens)
+	require.True(t, validator.DelegatorShares.Equal(sdk.NewDec(120)))
+	require.True(t, validator.TokensFromShares(validator.DelegatorShares).Equal(validator.Tokens.ToDec()))
+	requirePowerIndexed(validator)
+
+	// full removal leaves neither tokens nor shares
+	validator, removed = keeper.RemoveValidatorTokensAndShares(ctx, validator, validator.DelegatorShares)
+	require.Equal(t, sdk.NewInt(80), removed)
+	require.True(t, validator.Tokens.IsZero())
+	require.True(t, validator.DelegatorShares.IsZero())
+	stored := keeper.mustGetValidator(ctx, validator.OperatorAddress)
+	require.True(t, stored.Tokens.IsZero())
+	require.True(t, stored.DelegatorShares.IsZero())
+	requirePowerIndexed(validator)
+}
@@ -18,8 +18,10 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/module"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	sdksimulation "github.com/cosmos/cosmos-sdk/x/simulation"
 	"github.com/okex/okchain/x/staking/client/cli"
 	"github.com/okex/okchain/x/staking/client/rest"
+	"github.com/okex/okchain/x/staking/simulation"
 	"github.com/okex/okchain/x/staking/types"
 )
 
@@ -160,3 +162,8 @@ func (AppModule) BeginBlock(_ sdk.Context, _ abci.RequestBeginBlock) {}
 func (am AppModule) EndBlock(ctx sdk.Context, _ abci.RequestEndBlock) []abci.ValidatorUpdate {
 	return EndBlocker(ctx, am.keeper)
 }
+
+// WeightedOperations returns the randomized operations of staking with their weights for the simulation
+func (am AppModule) WeightedOperations() sdksimulation.WeightedOperations {
+	return simulation.WeightedOperations(am.accKeeper, am.keeper, am.NewHandler())
+}
@@ -0,0 +1,63 @@
+package staking
+
+import (
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/supply"
+	govtypes "github.com/okex/okchain/x/gov/types"
+	"github.com/okex/okchain/x/staking/keeper"
+	"github.com/okex/okchain/x/staking/types"
+)
+
+// NewProposalHandler handles the passed proposals of staking, which are only executed by the gov module
+func NewProposalHandler(k *keeper.Keeper, sk types.SlashingKeeper) govtypes.Handler {
+	return func(ctx sdk.Context, proposal *govtypes.Proposal) sdk.Error {
+		switch c := proposal.Content.(type) {
+		case types.UnjailValidatorProposal:
+			return handleUnjailValidatorProposal(ctx, *k, sk, c)
+		case types.ForceUpdateValidatorStateProposal:
+			return handleForceUpdateValidatorStateProposal(ctx, *k, c)
+		default:
+			return sdk.ErrUnknownRequest(fmt.Sprintf("unrecognized staking proposal content type: %T", c))
+		}
+	}
+}
+
+func handleUnjailValidatorProposal(ctx sdk.Context, k keeper.Keeper, sk types.SlashingKeeper,
+	proposal types.UnjailValidatorProposal) sdk.Error {
+	// the validator may have changed during the voting period
+	if err := k.ValidateUnjailValidator(ctx, proposal.ValidatorAddress); err != nil {
+		return err
+	}
+	validator, _ := k.GetValidator(ctx, proposal.ValidatorAddress)
+	consAddr := validator.ConsAddress()
+
+	// clear the jail period recorded by slashing, which would block the validator to unjail itself next time
+	signingInfo, found := sk.GetValidatorSigningInfo(ctx, consAddr)
+	if found {
+		if signingInfo.Tombstoned {
+			return types.ErrValidatorTombstoned(types.DefaultCodespace, proposal.ValidatorAddress.String())
+		}
+		signingInfo.JailedUntil = time.Unix(0, 0).UTC()
+		sk.SetValidatorSigningInfo(ctx, consAddr, signingInfo)
+	}
+
+	k.Unjail(ctx, consAddr)
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeUnjailValidator,
+			sdk.NewAttribute(types.AttributeKeyValidator, proposal.ValidatorAddress.String()),
+		),
+	)
+	return nil
+}
+
+func handleForceUpdateValidatorStateProposal(ctx sdk.Context, k keeper.Keeper,
+	proposal types.ForceUpdateValidatorStateProposal) sdk.Error {
+	// the passed proposal is executed on behalf of the gov module account, and the validator may have changed during
+	// the voting period, which is checked again by the keeper
+	return k.ForceUpdateValidatorState(ctx, supply.NewModuleAddress(govtypes.ModuleName), proposal.ValidatorAddress,
+		proposal.Status, proposal.DelegatorShares)
+}
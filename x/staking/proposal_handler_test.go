@@ -0,0 +1,145 @@
+package staking
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+	govtypes "github.com/okex/okchain/x/gov/types"
+	keep "github.com/okex/okchain/x/staking/keeper"
+	"github.com/okex/okchain/x/staking/types"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+type mockSlashingKeeper struct {
+	signingInfos map[string]slashingtypes.ValidatorSigningInfo
+}
+
+func (sk mockSlashingKeeper) GetValidatorSigningInfo(_ sdk.Context, address sdk.ConsAddress) (
+	info slashingtypes.ValidatorSigningInfo, found bool) {
+	info, found = sk.signingInfos[address.String()]
+	return
+}
+
+func (sk mockSlashingKeeper) SetValidatorSigningInfo(_ sdk.Context, address sdk.ConsAddress,
+	info slashingtypes.ValidatorSigningInfo) {
+	sk.signingInfos[address.String()] = info
+}
+
+func TestUnjailValidatorProposal(t *testing.T) {
+	ctx, _, mKeeper := CreateTestInput(t, false, SufficientInitPower)
+	keeper := mKeeper.Keeper
+	handler := NewHandler(keeper)
+	addr1, addr2 := sdk.ValAddress(keep.Addrs[0]), sdk.ValAddress(keep.Addrs[1])
+	for i, valAddr := range []sdk.ValAddress{addr1, addr2} {
+		got := handler(ctx, NewTestMsgCreateValidator(valAddr, keep.PKs[i], DefaultValidInitMsd))
+		require.True(t, got.IsOK(), "%v", got)
+	}
+	keeper.ApplyAndReturnValidatorSetUpdates(ctx)
+
+	// the validator 2 is jailed and isn't able to unjail itself until long after
+	consAddr2 := sdk.GetConsAddress(keep.PKs[1])
+	keeper.Jail(ctx, consAddr2)
+	jailedUntil := ctx.BlockTime().Add(time.Hour * 24 * 365)
+	sk := mockSlashingKeeper{map[string]slashingtypes.ValidatorSigningInfo{
+		consAddr2.String(): slashingtypes.NewValidatorSigningInfo(consAddr2, 0, 0, jailedUntil, false, 0,
+			slashingtypes.Created),
+	}}
+	proposalHandler := NewProposalHandler(&keeper, sk)
+	execute := func(content govtypes.Content) sdk.Error {
+		cacheCtx, write := ctx.CacheContext()
+		err := proposalHandler(cacheCtx, &govtypes.Proposal{Content: content})
+		if err == nil {
+			write()
+		}
+		return err
+	}
+	proposal := types.NewUnjailValidatorProposal("unjail", "the operator lost the keys", addr2)
+	require.Nil(t, proposal.ValidateBasic())
+	deposit := keeper.ParamsUnjailMinDeposit(ctx)
+
+	// unauthorized proposer
+	msg := govtypes.NewMsgSubmitProposal(proposal, deposit, keep.Addrs[10])
+	require.Equal(t, govtypes.CodeInvalidProposer, keeper.CheckMsgSubmitProposal(ctx, msg).Code())
+	// too little initial deposit
+	msg = govtypes.NewMsgSubmitProposal(proposal, deposit.MulDec(sdk.NewDecWithPrec(1, 2)), keep.Addrs[0])
+	require.NotNil(t, keeper.CheckMsgSubmitProposal(ctx, msg))
+	// the target validator isn't jailed
+	msg = govtypes.NewMsgSubmitProposal(types.NewUnjailValidatorProposal("unjail", "desc", addr1), deposit,
+		keep.Addrs[0])
+	require.Equal(t, types.CodeInvalidValidator, keeper.CheckMsgSubmitProposal(ctx, msg).Code())
+	// authorized proposer
+	msg = govtypes.NewMsgSubmitProposal(proposal, deposit, keep.Addrs[0])
+	require.Nil(t, keeper.CheckMsgSubmitProposal(ctx, msg))
+	require.Equal(t, deposit, keeper.GetMinDeposit(ctx, proposal))
+	require.Equal(t, types.DefaultUnjailVotingPeriod, keeper.GetVotingPeriod(ctx, proposal))
+
+	// a tombstoned validator is kept jailed
+	info := sk.signingInfos[consAddr2.String()]
+	info.Tombstoned = true
+	sk.signingInfos[consAddr2.String()] = info
+	require.NotNil(t, execute(proposal))
+	require.True(t, keeper.Validator(ctx, addr2).IsJailed())
+	info.Tombstoned = false
+	sk.signingInfos[consAddr2.String()] = info
+
+	// the passed proposal unjails the validator and clears its jail period
+	require.Nil(t, execute(proposal))
+	require.False(t, keeper.Validator(ctx, addr2).IsJailed())
+	require.Equal(t, time.Unix(0, 0).UTC(), sk.signingInfos[consAddr2.String()].JailedUntil)
+
+	// nothing to unjail any more
+	require.NotNil(t, execute(proposal))
+	// unknown content
+	require.NotNil(t, execute(govtypes.NewTextProposal("text", "desc")))
+}
+
+func TestForceUpdateValidatorStateProposal(t *testing.T) {
+	ctx, _, mKeeper := CreateTestInput(t, false, SufficientInitPower)
+	keeper := mKeeper.Keeper
+	handler := NewHandler(keeper)
+	addr1, addr2 := sdk.ValAddress(keep.Addrs[0]), sdk.ValAddress(keep.Addrs[1])
+	for i, valAddr := range []sdk.ValAddress{addr1, addr2} {
+		got := handler(ctx, NewTestMsgCreateValidator(valAddr, keep.PKs[i], DefaultValidInitMsd))
+		require.True(t, got.IsOK(), "%v", got)
+	}
+	keeper.ApplyAndReturnValidatorSetUpdates(ctx)
+	proposalHandler := NewProposalHandler(&keeper, mockSlashingKeeper{})
+	corruptedShares := sdk.NewDec(1000)
+	proposal := types.NewForceUpdateValidatorStateProposal("correct", "repair after a bug", addr2, sdk.Unbonding,
+		corruptedShares)
+	require.Nil(t, proposal.ValidateBasic())
+	require.NotNil(t, types.NewForceUpdateValidatorStateProposal("correct", "desc", addr2, sdk.BondStatus(0x10),
+		corruptedShares).ValidateBasic())
+	require.NotNil(t, types.NewForceUpdateValidatorStateProposal("correct", "desc", addr2, sdk.Bonded,
+		sdk.NewDec(-1)).ValidateBasic())
+	deposit := keeper.ParamsUnjailMinDeposit(ctx)
+	require.Equal(t, deposit, keeper.GetMinDeposit(ctx, proposal))
+
+	// unauthorized proposer
+	msg := govtypes.NewMsgSubmitProposal(proposal, deposit, keep.Addrs[10])
+	require.Equal(t, govtypes.CodeInvalidProposer, keeper.CheckMsgSubmitProposal(ctx, msg).Code())
+	// illegal status transition, since a bonded validator has to begin unbonding first
+	msg = govtypes.NewMsgSubmitProposal(types.NewForceUpdateValidatorStateProposal("correct", "desc", addr2,
+		sdk.Unbonded, corruptedShares), deposit, keep.Addrs[0])
+	require.Equal(t, types.CodeInvalidValidator, keeper.CheckMsgSubmitProposal(ctx, msg).Code())
+	// authorized proposer
+	msg = govtypes.NewMsgSubmitProposal(proposal, deposit, keep.Addrs[0])
+	require.Nil(t, keeper.CheckMsgSubmitProposal(ctx, msg))
+
+	// the passed proposal corrects the validator on behalf of the gov module account
+	require.Nil(t, proposalHandler(ctx, &govtypes.Proposal{Content: proposal}))
+	validator, found := keeper.GetValidator(ctx, addr2)
+	require.True(t, found)
+	require.Equal(t, sdk.Unbonding, validator.Status)
+	require.True(t, validator.DelegatorShares.Equal(corruptedShares))
+	require.Equal(t, []sdk.ValAddress{addr2}, keeper.GetValidatorQueueTimeSlice(ctx,
+		validator.UnbondingCompletionTime))
+	// and leaves the validator set at the end of the block
+	ctx = ctx.WithBlockHeight(1)
+	require.False(t, keeper.IsEndOfEpoch(ctx))
+	require.Equal(t, []abci.ValidatorUpdate{validator.ABCIValidatorUpdateZero()}, EndBlocker(ctx, keeper))
+	require.Equal(t, int64(0), keeper.GetLastValidatorPower(ctx, addr2))
+}
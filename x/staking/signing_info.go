@@ -0,0 +1,39 @@
+package staking
+
+import (
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/slashing"
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+	"github.com/okex/okchain/x/staking/types"
+)
+
+var _ types.SlashingKeeper = SigningInfoKeeper{}
+
+// SigningInfoKeeper wraps the slashing keeper with the direct lookup of the signing info of a validator, which the
+// slashing keeper keeps unexported
+type SigningInfoKeeper struct {
+	slashing.Keeper
+	storeKey sdk.StoreKey
+	cdc      *codec.Codec
+}
+
+// NewSigningInfoKeeper creates a new SigningInfoKeeper reading the signing infos from the slashing store
+func NewSigningInfoKeeper(sk slashing.Keeper, key sdk.StoreKey, cdc *codec.Codec) SigningInfoKeeper {
+	return SigningInfoKeeper{
+		Keeper:   sk,
+		storeKey: key,
+		cdc:      cdc,
+	}
+}
+
+// GetValidatorSigningInfo gets the signing info of a validator by its consensus address
+func (sk SigningInfoKeeper) GetValidatorSigningInfo(ctx sdk.Context, consAddr sdk.ConsAddress) (
+	info slashingtypes.ValidatorSigningInfo, found bool) {
+	bz := ctx.KVStore(sk.storeKey).Get(slashingtypes.GetValidatorSigningInfoKey(consAddr))
+	if bz == nil {
+		return info, false
+	}
+	sk.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &info)
+	return info, true
+}
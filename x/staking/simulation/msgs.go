@@ -0,0 +1,188 @@
+package simulation
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/simulation"
+	"github.com/okex/okchain/x/staking/keeper"
+	"github.com/okex/okchain/x/staking/types"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+)
+
+// weights of the staking operations in the simulation
+const (
+	OpWeightMsgCreateValidator = 10
+	OpWeightMsgEditValidator   = 5
+	OpWeightMsgDelegate        = 40
+	OpWeightMsgUndelegate      = 20
+	OpWeightMsgVote            = 40
+)
+
+// WeightedOperations returns all the operations of staking with their weights. The msgs are handled by the handler
+// of the staking module
+func WeightedOperations(ak types.AccountKeeper, k keeper.Keeper, handler sdk.Handler) simulation.WeightedOperations {
+	return simulation.WeightedOperations{
+		{Weight: OpWeightMsgCreateValidator, Op: SimulateMsgCreateValidator(ak, k, handler)},
+		{Weight: OpWeightMsgEditValidator, Op: SimulateMsgEditValidator(k, handler)},
+		{Weight: OpWeightMsgDelegate, Op: SimulateMsgDelegate(ak, k, handler)},
+		{Weight: OpWeightMsgUndelegate, Op: SimulateMsgUndelegate(k, handler)},
+		{Weight: OpWeightMsgVote, Op: SimulateMsgVote(k, handler)},
+	}
+}
+
+// SimulateMsgCreateValidator generates a MsgCreateValidator with the min self delegation limit
+func SimulateMsgCreateValidator(ak types.AccountKeeper, k keeper.Keeper, handler sdk.Handler) simulation.Operation {
+	return func(r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simulation.Account) (
+		opMsg simulation.OperationMsg, fOps []simulation.FutureOperation, err error) {
+
+		acc := simulation.RandomAcc(r, accs)
+		valAddr := sdk.ValAddress(acc.Address)
+		if _, found := k.GetValidator(ctx, valAddr); found {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+
+		denom, msd := k.BondDenom(ctx), k.ParamsMinSelfDelegationLimited(ctx)
+		if balance := ak.GetAccount(ctx, acc.Address).GetCoins().AmountOf(denom); balance.LT(msd) {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+
+		// the consensus key is derived from the account, so that it's unique among the validators
+		consPubKey := ed25519.GenPrivKeyFromSecret(acc.Address).PubKey()
+		description := types.NewDescription(simulation.RandStringOfLength(r, 10), "", "", "")
+		msg := types.NewMsgCreateValidator(valAddr, consPubKey, description, sdk.NewDecCoinFromDec(denom, msd))
+		return deliver(ctx, handler, msg)
+	}
+}
+
+// SimulateMsgEditValidator generates a MsgEditValidator with a random description
+func SimulateMsgEditValidator(k keeper.Keeper, handler sdk.Handler) simulation.Operation {
+	return func(r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simulation.Account) (
+		opMsg simulation.OperationMsg, fOps []simulation.FutureOperation, err error) {
+
+		validators := k.GetAllValidators(ctx)
+		if len(validators) == 0 {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+
+		validator := validators[r.Intn(len(validators))]
+		description := types.NewDescription(simulation.RandStringOfLength(r, 10),
+			simulation.RandStringOfLength(r, 10), simulation.RandStringOfLength(r, 10),
+			simulation.RandStringOfLength(r, 10))
+		msg := types.NewMsgEditValidator(validator.OperatorAddress, description, nil, nil, nil)
+		return deliver(ctx, handler, msg)
+	}
+}
+
+// SimulateMsgDelegate generates a MsgDelegate with a random amount between the min delegation and the balance
+func SimulateMsgDelegate(ak types.AccountKeeper, k keeper.Keeper, handler sdk.Handler) simulation.Operation {
+	return func(r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simulation.Account) (
+		opMsg simulation.OperationMsg, fOps []simulation.FutureOperation, err error) {
+
+		acc := simulation.RandomAcc(r, accs)
+		denom := k.BondDenom(ctx)
+		minDelegation, found := minDelegationAmount(ctx, k)
+		if !found {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+		balance := ak.GetAccount(ctx, acc.Address).GetCoins().AmountOf(denom)
+		if balance.LT(minDelegation) {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+
+		amount := minDelegation.Add(simulation.RandomDecAmount(r, balance.Sub(minDelegation)))
+		msg := types.NewMsgDelegate(acc.Address, sdk.NewDecCoinFromDec(denom, amount))
+		return deliver(ctx, handler, msg)
+	}
+}
+
+// SimulateMsgUndelegate generates a MsgUndelegate of either all the delegated coins or a random part of them,
+// which leaves no less than the min delegation
+func SimulateMsgUndelegate(k keeper.Keeper, handler sdk.Handler) simulation.Operation {
+	return func(r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simulation.Account) (
+		opMsg simulation.OperationMsg, fOps []simulation.FutureOperation, err error) {
+
+		acc := simulation.RandomAcc(r, accs)
+		delegator, found := k.GetDelegator(ctx, acc.Address)
+		if !found {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+
+		denom := k.BondDenom(ctx)
+		minDelegation, found := minDelegationAmount(ctx, k)
+		delegated := delegator.GetDelegatedCoins(denom).AmountOf(denom)
+		if !found || delegated.IsZero() {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+
+		amount := delegated
+		if spare := delegated.Sub(minDelegation.MulInt64(2)); r.Intn(2) == 0 && spare.IsPositive() {
+			amount = minDelegation.Add(simulation.RandomDecAmount(r, spare))
+		}
+		msg := types.NewMsgUndelegate(acc.Address, sdk.NewDecCoinFromDec(denom, amount))
+		return deliver(ctx, handler, msg)
+	}
+}
+
+// SimulateMsgVote generates a MsgVote to a random set of the validators with min self delegation, whose size is
+// limited by MaxValsToVote
+func SimulateMsgVote(k keeper.Keeper, handler sdk.Handler) simulation.Operation {
+	return func(r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simulation.Account) (
+		opMsg simulation.OperationMsg, fOps []simulation.FutureOperation, err error) {
+
+		acc := simulation.RandomAcc(r, accs)
+		if delegator, found := k.GetDelegator(ctx, acc.Address); !found || delegator.Tokens.IsZero() {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+
+		var valAddrs []sdk.ValAddress
+		for _, validator := range k.GetAllValidators(ctx) {
+			if validator.MinSelfDelegation.IsPositive() {
+				valAddrs = append(valAddrs, validator.OperatorAddress)
+			}
+		}
+		if len(valAddrs) == 0 {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+
+		// no validator is allowed to vote to if the param MaxValsToVote is zero
+		maxValsToVote := int(k.ParamsMaxValsToVote(ctx))
+		if maxValsToVote == 0 {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+		if maxValsToVote > len(valAddrs) {
+			maxValsToVote = len(valAddrs)
+		}
+		r.Shuffle(len(valAddrs), func(i, j int) { valAddrs[i], valAddrs[j] = valAddrs[j], valAddrs[i] })
+		msg := types.NewMsgVote(acc.Address, valAddrs[:simulation.RandIntBetween(r, 1, maxValsToVote+1)])
+		return deliver(ctx, handler, msg)
+	}
+}
+
+// minDelegationAmount returns the amount of the bond denom equal to the min delegation by its weight, false if the
+// bond denom isn't weighted in the param BondDenoms
+func minDelegationAmount(ctx sdk.Context, k keeper.Keeper) (sdk.Dec, bool) {
+	weight, found := k.ParamsBondDenoms(ctx).Weight(k.BondDenom(ctx))
+	if !found || !weight.IsPositive() {
+		return sdk.ZeroDec(), false
+	}
+	return k.ParamsMinDelegation(ctx).Quo(weight), true
+}
+
+// deliver handles the msg in a cached context, which is written only if the msg is handled successfully
+func deliver(ctx sdk.Context, handler sdk.Handler, msg sdk.Msg) (
+	simulation.OperationMsg, []simulation.FutureOperation, error) {
+	if err := msg.ValidateBasic(); err != nil {
+		return simulation.NoOpMsg(types.ModuleName), nil,
+			fmt.Errorf("expected msg to pass ValidateBasic: %s, got error %v", msg.GetSignBytes(), err)
+	}
+
+	cacheCtx, write := ctx.CacheContext()
+	ok := handler(cacheCtx, msg).IsOK()
+	if ok {
+		write()
+	}
+	return simulation.NewOperationMsg(msg, ok, ""), nil, nil
+}
@@ -0,0 +1,63 @@
+package simulation
+
+import (
+	"math/rand"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/simulation"
+	"github.com/okex/okchain/x/staking/keeper"
+	"github.com/okex/okchain/x/staking/types"
+	"github.com/stretchr/testify/require"
+)
+
+// failHandler fails the test if any msg is delivered
+func failHandler(t *testing.T) sdk.Handler {
+	return func(_ sdk.Context, msg sdk.Msg) sdk.Result {
+		require.FailNow(t, "unexpected msg delivered", msg.Type())
+		return sdk.Result{}
+	}
+}
+
+func TestSimulateMsgVoteMaxValsToVoteZero(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	ctx, _, mk := keeper.CreateTestInput(t, false, 0)
+	k := mk.Keeper
+	params := k.GetParams(ctx)
+	params.MaxValsToVote = 0
+	k.SetParams(ctx, params)
+
+	accs := simulation.RandomAccounts(r, 1)
+	delegator := types.NewDelegator(accs[0].Address)
+	delegator.Tokens = sdk.OneDec()
+	k.SetDelegator(ctx, delegator)
+	validator := types.NewValidator(sdk.ValAddress(keeper.Addrs[0]), keeper.PKs[0], types.Description{})
+	validator.MinSelfDelegation = sdk.OneDec()
+	k.SetValidator(ctx, validator)
+
+	opMsg, _, err := SimulateMsgVote(k, failHandler(t))(r, nil, ctx, accs)
+	require.NoError(t, err)
+	require.False(t, opMsg.OK)
+}
+
+func TestSimulateMsgDelegateBondDenomNotWeighted(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	ctx, ak, mk := keeper.CreateTestInput(t, false, 0)
+	k := mk.Keeper
+	accs := simulation.RandomAccounts(r, 1)
+	account := ak.NewAccountWithAddress(ctx, accs[0].Address)
+	require.NoError(t, account.SetCoins(sdk.NewCoins(sdk.NewCoin(k.BondDenom(ctx), sdk.NewInt(1000000)))))
+	ak.SetAccount(ctx, account)
+
+	params := k.GetParams(ctx)
+	for _, bondDenoms := range []types.WeightedDenoms{
+		{types.NewWeightedDenom("other", sdk.OneDec())},
+		{types.NewWeightedDenom(k.BondDenom(ctx), sdk.ZeroDec())},
+	} {
+		params.BondDenoms = bondDenoms
+		k.SetParams(ctx, params)
+		opMsg, _, err := SimulateMsgDelegate(ak, k, failHandler(t))(r, nil, ctx, accs)
+		require.NoError(t, err)
+		require.False(t, opMsg.OK)
+	}
+}
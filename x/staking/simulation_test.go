@@ -0,0 +1,126 @@
+package staking
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/rand"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/cosmos-sdk/x/mock"
+	"github.com/cosmos/cosmos-sdk/x/simulation"
+	"github.com/cosmos/cosmos-sdk/x/supply"
+	"github.com/okex/okchain/x/staking/keeper"
+	"github.com/okex/okchain/x/staking/types"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+)
+
+const (
+	simSeed       = 42
+	simBlocks     = 50
+	simBlockSize  = 20
+	simAccBalance = 1000000
+)
+
+// getSimApp returns a mock application with the staking module mounted, whose genesis funds the simulation accounts
+// and creates a bonded validator by the first account, so that the simulated chain has a proposer
+func getSimApp(t *testing.T) (*mock.App, Keeper, simulation.AppStateFn) {
+	mApp := mock.NewApp()
+	supply.RegisterCodec(mApp.Cdc)
+	keyStaking := sdk.NewKVStoreKey(StoreKey)
+	tkeyStaking := sdk.NewTransientStoreKey(TStoreKey)
+	keySupply := sdk.NewKVStoreKey(supply.StoreKey)
+
+	bk := bank.NewBaseKeeper(mApp.AccountKeeper, mApp.ParamsKeeper.Subspace(bank.DefaultParamspace),
+		bank.DefaultCodespace, make(map[string]bool))
+	maccPerms := map[string][]string{
+		NotBondedPoolName: {supply.Burner, supply.Staking},
+		BondedPoolName:    {supply.Burner, supply.Staking},
+	}
+	supplyKeeper := supply.NewKeeper(mApp.Cdc, keySupply, mApp.AccountKeeper, bk, maccPerms)
+	k := NewKeeper(keeper.MakeTestCodec(), keyStaking, tkeyStaking, supplyKeeper,
+		mApp.ParamsKeeper.Subspace(DefaultParamspace), DefaultCodespace)
+	appModule := NewAppModule(k, mApp.AccountKeeper, supplyKeeper)
+
+	var genesisAccs []simulation.Account
+	mApp.Router().AddRoute(RouterKey, appModule.NewHandler())
+	mApp.SetEndBlocker(getEndBlocker(k))
+	mApp.SetInitChainer(func(ctx sdk.Context, req abci.RequestInitChain) abci.ResponseInitChain {
+		mApp.InitChainer(ctx, req)
+		supplyKeeper.SetSupply(ctx, supply.NewSupply(mApp.TotalCoinsSupply))
+
+		genesisState := DefaultGenesisState()
+		genesisState.Params.Epoch = 3
+		genesisState.Params.MaxValidators = 5
+		genesisState.Params.UnbondingTime = 2 * time.Hour
+		genesisState.Params.PowerReduction = sdk.NewInt(1000)
+		InitGenesis(ctx, k, mApp.AccountKeeper, supplyKeeper, genesisState)
+		k.SetEpoch(ctx, genesisState.Params.Epoch)
+
+		handler, owner := appModule.NewHandler(), genesisAccs[0].Address
+		consPubKey := ed25519.GenPrivKeyFromSecret(owner).PubKey()
+		msd := sdk.NewDecCoinFromDec(k.BondDenom(ctx), k.ParamsMinSelfDelegationLimited(ctx))
+		for _, msg := range []sdk.Msg{
+			NewMsgCreateValidator(sdk.ValAddress(owner), consPubKey, Description{Moniker: "genesis"}, msd),
+			NewMsgDelegate(owner, sdk.NewDecCoinFromDec(k.BondDenom(ctx), sdk.NewDec(simAccBalance/2))),
+			NewMsgVote(owner, []sdk.ValAddress{sdk.ValAddress(owner)}),
+		} {
+			require.True(t, handler(ctx, msg).IsOK())
+		}
+		return abci.ResponseInitChain{Validators: k.ApplyAndReturnValidatorSetUpdates(ctx)}
+	})
+	require.NoError(t, mApp.CompleteSetup(keyStaking, tkeyStaking, keySupply))
+
+	appStateFn := func(r *rand.Rand, accs []simulation.Account) (json.RawMessage, []simulation.Account, string,
+		time.Time) {
+		genesisAccs = accs
+		balance := sdk.NewCoins(sdk.NewCoin(sdk.DefaultBondDenom, sdk.NewInt(simAccBalance)))
+		for _, acc := range accs {
+			account := auth.NewBaseAccountWithAddress(acc.Address)
+			require.NoError(t, account.SetCoins(balance))
+			mApp.GenesisAccounts = append(mApp.GenesisAccounts, &account)
+			mApp.TotalCoinsSupply = mApp.TotalCoinsSupply.Add(balance)
+		}
+		return json.RawMessage("{}"), accs, keeper.TestChainID, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return mApp, k, appStateFn
+}
+
+func TestSimulateFromSeed(t *testing.T) {
+	mApp, k, appStateFn := getSimApp(t)
+	appModule := NewAppModule(k, mApp.AccountKeeper, nil)
+	invariants := sdk.Invariants{
+		keeper.ModuleAccountInvariantsCustom(k),
+		keeper.NonNegativePowerInvariantCustom(k),
+		keeper.PositiveDelegatorInvariant(k),
+		keeper.DelegatorVotesInvariant(k),
+		keeper.ValidatorTokensInvariant(k),
+	}
+
+	stopEarly, _, err := simulation.SimulateFromSeed(t, ioutil.Discard, mApp.BaseApp, appStateFn, simSeed,
+		appModule.WeightedOperations(), invariants, 1, simBlocks, 0, simBlockSize, "", false, true, false,
+		true, true, make(map[string]bool))
+	require.NoError(t, err)
+	require.False(t, stopEarly)
+
+	// the validators are created, voted and bonded by the simulated operations
+	ctx := mApp.BaseApp.NewContext(true, abci.Header{ChainID: keeper.TestChainID})
+	require.True(t, len(k.GetAllValidators(ctx)) > 1)
+	var bonded int
+	k.IterateLastValidatorPowers(ctx, func(sdk.ValAddress, int64) (stop bool) {
+		bonded++
+		return false
+	})
+	require.True(t, bonded > 1)
+	var delegators int
+	k.IterateDelegator(ctx, func(_ int64, _ types.Delegator) (stop bool) {
+		delegators++
+		return false
+	})
+	require.True(t, delegators > 0)
+}
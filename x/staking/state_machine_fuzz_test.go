@@ -0,0 +1,285 @@
+package staking
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/okex/okchain/x/staking/keeper"
+	"github.com/okex/okchain/x/staking/types"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// fuzzSeeds are the fixed seeds of the randomized operation sequences, which keep the fuzzing reproducible in CI
+var fuzzSeeds = []int64{1, 7, 42, 2020}
+
+const (
+	fuzzSteps          = 400
+	fuzzValidatorNum   = 4
+	fuzzDelegatorNum   = 6
+	fuzzBlockInterval  = 10 * time.Minute
+	fuzzUnbondingTime  = 2 * time.Hour
+	fuzzMaxDelegateAmt = 500
+)
+
+// stakingFuzzer applies the randomized staking operations and checks the invariants after each step
+type stakingFuzzer struct {
+	t        *testing.T
+	rnd      *rand.Rand
+	mk       keeper.MockStakingKeeper
+	handler  sdk.Handler
+	height   int64
+	genesis  time.Time
+	valAddrs []sdk.ValAddress
+	dlgAddrs []sdk.AccAddress
+	// logs of the operations applied, printed once an invariant is broken
+	logs []string
+}
+
+func newStakingFuzzer(t *testing.T, seed int64) *stakingFuzzer {
+	_, _, mk := CreateTestInput(t, false, SufficientInitPower*100)
+	f := &stakingFuzzer{
+		t:       t,
+		rnd:     rand.New(rand.NewSource(seed)),
+		mk:      mk,
+		handler: NewHandler(mk.Keeper),
+		genesis: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	// the addresses of validators and delegators never overlap, so that the min self delegation being undelegated
+	// never mixes with the undelegation of a delegator
+	for i := 0; i < fuzzValidatorNum; i++ {
+		f.valAddrs = append(f.valAddrs, sdk.ValAddress(Addrs[10+i]))
+	}
+	for i := 0; i < fuzzDelegatorNum; i++ {
+		f.dlgAddrs = append(f.dlgAddrs, Addrs[20+i])
+	}
+
+	ctx := f.context()
+	params := DefaultParams()
+	params.Epoch = 3
+	params.MaxValidators = fuzzValidatorNum - 1
+	params.UnbondingTime = fuzzUnbondingTime
+	mk.Keeper.SetParams(ctx, params)
+	mk.Keeper.SetEpoch(ctx, params.Epoch)
+	for i, valAddr := range f.valAddrs {
+		f.deliver(ctx, fmt.Sprintf("create validator %s", valAddr),
+			NewTestMsgCreateValidator(valAddr, PKs[10+i], DefaultValidInitMsd))
+	}
+	return f
+}
+
+// context returns a context of the current height, whose block time is derived from the height deterministically
+func (f *stakingFuzzer) context() sdk.Context {
+	header := abci.Header{
+		ChainID: keeper.TestChainID,
+		Height:  f.height,
+		Time:    f.genesis.Add(time.Duration(f.height) * fuzzBlockInterval),
+	}
+	ctx := sdk.NewContext(f.mk.MountedStore, header, false, log.NewNopLogger())
+	return ctx.WithConsensusParams(&abci.ConsensusParams{
+		Validator: &abci.ValidatorParams{PubKeyTypes: []string{tmtypes.ABCIPubKeyTypeEd25519}},
+	})
+}
+
+// deliver handles the msg as a tx does, the state changes are only written when the msg succeeds
+func (f *stakingFuzzer) deliver(ctx sdk.Context, desc string, msg sdk.Msg) {
+	res := deliverMsg(ctx, f.handler, msg)
+	f.logs = append(f.logs, fmt.Sprintf("[%d] %s, ok: %v %s", f.height, desc, res.IsOK(), res.Log))
+}
+
+func (f *stakingFuzzer) randomDelegator() sdk.AccAddress {
+	return f.dlgAddrs[f.rnd.Intn(len(f.dlgAddrs))]
+}
+
+func (f *stakingFuzzer) randomValidator() sdk.ValAddress {
+	return f.valAddrs[f.rnd.Intn(len(f.valAddrs))]
+}
+
+func (f *stakingFuzzer) randomAmount(max sdk.Dec) sdk.DecCoin {
+	// a random amount in (0, max] with 4 decimals
+	precision := int64(10000)
+	units := max.MulInt64(precision).TruncateInt64()
+	if units <= 0 {
+		units = 1
+	}
+	return sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDecWithPrec(f.rnd.Int63n(units)+1, 4))
+}
+
+func (f *stakingFuzzer) step() {
+	ctx := f.context()
+	k := f.mk.Keeper
+	switch op := f.rnd.Intn(100); {
+	case op < 25:
+		delAddr := f.randomDelegator()
+		amount := f.randomAmount(sdk.NewDec(fuzzMaxDelegateAmt))
+		f.deliver(ctx, fmt.Sprintf("%s delegates %s", delAddr, amount), NewMsgDelegate(delAddr, amount))
+	case op < 40:
+		delAddr := f.randomDelegator()
+		max := sdk.NewDec(fuzzMaxDelegateAmt)
+		if delegator, found := k.GetDelegator(ctx, delAddr); found {
+			max = delegator.Tokens
+		}
+		amount := f.randomAmount(max)
+		// undelegate all sometimes
+		if f.rnd.Intn(4) == 0 {
+			amount.Amount = max
+		}
+		f.deliver(ctx, fmt.Sprintf("%s undelegates %s", delAddr, amount), NewMsgUndelegate(delAddr, amount))
+	case op < 60:
+		// voting to another set of validators is how the votes are redelegated
+		delAddr := f.randomDelegator()
+		var valAddrs []sdk.ValAddress
+		for _, i := range f.rnd.Perm(len(f.valAddrs))[:f.rnd.Intn(len(f.valAddrs))+1] {
+			valAddrs = append(valAddrs, f.valAddrs[i])
+		}
+		f.deliver(ctx, fmt.Sprintf("%s votes %s", delAddr, valAddrs), NewMsgVote(delAddr, valAddrs))
+	case op < 65:
+		delAddr := f.randomDelegator()
+		reg := f.rnd.Intn(2) == 0
+		f.deliver(ctx, fmt.Sprintf("%s registers proxy: %v", delAddr, reg), types.NewMsgRegProxy(delAddr, reg))
+	case op < 70:
+		delAddr, proxyAddr := f.randomDelegator(), f.randomDelegator()
+		f.deliver(ctx, fmt.Sprintf("%s binds proxy %s", delAddr, proxyAddr),
+			types.NewMsgBindProxy(delAddr, proxyAddr))
+	case op < 74:
+		delAddr := f.randomDelegator()
+		f.deliver(ctx, fmt.Sprintf("%s unbinds proxy", delAddr), types.NewMsgUnbindProxy(delAddr))
+	case op < 78:
+		// jailing is the only punishment of validators without the token slashing, and only the bonded validators
+		// are punished as the slashing module does
+		validator, found := k.GetValidator(ctx, f.randomValidator())
+		if !found || !validator.Jailed && !validator.IsBonded() {
+			return
+		}
+		if validator.Jailed {
+			k.Unjail(ctx, validator.GetConsAddr())
+		} else {
+			k.Jail(ctx, validator.GetConsAddr())
+			k.AppendAbandonedValidatorAddrs(ctx, validator.GetConsAddr())
+		}
+		f.logs = append(f.logs, fmt.Sprintf("[%d] %s jailed: %v", f.height, validator.OperatorAddress,
+			!validator.Jailed))
+	case op < 80:
+		valAddr := f.randomValidator()
+		f.deliver(ctx, fmt.Sprintf("destroy validator %s", valAddr),
+			types.NewMsgDestroyValidator(sdk.AccAddress(valAddr)))
+	default:
+		EndBlocker(ctx, k)
+		f.logs = append(f.logs, fmt.Sprintf("[%d] end block", f.height))
+		f.height++
+	}
+}
+
+// checkInvariants checks the invariants of staking and the backing of votes after a step
+func (f *stakingFuzzer) checkInvariants(seed int64, step int) {
+	ctx := f.context()
+	k := f.mk.Keeper
+	invariants := []sdk.Invariant{
+		keeper.ModuleAccountInvariantsCustom(k),
+		keeper.NonNegativePowerInvariantCustom(k),
+		keeper.PositiveDelegatorInvariant(k),
+		keeper.DelegatorVotesInvariant(k),
+		voteBackingInvariant(k),
+	}
+	for _, invariant := range invariants {
+		if msg, broken := invariant(ctx); broken {
+			for _, log := range f.logs {
+				f.t.Log(log)
+			}
+			f.t.Fatalf("seed %d, step %d: %s", seed, step, msg)
+		}
+	}
+}
+
+// voteBackingInvariant checks that every vote is backed by the shares of its voter and every binding to a proxy is
+// backed by the tokens delegated to the proxy
+func voteBackingInvariant(k keeper.Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		var msg string
+		delegatedToProxies := make(map[string]sdk.Dec)
+		k.IterateDelegator(ctx, func(_ int64, delegator types.Delegator) (stop bool) {
+			for _, valAddr := range delegator.ValidatorAddresses {
+				if _, found := k.GetValidator(ctx, valAddr); !found {
+					continue
+				}
+				if votes, found := k.GetVote(ctx, delegator.DelegatorAddress, valAddr); !found ||
+					!votes.Equal(delegator.Shares) {
+					msg += fmt.Sprintf("\tvotes of %s on %s don't match the shares %s\n",
+						delegator.DelegatorAddress, valAddr, delegator.Shares)
+				}
+			}
+			if delegator.HasProxy() {
+				proxyAddr := delegator.ProxyAddress.String()
+				if _, ok := delegatedToProxies[proxyAddr]; !ok {
+					delegatedToProxies[proxyAddr] = sdk.ZeroDec()
+				}
+				delegatedToProxies[proxyAddr] = delegatedToProxies[proxyAddr].Add(delegator.Tokens)
+			}
+			return false
+		})
+
+		k.IterateVotes(ctx, func(_ int64, voterAddr sdk.AccAddress, valAddr sdk.ValAddress,
+			votes types.Votes) (stop bool) {
+			delegator, found := k.GetDelegator(ctx, voterAddr)
+			if !found || !containsValAddr(delegator.ValidatorAddresses, valAddr) {
+				msg += fmt.Sprintf("\tvotes of %s on %s aren't backed by a delegator\n", voterAddr, valAddr)
+			}
+			return false
+		})
+
+		k.IterateDelegator(ctx, func(_ int64, delegator types.Delegator) (stop bool) {
+			if !delegator.IsProxy {
+				return false
+			}
+			expected, ok := delegatedToProxies[delegator.DelegatorAddress.String()]
+			if !ok {
+				expected = sdk.ZeroDec()
+			}
+			if !delegator.TotalDelegatedTokens.Equal(expected) {
+				msg += fmt.Sprintf("\ttokens delegated to proxy %s: %s, sum of the bound delegators: %s\n",
+					delegator.DelegatorAddress, delegator.TotalDelegatedTokens, expected)
+			}
+			return false
+		})
+
+		return sdk.FormatInvariant(types.ModuleName, "vote backing", msg), len(msg) != 0
+	}
+}
+
+func containsValAddr(valAddrs []sdk.ValAddress, valAddr sdk.ValAddress) bool {
+	for _, addr := range valAddrs {
+		if addr.Equals(valAddr) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestStakingStateMachineFuzz(t *testing.T) {
+	for _, seed := range fuzzSeeds {
+		seed := seed
+		t.Run(fmt.Sprintf("seed-%d", seed), func(t *testing.T) {
+			f := newStakingFuzzer(t, seed)
+			f.checkInvariants(seed, 0)
+			for step := 1; step <= fuzzSteps; step++ {
+				f.step()
+				f.checkInvariants(seed, step)
+			}
+
+			// the fuzzing is meaningless if nothing succeeds
+			var succeeded int
+			for _, log := range f.logs {
+				if len(log) != 0 && !strings.Contains(log, "ok: false") {
+					succeeded++
+				}
+			}
+			require.True(t, succeeded > fuzzSteps/4, "only %d of %d operations succeeded", succeeded, fuzzSteps)
+		})
+	}
+}
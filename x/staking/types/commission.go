@@ -2,7 +2,6 @@ package types
 
 import (
 	"fmt"
-	"github.com/cosmos/cosmos-sdk/config"
 	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -99,13 +98,19 @@ func (c CommissionRates) Validate() sdk.Error {
 	return nil
 }
 
+// NextUpdateTime returns the earliest time when the commission rate is allowed to change again, given the change
+// window
+func (c Commission) NextUpdateTime(window time.Duration) time.Time {
+	return c.UpdateTime.Add(window)
+}
+
 // ValidateNewRate performs basic sanity validation checks of a new commission rate
 // If validation fails, an SDK error is returned.
-func (c Commission) ValidateNewRate(newRate sdk.Dec, blockTime time.Time) sdk.Error {
+func (c Commission) ValidateNewRate(newRate sdk.Dec, blockTime time.Time, window time.Duration) sdk.Error {
 	switch {
-	case blockTime.Sub(c.UpdateTime).Hours() < config.DefaultValidateRateUpdateInterval:
-		// new rate cannot be changed more than once within 24 hours
-		return ErrCommissionUpdateTime(DefaultCodespace)
+	case blockTime.Before(c.NextUpdateTime(window)):
+		// new rate cannot be changed more than once within the change window
+		return ErrCommissionUpdateTime(DefaultCodespace, window)
 
 	case newRate.LT(sdk.ZeroDec()):
 		// new rate cannot be negative
@@ -62,7 +62,7 @@ func TestCommissionValidateNewRate(t *testing.T) {
 	}
 
 	for i, tc := range testCases {
-		err := tc.input.ValidateNewRate(tc.newRate, tc.blockTime)
+		err := tc.input.ValidateNewRate(tc.newRate, tc.blockTime, DefaultCommissionChangeWindow)
 		require.Equal(
 			t, tc.expectErr, err != nil,
 			"unexpected result; tc #%d, input: %v, newRate: %s, blockTime: %s",
@@ -70,3 +70,21 @@ func TestCommissionValidateNewRate(t *testing.T) {
 		)
 	}
 }
+
+func TestCommissionChangeWindow(t *testing.T) {
+	now := time.Now().UTC()
+	c := NewCommissionWithTime(sdk.MustNewDecFromStr("0.40"), sdk.MustNewDecFromStr("0.80"),
+		sdk.MustNewDecFromStr("0.10"), now)
+	newRate := sdk.MustNewDecFromStr("0.50")
+
+	// blocked under the default window
+	require.Equal(t, now.Add(24*time.Hour), c.NextUpdateTime(DefaultCommissionChangeWindow))
+	require.NotNil(t, c.ValidateNewRate(newRate, now.Add(2*time.Hour), DefaultCommissionChangeWindow))
+
+	// allowed with a shortened window
+	window := time.Hour
+	require.Equal(t, now.Add(window), c.NextUpdateTime(window))
+	require.NotNil(t, c.ValidateNewRate(newRate, now.Add(window-time.Second), window))
+	require.Nil(t, c.ValidateNewRate(newRate, now.Add(window), window))
+	require.Nil(t, c.ValidateNewRate(newRate, now.Add(2*time.Hour), window))
+}
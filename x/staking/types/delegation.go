@@ -13,15 +13,118 @@ type UndelegationInfo struct {
 	DelegatorAddress sdk.AccAddress `json:"delegator_address" yaml:"delegator_address"`
 	Quantity         sdk.Dec        `json:"quantity" yaml:"quantity"`
 	CompletionTime   time.Time      `json:"completion_time"`
+	// coins to return when the undelegation completes, of which Quantity is the weighted amount
+	Coins sdk.DecCoins `json:"coins" yaml:"coins"`
+	// validators whose votes were withdrawn by the undelegation
+	ValidatorAddresses []sdk.ValAddress `json:"validator_addresses" yaml:"validator_addresses"`
+	// parts of the undelegation merged while it was in flight, each completing at its own time, which is empty if
+	// there's only one part. CompletionTime is the latest one of them then
+	Entries []UndelegationEntry `json:"entries,omitempty" yaml:"entries,omitempty"`
+}
+
+// UndelegationEntry is a part of the undelegation info, which completes at its own time
+type UndelegationEntry struct {
+	Quantity       sdk.Dec      `json:"quantity" yaml:"quantity"`
+	Coins          sdk.DecCoins `json:"coins" yaml:"coins"`
+	CompletionTime time.Time    `json:"completion_time" yaml:"completion_time"`
+}
+
+// NewUndelegationEntry creates a new instance of UndelegationEntry
+func NewUndelegationEntry(quantity sdk.Dec, coins sdk.DecCoins, completionTime time.Time) UndelegationEntry {
+	return UndelegationEntry{
+		Quantity:       quantity,
+		Coins:          coins,
+		CompletionTime: completionTime,
+	}
 }
 
 // NewUndelegationInfo creates a new delegation object
-func NewUndelegationInfo(delegatorAddr sdk.AccAddress, votesQuantity sdk.Dec,
+func NewUndelegationInfo(delegatorAddr sdk.AccAddress, votesQuantity sdk.Dec, coins sdk.DecCoins,
 	completionTime time.Time) UndelegationInfo {
 	return UndelegationInfo{
 		DelegatorAddress: delegatorAddr,
 		Quantity:         votesQuantity,
 		CompletionTime:   completionTime,
+		Coins:            coins,
+	}
+}
+
+// GetCoins returns the coins to return when the undelegation completes
+// NOTE: the undelegation info without coins is regarded as the one only in the primary bondable denom
+func (ud UndelegationInfo) GetCoins(primaryDenom string) sdk.DecCoins {
+	if ud.Coins.Empty() && ud.Quantity.IsPositive() {
+		return sdk.NewDecCoinsFromDec(primaryDenom, ud.Quantity)
+	}
+	return ud.Coins
+}
+
+// GetEntries returns the parts of the undelegation, which is the undelegation itself if there's only one part
+func (ud UndelegationInfo) GetEntries(primaryDenom string) []UndelegationEntry {
+	if len(ud.Entries) == 0 {
+		return []UndelegationEntry{NewUndelegationEntry(ud.Quantity, ud.GetCoins(primaryDenom), ud.CompletionTime)}
+	}
+	return ud.Entries
+}
+
+// AddEntry merges an undelegation completing at the completion time given into the one in flight, whose coins keep
+// completing at their own time
+func (ud *UndelegationInfo) AddEntry(primaryDenom string, quantity sdk.Dec, coins sdk.DecCoins,
+	completionTime time.Time) {
+	ud.Entries = ud.GetEntries(primaryDenom)
+	// the entries are kept in the order of their completion times
+	i := len(ud.Entries)
+	for i > 0 && ud.Entries[i-1].CompletionTime.After(completionTime) {
+		i--
+	}
+	ud.Entries = append(ud.Entries, UndelegationEntry{})
+	copy(ud.Entries[i+1:], ud.Entries[i:])
+	ud.Entries[i] = NewUndelegationEntry(quantity, coins, completionTime)
+
+	ud.Quantity = ud.Quantity.Add(quantity)
+	ud.Coins = ud.GetCoins(primaryDenom).Add(coins)
+	ud.CompletionTime = ud.Entries[len(ud.Entries)-1].CompletionTime
+}
+
+// RemoveMatureEntries removes the parts of the undelegation completing no later than the time given and returns their
+// coins, which are all the coins if there's only one part. It tells whether the undelegation is completed at all
+func (ud *UndelegationInfo) RemoveMatureEntries(primaryDenom string, currentTime time.Time) (coins sdk.DecCoins,
+	completed bool) {
+	if len(ud.Entries) == 0 {
+		return ud.GetCoins(primaryDenom), true
+	}
+
+	coins = sdk.DecCoins{}
+	i := 0
+	for ; i < len(ud.Entries) && !ud.Entries[i].CompletionTime.After(currentTime); i++ {
+		coins = coins.Add(ud.Entries[i].Coins)
+		ud.Quantity = ud.Quantity.Sub(ud.Entries[i].Quantity)
+	}
+	ud.Entries = ud.Entries[i:]
+	ud.Coins = ud.Coins.Sub(coins)
+	switch len(ud.Entries) {
+	case 0:
+		return coins, true
+	case 1:
+		// the only part left is kept as the undelegation itself
+		ud.Quantity, ud.Coins, ud.CompletionTime = ud.Entries[0].Quantity, ud.Entries[0].Coins, ud.Entries[0].CompletionTime
+		ud.Entries = nil
+	}
+	return coins, false
+}
+
+// AddValidatorAddresses adds the validators that the undelegation withdrew votes from, skipping the ones already in
+func (ud *UndelegationInfo) AddValidatorAddresses(valAddrs []sdk.ValAddress) {
+	for _, valAddr := range valAddrs {
+		existed := false
+		for _, addr := range ud.ValidatorAddresses {
+			if addr.Equals(valAddr) {
+				existed = true
+				break
+			}
+		}
+		if !existed {
+			ud.ValidatorAddresses = append(ud.ValidatorAddresses, valAddr)
+		}
 	}
 }
 
@@ -42,16 +145,45 @@ func UnmarshalUndelegationInfo(cdc *codec.Codec, value []byte) (undelegationInfo
 
 // String returns a human readable string representation of UndelegationInfo
 func (ud UndelegationInfo) String() string {
-	return fmt.Sprintf(`UnDelegation:
+	out := fmt.Sprintf(`UnDelegation:
   Delegator: %s
   Quantity:    %s
-  CompletionTime:    %s`,
-		ud.DelegatorAddress, ud.Quantity, ud.CompletionTime.Format(time.RFC3339))
+  Coins:    %s
+  CompletionTime:    %s
+  Validators:    %v`,
+		ud.DelegatorAddress, ud.Quantity, ud.Coins, ud.CompletionTime.Format(time.RFC3339), ud.ValidatorAddresses)
+	for _, entry := range ud.Entries {
+		out += fmt.Sprintf("\n  Entry:    %s %s %s", entry.Quantity, entry.Coins, entry.CompletionTime.Format(time.RFC3339))
+	}
+	return out
 }
 
 // DefaultUndelegation returns default entity for UndelegationInfo
 func DefaultUndelegation() UndelegationInfo {
 	return UndelegationInfo{
-		nil, sdk.ZeroDec(), time.Unix(0, 0).UTC(),
+		nil, sdk.ZeroDec(), time.Unix(0, 0).UTC(), nil, nil, nil,
 	}
 }
+
+// UnbondingStats is the summary of all the pending undelegations in the unbonding queue
+type UnbondingStats struct {
+	// number of the pending undelegation entries
+	Count int64 `json:"count" yaml:"count"`
+	// total weighted tokens and coins to return
+	TotalQuantity sdk.Dec      `json:"total_quantity" yaml:"total_quantity"`
+	TotalCoins    sdk.DecCoins `json:"total_coins" yaml:"total_coins"`
+	// completion times of the first and the last undelegations in the queue, zero if the queue is empty
+	EarliestCompletionTime time.Time `json:"earliest_completion_time" yaml:"earliest_completion_time"`
+	LatestCompletionTime   time.Time `json:"latest_completion_time" yaml:"latest_completion_time"`
+}
+
+// String returns a human readable string representation of UnbondingStats
+func (us UnbondingStats) String() string {
+	return fmt.Sprintf(`Unbonding Stats:
+  Count:    %d
+  Total Quantity:    %s
+  Total Coins:    %s
+  Earliest Completion Time:    %s
+  Latest Completion Time:    %s`,
+		us.Count, us.TotalQuantity, us.TotalCoins, us.EarliestCompletionTime, us.LatestCompletionTime)
+}
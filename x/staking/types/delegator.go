@@ -17,6 +17,8 @@ type Delegator struct {
 	IsProxy              bool             `json:"is_proxy" yaml:"is_proxy"`
 	TotalDelegatedTokens sdk.Dec          `json:"total_delegated_tokens" yaml:"total_delegated_tokens"`
 	ProxyAddress         sdk.AccAddress   `json:"proxy_address" yaml:"proxy_address"`
+	// coins delegated in each bondable denom, of which Tokens is the weighted amount
+	DelegatedCoins sdk.DecCoins `json:"delegated_coins" yaml:"delegated_coins"`
 }
 
 // NewDelegator creates a new Delegator object
@@ -29,7 +31,17 @@ func NewDelegator(delAddr sdk.AccAddress) Delegator {
 		false,
 		sdk.ZeroDec(),
 		nil,
+		nil,
+	}
+}
+
+// GetDelegatedCoins returns the coins delegated in each bondable denom
+// NOTE: the delegator without delegated coins is regarded as the one only delegating in the primary bondable denom
+func (d Delegator) GetDelegatedCoins(primaryDenom string) sdk.DecCoins {
+	if d.DelegatedCoins.Empty() && d.Tokens.IsPositive() {
+		return sdk.NewDecCoinsFromDec(primaryDenom, d.Tokens)
 	}
+	return d.DelegatedCoins
 }
 
 // GetVotedValidatorAddresses gets validator address that the delegator voted to for other module
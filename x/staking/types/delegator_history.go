@@ -0,0 +1,63 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// DelegatorHistoryRetention is the number of the latest staking actions of each delegator kept in store
+const DelegatorHistoryRetention uint64 = 100
+
+// types of the staking actions of a delegator
+const (
+	DelegatorActionDelegate   = "delegate"
+	DelegatorActionUndelegate = "undelegate"
+	DelegatorActionVote       = "vote"
+	// a delegation deferred within the epoch boundary grace which is no longer valid at the end of the epoch, whose
+	// coins are returned
+	DelegatorActionDelegateDropped = "delegate_dropped"
+)
+
+// DelegatorAction is a staking action of a delegator recorded at the height it took place. The amount is the coin
+// delegated or undelegated, or the tokens behind the votes
+type DelegatorAction struct {
+	Sequence           uint64           `json:"sequence" yaml:"sequence"`
+	Action             string           `json:"action" yaml:"action"`
+	Height             int64            `json:"height" yaml:"height"`
+	Amount             sdk.DecCoin      `json:"amount" yaml:"amount"`
+	ValidatorAddresses []sdk.ValAddress `json:"validator_addresses" yaml:"validator_addresses"`
+}
+
+// NewDelegatorAction creates a new instance of DelegatorAction
+func NewDelegatorAction(sequence uint64, action string, height int64, amount sdk.DecCoin,
+	valAddrs []sdk.ValAddress) DelegatorAction {
+	return DelegatorAction{
+		Sequence:           sequence,
+		Action:             action,
+		Height:             height,
+		Amount:             amount,
+		ValidatorAddresses: valAddrs,
+	}
+}
+
+// String returns a human readable string representation of DelegatorAction
+func (da DelegatorAction) String() string {
+	out := fmt.Sprintf("#%d %s at height %d: %s", da.Sequence, da.Action, da.Height, da.Amount)
+	if len(da.ValidatorAddresses) != 0 {
+		out += fmt.Sprintf(" to %v", da.ValidatorAddresses)
+	}
+	return out
+}
+
+// DelegatorHistory is the series of the staking actions of a delegator in the order they took place
+type DelegatorHistory []DelegatorAction
+
+// String returns a human readable string representation of DelegatorHistory
+func (dh DelegatorHistory) String() string {
+	out := "Delegator History:"
+	for _, action := range dh {
+		out += "\n  " + action.String()
+	}
+	return out
+}
@@ -0,0 +1,84 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// EpochInfo is the struct of the current epoch for querying
+type EpochInfo struct {
+	EpochNumber        uint64 `json:"epoch_number" yaml:"epoch_number"`
+	BlocksPerEpoch     uint16 `json:"blocks_per_epoch" yaml:"blocks_per_epoch"`
+	LastEpochEndHeight int64  `json:"last_epoch_end_height" yaml:"last_epoch_end_height"`
+	EpochEndHeight     int64  `json:"epoch_end_height" yaml:"epoch_end_height"`
+}
+
+// NewEpochInfo creates a new instance of EpochInfo
+func NewEpochInfo(epochNumber uint64, blocksPerEpoch uint16, lastEpochEndHeight int64) EpochInfo {
+	return EpochInfo{
+		EpochNumber:        epochNumber,
+		BlocksPerEpoch:     blocksPerEpoch,
+		LastEpochEndHeight: lastEpochEndHeight,
+		EpochEndHeight:     lastEpochEndHeight + int64(blocksPerEpoch),
+	}
+}
+
+// String returns a human readable string representation of EpochInfo
+func (ei EpochInfo) String() string {
+	return fmt.Sprintf(`Epoch Info:
+  Epoch Number:          %d
+  Blocks Per Epoch:      %d
+  Last Epoch End Height: %d
+  Epoch End Height:      %d`, ei.EpochNumber, ei.BlocksPerEpoch, ei.LastEpochEndHeight, ei.EpochEndHeight)
+}
+
+// BondedHistoryRetention is the number of the latest epochs whose bonded snapshots are kept in store
+const BondedHistoryRetention uint64 = 1000
+
+// BondedSnapshot is the total bonded tokens recorded at the end of an epoch
+type BondedSnapshot struct {
+	EpochNumber  uint64       `json:"epoch_number" yaml:"epoch_number"`
+	Height       int64        `json:"height" yaml:"height"`
+	BondedTokens sdk.DecCoins `json:"bonded_tokens" yaml:"bonded_tokens"`
+}
+
+// NewBondedSnapshot creates a new instance of BondedSnapshot
+func NewBondedSnapshot(epochNumber uint64, height int64, bondedTokens sdk.DecCoins) BondedSnapshot {
+	return BondedSnapshot{
+		EpochNumber:  epochNumber,
+		Height:       height,
+		BondedTokens: bondedTokens,
+	}
+}
+
+// String returns a human readable string representation of BondedSnapshot
+func (bs BondedSnapshot) String() string {
+	return fmt.Sprintf("epoch %d at height %d: %s", bs.EpochNumber, bs.Height, bs.BondedTokens)
+}
+
+// BondedHistory is the series of the bonded snapshots in the order of the epochs
+type BondedHistory []BondedSnapshot
+
+// String returns a human readable string representation of BondedHistory
+func (bh BondedHistory) String() string {
+	out := "Bonded History:"
+	for _, snapshot := range bh {
+		out += "\n  " + snapshot.String()
+	}
+	return out
+}
+
+// PendingDelegation is a delegation received within the epoch boundary grace, which is deferred to the end of the epoch
+type PendingDelegation struct {
+	DelegatorAddress sdk.AccAddress `json:"delegator_address" yaml:"delegator_address"`
+	Amount           sdk.DecCoin    `json:"amount" yaml:"amount"`
+}
+
+// NewPendingDelegation creates a new instance of PendingDelegation
+func NewPendingDelegation(delAddr sdk.AccAddress, amount sdk.DecCoin) PendingDelegation {
+	return PendingDelegation{
+		DelegatorAddress: delAddr,
+		Amount:           amount,
+	}
+}
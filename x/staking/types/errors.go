@@ -3,6 +3,7 @@ package types
 
 import (
 	"strings"
+	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
@@ -17,6 +18,7 @@ const (
 	CodeInvalidInput      CodeType = 103
 	CodeInvalidAddress             = sdk.CodeInvalidAddress
 	CodeUnknownRequest             = sdk.CodeUnknownRequest
+	CodeUnauthorized               = sdk.CodeUnauthorized
 
 	CodeInvalidMinSelfDelegation CodeType = 104
 	CodeInvalidProxy             CodeType = 105
@@ -57,6 +59,12 @@ func ErrValidatorPubKeyTypeNotSupported(codespace sdk.CodespaceType, keyType str
 		"validator pubkey type %s is not supported, must use %s", keyType, strings.Join(supportedTypes, ","))
 }
 
+// ErrValidatorMonikerExists returns an error when the moniker has been taken by another validator
+func ErrValidatorMonikerExists(codespace sdk.CodespaceType, moniker string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidValidator,
+		"failed. moniker %s has already been used by another validator (case-insensitive)", moniker)
+}
+
 // ErrDescriptionLength returns an error when the description of validator has a wrong length
 func ErrDescriptionLength(codespace sdk.CodespaceType, descriptor string, got, max int) sdk.Error {
 	return sdk.NewError(codespace, CodeInvalidValidator,
@@ -79,8 +87,8 @@ func ErrCommissionGTMaxRate(codespace sdk.CodespaceType) sdk.Error {
 }
 
 // ErrCommissionUpdateTime returns an error when the commission is remodified within 24 hours
-func ErrCommissionUpdateTime(codespace sdk.CodespaceType) sdk.Error {
-	return sdk.NewError(codespace, CodeInvalidValidator, "commission cannot be changed more than once in 24h")
+func ErrCommissionUpdateTime(codespace sdk.CodespaceType, window time.Duration) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidValidator, "commission cannot be changed more than once in %s", window)
 }
 
 // ErrCommissionChangeRateNegative returns an error when the commission change rate is not positive
@@ -195,6 +203,36 @@ func ErrNilValidatorAddrs(codespace sdk.CodespaceType) sdk.Error {
 		"failed. validator addresses are nil")
 }
 
+// ErrInvalidRankRange returns an error when the rank range to query is invalid or too large
+func ErrInvalidRankRange(codespace sdk.CodespaceType, startRank, endRank, maxRanks int) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidInput,
+		"failed. invalid rank range [%d, %d], the ranks start from 1 and at most %d ranks are allowed in a query",
+		startRank, endRank, maxRanks)
+}
+
+// ErrUnknownParamKey returns an error when the key isn't one of the staking params
+func ErrUnknownParamKey(codespace sdk.CodespaceType, key string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidInput, "failed. unknown key %s in staking params", key)
+}
+
+// ErrBondDenomChange returns an error when the primary bond denom is changed while there are tokens bonded
+func ErrBondDenomChange(codespace sdk.CodespaceType, oldDenom, newDenom string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidInput,
+		"failed. the bond denom can't be changed from %s to %s while there are tokens bonded, "+
+			"unless the bond denom migration is enabled", oldDenom, newDenom)
+}
+
+// ErrValidatorNotJailed returns an error when unjailing a validator which isn't jailed
+func ErrValidatorNotJailed(codespace sdk.CodespaceType, valAddr string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidValidator, "failed. validator %s isn't jailed", valAddr)
+}
+
+// ErrValidatorTombstoned returns an error when unjailing a validator which is tombstoned for the double signing
+func ErrValidatorTombstoned(codespace sdk.CodespaceType, valAddr string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidValidator,
+		"failed. validator %s is tombstoned and can't be unjailed", valAddr)
+}
+
 // ErrExceedValidatorAddrs returns an error when the number of target validators exceeds the max limit
 func ErrExceedValidatorAddrs(codespace sdk.CodespaceType, num int) sdk.Error {
 	return sdk.NewError(codespace, CodeInvalidInput,
@@ -219,6 +257,13 @@ func ErrInsufficientDelegation(codespace sdk.CodespaceType, quantity, delLeft st
 		"failed. insufficient delegation. [delegation left]:%s, [quantity to unbond]:%s", delLeft, quantity)
 }
 
+// ErrInsufficientRemainder returns an error when the delegation left is positive but less than the min delegation limit
+func ErrInsufficientRemainder(codespace sdk.CodespaceType, remainder, minLimit string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidDelegation,
+		"failed. the delegation left should be either zero or no less than the min limit. [min limit]:%s, [delegation left]:%s",
+		minLimit, remainder)
+}
+
 // ErrInsufficientQuantity returns an error when the quantity is less than the min delegation limit
 func ErrInsufficientQuantity(codespace sdk.CodespaceType, quantity, minLimit string) sdk.Error {
 	return sdk.NewError(codespace, CodeInvalidDelegation,
@@ -267,8 +312,113 @@ func ErrNoDelegatorExisted(codespace sdk.CodespaceType, delAddr string) sdk.Erro
 		"failed. delegator %s isn't existed", delAddr)
 }
 
+// ErrMaxDelegationsReached returns an error when a new delegator comes while the number of delegators reaches the limit
+func ErrMaxDelegationsReached(codespace sdk.CodespaceType, maxDelegations uint64) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidDelegation,
+		"failed. the number of delegators has reached the limit %d, only the existing delegators are able to delegate",
+		maxDelegations)
+}
+
+// ErrValidatorNotAcceptingDelegations returns an error when a new delegator votes to a validator closed to delegations
+func ErrValidatorNotAcceptingDelegations(codespace sdk.CodespaceType, valAddr string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidVote,
+		"failed. validator %s doesn't accept the votes from new delegators", valAddr)
+}
+
+// ErrBelowValidatorMinDelegation returns an error when a new delegator votes to a validator with less tokens than the
+// min delegation of the validator
+func ErrBelowValidatorMinDelegation(codespace sdk.CodespaceType, valAddr string, tokens, minDelegation sdk.Dec,
+) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidVote,
+		"failed. validator %s requires at least %s tokens from a new delegator, got %s", valAddr, minDelegation, tokens)
+}
+
+// ErrNotEnoughValidators returns an error when a delegator votes before there are enough validators on the chain
+func ErrNotEnoughValidators(codespace sdk.CodespaceType, minValidators uint16) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidVote,
+		"failed. no vote is accepted until there are at least %d validators on the chain", minValidators)
+}
+
+// ErrSelfDelegationOnly returns an error when a delegator votes to a validator operated by others while only the
+// self-delegation is allowed
+func ErrSelfDelegationOnly(codespace sdk.CodespaceType, valAddr string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidVote,
+		"failed. only the operator is allowed to vote to validator %s in the self-delegation-only mode", valAddr)
+}
+
+// ErrValidatorDelegatorCountReached returns an error when a new delegator votes to a validator whose number of
+// delegators has reached its limit
+func ErrValidatorDelegatorCountReached(codespace sdk.CodespaceType, valAddr string, maxDelegatorCount uint64,
+) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidVote,
+		"failed. the number of delegators voting to validator %s has reached its limit %d", valAddr,
+		maxDelegatorCount)
+}
+
 // ErrTargetValsDuplicate returns an error when the target validators in voting list are duplicate
 func ErrTargetValsDuplicate(codespace sdk.CodespaceType) sdk.Error {
 	return sdk.NewError(codespace, CodeInvalidVote,
 		"failed. duplicate target validators")
 }
+
+// ErrValidatorSetNotFull returns an error when the validator set isn't full, so that no validator is about to be evicted
+func ErrValidatorSetNotFull(codespace sdk.CodespaceType, maxValidators uint16) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidValidator,
+		"failed. there are fewer validators with votes than the max validators %d", maxValidators)
+}
+
+// ErrInvalidUnbondingTime returns an error when the unbonding time to set is out of the range allowed
+func ErrInvalidUnbondingTime(codespace sdk.CodespaceType, unbondingTime, commissionChangeWindow,
+	maxUnbondingTime time.Duration) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidInput,
+		"failed. unbonding time %s must be longer than the commission change window %s and no more than %s",
+		unbondingTime, commissionChangeWindow, maxUnbondingTime)
+}
+
+// ErrValidatorVoteShareCapReached returns an error when the votes of a validator would exceed the cap of the share
+// of the total votes
+func ErrValidatorVoteShareCapReached(codespace sdk.CodespaceType, valAddr string, maxVoteShare sdk.Dec) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidVote,
+		"failed. the votes of validator %s would exceed the max share %s of the total votes", valAddr, maxVoteShare)
+}
+
+// ErrInvalidMaxValidators returns an error when the max validators to set is zero, over MaxMaxValidators or less than
+// the min validators
+func ErrInvalidMaxValidators(codespace sdk.CodespaceType, maxValidators, minValidators uint16) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidInput,
+		"failed. max validators %d must be positive, no more than %d and no less than the min validators %d",
+		maxValidators, MaxMaxValidators, minValidators)
+}
+
+// ErrValidatorVotesCapReached returns an error when the votes of a validator would exceed the cap
+func ErrValidatorVotesCapReached(codespace sdk.CodespaceType, valAddr string, maxValidatorVotes sdk.Int) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidVote,
+		"failed. the votes of validator %s would exceed the max validator votes %s", valAddr, maxValidatorVotes)
+}
+
+// ErrInvalidEpochRange returns an error when the epoch range to query is invalid or too large
+func ErrInvalidEpochRange(codespace sdk.CodespaceType, startEpoch, endEpoch, maxEpochs uint64) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidInput,
+		"failed. invalid epoch range [%d, %d], at most %d epochs are allowed in a query", startEpoch, endEpoch,
+		maxEpochs)
+}
+
+// ErrUnauthorizedStateCorrection returns an error when the state of a validator is forced to update by an account
+// other than the gov module account
+func ErrUnauthorizedStateCorrection(codespace sdk.CodespaceType, authority string) sdk.Error {
+	return sdk.NewError(codespace, CodeUnauthorized,
+		"failed. %s is not the gov module account, which is the only one allowed to correct a validator", authority)
+}
+
+// ErrInvalidStateCorrection returns an error when the corrected state of a validator is invalid
+func ErrInvalidStateCorrection(codespace sdk.CodespaceType, status sdk.BondStatus, delegatorShares sdk.Dec,
+) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidValidator,
+		"failed. invalid state of validator with status %s and delegator shares %s", status, delegatorShares)
+}
+
+// ErrSelfUndelegateCooldown returns an error when a validator self-undelegates again within the cooldown
+func ErrSelfUndelegateCooldown(codespace sdk.CodespaceType, valAddr string, nextTime time.Time) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidDelegation,
+		"failed. validator %s is not allowed to self-undelegate again until %s", valAddr, nextTime.Format(time.RFC3339))
+}
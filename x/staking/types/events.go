@@ -7,12 +7,31 @@ const (
 	EventTypeEditValidator     = "edit_validator"
 	EventTypeDelegate          = "delegate"
 	EventTypeUnbond            = "unbond"
+	EventTypeLargePowerChange  = "large_power_change"
+	EventTypeUnjailValidator   = "unjail_validator"
+	EventTypeSetUnbondingTime  = "set_unbonding_time"
+	EventTypeCorrectValidator  = "correct_validator"
+	EventTypeSetMaxValidators  = "set_max_validators"
+	EventTypeValidatorBonded   = "validator_bonded"
+	EventTypeValidatorUnbonded = "validator_unbonded"
 
 	AttributeKeyValidator         = "validator"
 	AttributeKeyCommissionRate    = "commission_rate"
 	AttributeKeyMinSelfDelegation = "min_self_delegation"
 	AttributeKeyDelegator         = "delegator"
 	AttributeKeyCompletionTime    = "completion_time"
+	AttributeKeyUnbondingTime     = "unbonding_time"
+	AttributeKeyEffectiveHeight   = "effective_height"
+	AttributeKeyAuthority         = "authority"
+	AttributeKeyOldStatus         = "old_status"
+	AttributeKeyNewStatus         = "new_status"
+	AttributeKeyOldShares         = "old_delegator_shares"
+	AttributeKeyNewShares         = "new_delegator_shares"
+	AttributeKeyOldTokens         = "old_tokens"
+	AttributeKeyMaxValidators     = "max_validators"
+	AttributeKeyMode              = "mode"
+	AttributeValueImmediate       = "immediate"
+	AttributeValueDeferred        = "deferred"
 	AttributeValueCategory        = ModuleName
 
 	EventTypeVote = "vote"
@@ -20,4 +39,9 @@ const (
 	AttributeKeyVoter           = "voter"
 	AttributeKeyValidatorToVote = "validator_to_vote"
 	AttributeKeyVotes           = "votes"
+
+	AttributeKeyLastPower      = "last_power"
+	AttributeKeyProjectedPower = "projected_power"
+	AttributeKeyOldPower       = "old_power"
+	AttributeKeyNewPower       = "new_power"
 )
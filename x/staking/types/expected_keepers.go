@@ -3,6 +3,8 @@ package types
 import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	authexported "github.com/cosmos/cosmos-sdk/x/auth/exported"
+	"github.com/cosmos/cosmos-sdk/x/mint"
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
 	supplyexported "github.com/cosmos/cosmos-sdk/x/supply/exported"
 	stakingexported "github.com/okex/okchain/x/staking/exported"
 )
@@ -10,6 +12,15 @@ import (
 // AccountKeeper defines the expected account keeper (noalias)
 type AccountKeeper interface {
 	IterateAccounts(ctx sdk.Context, process func(authexported.Account) (stop bool))
+	GetAccount(ctx sdk.Context, addr sdk.AccAddress) authexported.Account
+}
+
+// SlashingKeeper defines the expected slashing keeper to clear the jail period of a validator unjailed by governance
+// and to read when the validators were first bonded (noalias)
+type SlashingKeeper interface {
+	GetValidatorSigningInfo(ctx sdk.Context, address sdk.ConsAddress) (info slashingtypes.ValidatorSigningInfo,
+		found bool)
+	SetValidatorSigningInfo(ctx sdk.Context, address sdk.ConsAddress, info slashingtypes.ValidatorSigningInfo)
 }
 
 // SupplyKeeper defines the expected supply Keeper (noalias)
@@ -31,6 +42,12 @@ type SupplyKeeper interface {
 	BurnCoins(ctx sdk.Context, name string, amt sdk.Coins) sdk.Error
 }
 
+// MintKeeper defines the expected mint keeper to read the block reward from its minter (noalias)
+type MintKeeper interface {
+	GetParams(ctx sdk.Context) mint.Params
+	GetMinterCustom(ctx sdk.Context) mint.MinterCustom
+}
+
 // ValidatorSet expected properties for the set of all validators (noalias)
 type ValidatorSet interface {
 	// iterate through validators by operator address, execute func for each validator
@@ -74,7 +91,8 @@ type ValidatorSet interface {
 type StakingHooks interface {
 	// Must be called when a validator is created
 	AfterValidatorCreated(ctx sdk.Context, valAddr sdk.ValAddress)
-	// Must be called when a validator's state changes
+	// Must be called before the votes on a validator change, on delegating, undelegating and voting, so that the
+	// distribution is able to settle the rewards by the votes before
 	BeforeValidatorModified(ctx sdk.Context, valAddr sdk.ValAddress)
 	// Must be called when a validator is deleted
 	AfterValidatorRemoved(ctx sdk.Context, consAddr sdk.ConsAddress, valAddr sdk.ValAddress)
@@ -86,4 +104,6 @@ type StakingHooks interface {
 	// required by okchain
 	// Must be called when a validator is destroyed by tx
 	AfterValidatorDestroyed(ctx sdk.Context, consAddr sdk.ConsAddress, valAddr sdk.ValAddress)
+	// Must be called before the delegation from a delegator to a validator is fully removed
+	BeforeDelegationRemoved(ctx sdk.Context, delAddr sdk.AccAddress, valAddr sdk.ValAddress)
 }
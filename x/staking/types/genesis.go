@@ -2,6 +2,7 @@ package types
 
 import (
 	"fmt"
+	"math"
 	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -17,7 +18,44 @@ type GenesisState struct {
 	UnbondingDelegations []UndelegationInfo          `json:"unbonding_delegations" yaml:"unbonding_delegations"`
 	Votes                []VotesExported             `json:"votes" yaml:"votes"`
 	ProxyDelegatorKeys   []ProxyDelegatorKeyExported `json:"proxy_delegator_keys" yaml:"proxy_delegator_keys"`
+	PendingDelegations   []PendingDelegation         `json:"pending_delegations" yaml:"pending_delegations"`
+	EpochNumber          uint64                      `json:"epoch_number" yaml:"epoch_number"`
 	Exported             bool                        `json:"exported" yaml:"exported"`
+	// optional, overrides the Epoch param with the block count converted from it during InitGenesis
+	EpochDuration *EpochDuration `json:"epoch_duration,omitempty" yaml:"epoch_duration,omitempty"`
+}
+
+// EpochDuration expresses the epoch as a duration plus the expected block time, which is easier to set than the
+// block count of an epoch
+type EpochDuration struct {
+	Duration          time.Duration `json:"duration" yaml:"duration"`
+	ExpectedBlockTime time.Duration `json:"expected_block_time" yaml:"expected_block_time"`
+}
+
+// NewEpochDuration creates a new instance of EpochDuration
+func NewEpochDuration(duration, expectedBlockTime time.Duration) EpochDuration {
+	return EpochDuration{
+		Duration:          duration,
+		ExpectedBlockTime: expectedBlockTime,
+	}
+}
+
+// Blocks converts the epoch duration to the block count of an epoch
+func (ed EpochDuration) Blocks() (uint16, error) {
+	if ed.Duration <= 0 || ed.ExpectedBlockTime <= 0 {
+		return 0, fmt.Errorf("both the epoch duration and the expected block time must be positive, got %s and %s",
+			ed.Duration, ed.ExpectedBlockTime)
+	}
+	if ed.Duration%ed.ExpectedBlockTime != 0 {
+		return 0, fmt.Errorf("epoch duration %s isn't a whole number of blocks of %s", ed.Duration,
+			ed.ExpectedBlockTime)
+	}
+	blocks := ed.Duration / ed.ExpectedBlockTime
+	if blocks > math.MaxUint16 {
+		return 0, fmt.Errorf("epoch duration %s is %d blocks of %s, more than the max %d", ed.Duration, blocks,
+			ed.ExpectedBlockTime, math.MaxUint16)
+	}
+	return uint16(blocks), nil
 }
 
 // LastValidatorPower is needed for validator set update logic
@@ -61,6 +99,11 @@ type ValidatorExported struct {
 	UnbondingHeight         int64          `json:"unbonding_height"`
 	UnbondingCompletionTime time.Time      `json:"unbonding_time"`
 	MinSelfDelegation       sdk.Dec        `json:"min_self_delegation"`
+	ClosedToDelegations     bool           `json:"closed_to_delegations"`
+	MaxDelegatorCount       uint64         `json:"max_delegator_count"`
+	MinDelegation           sdk.Dec        `json:"min_delegation"`
+	BondHeight              int64          `json:"bond_height"`
+	BondedSince             time.Time      `json:"bonded_since"`
 }
 
 // Import converts validator exported format to inner one by filling the zero-value of Tokens and Commission
@@ -82,6 +125,11 @@ func (ve ValidatorExported) Import() Validator {
 		ve.UnbondingCompletionTime,
 		NewCommission(sdk.NewDec(1), sdk.NewDec(1), sdk.NewDec(0)),
 		ve.MinSelfDelegation,
+		ve.ClosedToDelegations,
+		ve.MaxDelegatorCount,
+		ve.MinDelegation,
+		ve.BondHeight,
+		ve.BondedSince,
 	}
 }
 
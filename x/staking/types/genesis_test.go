@@ -0,0 +1,42 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEpochDurationBlocks(t *testing.T) {
+	tests := []struct {
+		duration          time.Duration
+		expectedBlockTime time.Duration
+		blocks            uint16
+		wantErr           bool
+	}{
+		{time.Hour, 3 * time.Second, 1200, false},
+		{24 * time.Hour, 5 * time.Second, 17280, false},
+		{10 * time.Minute, 10 * time.Minute, 1, false},
+		{1500 * time.Millisecond, 500 * time.Millisecond, 3, false},
+		// not a whole number of blocks
+		{time.Hour, 7 * time.Second, 0, true},
+		// shorter than a block
+		{time.Second, 3 * time.Second, 0, true},
+		// non-positive duration or block time
+		{0, 3 * time.Second, 0, true},
+		{time.Hour, 0, 0, true},
+		{-time.Hour, 3 * time.Second, 0, true},
+		// too many blocks
+		{24 * time.Hour, time.Second, 0, true},
+	}
+
+	for _, tt := range tests {
+		blocks, err := NewEpochDuration(tt.duration, tt.expectedBlockTime).Blocks()
+		if tt.wantErr {
+			require.Error(t, err, "%s / %s", tt.duration, tt.expectedBlockTime)
+			continue
+		}
+		require.NoError(t, err, "%s / %s", tt.duration, tt.expectedBlockTime)
+		require.Equal(t, tt.blocks, blocks)
+	}
+}
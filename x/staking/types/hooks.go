@@ -55,3 +55,10 @@ func (h MultiStakingHooks) AfterValidatorDestroyed(ctx sdk.Context, consAddr sdk
 		h[i].AfterValidatorDestroyed(ctx, consAddr, valAddr)
 	}
 }
+
+// BeforeDelegationRemoved handles the hooks before the delegation was removed
+func (h MultiStakingHooks) BeforeDelegationRemoved(ctx sdk.Context, delAddr sdk.AccAddress, valAddr sdk.ValAddress) {
+	for i := range h {
+		h[i].BeforeDelegationRemoved(ctx, delAddr, valAddr)
+	}
+}
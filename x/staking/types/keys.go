@@ -3,9 +3,11 @@ package types
 import (
 	"encoding/binary"
 	"fmt"
+	"strings"
 	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/crypto/tmhash"
 )
 
 const (
@@ -23,6 +25,10 @@ const (
 
 	// RouterKey is the msg router key for the staking module
 	RouterKey = ModuleName
+
+	// StoreVersion is the version of the staking store written by this software. The stores written by the earlier
+	// software are in version 0 and upgraded by Keeper.MigrateStore
+	StoreVersion uint64 = 1
 )
 
 //nolint
@@ -32,9 +38,25 @@ var (
 	LastValidatorPowerKey = []byte{0x11} // prefix for each key to a validator index, for bonded validators
 	LastTotalPowerKey     = []byte{0x12} // prefix for the total power
 
+	EpochNumberKey = []byte{0x13} // key for the number of the current epoch
+	// key for the unbonding time scheduled to take effect at the end of the current epoch
+	PendingUnbondingTimeKey = []byte{0x14}
+	// prefix for the total bonded tokens snapshotted at the end of each epoch
+	BondedHistoryKey = []byte{0x15}
+	// key for the max validators scheduled to take effect at the end of the current epoch
+	PendingMaxValidatorsKey = []byte{0x16}
+	// key for the delegations deferred to the end of the current epoch by the epoch boundary grace
+	PendingDelegationsKey = []byte{0x17}
+	// key for the version of the staking store, which tells the store migrations already run
+	StoreVersionKey = []byte{0x18}
+	// key for the bond denom weights which the tokens of the delegators are converted by
+	AppliedBondDenomsKey = []byte{0x19}
+
 	ValidatorsKey             = []byte{0x21} // prefix for each key to a validator
 	ValidatorsByConsAddrKey   = []byte{0x22} // prefix for each key to a validator index, by pubkey
 	ValidatorsByPowerIndexKey = []byte{0x23} // prefix for each key to a validator index, sorted by power
+	ValidatorsByMonikerKey    = []byte{0x24} // prefix for each key to a validator index, by lower-cased moniker
+	ValidatorsByIdentityKey   = []byte{0x25} // prefix for each key to a validator index, by hashed identity
 
 	ValidatorQueueKey = []byte{0x43} // prefix for the timestamps in validator queue
 
@@ -43,10 +65,26 @@ var (
 	UnDelegationInfoKey = []byte{0x53}
 	UnDelegateQueueKey  = []byte{0x54}
 	ProxyKey            = []byte{0x55}
+	DelegatorCountKey   = []byte{0x56} // key for the total number of delegators
+	// prefix for the index of the undelegations by the validators that they withdrew votes from
+	UnDelegationByValIndexKey = []byte{0x57}
+	// prefix for the time of the last self-undelegation of each validator
+	LastSelfUndelegationKey = []byte{0x58}
+	// prefix for the staking actions of each delegator, ordered by the sequence of the actions
+	DelegatorHistoryKey = []byte{0x59}
+	// key for the total votes of all the validators, kept along with the validators
+	TotalVotesKey = []byte{0x5A}
 
 	// prefix key for vals info to enforce the update of validator-set
 	ValidatorAbandonedKey = []byte{0x60}
 
+	// key for the params cached in the transient store within a block
+	ParamsCacheKey = []byte{0x70}
+	// prefix for the validators whose power changed within a block, kept in the transient store
+	PowerChangedKey = []byte{0x71}
+	// key for the flag to recompute the validator set at the end of the current block, kept in the transient store
+	ValidatorSetRecomputeKey = []byte{0x72}
+
 	lenTime = len(sdk.FormatTimeBytes(time.Now()))
 )
 
@@ -62,17 +100,50 @@ func GetValidatorByConsAddrKey(addr sdk.ConsAddress) []byte {
 	return append(ValidatorsByConsAddrKey, addr.Bytes()...)
 }
 
+// GetValidatorByMonikerKey gets the key for the validator with moniker, which is case-insensitive
+// VALUE: validator operator address ([]byte)
+func GetValidatorByMonikerKey(moniker string) []byte {
+	return append(ValidatorsByMonikerKey, []byte(strings.ToLower(moniker))...)
+}
+
+// GetValidatorsByIdentityKey gets the prefix of the keys for the validators sharing an identity. The identity is
+// hashed, so that the keys are in a fixed length no matter how long the identity is
+func GetValidatorsByIdentityKey(identity string) []byte {
+	return append(ValidatorsByIdentityKey, tmhash.Sum([]byte(identity))...)
+}
+
+// GetValidatorByIdentityKey gets the key for the validator with identity
+// VALUE: none (key rearrangement used)
+func GetValidatorByIdentityKey(identity string, valAddr sdk.ValAddress) []byte {
+	return append(GetValidatorsByIdentityKey(identity), valAddr.Bytes()...)
+}
+
 // AddressFromLastValidatorPowerKey gets the validator operator address from LastValidatorPowerKey
 func AddressFromLastValidatorPowerKey(key []byte) []byte {
 	return key[1:] // remove prefix bytes
 }
 
-// GetValidatorsByPowerIndexKey gets the validator by power index
+// GetValidatorsByPowerIndexKey gets the validator by power index with the default tie-break by address
 // Power index is the key used in the power-store, and represents the relative power ranking of the validator
 // VALUE: validator operator address ([]byte)
-func GetValidatorsByPowerIndexKey(validator Validator) []byte {
+func GetValidatorsByPowerIndexKey(validator Validator, powerReduction sdk.Int) []byte {
+	return GetValidatorsByPowerIndexKeyWithTieBreak(validator, powerReduction, TieBreakByAddress)
+}
+
+// GetValidatorsByPowerIndexKeyWithTieBreak gets the validator by power index, where the validators with equal power
+// are ordered by the tie-break rule
+func GetValidatorsByPowerIndexKeyWithTieBreak(validator Validator, powerReduction sdk.Int, tieBreak string) []byte {
 	// NOTE the address doesn't need to be stored because counter bytes must always be different
-	return getValidatorPowerRank(validator)
+	key := getValidatorPowerRank(validator, powerReduction)
+	if tieBreak != TieBreakOldestFirst {
+		return key
+	}
+
+	// key is of format prefix || powerbytes || inverted bond height || addrBytes, so that the older validator comes
+	// first in the reverse iteration
+	bondHeightBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(bondHeightBytes, ^uint64(validator.BondHeight))
+	return append(key[:9:9], append(bondHeightBytes, key[9:]...)...)
 }
 
 // GetLastValidatorPowerKey gets the bonded validator index key for an operator address
@@ -88,9 +159,9 @@ func GetValidatorQueueTimeKey(timestamp time.Time) []byte {
 
 // getValidatorPowerRank gets the power ranking of a validator by okchain's rule
 // just according to the votes instead of tokens on validator
-func getValidatorPowerRank(validator Validator) []byte {
+func getValidatorPowerRank(validator Validator, powerReduction sdk.Int) []byte {
 	// consensus power based on votes on validator
-	consensusPower := votesToConsensusPower(validator.DelegatorShares)
+	consensusPower := VotesToConsensusPower(validator.DelegatorShares, powerReduction)
 	consensusPowerBytes := make([]byte, 8)
 	binary.BigEndian.PutUint64(consensusPowerBytes[:], uint64(consensusPower))
 
@@ -136,6 +207,33 @@ func GetUndelegationInfoKey(delAddr sdk.AccAddress) []byte {
 	return append(UnDelegationInfoKey, delAddr.Bytes()...)
 }
 
+// GetUndelegationsByValKey gets the prefix for the undelegations which withdrew votes from a validator
+func GetUndelegationsByValKey(valAddr sdk.ValAddress) []byte {
+	return append(UnDelegationByValIndexKey, valAddr.Bytes()...)
+}
+
+// GetUndelegationByValIndexKey gets the key for the index of an undelegation by a validator
+func GetUndelegationByValIndexKey(valAddr sdk.ValAddress, delAddr sdk.AccAddress) []byte {
+	return append(GetUndelegationsByValKey(valAddr), delAddr.Bytes()...)
+}
+
+// GetLastSelfUndelegationKey gets the key for the time of the last self-undelegation of a validator
+// VALUE: time.Time
+func GetLastSelfUndelegationKey(valAddr sdk.ValAddress) []byte {
+	return append(LastSelfUndelegationKey, valAddr.Bytes()...)
+}
+
+// GetDelegatorHistoryKey gets the prefix for all the staking actions of a delegator
+func GetDelegatorHistoryKey(delAddr sdk.AccAddress) []byte {
+	return append(DelegatorHistoryKey, delAddr.Bytes()...)
+}
+
+// GetDelegatorActionKey gets the key for a staking action of a delegator by its sequence
+// VALUE: staking/DelegatorAction
+func GetDelegatorActionKey(delAddr sdk.AccAddress, sequence uint64) []byte {
+	return append(GetDelegatorHistoryKey(delAddr), sdk.Uint64ToBigEndian(sequence)...)
+}
+
 // GetCompleteTimeKey get the key for the preflix of time
 func GetCompleteTimeKey(timestamp time.Time) []byte {
 	bz := sdk.FormatTimeBytes(timestamp)
@@ -159,3 +257,15 @@ func SplitCompleteTimeWithAddrKey(key []byte) (time.Time, sdk.AccAddress) {
 	delAddr := sdk.AccAddress(key[1+lenTime:])
 	return endTime, delAddr
 }
+
+// GetPowerChangedKey gets the key of a validator whose power changed within a block
+// VALUE: none (key rearrangement used)
+func GetPowerChangedKey(valAddr sdk.ValAddress) []byte {
+	return append(PowerChangedKey, valAddr.Bytes()...)
+}
+
+// GetBondedSnapshotKey gets the key of the bonded snapshot of an epoch, ordered by the epoch number
+// VALUE: staking/BondedSnapshot
+func GetBondedSnapshotKey(epochNumber uint64) []byte {
+	return append(BondedHistoryKey, sdk.Uint64ToBigEndian(epochNumber)...)
+}
@@ -1,8 +1,10 @@
 package types
 
 import (
+	"bytes"
 	"encoding/hex"
 	"github.com/tendermint/tendermint/crypto/ed25519"
+	"sort"
 	"testing"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -34,8 +36,39 @@ func TestGetValidatorPowerRank(t *testing.T) {
 		{val4, "2300000000000100009c288ede7df62742fc3b7d0962045a8cef0f79f6"},
 	}
 	for i, tt := range tests {
-		got := hex.EncodeToString(getValidatorPowerRank(tt.validator))
+		got := hex.EncodeToString(getValidatorPowerRank(tt.validator, DefaultPowerReduction))
 
 		assert.Equal(t, tt.wantHex, got, "Keys did not match on test case %d", i)
 	}
 }
+
+func TestValidatorsByPowerIndexKeyOrder(t *testing.T) {
+	newValidator := func(addr byte, votes int64) Validator {
+		val := NewValidator(sdk.ValAddress(bytes.Repeat([]byte{addr}, sdk.AddrLen)), pk1, Description{})
+		val.DelegatorShares = sdk.NewDec(votes)
+		return val
+	}
+	// in the expected order of the reverse iteration: the higher power first, then the lower address among ties
+	vals := []Validator{
+		newValidator(0x02, 1<<32),
+		newValidator(0x01, 300),
+		newValidator(0x01, 256),
+		newValidator(0x00, 255),
+		newValidator(0x01, 255),
+		newValidator(0xff, 255),
+		newValidator(0x01, 1),
+		newValidator(0x00, 0),
+		newValidator(0x05, 0),
+	}
+
+	keys := make([][]byte, len(vals))
+	for i, val := range vals {
+		keys[i] = GetValidatorsByPowerIndexKey(val, DefaultPowerReduction)
+	}
+	assert.True(t, sort.SliceIsSorted(keys, func(i, j int) bool { return bytes.Compare(keys[i], keys[j]) > 0 }))
+
+	// the votes under one power unit don't break the order by address
+	val := newValidator(0x00, 255)
+	val.DelegatorShares = val.DelegatorShares.Add(sdk.NewDecWithPrec(5, 1))
+	assert.Equal(t, keys[3], GetValidatorsByPowerIndexKey(val, DefaultPowerReduction))
+}
@@ -129,16 +129,24 @@ func (msg MsgCreateValidator) ValidateBasic() sdk.Error {
 }
 
 // MsgEditValidator - struct for editing a validator
+// The delegation acceptance policy is only updated with the non-nil fields
 type MsgEditValidator struct {
 	Description
-	ValidatorAddress sdk.ValAddress `json:"address" yaml:"address"`
+	ValidatorAddress     sdk.ValAddress `json:"address" yaml:"address"`
+	AcceptingDelegations *bool          `json:"accepting_delegations" yaml:"accepting_delegations"`
+	MaxDelegatorCount    *uint64        `json:"max_delegator_count" yaml:"max_delegator_count"`
+	MinDelegation        *sdk.Dec       `json:"min_delegation" yaml:"min_delegation"`
 }
 
 // NewMsgEditValidator creates a msg of edit-validator
-func NewMsgEditValidator(valAddr sdk.ValAddress, description Description) MsgEditValidator {
+func NewMsgEditValidator(valAddr sdk.ValAddress, description Description, acceptingDelegations *bool,
+	maxDelegatorCount *uint64, minDelegation *sdk.Dec) MsgEditValidator {
 	return MsgEditValidator{
-		Description:      description,
-		ValidatorAddress: valAddr,
+		Description:          description,
+		ValidatorAddress:     valAddr,
+		AcceptingDelegations: acceptingDelegations,
+		MaxDelegatorCount:    maxDelegatorCount,
+		MinDelegation:        minDelegation,
 	}
 }
 
@@ -161,9 +169,14 @@ func (msg MsgEditValidator) ValidateBasic() sdk.Error {
 		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "nil validator address")
 	}
 
-	if msg.Description == (Description{}) {
+	if msg.Description == (Description{}) && msg.AcceptingDelegations == nil && msg.MaxDelegatorCount == nil &&
+		msg.MinDelegation == nil {
 		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "transaction must include some information to modify")
 	}
 
+	if msg.MinDelegation != nil && (msg.MinDelegation.IsNil() || msg.MinDelegation.IsNegative()) {
+		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "min delegation must not be negative")
+	}
+
 	return nil
 }
@@ -119,7 +119,7 @@ func TestMsgEditValidator(t *testing.T) {
 
 	for _, tc := range tests {
 		description := NewDescription(tc.moniker, tc.identity, tc.website, tc.details)
-		msg := NewMsgEditValidator(tc.validatorAddr, description)
+		msg := NewMsgEditValidator(tc.validatorAddr, description, nil, nil, nil)
 		if tc.expectPass {
 			require.Nil(t, msg.ValidateBasic(), "test: %v", tc.name)
 			checkMsg(t, msg, "edit_validator")
@@ -129,6 +129,22 @@ func TestMsgEditValidator(t *testing.T) {
 	}
 }
 
+func TestMsgEditValidatorDelegationPolicy(t *testing.T) {
+	accepting, maxDelegatorCount := false, uint64(10)
+	require.Nil(t, NewMsgEditValidator(valAddr1, Description{}, &accepting, nil, nil).ValidateBasic())
+	require.Nil(t, NewMsgEditValidator(valAddr1, Description{}, nil, &maxDelegatorCount, nil).ValidateBasic())
+	require.NotNil(t, NewMsgEditValidator(valAddr1, Description{}, nil, nil, nil).ValidateBasic())
+	minDelegation, negative := sdk.NewDec(100), sdk.NewDec(-1)
+	require.Nil(t, NewMsgEditValidator(valAddr1, Description{}, nil, nil, &minDelegation).ValidateBasic())
+	require.NotNil(t, NewMsgEditValidator(valAddr1, Description{}, nil, nil, &negative).ValidateBasic())
+
+	// the policy survives the amino json round trip
+	msg := NewMsgEditValidator(valAddr1, Description{}, &accepting, &maxDelegatorCount, &minDelegation)
+	var decoded MsgEditValidator
+	require.NoError(t, ModuleCdc.UnmarshalJSON(ModuleCdc.MustMarshalJSON(msg), &decoded))
+	require.Equal(t, msg, decoded)
+}
+
 func checkMsg(t *testing.T, msg sdk.Msg, expType string) {
 	require.Contains(t, msg.Route(), RouterKey)
 	require.Contains(t, msg.Type(), expType)
@@ -17,12 +17,29 @@ const (
 	// unbonding time.
 	// TODO: Justify our choice of default here.
 	DefaultUnbondingTime = config.DefaultUnbondingTime
+	// MaxUnbondingTime is the ceiling of the unbonding time, so that the undelegated coins are never locked too long
+	MaxUnbondingTime = time.Hour * 24 * 7 * 8
 
 	// Default maximum number of bonded validators
 	DefaultMaxValidators = config.DefaultMaxValidators
+	// MaxMaxValidators is the ceiling of MaxValidators, over which the validator set would overwhelm the consensus
+	MaxMaxValidators uint16 = 500
 
 	DefaultEpoch         uint16 = config.DefaultBlocksPerEpoch
 	DefaultMaxValsToVote uint16 = config.DefaultMaxValsToVote
+	// the amounts on okchain are already in the display units of the bond denom
+	DefaultBondDenomDecimals uint16 = 0
+
+	// DefaultUnjailMaxDepositPeriod and DefaultUnjailVotingPeriod are the periods of the UnjailValidatorProposal
+	DefaultUnjailMaxDepositPeriod = time.Hour * 24
+	DefaultUnjailVotingPeriod     = time.Hour * 72
+	// DefaultCommissionChangeWindow is the min interval between two commission rate changes of a validator
+	DefaultCommissionChangeWindow = time.Hour * config.DefaultValidateRateUpdateInterval
+
+	// TieBreakByAddress orders the validators with equal power by their operator addresses
+	TieBreakByAddress = "address"
+	// TieBreakOldestFirst orders the validators with equal power by their bond heights, the oldest first
+	TieBreakOldestFirst = "oldest-first"
 )
 
 var (
@@ -30,6 +47,12 @@ var (
 	DefaultMinSelfDelegationLimit = config.DefaultMinSelfDelegationLimit
 	// DefaultMinDelegation is the limit value of delegation or undelegation
 	DefaultMinDelegation = config.DefaultMinDelegation
+	// DefaultPowerReduction is the amount of votes required for 1 unit of consensus-engine power
+	DefaultPowerReduction = sdk.PowerReduction
+	// DefaultPowerAlertThreshold is the fraction of the last total power to alert a large power change of a validator
+	DefaultPowerAlertThreshold = sdk.NewDecWithPrec(1, 1)
+	// DefaultUnjailMinDeposit is the min deposit of the UnjailValidatorProposal
+	DefaultUnjailMinDeposit = sdk.DecCoins{sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(100))}
 )
 
 // nolint - Keys for parameter access
@@ -43,6 +66,27 @@ var (
 	KeyMaxValsToVote          = []byte("MaxValsToVote")
 	KeyMinSelfDelegationLimit = []byte("MinSelfDelegationLimit")
 	KeyMinDelegation          = []byte("MinDelegation")
+	KeyEnforceUniqueMoniker   = []byte("EnforceUniqueMoniker")
+	KeyPowerReduction         = []byte("PowerReduction")
+	KeyBondDenoms             = []byte("BondDenoms")
+	KeyPowerAlertThreshold    = []byte("PowerAlertThreshold")
+	KeyMaxDelegations         = []byte("MaxDelegations")
+	KeyPowerTieBreak          = []byte("PowerTieBreak")
+	KeyBondDenomDecimals      = []byte("BondDenomDecimals")
+	KeySelfDelegationOnly     = []byte("SelfDelegationOnly")
+	KeyBondDenomMigration     = []byte("BondDenomMigration")
+	KeyMinValidators          = []byte("MinValidators")
+	KeyUnjailMaxDepositPeriod = []byte("UnjailMaxDepositPeriod")
+	KeyUnjailMinDeposit       = []byte("UnjailMinDeposit")
+	KeyUnjailVotingPeriod     = []byte("UnjailVotingPeriod")
+	KeyCommissionChangeWindow = []byte("CommissionChangeWindow")
+	KeyMaxValidatorVotes      = []byte("MaxValidatorVotes")
+
+	KeyValidatorSelfUndelegateCooldown = []byte("ValidatorSelfUndelegateCooldown")
+	KeyMaxValidatorVoteShare           = []byte("MaxValidatorVoteShare")
+	KeyMinDelegationTolerance          = []byte("MinDelegationTolerance")
+	KeyRecordDelegatorHistory          = []byte("RecordDelegatorHistory")
+	KeyEpochBoundaryGrace              = []byte("EpochBoundaryGrace")
 )
 
 var _ params.ParamSet = (*Params)(nil)
@@ -58,16 +102,64 @@ type Params struct {
 	Epoch         uint16 `json:"epoch" yaml:"epoch"`
 	MaxValsToVote uint16 `json:"max_validators_to_vote" yaml:"max_validators_to_vote"`
 	// bondable coin denomination
+	// Deprecated: it's kept as an alias of the primary denom of BondDenoms
 	BondDenom string `json:"bond_denom" yaml:"bond_denom"`
 	// limited amount of the msd
 	MinSelfDelegationLimit sdk.Dec `json:"min_self_delegation" yaml:"min_self_delegation"`
 	//limited amount of delegate
 	MinDelegation sdk.Dec `json:"min_delegation" yaml:"min_delegation"`
+	// whether the moniker of each validator must be unique (case-insensitive)
+	EnforceUniqueMoniker bool `json:"enforce_unique_moniker" yaml:"enforce_unique_moniker"`
+	// amount of votes for 1 unit of consensus power, only takes effect after the current epoch ends
+	PowerReduction sdk.Int `json:"power_reduction" yaml:"power_reduction"`
+	// bondable coin denominations with the weights, the first one is the primary
+	BondDenoms WeightedDenoms `json:"bond_denoms" yaml:"bond_denoms"`
+	// fraction of the last total power, over which a power change of a validator is alerted. zero disables it
+	PowerAlertThreshold sdk.Dec `json:"power_alert_threshold" yaml:"power_alert_threshold"`
+	// maximum number of delegators on the chain. zero means no limit
+	MaxDelegations uint64 `json:"max_delegations" yaml:"max_delegations"`
+	// rule to order the validators with equal power, only takes effect after the current epoch ends
+	PowerTieBreak string `json:"power_tie_break" yaml:"power_tie_break"`
+	// decimals of the bond denom, by which the amounts are scaled down to the display units for the clients
+	BondDenomDecimals uint16 `json:"bond_denom_decimals" yaml:"bond_denom_decimals"`
+	// whether the validators are only able to be voted by their operators, for the permissioned setups
+	SelfDelegationOnly bool `json:"self_delegation_only" yaml:"self_delegation_only"`
+	// whether the primary bond denom is allowed to be changed while there are tokens bonded, for a deliberate migration
+	BondDenomMigration bool `json:"bond_denom_migration" yaml:"bond_denom_migration"`
+	// minimum number of validators on the chain before any vote is accepted, for the bootstrap. zero disables it
+	MinValidators uint16 `json:"min_validators" yaml:"min_validators"`
+	// deposit and voting settings of the UnjailValidatorProposal
+	UnjailMaxDepositPeriod time.Duration `json:"unjail_max_deposit_period" yaml:"unjail_max_deposit_period"`
+	UnjailMinDeposit       sdk.DecCoins  `json:"unjail_min_deposit" yaml:"unjail_min_deposit"`
+	UnjailVotingPeriod     time.Duration `json:"unjail_voting_period" yaml:"unjail_voting_period"`
+	// min interval between two commission rate changes of a validator
+	CommissionChangeWindow time.Duration `json:"commission_change_window" yaml:"commission_change_window"`
+	// maximum amount of the votes held by a single validator. zero means no limit
+	MaxValidatorVotes sdk.Int `json:"max_validator_votes" yaml:"max_validator_votes"`
+	// min interval between two undelegations of a validator operator. zero disables it
+	ValidatorSelfUndelegateCooldown time.Duration `json:"validator_self_undelegate_cooldown" yaml:"validator_self_undelegate_cooldown"`
+	// maximum fraction of the total votes of all the validators held by a single validator. zero means no limit
+	MaxValidatorVoteShare sdk.Dec `json:"max_validator_vote_share" yaml:"max_validator_vote_share"`
+	// amount of tokens by which a delegation or undelegation is allowed to fall short of MinDelegation, for the amounts
+	// rounded down on the conversion from the display units. zero means the check is exact
+	MinDelegationTolerance sdk.Dec `json:"min_delegation_tolerance" yaml:"min_delegation_tolerance"`
+	// whether the staking actions of each delegator are logged for the queries of the delegator history
+	RecordDelegatorHistory bool `json:"record_delegator_history" yaml:"record_delegator_history"`
+	// number of the last blocks of an epoch, within which the delegations are deferred to the end of the epoch. zero
+	// disables it
+	EpochBoundaryGrace uint16 `json:"epoch_boundary_grace" yaml:"epoch_boundary_grace"`
 }
 
 // NewParams creates a new Params instance
 func NewParams(unbondingTime time.Duration, maxValidators uint16, bondDenom string, epoch uint16, maxValsToVote uint16,
-	minSelfDelegationLimited sdk.Dec, minDelegation sdk.Dec) Params {
+	minSelfDelegationLimited sdk.Dec, minDelegation sdk.Dec, enforceUniqueMoniker bool, powerReduction sdk.Int,
+	bondDenoms WeightedDenoms, powerAlertThreshold sdk.Dec, maxDelegations uint64, powerTieBreak string,
+	bondDenomDecimals uint16, selfDelegationOnly bool, bondDenomMigration bool, minValidators uint16,
+	unjailMaxDepositPeriod time.Duration, unjailMinDeposit sdk.DecCoins, unjailVotingPeriod time.Duration,
+	commissionChangeWindow time.Duration, maxValidatorVotes sdk.Int, validatorSelfUndelegateCooldown time.Duration,
+	maxValidatorVoteShare sdk.Dec, minDelegationTolerance sdk.Dec, recordDelegatorHistory bool,
+	epochBoundaryGrace uint16,
+) Params {
 
 	return Params{
 		UnbondingTime:          unbondingTime,
@@ -77,6 +169,27 @@ func NewParams(unbondingTime time.Duration, maxValidators uint16, bondDenom stri
 		MaxValsToVote:          maxValsToVote,
 		MinSelfDelegationLimit: minSelfDelegationLimited,
 		MinDelegation:          minDelegation,
+		EnforceUniqueMoniker:   enforceUniqueMoniker,
+		PowerReduction:         powerReduction,
+		BondDenoms:             bondDenoms,
+		PowerAlertThreshold:    powerAlertThreshold,
+		MaxDelegations:         maxDelegations,
+		PowerTieBreak:          powerTieBreak,
+		BondDenomDecimals:      bondDenomDecimals,
+		SelfDelegationOnly:     selfDelegationOnly,
+		BondDenomMigration:     bondDenomMigration,
+		MinValidators:          minValidators,
+		UnjailMaxDepositPeriod: unjailMaxDepositPeriod,
+		UnjailMinDeposit:       unjailMinDeposit,
+		UnjailVotingPeriod:     unjailVotingPeriod,
+		CommissionChangeWindow: commissionChangeWindow,
+		MaxValidatorVotes:      maxValidatorVotes,
+
+		ValidatorSelfUndelegateCooldown: validatorSelfUndelegateCooldown,
+		MaxValidatorVoteShare:           maxValidatorVoteShare,
+		MinDelegationTolerance:          minDelegationTolerance,
+		RecordDelegatorHistory:          recordDelegatorHistory,
+		EpochBoundaryGrace:              epochBoundaryGrace,
 	}
 }
 
@@ -90,6 +203,26 @@ func (p *Params) ParamSetPairs() params.ParamSetPairs {
 		{Key: KeyMaxValsToVote, Value: &p.MaxValsToVote},
 		{Key: KeyMinSelfDelegationLimit, Value: &p.MinSelfDelegationLimit},
 		{Key: KeyMinDelegation, Value: &p.MinDelegation},
+		{Key: KeyEnforceUniqueMoniker, Value: &p.EnforceUniqueMoniker},
+		{Key: KeyPowerReduction, Value: &p.PowerReduction},
+		{Key: KeyBondDenoms, Value: &p.BondDenoms},
+		{Key: KeyPowerAlertThreshold, Value: &p.PowerAlertThreshold},
+		{Key: KeyMaxDelegations, Value: &p.MaxDelegations},
+		{Key: KeyPowerTieBreak, Value: &p.PowerTieBreak},
+		{Key: KeyBondDenomDecimals, Value: &p.BondDenomDecimals},
+		{Key: KeySelfDelegationOnly, Value: &p.SelfDelegationOnly},
+		{Key: KeyBondDenomMigration, Value: &p.BondDenomMigration},
+		{Key: KeyMinValidators, Value: &p.MinValidators},
+		{Key: KeyUnjailMaxDepositPeriod, Value: &p.UnjailMaxDepositPeriod},
+		{Key: KeyUnjailMinDeposit, Value: &p.UnjailMinDeposit},
+		{Key: KeyUnjailVotingPeriod, Value: &p.UnjailVotingPeriod},
+		{Key: KeyCommissionChangeWindow, Value: &p.CommissionChangeWindow},
+		{Key: KeyMaxValidatorVotes, Value: &p.MaxValidatorVotes},
+		{Key: KeyValidatorSelfUndelegateCooldown, Value: &p.ValidatorSelfUndelegateCooldown},
+		{Key: KeyMaxValidatorVoteShare, Value: &p.MaxValidatorVoteShare},
+		{Key: KeyMinDelegationTolerance, Value: &p.MinDelegationTolerance},
+		{Key: KeyRecordDelegatorHistory, Value: &p.RecordDelegatorHistory},
+		{Key: KeyEpochBoundaryGrace, Value: &p.EpochBoundaryGrace},
 	}
 }
 
@@ -105,7 +238,11 @@ func (p Params) Equal(p2 Params) bool {
 func DefaultParams() Params {
 	return NewParams(DefaultUnbondingTime, DefaultMaxValidators,
 		sdk.DefaultBondDenom, DefaultEpoch, DefaultMaxValsToVote,
-		DefaultMinSelfDelegationLimit, DefaultMinDelegation)
+		DefaultMinSelfDelegationLimit, DefaultMinDelegation, false, DefaultPowerReduction,
+		WeightedDenoms{NewWeightedDenom(sdk.DefaultBondDenom, sdk.OneDec())}, DefaultPowerAlertThreshold, 0, TieBreakByAddress,
+		DefaultBondDenomDecimals, false, false, 0,
+		DefaultUnjailMaxDepositPeriod, DefaultUnjailMinDeposit, DefaultUnjailVotingPeriod,
+		DefaultCommissionChangeWindow, sdk.ZeroInt(), 0, sdk.ZeroDec(), sdk.ZeroDec(), false, 0)
 }
 
 // String returns a human readable string representation of the Params
@@ -117,8 +254,34 @@ func (p Params) String() string {
   Bonded Coin Denom: 		%s
   MaxValsToVote:     		%d
   MinSelfDelegationLimited  %d
-  MinDelegation				%d`, p.UnbondingTime,
-		p.MaxValidators, p.Epoch, p.BondDenom, p.MaxValsToVote, p.MinSelfDelegationLimit, p.MinDelegation)
+  MinDelegation				%d
+  EnforceUniqueMoniker		%t
+  PowerReduction			%s
+  Bonded Coin Denoms		%s
+  PowerAlertThreshold		%s
+  MaxDelegations			%d
+  PowerTieBreak				%s
+  BondDenomDecimals			%d
+  SelfDelegationOnly		%v
+  BondDenomMigration		%v
+  MinValidators				%d
+  UnjailMaxDepositPeriod	%s
+  UnjailMinDeposit			%s
+  UnjailVotingPeriod		%s
+  CommissionChangeWindow	%s
+  MaxValidatorVotes		%s
+  ValidatorSelfUndelegateCooldown	%s
+  MaxValidatorVoteShare		%s
+  MinDelegationTolerance	%s
+  RecordDelegatorHistory	%v
+  EpochBoundaryGrace		%d`, p.UnbondingTime,
+		p.MaxValidators, p.Epoch, p.BondDenom, p.MaxValsToVote, p.MinSelfDelegationLimit, p.MinDelegation,
+		p.EnforceUniqueMoniker, p.PowerReduction, p.BondDenoms, p.PowerAlertThreshold,
+		p.MaxDelegations, p.PowerTieBreak, p.BondDenomDecimals, p.SelfDelegationOnly,
+		p.BondDenomMigration, p.MinValidators, p.UnjailMaxDepositPeriod, p.UnjailMinDeposit, p.UnjailVotingPeriod,
+		p.CommissionChangeWindow, p.MaxValidatorVotes, p.ValidatorSelfUndelegateCooldown,
+		p.MaxValidatorVoteShare, p.MinDelegationTolerance, p.RecordDelegatorHistory,
+		p.EpochBoundaryGrace)
 }
 
 // Validate gives a quick validity check for a set of params
@@ -126,8 +289,9 @@ func (p Params) Validate() error {
 	if p.BondDenom == "" {
 		return fmt.Errorf("staking parameter BondDenom can't be an empty string")
 	}
-	if p.MaxValidators == 0 {
-		return fmt.Errorf("staking parameter MaxValidators must be a positive integer")
+	if p.MaxValidators == 0 || p.MaxValidators > MaxMaxValidators {
+		return fmt.Errorf("staking parameter MaxValidators must be a positive integer no more than %d",
+			MaxMaxValidators)
 	}
 	if p.Epoch == 0 {
 		return fmt.Errorf("staking parameter Epoch must be a positive integer")
@@ -138,5 +302,56 @@ func (p Params) Validate() error {
 	if p.MinSelfDelegationLimit.LTE(sdk.ZeroDec()) {
 		return fmt.Errorf("staking parameter MinSelfDelegationLimit cannot be a negative integer")
 	}
+	if p.PowerReduction == (sdk.Int{}) || !p.PowerReduction.IsPositive() {
+		return fmt.Errorf("staking parameter PowerReduction must be a positive integer")
+	}
+	if err := p.BondDenoms.Validate(); err != nil {
+		return fmt.Errorf("staking parameter BondDenoms is invalid: %s", err)
+	}
+	if p.BondDenoms.Primary() != p.BondDenom {
+		return fmt.Errorf("staking parameter BondDenom must be the primary denom of BondDenoms")
+	}
+	if p.PowerAlertThreshold.IsNil() || p.PowerAlertThreshold.IsNegative() || p.PowerAlertThreshold.GT(sdk.OneDec()) {
+		return fmt.Errorf("staking parameter PowerAlertThreshold must be in [0, 1]")
+	}
+	if p.PowerTieBreak != TieBreakByAddress && p.PowerTieBreak != TieBreakOldestFirst {
+		return fmt.Errorf("staking parameter PowerTieBreak must be either %s or %s", TieBreakByAddress,
+			TieBreakOldestFirst)
+	}
+	if p.BondDenomDecimals > sdk.Precision {
+		return fmt.Errorf("staking parameter BondDenomDecimals must be no more than %d", sdk.Precision)
+	}
+	if p.MinValidators > p.MaxValidators {
+		return fmt.Errorf("staking parameter MinValidators must be no more than MaxValidators %d", p.MaxValidators)
+	}
+	if !p.UnjailMinDeposit.IsValid() {
+		return fmt.Errorf("staking parameter UnjailMinDeposit is invalid: %s", p.UnjailMinDeposit)
+	}
+	if p.MaxValidatorVotes == (sdk.Int{}) || p.MaxValidatorVotes.IsNegative() {
+		return fmt.Errorf("staking parameter MaxValidatorVotes must be a non-negative integer")
+	}
+	if p.ValidatorSelfUndelegateCooldown < 0 {
+		return fmt.Errorf("staking parameter ValidatorSelfUndelegateCooldown must not be negative")
+	}
+	if p.MaxValidatorVoteShare.IsNil() || p.MaxValidatorVoteShare.IsNegative() ||
+		p.MaxValidatorVoteShare.GT(sdk.OneDec()) {
+		return fmt.Errorf("staking parameter MaxValidatorVoteShare must be in [0, 1]")
+	}
+	// the tolerance only matters to a positive MinDelegation, which it mustn't cancel out
+	if p.MinDelegationTolerance.IsNil() || p.MinDelegationTolerance.IsNegative() ||
+		(p.MinDelegation.IsPositive() && p.MinDelegationTolerance.GTE(p.MinDelegation)) {
+		return fmt.Errorf("staking parameter MinDelegationTolerance must be non-negative and less than the positive "+
+			"MinDelegation %s", p.MinDelegation)
+	}
+	if p.UnbondingTime > MaxUnbondingTime {
+		return fmt.Errorf("staking parameter UnbondingTime must be no more than %s", MaxUnbondingTime)
+	}
+	if p.CommissionChangeWindow <= 0 || p.CommissionChangeWindow >= p.UnbondingTime {
+		return fmt.Errorf("staking parameter CommissionChangeWindow must be positive and less than UnbondingTime %s",
+			p.UnbondingTime)
+	}
+	if p.EpochBoundaryGrace >= p.Epoch {
+		return fmt.Errorf("staking parameter EpochBoundaryGrace must be less than Epoch %d", p.Epoch)
+	}
 	return nil
 }
@@ -2,6 +2,7 @@ package types
 
 import (
 	"testing"
+	"time"
 
 	"github.com/cosmos/cosmos-sdk/types"
 
@@ -30,6 +31,12 @@ func TestParams(t *testing.T) {
 	p2 = p1
 	p2.MaxValidators = 0
 	require.Error(t, p2.Validate())
+	p2.MaxValidators = MaxMaxValidators - 1
+	require.NoError(t, p2.Validate())
+	p2.MaxValidators = MaxMaxValidators
+	require.NoError(t, p2.Validate())
+	p2.MaxValidators = MaxMaxValidators + 1
+	require.Error(t, p2.Validate())
 
 	p2 = p1
 	p2.Epoch = 0
@@ -43,4 +50,122 @@ func TestParams(t *testing.T) {
 	p2.MinSelfDelegationLimit = types.ZeroDec()
 	require.Error(t, p2.Validate())
 
+	p2 = p1
+	p2.PowerReduction = types.ZeroInt()
+	require.Error(t, p2.Validate())
+
+	p2 = p1
+	p2.PowerReduction = types.Int{}
+	require.Error(t, p2.Validate())
+
+	p2 = p1
+	p2.BondDenoms = nil
+	require.Error(t, p2.Validate())
+
+	p2 = p1
+	p2.BondDenoms = WeightedDenoms{NewWeightedDenom("soup", types.OneDec())}
+	require.Error(t, p2.Validate())
+
+	p2 = p1
+	p2.PowerAlertThreshold = types.NewDecWithPrec(-1, 1)
+	require.Error(t, p2.Validate())
+
+	p2 = p1
+	p2.PowerAlertThreshold = types.NewDecWithPrec(11, 1)
+	require.Error(t, p2.Validate())
+
+	p2 = p1
+	p2.PowerAlertThreshold = types.ZeroDec()
+	require.NoError(t, p2.Validate())
+
+	p2 = p1
+	p2.BondDenomDecimals = types.Precision
+	require.NoError(t, p2.Validate())
+
+	p2 = p1
+	p2.BondDenomDecimals = types.Precision + 1
+	require.Error(t, p2.Validate())
+
+	p2 = p1
+	p2.MinValidators = p2.MaxValidators
+	require.NoError(t, p2.Validate())
+
+	p2 = p1
+	p2.MinValidators = p2.MaxValidators + 1
+	require.Error(t, p2.Validate())
+
+	for _, window := range []time.Duration{0, -time.Hour, p1.UnbondingTime} {
+		p2 = p1
+		p2.CommissionChangeWindow = window
+		require.Error(t, p2.Validate())
+	}
+	p2 = p1
+	p2.CommissionChangeWindow = time.Hour
+	require.NoError(t, p2.Validate())
+
+	p2 = p1
+	p2.MaxValidatorVotes = types.NewInt(-1)
+	require.Error(t, p2.Validate())
+	p2.MaxValidatorVotes = types.Int{}
+	require.Error(t, p2.Validate())
+	p2.MaxValidatorVotes = types.NewInt(1000000)
+	require.NoError(t, p2.Validate())
+
+	p2 = p1
+	p2.ValidatorSelfUndelegateCooldown = -time.Second
+	require.Error(t, p2.Validate())
+	p2.ValidatorSelfUndelegateCooldown = time.Hour
+	require.NoError(t, p2.Validate())
+
+	p2 = p1
+	for _, invalid := range []types.Dec{{}, types.NewDec(-1), types.NewDecWithPrec(101, 2)} {
+		p2.MaxValidatorVoteShare = invalid
+		require.Error(t, p2.Validate())
+	}
+	p2.MaxValidatorVoteShare = types.OneDec()
+	require.NoError(t, p2.Validate())
+
+	p2 = p1
+	for _, invalid := range []types.Dec{{}, types.NewDec(-1), p1.MinDelegation} {
+		p2.MinDelegationTolerance = invalid
+		require.Error(t, p2.Validate())
+	}
+	p2.MinDelegationTolerance = p1.MinDelegation.QuoInt64(2)
+	require.NoError(t, p2.Validate())
+	// any tolerance is allowed without the min delegation
+	p2.MinDelegation = types.ZeroDec()
+	require.NoError(t, p2.Validate())
+
+	p2 = p1
+	p2.EpochBoundaryGrace = p1.Epoch
+	require.Error(t, p2.Validate())
+	p2.EpochBoundaryGrace = p1.Epoch - 1
+	require.NoError(t, p2.Validate())
+}
+
+func TestWeightedDenoms(t *testing.T) {
+	wds := WeightedDenoms{NewWeightedDenom(types.DefaultBondDenom, types.OneDec()),
+		NewWeightedDenom("xxb", types.NewDecWithPrec(5, 1))}
+	require.NoError(t, wds.Validate())
+	require.Equal(t, types.DefaultBondDenom, wds.Primary())
+	require.Contains(t, wds.String(), "xxb")
+
+	weight, found := wds.Weight("xxb")
+	require.True(t, found)
+	require.True(t, weight.Equal(types.NewDecWithPrec(5, 1)))
+	_, found = wds.Weight("abc")
+	require.False(t, found)
+
+	coins := types.NewDecCoinsFromDec(types.DefaultBondDenom, types.NewDec(10)).
+		Add(types.NewDecCoinsFromDec("xxb", types.NewDec(10))).
+		Add(types.NewDecCoinsFromDec("abc", types.NewDec(10)))
+	require.True(t, wds.WeightedAmount(coins).Equal(types.NewDec(15)))
+
+	// invalid ones
+	require.Error(t, WeightedDenoms{}.Validate())
+	require.Error(t, append(wds, NewWeightedDenom("xxb", types.OneDec())).Validate())
+	require.Error(t, append(wds, NewWeightedDenom("abc", types.ZeroDec())).Validate())
+	require.Error(t, append(wds, NewWeightedDenom("abc", types.Dec{})).Validate())
+	require.Error(t, append(wds, NewWeightedDenom("A B", types.OneDec())).Validate())
+	require.Error(t, WeightedDenoms{wds[1]}.Validate())
 }
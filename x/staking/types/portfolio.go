@@ -0,0 +1,118 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// VoteToValidator is the struct of the votes made by a delegator to a validator
+type VoteToValidator struct {
+	ValidatorAddress sdk.ValAddress `json:"validator_address" yaml:"validator_address"`
+	Votes            sdk.Dec        `json:"votes" yaml:"votes"`
+}
+
+// NewVoteToValidator creates a new instance of VoteToValidator
+func NewVoteToValidator(valAddr sdk.ValAddress, votes Votes) VoteToValidator {
+	return VoteToValidator{
+		ValidatorAddress: valAddr,
+		Votes:            votes,
+	}
+}
+
+// EffectiveVotes is the struct of the votes of a delegator split by the param MaxValsToVote. Only the votes to the
+// first MaxValsToVote validators in the order voted are counted, and the rest are in excess of the cap, which happens
+// when the param is lowered after the delegator voted
+type EffectiveVotes struct {
+	DelegatorAddress sdk.AccAddress    `json:"delegator_address" yaml:"delegator_address"`
+	MaxValsToVote    uint16            `json:"max_vals_to_vote" yaml:"max_vals_to_vote"`
+	Counted          []VoteToValidator `json:"counted" yaml:"counted"`
+	Excess           []VoteToValidator `json:"excess" yaml:"excess"`
+}
+
+// NewEffectiveVotes creates a new instance of EffectiveVotes by splitting the votes at the cap
+func NewEffectiveVotes(delAddr sdk.AccAddress, maxValsToVote uint16, votes []VoteToValidator) EffectiveVotes {
+	cut := int(maxValsToVote)
+	if cut > len(votes) {
+		cut = len(votes)
+	}
+	return EffectiveVotes{
+		DelegatorAddress: delAddr,
+		MaxValsToVote:    maxValsToVote,
+		Counted:          votes[:cut],
+		Excess:           votes[cut:],
+	}
+}
+
+// DelegatorVotePower is the struct of the votes that a delegator is able to make by its bonded tokens at the current
+// block time, of which the committed ones are what it voted last time and the available ones are the remainder
+type DelegatorVotePower struct {
+	DelegatorAddress sdk.AccAddress `json:"delegator_address" yaml:"delegator_address"`
+	TotalShares      sdk.Dec        `json:"total_shares" yaml:"total_shares"`
+	CommittedShares  sdk.Dec        `json:"committed_shares" yaml:"committed_shares"`
+	AvailableShares  sdk.Dec        `json:"available_shares" yaml:"available_shares"`
+}
+
+// NewDelegatorVotePower creates a new instance of DelegatorVotePower
+func NewDelegatorVotePower(delAddr sdk.AccAddress, totalShares, committedShares sdk.Dec) DelegatorVotePower {
+	availableShares := totalShares.Sub(committedShares)
+	if availableShares.IsNegative() {
+		availableShares = sdk.ZeroDec()
+	}
+	return DelegatorVotePower{
+		DelegatorAddress: delAddr,
+		TotalShares:      totalShares,
+		CommittedShares:  committedShares,
+		AvailableShares:  availableShares,
+	}
+}
+
+// DelegatorPortfolio is the struct of all the staking info of a delegator for querying in one call
+// NOTE: there are neither redelegations nor rewards of delegators in okchain's staking
+type DelegatorPortfolio struct {
+	DelegatorAddress     sdk.AccAddress    `json:"delegator_address" yaml:"delegator_address"`
+	Tokens               sdk.Dec           `json:"tokens" yaml:"tokens"`
+	Shares               sdk.Dec           `json:"shares" yaml:"shares"`
+	Votes                []VoteToValidator `json:"votes" yaml:"votes"`
+	IsProxy              bool              `json:"is_proxy" yaml:"is_proxy"`
+	TotalDelegatedTokens sdk.Dec           `json:"total_delegated_tokens" yaml:"total_delegated_tokens"`
+	ProxyAddress         sdk.AccAddress    `json:"proxy_address" yaml:"proxy_address"`
+	Undelegation         UndelegationInfo  `json:"undelegation" yaml:"undelegation"`
+}
+
+// NewDelegatorPortfolio creates a new instance of DelegatorPortfolio
+func NewDelegatorPortfolio(delegator Delegator, votes []VoteToValidator, undelegation UndelegationInfo,
+) DelegatorPortfolio {
+	return DelegatorPortfolio{
+		DelegatorAddress:     delegator.DelegatorAddress,
+		Tokens:               delegator.Tokens,
+		Shares:               delegator.Shares,
+		Votes:                votes,
+		IsProxy:              delegator.IsProxy,
+		TotalDelegatedTokens: delegator.TotalDelegatedTokens,
+		ProxyAddress:         delegator.ProxyAddress,
+		Undelegation:         undelegation,
+	}
+}
+
+// String returns a human readable string representation of DelegatorPortfolio
+func (dp DelegatorPortfolio) String() string {
+	var votes strings.Builder
+	for _, vote := range dp.Votes {
+		votes.WriteString(fmt.Sprintf("\n		%s: %s", vote.ValidatorAddress, vote.Votes))
+	}
+
+	return fmt.Sprintf(`Delegator Portfolio:
+	DelegatorAddress:		%s
+	Tokens:					%s
+	Shares:					%s
+	Votes:					%s
+	IsProxy:				%v
+	TotalDelegatedTokens:	%s
+	ProxyAddress:			%s
+	UnbondedTokens:			%s
+	CompletionTime:			%s`,
+		dp.DelegatorAddress, dp.Tokens, dp.Shares, votes.String(), dp.IsProxy, dp.TotalDelegatedTokens,
+		dp.ProxyAddress, dp.Undelegation.Quantity, dp.Undelegation.CompletionTime)
+}
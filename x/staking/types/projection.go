@@ -0,0 +1,156 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// ProjectedValidator is the struct of a validator which would be bonded if the epoch ended now
+type ProjectedValidator struct {
+	OperatorAddress sdk.ValAddress `json:"operator_address" yaml:"operator_address"`
+	Power           int64          `json:"power" yaml:"power"`
+}
+
+// NewProjectedValidator creates a new instance of ProjectedValidator
+func NewProjectedValidator(valAddr sdk.ValAddress, power int64) ProjectedValidator {
+	return ProjectedValidator{
+		OperatorAddress: valAddr,
+		Power:           power,
+	}
+}
+
+// ProjectedValidatorSet is the struct of the validator set which would be bonded if the epoch ended at the
+// queried height, together with its delta from the current bonded set
+// NOTE: it's only a projection. The set is updated at the end of the epoch, so any vote, unbonding or jailing
+// before that will change the result
+type ProjectedValidatorSet struct {
+	ProjectedAtHeight int64                `json:"projected_at_height" yaml:"projected_at_height"`
+	EpochEndHeight    int64                `json:"epoch_end_height" yaml:"epoch_end_height"`
+	Validators        []ProjectedValidator `json:"validators" yaml:"validators"`
+	Entering          []sdk.ValAddress     `json:"entering" yaml:"entering"`
+	Leaving           []sdk.ValAddress     `json:"leaving" yaml:"leaving"`
+}
+
+// NewProjectedValidatorSet creates a new instance of ProjectedValidatorSet
+func NewProjectedValidatorSet(projectedAtHeight, epochEndHeight int64, validators []ProjectedValidator,
+	entering, leaving []sdk.ValAddress) ProjectedValidatorSet {
+	return ProjectedValidatorSet{
+		ProjectedAtHeight: projectedAtHeight,
+		EpochEndHeight:    epochEndHeight,
+		Validators:        validators,
+		Entering:          entering,
+		Leaving:           leaving,
+	}
+}
+
+// String returns a human readable string representation of ProjectedValidatorSet
+func (pvs ProjectedValidatorSet) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(`Projected Validator Set (may change before the end of the epoch):
+  Projected At Height: %d
+  Epoch End Height:    %d
+  Validators:`, pvs.ProjectedAtHeight, pvs.EpochEndHeight))
+	for _, val := range pvs.Validators {
+		sb.WriteString(fmt.Sprintf("\n    %s: %d", val.OperatorAddress, val.Power))
+	}
+	sb.WriteString(fmt.Sprintf("\n  Entering: %v\n  Leaving:  %v", pvs.Entering, pvs.Leaving))
+	return sb.String()
+}
+
+// MarginalValidator is the struct of the validator ranked at the cutoff of MaxValidators by the current votes, which
+// is the next one to drop out of the validator set, and the first candidate below the cutoff
+type MarginalValidator struct {
+	OperatorAddress  sdk.ValAddress `json:"operator_address" yaml:"operator_address"`
+	Power            int64          `json:"power" yaml:"power"`
+	CandidateAddress sdk.ValAddress `json:"candidate_address" yaml:"candidate_address"`
+	CandidatePower   int64          `json:"candidate_power" yaml:"candidate_power"`
+	PowerGap         int64          `json:"power_gap" yaml:"power_gap"`
+}
+
+// NewMarginalValidator creates a new instance of MarginalValidator. The candidate is empty with zero power if there's
+// no validator with votes below the cutoff
+func NewMarginalValidator(valAddr sdk.ValAddress, power int64, candidateAddr sdk.ValAddress,
+	candidatePower int64) MarginalValidator {
+	return MarginalValidator{
+		OperatorAddress:  valAddr,
+		Power:            power,
+		CandidateAddress: candidateAddr,
+		CandidatePower:   candidatePower,
+		PowerGap:         power - candidatePower,
+	}
+}
+
+// String returns a human readable string representation of MarginalValidator
+func (mv MarginalValidator) String() string {
+	return fmt.Sprintf(`Marginal Validator:
+  Operator Address:  %s
+  Power:             %d
+  Candidate Address: %s
+  Candidate Power:   %d
+  Power Gap:         %d`, mv.OperatorAddress, mv.Power, mv.CandidateAddress, mv.CandidatePower, mv.PowerGap)
+}
+
+// ValidatorPowerShare is the struct of a validator's power together with its percentage of the total power
+type ValidatorPowerShare struct {
+	OperatorAddress sdk.ValAddress `json:"operator_address" yaml:"operator_address"`
+	Power           int64          `json:"power" yaml:"power"`
+	Percentage      sdk.Dec        `json:"percentage" yaml:"percentage"`
+}
+
+// PowerDistribution is the struct of the distribution of the consensus power over the validators, together with the
+// Nakamoto coefficient which is the min number of validators controlling more than 1/3 of the total power
+type PowerDistribution struct {
+	TotalPower          int64                 `json:"total_power" yaml:"total_power"`
+	Validators          []ValidatorPowerShare `json:"validators" yaml:"validators"`
+	NakamotoCoefficient int64                 `json:"nakamoto_coefficient" yaml:"nakamoto_coefficient"`
+}
+
+// NewPowerDistribution creates a new instance of PowerDistribution from the powers of the validators. Both the total
+// power and the Nakamoto coefficient are zero without any validator
+func NewPowerDistribution(validators []ProjectedValidator) PowerDistribution {
+	distribution := PowerDistribution{Validators: make([]ValidatorPowerShare, 0, len(validators))}
+	powers := make([]int64, len(validators))
+	for i, val := range validators {
+		distribution.TotalPower += val.Power
+		powers[i] = val.Power
+	}
+	if distribution.TotalPower == 0 {
+		return distribution
+	}
+
+	for _, val := range validators {
+		distribution.Validators = append(distribution.Validators, ValidatorPowerShare{
+			OperatorAddress: val.OperatorAddress,
+			Power:           val.Power,
+			Percentage:      sdk.NewDec(val.Power).MulInt64(100).QuoInt64(distribution.TotalPower),
+		})
+	}
+
+	// the most powerful validators first
+	sort.Slice(powers, func(i, j int) bool { return powers[i] > powers[j] })
+	var cumulative int64
+	for _, power := range powers {
+		cumulative += power
+		distribution.NakamotoCoefficient++
+		if cumulative*3 > distribution.TotalPower {
+			break
+		}
+	}
+	return distribution
+}
+
+// String returns a human readable string representation of PowerDistribution
+func (pd PowerDistribution) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(`Power Distribution:
+  Total Power:          %d
+  Nakamoto Coefficient: %d
+  Validators:`, pd.TotalPower, pd.NakamotoCoefficient))
+	for _, val := range pd.Validators {
+		sb.WriteString(fmt.Sprintf("\n    %s: %d (%s%%)", val.OperatorAddress, val.Power, val.Percentage))
+	}
+	return sb.String()
+}
@@ -0,0 +1,48 @@
+package types
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPowerDistribution(t *testing.T) {
+	projected := func(powers ...int64) []ProjectedValidator {
+		validators := make([]ProjectedValidator, len(powers))
+		for i, power := range powers {
+			validators[i] = NewProjectedValidator(sdk.ValAddress([]byte{byte(i)}), power)
+		}
+		return validators
+	}
+	tests := []struct {
+		powers     []int64
+		totalPower int64
+		nakamoto   int64
+	}{
+		{nil, 0, 0},
+		{[]int64{100}, 100, 1},
+		{[]int64{40, 30, 20, 10}, 100, 1},
+		{[]int64{25, 25, 25, 25}, 100, 2},
+		{[]int64{10, 10, 10, 10, 10, 10, 10, 10, 10, 10}, 100, 4},
+		// exactly 1/3 isn't more than 1/3
+		{[]int64{1, 1, 1}, 3, 2},
+		// not in the order of the powers
+		{[]int64{10, 20, 30, 40, 50, 50}, 200, 2},
+	}
+	for i, tc := range tests {
+		distribution := NewPowerDistribution(projected(tc.powers...))
+		require.Equal(t, tc.totalPower, distribution.TotalPower, "test case %d", i)
+		require.Equal(t, tc.nakamoto, distribution.NakamotoCoefficient, "test case %d", i)
+		require.Equal(t, len(tc.powers), len(distribution.Validators), "test case %d", i)
+	}
+
+	// the percentages equal the powers out of the total power 100
+	distribution := NewPowerDistribution(projected(40, 30, 20, 10))
+	for _, val := range distribution.Validators {
+		require.True(t, sdk.NewDec(val.Power).Equal(val.Percentage))
+	}
+	distribution = NewPowerDistribution(projected(1, 1, 1))
+	require.Equal(t, "33.33333333", distribution.Validators[0].Percentage.String())
+	require.Contains(t, distribution.String(), "Nakamoto Coefficient: 2")
+}
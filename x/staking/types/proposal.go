@@ -0,0 +1,180 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	govtypes "github.com/okex/okchain/x/gov/types"
+)
+
+const (
+	proposalTypeUnjailValidator           = "UnjailValidator"
+	proposalTypeForceUpdateValidatorState = "ForceUpdateValidatorState"
+)
+
+func init() {
+	govtypes.RegisterProposalType(proposalTypeUnjailValidator)
+	govtypes.RegisterProposalTypeCodec(UnjailValidatorProposal{}, "okchain/staking/UnjailValidatorProposal")
+	govtypes.RegisterProposalType(proposalTypeForceUpdateValidatorState)
+	govtypes.RegisterProposalTypeCodec(ForceUpdateValidatorStateProposal{},
+		"okchain/staking/ForceUpdateValidatorStateProposal")
+}
+
+// Assert the proposals implement govtypes.Content at compile-time
+var (
+	_ govtypes.Content = (*UnjailValidatorProposal)(nil)
+	_ govtypes.Content = (*ForceUpdateValidatorStateProposal)(nil)
+)
+
+// UnjailValidatorProposal is the proposal to unjail a validator which isn't able to unjail itself, e.g. the operator
+// lost the keys, once it passes
+type UnjailValidatorProposal struct {
+	Title            string         `json:"title" yaml:"title"`
+	Description      string         `json:"description" yaml:"description"`
+	ValidatorAddress sdk.ValAddress `json:"validator_address" yaml:"validator_address"`
+}
+
+// NewUnjailValidatorProposal creates a new instance of UnjailValidatorProposal
+func NewUnjailValidatorProposal(title, description string, valAddr sdk.ValAddress) UnjailValidatorProposal {
+	return UnjailValidatorProposal{
+		Title:            title,
+		Description:      description,
+		ValidatorAddress: valAddr,
+	}
+}
+
+// GetTitle returns the title of the proposal
+func (uvp UnjailValidatorProposal) GetTitle() string {
+	return uvp.Title
+}
+
+// GetDescription returns the description of the proposal
+func (uvp UnjailValidatorProposal) GetDescription() string {
+	return uvp.Description
+}
+
+// ProposalRoute returns the route key of the proposal
+func (UnjailValidatorProposal) ProposalRoute() string {
+	return RouterKey
+}
+
+// ProposalType returns the type of the proposal
+func (UnjailValidatorProposal) ProposalType() string {
+	return proposalTypeUnjailValidator
+}
+
+// ValidateBasic gives a quick validity check of the proposal
+func (uvp UnjailValidatorProposal) ValidateBasic() sdk.Error {
+	if err := validateProposalText(uvp.Title, uvp.Description); err != nil {
+		return err
+	}
+
+	if uvp.ValidatorAddress.Empty() {
+		return ErrNilValidatorAddr(DefaultCodespace)
+	}
+
+	return nil
+}
+
+// String returns a human readable string representation of UnjailValidatorProposal
+func (uvp UnjailValidatorProposal) String() string {
+	return fmt.Sprintf(`UnjailValidatorProposal:
+  Title:               %s
+  Description:         %s
+  Type:                %s
+  Validator:           %s
+`, uvp.Title, uvp.Description, uvp.ProposalType(), uvp.ValidatorAddress)
+}
+
+// ForceUpdateValidatorStateProposal is the proposal to correct the status and the delegator shares of a validator in
+// the rare recovery of a corrupted state, e.g. the repair after a bug, once it passes
+type ForceUpdateValidatorStateProposal struct {
+	Title            string         `json:"title" yaml:"title"`
+	Description      string         `json:"description" yaml:"description"`
+	ValidatorAddress sdk.ValAddress `json:"validator_address" yaml:"validator_address"`
+	Status           sdk.BondStatus `json:"status" yaml:"status"`
+	DelegatorShares  sdk.Dec        `json:"delegator_shares" yaml:"delegator_shares"`
+}
+
+// NewForceUpdateValidatorStateProposal creates a new instance of ForceUpdateValidatorStateProposal
+func NewForceUpdateValidatorStateProposal(title, description string, valAddr sdk.ValAddress, status sdk.BondStatus,
+	delegatorShares sdk.Dec) ForceUpdateValidatorStateProposal {
+	return ForceUpdateValidatorStateProposal{
+		Title:            title,
+		Description:      description,
+		ValidatorAddress: valAddr,
+		Status:           status,
+		DelegatorShares:  delegatorShares,
+	}
+}
+
+// GetTitle returns the title of the proposal
+func (fp ForceUpdateValidatorStateProposal) GetTitle() string {
+	return fp.Title
+}
+
+// GetDescription returns the description of the proposal
+func (fp ForceUpdateValidatorStateProposal) GetDescription() string {
+	return fp.Description
+}
+
+// ProposalRoute returns the route key of the proposal
+func (ForceUpdateValidatorStateProposal) ProposalRoute() string {
+	return RouterKey
+}
+
+// ProposalType returns the type of the proposal
+func (ForceUpdateValidatorStateProposal) ProposalType() string {
+	return proposalTypeForceUpdateValidatorState
+}
+
+// ValidateBasic gives a quick validity check of the proposal
+func (fp ForceUpdateValidatorStateProposal) ValidateBasic() sdk.Error {
+	if err := validateProposalText(fp.Title, fp.Description); err != nil {
+		return err
+	}
+
+	if fp.ValidatorAddress.Empty() {
+		return ErrNilValidatorAddr(DefaultCodespace)
+	}
+	if (fp.Status != sdk.Unbonded && fp.Status != sdk.Unbonding && fp.Status != sdk.Bonded) ||
+		fp.DelegatorShares.IsNil() || fp.DelegatorShares.IsNegative() {
+		return ErrInvalidStateCorrection(DefaultCodespace, fp.Status, fp.DelegatorShares)
+	}
+
+	return nil
+}
+
+// String returns a human readable string representation of ForceUpdateValidatorStateProposal
+func (fp ForceUpdateValidatorStateProposal) String() string {
+	return fmt.Sprintf(`ForceUpdateValidatorStateProposal:
+  Title:               %s
+  Description:         %s
+  Type:                %s
+  Validator:           %s
+  Status:              %s
+  Delegator Shares:    %s
+`, fp.Title, fp.Description, fp.ProposalType(), fp.ValidatorAddress, fp.Status, fp.DelegatorShares)
+}
+
+// validateProposalText checks the title and the description shared by all the staking proposals
+func validateProposalText(title, description string) sdk.Error {
+	if len(strings.TrimSpace(title)) == 0 {
+		return govtypes.ErrInvalidProposalContent(DefaultCodespace, "proposal title cannot be blank")
+	}
+	if len(title) > govtypes.MaxTitleLength {
+		return govtypes.ErrInvalidProposalContent(DefaultCodespace,
+			fmt.Sprintf("proposal title is longer than max length of %d", govtypes.MaxTitleLength))
+	}
+
+	if len(description) == 0 {
+		return govtypes.ErrInvalidProposalContent(DefaultCodespace, "proposal description cannot be blank")
+	}
+	if len(description) > govtypes.MaxDescriptionLength {
+		return govtypes.ErrInvalidProposalContent(DefaultCodespace,
+			fmt.Sprintf("proposal description is longer than max length of %d", govtypes.MaxDescriptionLength))
+	}
+
+	return nil
+}
@@ -1,22 +1,70 @@
 package types
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/params"
 )
 
 // query endpoints supported by the staking Querier
 const (
-	QueryValidators          = "validators"
-	QueryValidator           = "validator"
-	QueryUnbondingDelegation = "unbondingDelegation"
-	QueryPool                = "pool"
-	QueryParameters          = "parameters"
-	QueryAddress             = "address"
-	QueryForAddress          = "validatorAddress"
-	QueryForAccAddress       = "validatorAccAddress"
-	QueryProxy               = "proxy"
-	QueryValidatorVotes      = "validatorVotes"
-	QueryDelegator           = "delegator"
+	QueryValidators           = "validators"
+	QueryValidator            = "validator"
+	QueryUnbondingDelegation  = "unbondingDelegation"
+	QueryPool                 = "pool"
+	QueryParameters           = "parameters"
+	QueryAddress              = "address"
+	QueryForAddress           = "validatorAddress"
+	QueryForAccAddress        = "validatorAccAddress"
+	QueryProxy                = "proxy"
+	QueryValidatorVotes       = "validatorVotes"
+	QueryDelegator            = "delegator"
+	QueryValidatorShares      = "validatorShares"
+	QueryValidatorsByAddrs    = "validatorsByAddresses"
+	QueryDelegatorPortfolio   = "delegatorPortfolio"
+	QueryDelegatorBonded      = "delegatorBonded"
+	QueryEpochInfo            = "epochInfo"
+	QueryStakingRatio         = "stakingRatio"
+	QueryDryRunParams         = "dryRunParams"
+	QueryUnvotedValidators    = "unvotedValidators"
+	QueryMinDelegationDisplay = "minDelegationDisplay"
+	QueryValidatorTenure      = "validatorTenure"
+	QueryDelegatorDelegations = "delegatorDelegations"
+	QueryValidatorsByRank     = "validatorsByRank"
+	QueryUnbondingStats       = "unbondingStats"
+	QueryMedianCommission     = "medianCommission"
+	QueryIsValidatorOperator  = "isValidatorOperator"
+	QueryPowerIndex           = "powerIndex"
+	QueryCommissionCooldown   = "commissionCooldown"
+	QueryValidatorDelegations = "validatorDelegations"
+	QueryMarginalValidator    = "marginalValidator"
+	QueryBondedHistory        = "bondedHistory"
+	QueryCanDelegate          = "canDelegate"
+	QueryValidatorsByIdentity = "validatorsByIdentity"
+	QueryCandidateValidators  = "candidateValidators"
+	QueryAddressConversion    = "addressConversion"
+	QueryDelegatorVotePower   = "delegatorVotePower"
+	QueryUnbondingValidators  = "unbondingValidators"
+	QueryEffectiveVotes       = "effectiveVotes"
+	QueryDelegatorValidators  = "delegatorValidators"
+	QueryStoreStats           = "storeStats"
+	QueryPowerDistribution    = "powerDistribution"
+	QueryBlockReward          = "blockReward"
+	QueryDelegatorHistory     = "delegatorHistory"
+	QuerySharesFromTokens     = "sharesFromTokens"
+	// QueryProjectedValidatorSet is only a projection of the next validator set which may change before the end
+	// of the epoch
+	QueryProjectedValidatorSet = "projectedValidatorSet"
+
+	// MaxValidatorsByAddrsQuery is the max number of validator addresses in a single batch query
+	MaxValidatorsByAddrsQuery = 100
+	// DefaultValidatorDelegationsLimit is the default page size of the query 'custom/staking/validatorDelegations'
+	DefaultValidatorDelegationsLimit = 100
+	// MaxValidatorsByRankQuery is the max number of ranks in a single query of validators by rank
+	MaxValidatorsByRankQuery = 100
 )
 
 // QueryValidatorVotesParams defines the params for the following queries:
@@ -33,10 +81,14 @@ func NewQueryValidatorVotesParams(valAddr sdk.ValAddress) QueryValidatorVotesPar
 }
 
 // QueryDelegatorParams defines the params for the following queries:
-// - 'custom/staking/delegatorDelegations'
+// - 'custom/staking/delegatorPortfolio'
+// - 'custom/staking/delegatorBonded'
+// - 'custom/staking/delegatorVotePower'
+// - 'custom/staking/effectiveVotes'
 // - 'custom/staking/delegatorUnbondingDelegations'
 // - 'custom/staking/delegatorRedelegations'
 // - 'custom/staking/delegatorValidators'
+// - 'custom/staking/delegatorHistory'
 type QueryDelegatorParams struct {
 	DelegatorAddr sdk.AccAddress
 }
@@ -50,6 +102,8 @@ func NewQueryDelegatorParams(delegatorAddr sdk.AccAddress) QueryDelegatorParams
 
 // QueryValidatorParams defines the params for the following queries:
 // - 'custom/staking/validator'
+// - 'custom/staking/validatorShares'
+// - 'custom/staking/validatorTenure'
 // - 'custom/staking/validatorDelegations'
 // - 'custom/staking/validatorUnbondingDelegations'
 // - 'custom/staking/validatorRedelegations'
@@ -92,3 +146,391 @@ type QueryValidatorsParams struct {
 func NewQueryValidatorsParams(page, limit int, status string) QueryValidatorsParams {
 	return QueryValidatorsParams{page, limit, status}
 }
+
+// QueryValidatorsByAddrsParams defines the params for the following queries:
+// - 'custom/staking/validatorsByAddresses'
+type QueryValidatorsByAddrsParams struct {
+	ValidatorAddrs []sdk.ValAddress
+}
+
+// NewQueryValidatorsByAddrsParams creates a new instance of QueryValidatorsByAddrsParams
+func NewQueryValidatorsByAddrsParams(validatorAddrs []sdk.ValAddress) QueryValidatorsByAddrsParams {
+	return QueryValidatorsByAddrsParams{
+		ValidatorAddrs: validatorAddrs,
+	}
+}
+
+// QueryValidatorsByIdentityParams defines the params for the following queries:
+// - 'custom/staking/validatorsByIdentity'
+type QueryValidatorsByIdentityParams struct {
+	Identity string
+}
+
+// NewQueryValidatorsByIdentityParams creates a new instance of QueryValidatorsByIdentityParams
+func NewQueryValidatorsByIdentityParams(identity string) QueryValidatorsByIdentityParams {
+	return QueryValidatorsByIdentityParams{
+		Identity: identity,
+	}
+}
+
+// QueryValidatorsByRankParams defines the params for the following queries:
+// - 'custom/staking/validatorsByRank'
+type QueryValidatorsByRankParams struct {
+	// the ranks start from 1 and both ends are included
+	StartRank, EndRank int
+}
+
+// NewQueryValidatorsByRankParams creates a new instance of QueryValidatorsByRankParams
+func NewQueryValidatorsByRankParams(startRank, endRank int) QueryValidatorsByRankParams {
+	return QueryValidatorsByRankParams{
+		StartRank: startRank,
+		EndRank:   endRank,
+	}
+}
+
+// QueryBondedHistoryParams defines the params for the following queries:
+// - 'custom/staking/bondedHistory'
+type QueryBondedHistoryParams struct {
+	// both ends are included
+	StartEpoch, EndEpoch uint64
+}
+
+// NewQueryBondedHistoryParams creates a new instance of QueryBondedHistoryParams
+func NewQueryBondedHistoryParams(startEpoch, endEpoch uint64) QueryBondedHistoryParams {
+	return QueryBondedHistoryParams{
+		StartEpoch: startEpoch,
+		EndEpoch:   endEpoch,
+	}
+}
+
+// QueryCanDelegateParams defines the params for the following queries:
+// - 'custom/staking/canDelegate'
+type QueryCanDelegateParams struct {
+	DelegatorAddr sdk.AccAddress
+	ValidatorAddr sdk.ValAddress
+	Amount        sdk.DecCoin
+}
+
+// NewQueryCanDelegateParams creates a new instance of QueryCanDelegateParams
+func NewQueryCanDelegateParams(delAddr sdk.AccAddress, valAddr sdk.ValAddress, amount sdk.DecCoin,
+) QueryCanDelegateParams {
+	return QueryCanDelegateParams{
+		DelegatorAddr: delAddr,
+		ValidatorAddr: valAddr,
+		Amount:        amount,
+	}
+}
+
+// CanDelegateResponse is the result of the query 'custom/staking/canDelegate', where the reason explains the
+// constraint violated if the delegation can't be made
+type CanDelegateResponse struct {
+	CanDelegate bool   `json:"can_delegate" yaml:"can_delegate"`
+	Reason      string `json:"reason" yaml:"reason"`
+}
+
+// NewCanDelegateResponse creates a new instance of CanDelegateResponse from the error of the check
+func NewCanDelegateResponse(err sdk.Error) CanDelegateResponse {
+	if err == nil {
+		return CanDelegateResponse{CanDelegate: true}
+	}
+	return CanDelegateResponse{Reason: fmt.Sprintf("%v", err.Data())}
+}
+
+// QuerySharesFromTokensParams defines the params for the following queries:
+// - 'custom/staking/sharesFromTokens'
+type QuerySharesFromTokensParams struct {
+	ValidatorAddr sdk.ValAddress
+	Amount        sdk.DecCoin
+}
+
+// NewQuerySharesFromTokensParams creates a new instance of QuerySharesFromTokensParams
+func NewQuerySharesFromTokensParams(valAddr sdk.ValAddress, amount sdk.DecCoin) QuerySharesFromTokensParams {
+	return QuerySharesFromTokensParams{
+		ValidatorAddr: valAddr,
+		Amount:        amount,
+	}
+}
+
+// SharesFromTokensResponse is the result of the query 'custom/staking/sharesFromTokens', where the tokens are the
+// amount weighted by its bond denom and the shares are the votes they would be turned into
+type SharesFromTokensResponse struct {
+	ValidatorAddr sdk.ValAddress `json:"validator_address" yaml:"validator_address"`
+	Tokens        sdk.Dec        `json:"tokens" yaml:"tokens"`
+	Shares        sdk.Dec        `json:"shares" yaml:"shares"`
+}
+
+// NewSharesFromTokensResponse creates a new instance of SharesFromTokensResponse
+func NewSharesFromTokensResponse(valAddr sdk.ValAddress, tokens, shares sdk.Dec) SharesFromTokensResponse {
+	return SharesFromTokensResponse{
+		ValidatorAddr: valAddr,
+		Tokens:        tokens,
+		Shares:        shares,
+	}
+}
+
+// QueryAddressConversionParams defines the params for the following queries:
+// - 'custom/staking/addressConversion'
+type QueryAddressConversionParams struct {
+	// either the bech32 operator address or the bech32 consensus address of a validator
+	Address string
+}
+
+// NewQueryAddressConversionParams creates a new instance of QueryAddressConversionParams
+func NewQueryAddressConversionParams(address string) QueryAddressConversionParams {
+	return QueryAddressConversionParams{
+		Address: address,
+	}
+}
+
+// AddressConversion is the result of the query 'custom/staking/addressConversion', which pairs the operator address
+// of a validator with its consensus address
+type AddressConversion struct {
+	OperatorAddress sdk.ValAddress  `json:"operator_address" yaml:"operator_address"`
+	ConsAddress     sdk.ConsAddress `json:"cons_address" yaml:"cons_address"`
+}
+
+// NewAddressConversion creates a new instance of AddressConversion from a validator
+func NewAddressConversion(validator Validator) AddressConversion {
+	return AddressConversion{
+		OperatorAddress: validator.OperatorAddress,
+		ConsAddress:     validator.ConsAddress(),
+	}
+}
+
+// QueryDryRunParamsParams defines the params for the following queries:
+// - 'custom/staking/dryRunParams'
+type QueryDryRunParamsParams struct {
+	Changes []params.ParamChange `json:"changes"`
+}
+
+// NewQueryDryRunParamsParams creates a new instance of QueryDryRunParamsParams
+func NewQueryDryRunParamsParams(changes []params.ParamChange) QueryDryRunParamsParams {
+	return QueryDryRunParamsParams{
+		Changes: changes,
+	}
+}
+
+// DryRunParamsResult is the result of the query 'custom/staking/dryRunParams'
+type DryRunParamsResult struct {
+	Params Params `json:"params"`
+	// the error of the validation on the merged params, empty if they are valid
+	Error string `json:"error"`
+}
+
+// QueryUnvotedValidatorsParams defines the params for the following queries:
+// - 'custom/staking/unvotedValidators'
+type QueryUnvotedValidatorsParams struct {
+	DelegatorAddr sdk.AccAddress
+	Page, Limit   int
+}
+
+// NewQueryUnvotedValidatorsParams creates a new instance of QueryUnvotedValidatorsParams
+func NewQueryUnvotedValidatorsParams(delegatorAddr sdk.AccAddress, page, limit int) QueryUnvotedValidatorsParams {
+	return QueryUnvotedValidatorsParams{
+		DelegatorAddr: delegatorAddr,
+		Page:          page,
+		Limit:         limit,
+	}
+}
+
+// UnvotedValidatorsResult is the result of the query 'custom/staking/unvotedValidators'
+type UnvotedValidatorsResult struct {
+	// how many more validators the delegator is able to vote for
+	RemainingValsToVote uint16     `json:"remaining_vals_to_vote" yaml:"remaining_vals_to_vote"`
+	Validators          Validators `json:"validators" yaml:"validators"`
+}
+
+// String returns a human readable string representation of UnvotedValidatorsResult
+func (uvr UnvotedValidatorsResult) String() string {
+	return fmt.Sprintf(`Remaining Vals To Vote: %d
+%s`, uvr.RemainingValsToVote, uvr.Validators)
+}
+
+// MinDelegationDisplay is the result of the query 'custom/staking/minDelegationDisplay'
+type MinDelegationDisplay struct {
+	Denom    string `json:"denom" yaml:"denom"`
+	Decimals uint16 `json:"decimals" yaml:"decimals"`
+	// the param MinDelegation as it is stored on chain
+	Raw sdk.Dec `json:"raw" yaml:"raw"`
+	// the param MinDelegation scaled down by the decimals of the bond denom
+	Display sdk.Dec `json:"display" yaml:"display"`
+}
+
+// NewMinDelegationDisplay creates a new instance of MinDelegationDisplay
+func NewMinDelegationDisplay(denom string, decimals uint16, raw sdk.Dec) MinDelegationDisplay {
+	display := raw
+	if decimals > 0 {
+		display = raw.Quo(sdk.NewDecFromBigInt(sdk.NewIntWithDecimal(1, int(decimals)).BigInt()))
+	}
+	return MinDelegationDisplay{
+		Denom:    denom,
+		Decimals: decimals,
+		Raw:      raw,
+		Display:  display,
+	}
+}
+
+// String returns a human readable string representation of MinDelegationDisplay
+func (mdd MinDelegationDisplay) String() string {
+	return fmt.Sprintf(`Min Delegation:
+  Denom:		%s
+  Decimals:		%d
+  Raw:			%s
+  Display:		%s`, mdd.Denom, mdd.Decimals, mdd.Raw, mdd.Display)
+}
+
+// ValidatorTenure is the result of the query 'custom/staking/validatorTenure'
+type ValidatorTenure struct {
+	OperatorAddress sdk.ValAddress `json:"operator_address" yaml:"operator_address"`
+	BondedSince     time.Time      `json:"bonded_since" yaml:"bonded_since"`
+	// how long the validator has been bonded continuously until the current block
+	Tenure time.Duration `json:"tenure" yaml:"tenure"`
+}
+
+// NewValidatorTenure creates a new instance of ValidatorTenure
+func NewValidatorTenure(valAddr sdk.ValAddress, bondedSince time.Time, tenure time.Duration) ValidatorTenure {
+	return ValidatorTenure{
+		OperatorAddress: valAddr,
+		BondedSince:     bondedSince,
+		Tenure:          tenure,
+	}
+}
+
+// String returns a human readable string representation of ValidatorTenure
+func (vt ValidatorTenure) String() string {
+	return fmt.Sprintf(`Validator Tenure:
+  Operator Address:	%s
+  Bonded Since:		%v
+  Tenure:		%s`, vt.OperatorAddress, vt.BondedSince, vt.Tenure)
+}
+
+// CommissionCooldown is the result of the query 'custom/staking/commissionCooldown'
+type CommissionCooldown struct {
+	OperatorAddress sdk.ValAddress `json:"operator_address" yaml:"operator_address"`
+	UpdateTime      time.Time      `json:"update_time" yaml:"update_time"`
+	NextUpdateTime  time.Time      `json:"next_update_time" yaml:"next_update_time"`
+	// how long until the commission rate is allowed to change, zero if it's allowed now
+	Remaining time.Duration `json:"remaining" yaml:"remaining"`
+}
+
+// NewCommissionCooldown creates a new instance of CommissionCooldown
+func NewCommissionCooldown(valAddr sdk.ValAddress, updateTime, nextUpdateTime time.Time,
+	remaining time.Duration) CommissionCooldown {
+	return CommissionCooldown{
+		OperatorAddress: valAddr,
+		UpdateTime:      updateTime,
+		NextUpdateTime:  nextUpdateTime,
+		Remaining:       remaining,
+	}
+}
+
+// String returns a human readable string representation of CommissionCooldown
+func (cc CommissionCooldown) String() string {
+	return fmt.Sprintf(`Commission Cooldown:
+  Operator Address:	%s
+  Update Time:		%v
+  Next Update Time:	%v
+  Remaining:		%s`, cc.OperatorAddress, cc.UpdateTime, cc.NextUpdateTime, cc.Remaining)
+}
+
+// QueryDelegatorDelegationsParams defines the params for the following queries:
+// - 'custom/staking/delegatorDelegations'
+type QueryDelegatorDelegationsParams struct {
+	DelegatorAddr sdk.AccAddress
+	Page, Limit   int
+}
+
+// NewQueryDelegatorDelegationsParams creates a new instance of QueryDelegatorDelegationsParams
+func NewQueryDelegatorDelegationsParams(delegatorAddr sdk.AccAddress, page, limit int) QueryDelegatorDelegationsParams {
+	return QueryDelegatorDelegationsParams{
+		DelegatorAddr: delegatorAddr,
+		Page:          page,
+		Limit:         limit,
+	}
+}
+
+// DelegatorDelegationsResult is the result of the query 'custom/staking/delegatorDelegations'
+type DelegatorDelegationsResult struct {
+	// number of all the validators that the delegator votes for
+	Total int               `json:"total" yaml:"total"`
+	Votes []VoteToValidator `json:"votes" yaml:"votes"`
+}
+
+// String returns a human readable string representation of DelegatorDelegationsResult
+func (ddr DelegatorDelegationsResult) String() string {
+	var votes strings.Builder
+	for _, vote := range ddr.Votes {
+		votes.WriteString(fmt.Sprintf("\n  %s: %s", vote.ValidatorAddress, vote.Votes))
+	}
+	return fmt.Sprintf(`Total: %d
+Votes:%s`, ddr.Total, votes.String())
+}
+
+// QueryValidatorDelegationsParams defines the params for the following queries:
+// - 'custom/staking/validatorDelegations'
+type QueryValidatorDelegationsParams struct {
+	ValidatorAddr sdk.ValAddress
+	Page, Limit   int
+}
+
+// NewQueryValidatorDelegationsParams creates a new instance of QueryValidatorDelegationsParams
+func NewQueryValidatorDelegationsParams(valAddr sdk.ValAddress, page, limit int) QueryValidatorDelegationsParams {
+	return QueryValidatorDelegationsParams{
+		ValidatorAddr: valAddr,
+		Page:          page,
+		Limit:         limit,
+	}
+}
+
+// ValidatorDelegationsResult is the result of the query 'custom/staking/validatorDelegations'
+type ValidatorDelegationsResult struct {
+	// number of all the delegators voting to the validator
+	Total int           `json:"total" yaml:"total"`
+	Votes VoteResponses `json:"votes" yaml:"votes"`
+}
+
+// String returns a human readable string representation of ValidatorDelegationsResult
+func (vdr ValidatorDelegationsResult) String() string {
+	return fmt.Sprintf(`Total: %d
+Votes:
+%s`, vdr.Total, vdr.Votes)
+}
+
+// QueryIsValidatorOperatorParams defines the params for the query 'custom/staking/isValidatorOperator'
+type QueryIsValidatorOperatorParams struct {
+	// bech32 address of either the account or the validator
+	Address string
+}
+
+// NewQueryIsValidatorOperatorParams creates a new instance of QueryIsValidatorOperatorParams
+func NewQueryIsValidatorOperatorParams(address string) QueryIsValidatorOperatorParams {
+	return QueryIsValidatorOperatorParams{
+		Address: address,
+	}
+}
+
+// IsValidatorOperatorResult is the result of the query 'custom/staking/isValidatorOperator'
+type IsValidatorOperatorResult struct {
+	AccAddress sdk.AccAddress `json:"acc_address" yaml:"acc_address"`
+	ValAddress sdk.ValAddress `json:"val_address" yaml:"val_address"`
+	IsOperator bool           `json:"is_operator" yaml:"is_operator"`
+}
+
+// StorePrefixStats is the number of the entries under a prefix of the staking store
+type StorePrefixStats struct {
+	Name    string `json:"name" yaml:"name"`
+	Prefix  string `json:"prefix" yaml:"prefix"`
+	Entries int64  `json:"entries" yaml:"entries"`
+}
+
+// StoreStats is the result of the query 'custom/staking/storeStats'
+type StoreStats []StorePrefixStats
+
+// String returns a human readable string representation of StoreStats
+func (ss StoreStats) String() string {
+	out := "Store Stats:"
+	for _, stats := range ss {
+		out += fmt.Sprintf("\n  %s (0x%s):    %d", stats.Name, stats.Prefix, stats.Entries)
+	}
+	return out
+}
@@ -0,0 +1,50 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// BlockReward is the reward minted per block by the minter of the mint module in MintDenom, which is recalculated from
+// the annual inflation over the staking token supply once a year
+type BlockReward struct {
+	Amount        sdk.DecCoin `json:"amount"`
+	InflationRate sdk.Dec     `json:"inflation_rate"`
+	BlocksPerYear uint64      `json:"blocks_per_year"`
+	BondedRatio   sdk.Dec     `json:"bonded_ratio"`
+	// RewardPerBondedToken is the block reward shared by each bonded token, which is zero with nothing bonded or with
+	// the reward minted in a denom other than BondDenom
+	RewardPerBondedToken sdk.Dec `json:"reward_per_bonded_token"`
+}
+
+// NewBlockReward creates a new instance of BlockReward with the amount minted per block, and the supply and the bonded
+// tokens in bondDenom
+func NewBlockReward(mintedPerBlock sdk.DecCoin, bondDenom string, inflationRate sdk.Dec, blocksPerYear uint64, supply,
+	bonded sdk.Dec) BlockReward {
+	reward := BlockReward{
+		Amount:               mintedPerBlock,
+		InflationRate:        inflationRate,
+		BlocksPerYear:        blocksPerYear,
+		BondedRatio:          sdk.ZeroDec(),
+		RewardPerBondedToken: sdk.ZeroDec(),
+	}
+	if supply.IsPositive() {
+		reward.BondedRatio = bonded.Quo(supply)
+	}
+	if mintedPerBlock.Denom == bondDenom && bonded.IsPositive() {
+		reward.RewardPerBondedToken = mintedPerBlock.Amount.Quo(bonded)
+	}
+	return reward
+}
+
+// String returns a human readable string representation of BlockReward
+func (br BlockReward) String() string {
+	return fmt.Sprintf(`Block Reward:
+  Amount:                  %s
+  Inflation Rate:          %s
+  Blocks Per Year:         %d
+  Bonded Ratio:            %s
+  Reward Per Bonded Token: %s`, br.Amount, br.InflationRate, br.BlocksPerYear, br.BondedRatio,
+		br.RewardPerBondedToken)
+}
@@ -0,0 +1,37 @@
+package types
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBlockReward(t *testing.T) {
+	// 1 minted per block, with a quarter of the supply bonded
+	minted := sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.OneDec())
+	reward := NewBlockReward(minted, sdk.DefaultBondDenom, sdk.NewDecWithPrec(1, 2), 10000, sdk.NewDec(1000000),
+		sdk.NewDec(250000))
+	require.Equal(t, minted, reward.Amount)
+	require.Equal(t, sdk.NewDecWithPrec(25, 2), reward.BondedRatio)
+	require.Equal(t, sdk.NewDecWithPrec(4, 6), reward.RewardPerBondedToken)
+	require.Equal(t, uint64(10000), reward.BlocksPerYear)
+
+	// nothing bonded
+	reward = NewBlockReward(minted, sdk.DefaultBondDenom, sdk.NewDecWithPrec(1, 2), 10000, sdk.NewDec(1000000),
+		sdk.ZeroDec())
+	require.Equal(t, sdk.OneDec(), reward.Amount.Amount)
+	require.True(t, reward.BondedRatio.IsZero())
+	require.True(t, reward.RewardPerBondedToken.IsZero())
+
+	// no supply
+	reward = NewBlockReward(minted, sdk.DefaultBondDenom, sdk.NewDecWithPrec(1, 2), 10000, sdk.ZeroDec(),
+		sdk.ZeroDec())
+	require.True(t, reward.BondedRatio.IsZero())
+
+	// minted in another denom
+	reward = NewBlockReward(sdk.NewDecCoinFromDec("mint", sdk.OneDec()), sdk.DefaultBondDenom,
+		sdk.NewDecWithPrec(1, 2), 10000, sdk.NewDec(1000000), sdk.NewDec(250000))
+	require.Equal(t, "mint", reward.Amount.Denom)
+	require.True(t, reward.RewardPerBondedToken.IsZero())
+}
@@ -26,7 +26,7 @@ const (
 )
 
 // Implements Validator interface
-var _ exported.ValidatorI = Validator{}
+var _ exported.ValidatorI = ValidatorWithPowerReduction{}
 
 // Validator defines the total amount of bond shares and their exchange rate to
 // coins. Slashing results in a decrease in the exchange rate, allowing correct
@@ -58,6 +58,17 @@ type Validator struct {
 	Commission Commission `json:"commission" yaml:"commission"`
 	// validator's self declared minimum self delegation
 	MinSelfDelegation sdk.Dec `json:"min_self_delegation" yaml:"min_self_delegation"`
+	// whether the validator rejects the votes from new delegators, so that the validators stored by the earlier
+	// software are open to delegations
+	ClosedToDelegations bool `json:"closed_to_delegations" yaml:"closed_to_delegations"`
+	// max number of delegators voting to the validator, zero means no limit
+	MaxDelegatorCount uint64 `json:"max_delegator_count" yaml:"max_delegator_count"`
+	// min tokens of a new delegator voting to the validator on top of the param MinDelegation, zero means no more
+	MinDelegation sdk.Dec `json:"min_delegation" yaml:"min_delegation"`
+	// height at which the validator was created, used to break the ties of power by seniority
+	BondHeight int64 `json:"bond_height" yaml:"bond_height"`
+	// time since which the validator has been in the bonded set continuously, unix epoch if it isn't bonded
+	BondedSince time.Time `json:"bonded_since" yaml:"bonded_since"`
 }
 
 // MarshalYAML implememts the text format for yaml marshaling due to consensus pubkey
@@ -76,6 +87,11 @@ func (v Validator) MarshalYAML() (interface{}, error) {
 		Commission              Commission
 		MinSelfDelegation       sdk.Dec
 		Votes                   sdk.Int
+		ClosedToDelegations     bool
+		MaxDelegatorCount       uint64
+		MinDelegation           sdk.Dec
+		BondHeight              int64
+		BondedSince             time.Time
 	}{
 		OperatorAddress:         v.OperatorAddress,
 		ConsPubKey:              sdk.MustBech32ifyConsPub(v.ConsPubKey),
@@ -88,6 +104,11 @@ func (v Validator) MarshalYAML() (interface{}, error) {
 		UnbondingCompletionTime: v.UnbondingCompletionTime,
 		Commission:              v.Commission,
 		MinSelfDelegation:       v.MinSelfDelegation,
+		ClosedToDelegations:     v.ClosedToDelegations,
+		MaxDelegatorCount:       v.MaxDelegatorCount,
+		MinDelegation:           v.MinDelegation,
+		BondHeight:              v.BondHeight,
+		BondedSince:             v.BondedSince,
 	})
 	if err != nil {
 		return nil, err
@@ -107,14 +128,28 @@ func (v Validators) String() (out string) {
 	return strings.TrimSpace(out)
 }
 
-// ToSDKValidators converts []Validators to []sdk.Validators
-func (v Validators) ToSDKValidators() (validators []exported.ValidatorI) {
+// ToSDKValidators converts []Validators to []sdk.Validators with the power reduction taking effect
+func (v Validators) ToSDKValidators(powerReduction sdk.Int) (validators []exported.ValidatorI) {
 	for _, val := range v {
-		validators = append(validators, val)
+		validators = append(validators, NewValidatorWithPowerReduction(val, powerReduction))
 	}
 	return validators
 }
 
+// ValidatorsByAddrsResponse is the struct for the batch query of validators by addresses
+type ValidatorsByAddrsResponse struct {
+	Validators Validators       `json:"validators"`
+	NotFound   []sdk.ValAddress `json:"not_found"`
+}
+
+// NewValidatorsByAddrsResponse creates a new instance of ValidatorsByAddrsResponse
+func NewValidatorsByAddrsResponse(validators Validators, notFound []sdk.ValAddress) ValidatorsByAddrsResponse {
+	return ValidatorsByAddrsResponse{
+		Validators: validators,
+		NotFound:   notFound,
+	}
+}
+
 // NewValidator initializes a new validator
 func NewValidator(operator sdk.ValAddress, pubKey crypto.PubKey, description Description) Validator {
 	return Validator{
@@ -129,6 +164,9 @@ func NewValidator(operator sdk.ValAddress, pubKey crypto.PubKey, description Des
 		UnbondingCompletionTime: time.Unix(0, 0).UTC(),
 		Commission:              NewCommission(sdk.ZeroDec(), sdk.ZeroDec(), sdk.ZeroDec()),
 		MinSelfDelegation:       sdk.OneDec(),
+		MaxDelegatorCount:       0,
+		MinDelegation:           sdk.ZeroDec(),
+		BondedSince:             time.Unix(0, 0).UTC(),
 	}
 }
 
@@ -169,12 +207,17 @@ func (v Validator) String() string {
   Unbonding Height:           %d
   Unbonding Completion Time:  %v
   Minimum Self Delegation:    %v
-  Commission:                 %s`,
+  Commission:                 %s
+  Closed To Delegations:      %v
+  Max Delegator Count:        %d
+  Minimum Delegation:         %s
+  Bond Height:                %d
+  Bonded Since:               %v`,
 		v.OperatorAddress, bechConsPubKey,
 		v.Jailed, v.Status, v.Tokens,
 		v.DelegatorShares, v.Description,
 		v.UnbondingHeight, v.UnbondingCompletionTime, v.MinSelfDelegation,
-		v.Commission)
+		v.Commission, v.ClosedToDelegations, v.MaxDelegatorCount, v.MinDelegation, v.BondHeight, v.BondedSince)
 }
 
 // this is a helper struct used for JSON de- and encoding only
@@ -201,6 +244,16 @@ type bechValidator struct {
 	Commission Commission `json:"commission" yaml:"commission"`
 	// minimum self delegation
 	MinSelfDelegation sdk.Dec `json:"min_self_delegation" yaml:"min_self_delegation"`
+	// whether the validator rejects the votes from new delegators
+	ClosedToDelegations bool `json:"closed_to_delegations" yaml:"closed_to_delegations"`
+	// max number of delegators voting to the validator, zero means no limit
+	MaxDelegatorCount uint64 `json:"max_delegator_count" yaml:"max_delegator_count"`
+	// min tokens of a new delegator voting to the validator on top of the param MinDelegation, zero means no more
+	MinDelegation sdk.Dec `json:"min_delegation" yaml:"min_delegation"`
+	// height at which the validator was created
+	BondHeight int64 `json:"bond_height" yaml:"bond_height"`
+	// time since which the validator has been bonded continuously
+	BondedSince time.Time `json:"bonded_since" yaml:"bonded_since"`
 }
 
 // MarshalJSON marshals the validator to JSON using Bech32
@@ -222,6 +275,11 @@ func (v Validator) MarshalJSON() ([]byte, error) {
 		UnbondingCompletionTime: v.UnbondingCompletionTime,
 		MinSelfDelegation:       v.MinSelfDelegation,
 		Commission:              v.Commission,
+		ClosedToDelegations:     v.ClosedToDelegations,
+		MaxDelegatorCount:       v.MaxDelegatorCount,
+		MinDelegation:           v.MinDelegation,
+		BondHeight:              v.BondHeight,
+		BondedSince:             v.BondedSince,
 	})
 }
 
@@ -247,6 +305,11 @@ func (v *Validator) UnmarshalJSON(data []byte) error {
 		UnbondingCompletionTime: bv.UnbondingCompletionTime,
 		Commission:              bv.Commission,
 		MinSelfDelegation:       bv.MinSelfDelegation,
+		ClosedToDelegations:     bv.ClosedToDelegations,
+		MaxDelegatorCount:       bv.MaxDelegatorCount,
+		MinDelegation:           bv.MinDelegation,
+		BondHeight:              bv.BondHeight,
+		BondedSince:             bv.BondedSince,
 	}
 	return nil
 }
@@ -282,6 +345,49 @@ func (v Validator) IsUnbonding() bool {
 	return v.GetStatus().Equal(sdk.Unbonding)
 }
 
+// ValidatorStatus is the status of a validator as a string, where being jailed takes precedence over the bond status
+type ValidatorStatus string
+
+// nolint
+const (
+	ValidatorStatusBonded    ValidatorStatus = "Bonded"
+	ValidatorStatusUnbonding ValidatorStatus = "Unbonding"
+	ValidatorStatusUnbonded  ValidatorStatus = "Unbonded"
+	ValidatorStatusJailed    ValidatorStatus = "Jailed"
+)
+
+// GetValidatorStatus gets the ValidatorStatus of the validator
+func (v Validator) GetValidatorStatus() ValidatorStatus {
+	switch {
+	case v.Jailed:
+		return ValidatorStatusJailed
+	case v.IsBonded():
+		return ValidatorStatusBonded
+	case v.IsUnbonding():
+		return ValidatorStatusUnbonding
+	default:
+		return ValidatorStatusUnbonded
+	}
+}
+
+// ValidateStatusTransition checks whether a validator is able to move from one bond status to another. A validator
+// is bonded from either unbonded or unbonding, while a bonded one has to go through unbonding before it's unbonded
+func ValidateStatusTransition(from, to sdk.BondStatus) error {
+	legal := false
+	switch to {
+	case sdk.Bonded:
+		legal = from == sdk.Unbonded || from == sdk.Unbonding
+	case sdk.Unbonding:
+		legal = from == sdk.Bonded
+	case sdk.Unbonded:
+		legal = from == sdk.Unbonding
+	}
+	if !legal {
+		return fmt.Errorf("illegal validator status transition from %s to %s", from, to)
+	}
+	return nil
+}
+
 // DoNotModifyDesc is the constant used in flags to indicate that description field should not be updated
 const DoNotModifyDesc = "[do-not-modify]"
 
@@ -345,15 +451,6 @@ func (d Description) EnsureLength() (Description, sdk.Error) {
 	return d, nil
 }
 
-// ABCIValidatorUpdate returns an abci.ValidatorUpdate from a staking validator type
-// with the full validator power
-func (v Validator) ABCIValidatorUpdate() abci.ValidatorUpdate {
-	return abci.ValidatorUpdate{
-		PubKey: tmtypes.TM2PB.PubKey(v.ConsPubKey),
-		Power:  v.ConsensusPower(),
-	}
-}
-
 // ABCIValidatorUpdateZero returns an abci.ValidatorUpdate from a staking validator type
 // with zero power used for validator updates.
 func (v Validator) ABCIValidatorUpdateZero() abci.ValidatorUpdate {
@@ -425,14 +522,6 @@ func (v Validator) BondedTokens() sdk.Int {
 	return sdk.ZeroInt()
 }
 
-// ConsensusPower gets the consensus-engine power
-func (v Validator) ConsensusPower() int64 {
-	if v.IsBonded() {
-		return v.PotentialConsensusPowerByVotes()
-	}
-	return 0
-}
-
 // UpdateStatus updates the location of the shares within a validator
 // to reflect the new status
 func (v Validator) UpdateStatus(newStatus sdk.BondStatus) Validator {
@@ -449,7 +538,62 @@ func (v Validator) GetConsPubKey() crypto.PubKey  { return v.ConsPubKey }
 func (v Validator) GetConsAddr() sdk.ConsAddress  { return sdk.ConsAddress(v.ConsPubKey.Address()) }
 func (v Validator) GetTokens() sdk.Int            { return v.Tokens }
 func (v Validator) GetBondedTokens() sdk.Int      { return sdk.ZeroInt() }
-func (v Validator) GetConsensusPower() int64      { return v.ConsensusPower() }
 func (v Validator) GetCommission() sdk.Dec        { return v.Commission.Rate }
 func (v Validator) GetMinSelfDelegation() sdk.Dec { return v.MinSelfDelegation }
 func (v Validator) GetDelegatorShares() sdk.Dec   { return v.DelegatorShares }
+
+// PowerIndexEntry is a raw entry of the validator power index decoded from the store key
+type PowerIndexEntry struct {
+	Power    int64          `json:"power" yaml:"power"`
+	Operator sdk.ValAddress `json:"operator" yaml:"operator"`
+}
+
+// PowerIndexEntries is a collection of PowerIndexEntry
+type PowerIndexEntries []PowerIndexEntry
+
+// String returns a human readable string representation of PowerIndexEntries
+func (entries PowerIndexEntries) String() (out string) {
+	for i, entry := range entries {
+		out += fmt.Sprintf("%d\t%d\t%s\n", i+1, entry.Power, entry.Operator)
+	}
+	return strings.TrimSpace(out)
+}
+
+// UnbondingValidator is the struct of a validator leaving the bonded set, which is still liable to be slashed for the
+// infractions committed while bonded until the unbonding completes
+type UnbondingValidator struct {
+	OperatorAddress         sdk.ValAddress  `json:"operator_address" yaml:"operator_address"`
+	ConsAddress             sdk.ConsAddress `json:"cons_address" yaml:"cons_address"`
+	UnbondingHeight         int64           `json:"unbonding_height" yaml:"unbonding_height"`
+	UnbondingCompletionTime time.Time       `json:"unbonding_time" yaml:"unbonding_time"`
+}
+
+// NewUnbondingValidator creates a new instance of UnbondingValidator
+func NewUnbondingValidator(validator Validator) UnbondingValidator {
+	return UnbondingValidator{
+		OperatorAddress:         validator.OperatorAddress,
+		ConsAddress:             validator.ConsAddress(),
+		UnbondingHeight:         validator.UnbondingHeight,
+		UnbondingCompletionTime: validator.UnbondingCompletionTime,
+	}
+}
+
+// ValidatorWithPowerReduction is a validator handed out to the other modules as ValidatorI together with the power
+// reduction taking effect, so that the consensus power they get is the one sent to Tendermint
+type ValidatorWithPowerReduction struct {
+	Validator
+	PowerReduction sdk.Int
+}
+
+// NewValidatorWithPowerReduction creates a new instance of ValidatorWithPowerReduction
+func NewValidatorWithPowerReduction(validator Validator, powerReduction sdk.Int) ValidatorWithPowerReduction {
+	return ValidatorWithPowerReduction{
+		Validator:      validator,
+		PowerReduction: powerReduction,
+	}
+}
+
+// GetConsensusPower gets the consensus-engine power by the power reduction taking effect, which implements ValidatorI
+func (v ValidatorWithPowerReduction) GetConsensusPower() int64 {
+	return v.ConsensusPowerByVotes(v.PowerReduction)
+}
@@ -28,6 +28,11 @@ func (v Validator) Export() ValidatorExported {
 		v.UnbondingHeight,
 		v.UnbondingCompletionTime,
 		v.MinSelfDelegation,
+		v.ClosedToDelegations,
+		v.MaxDelegatorCount,
+		v.MinDelegation,
+		v.BondHeight,
+		v.BondedSince,
 	}
 }
 
@@ -43,6 +48,11 @@ func (v Validator) Standardize() StandardizedValidator {
 		v.UnbondingHeight,
 		v.UnbondingCompletionTime,
 		v.MinSelfDelegation,
+		v.ClosedToDelegations,
+		v.MaxDelegatorCount,
+		v.MinDelegation,
+		v.BondHeight,
+		v.BondedSince,
 	}
 }
 
@@ -79,6 +89,11 @@ type StandardizedValidator struct {
 	UnbondingHeight         int64          `json:"unbonding_height" yaml:"unbonding_height"`
 	UnbondingCompletionTime time.Time      `json:"unbonding_time" yaml:"unbonding_time"`
 	MinSelfDelegation       sdk.Dec        `json:"min_self_delegation" yaml:"min_self_delegation"`
+	ClosedToDelegations     bool           `json:"closed_to_delegations" yaml:"closed_to_delegations"`
+	MaxDelegatorCount       uint64         `json:"max_delegator_count" yaml:"max_delegator_count"`
+	MinDelegation           sdk.Dec        `json:"min_delegation" yaml:"min_delegation"`
+	BondHeight              int64          `json:"bond_height" yaml:"bond_height"`
+	BondedSince             time.Time      `json:"bonded_since" yaml:"bonded_since"`
 }
 
 // String returns a human readable string representation of a StandardizeValidator
@@ -97,10 +112,16 @@ func (sv StandardizedValidator) String() string {
   Description:                %s
   Unbonding Height:           %d
   Unbonding Completion Time:  %v
-  Minimum Self Delegation:    %v`,
+  Minimum Self Delegation:    %v
+  Closed To Delegations:      %v
+  Max Delegator Count:        %d
+  Minimum Delegation:         %s
+  Bond Height:                %d
+  Bonded Since:               %v`,
 		sv.OperatorAddress, bechConsPubkey, sv.Jailed, sv.Status,
 		sv.DelegatorShares, sv.Description, sv.UnbondingHeight,
-		sv.UnbondingCompletionTime, sv.MinSelfDelegation)
+		sv.UnbondingCompletionTime, sv.MinSelfDelegation, sv.ClosedToDelegations, sv.MaxDelegatorCount,
+		sv.MinDelegation, sv.BondHeight, sv.BondedSince)
 }
 
 // MarshalYAML implememts the text format for yaml marshaling
@@ -126,30 +147,29 @@ func (svs StandardizedValidators) String() string {
 	return strings.TrimSpace(output)
 }
 
-// votes from the voting convert to the consensus power
-// reduce
-func votesToConsensusPower(votes sdk.Dec) int64 {
-	return votes.QuoInt(sdk.PowerReduction).Int64()
+// VotesToConsensusPower converts the votes to the consensus power by the power reduction
+func VotesToConsensusPower(votes sdk.Dec, powerReduction sdk.Int) int64 {
+	return votes.QuoInt(powerReduction).Int64()
 }
 
 // PotentialConsensusPowerByVotes gets potential consensus-engine power based on votes
-func (v Validator) PotentialConsensusPowerByVotes() int64 {
-	return votesToConsensusPower(v.DelegatorShares)
+func (v Validator) PotentialConsensusPowerByVotes(powerReduction sdk.Int) int64 {
+	return VotesToConsensusPower(v.DelegatorShares, powerReduction)
 }
 
 // ConsensusPowerByVotes gets the consensus-engine power
-func (v Validator) ConsensusPowerByVotes() int64 {
+func (v Validator) ConsensusPowerByVotes(powerReduction sdk.Int) int64 {
 	if v.IsBonded() {
-		return v.PotentialConsensusPowerByVotes()
+		return v.PotentialConsensusPowerByVotes(powerReduction)
 	}
 	return 0
 }
 
 // ABCIValidatorUpdateByVotes returns an abci.ValidatorUpdate from a staking validator type
 // with the full validator power based on votes
-func (v Validator) ABCIValidatorUpdateByVotes() abci.ValidatorUpdate {
+func (v Validator) ABCIValidatorUpdateByVotes(powerReduction sdk.Int) abci.ValidatorUpdate {
 	return abci.ValidatorUpdate{
 		PubKey: tmtypes.TM2PB.PubKey(v.ConsPubKey),
-		Power:  v.ConsensusPowerByVotes(),
+		Power:  v.ConsensusPowerByVotes(powerReduction),
 	}
 }
@@ -43,7 +43,7 @@ func TestValidators(t *testing.T) {
 	require.True(t, len(vaStr) > 0, vaStr)
 	stdVas := valdators.Standardize()
 	require.NotNil(t, stdVas)
-	iVas := valdators.ToSDKValidators()
+	iVas := valdators.ToSDKValidators(DefaultPowerReduction)
 	require.NotNil(t, iVas)
 	require.True(t, len(iVas) == len(valdators))
 
@@ -93,12 +93,20 @@ func TestUpdateDescription(t *testing.T) {
 	}
 }
 
-func TestABCIValidatorUpdate(t *testing.T) {
+func TestValidatorWithPowerReductionConsensusPower(t *testing.T) {
 	validator := NewValidator(valAddr1, pk1, Description{})
-
-	abciVal := validator.ABCIValidatorUpdate()
-	require.Equal(t, tmtypes.TM2PB.PubKey(validator.ConsPubKey), abciVal.PubKey)
-	require.Equal(t, validator.BondedTokens().Int64(), abciVal.Power)
+	validator.Status = sdk.Bonded
+	validator.DelegatorShares = sdk.NewDec(100)
+
+	// the power handed out is the one sent to Tendermint by the same power reduction
+	for _, powerReduction := range []sdk.Int{DefaultPowerReduction, DefaultPowerReduction.MulRaw(10)} {
+		validatorI := NewValidatorWithPowerReduction(validator, powerReduction)
+		abciVal := validator.ABCIValidatorUpdateByVotes(powerReduction)
+		require.Equal(t, tmtypes.TM2PB.PubKey(validator.ConsPubKey), abciVal.PubKey)
+		require.Equal(t, abciVal.Power, validatorI.GetConsensusPower())
+	}
+	require.Equal(t, int64(10),
+		NewValidatorWithPowerReduction(validator, DefaultPowerReduction.MulRaw(10)).GetConsensusPower())
 }
 
 func TestABCIValidatorUpdateZero(t *testing.T) {
@@ -169,3 +177,35 @@ func TestValidatorSetInitialCommission(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateStatusTransition(t *testing.T) {
+	tests := []struct {
+		from, to sdk.BondStatus
+		legal    bool
+	}{
+		{sdk.Unbonded, sdk.Bonded, true},
+		{sdk.Unbonding, sdk.Bonded, true},
+		{sdk.Bonded, sdk.Unbonding, true},
+		{sdk.Unbonding, sdk.Unbonded, true},
+		{sdk.Bonded, sdk.Unbonded, false},
+		{sdk.Unbonded, sdk.Unbonding, false},
+		{sdk.Bonded, sdk.Bonded, false},
+		{sdk.Unbonding, sdk.Unbonding, false},
+		{sdk.Unbonded, sdk.Unbonded, false},
+	}
+	for _, tc := range tests {
+		err := ValidateStatusTransition(tc.from, tc.to)
+		require.Equal(t, tc.legal, err == nil, "%s -> %s", tc.from, tc.to)
+	}
+}
+
+func TestGetValidatorStatus(t *testing.T) {
+	validator := NewValidator(valAddr1, pk1, Description{})
+	require.Equal(t, ValidatorStatusUnbonded, validator.GetValidatorStatus())
+	validator.Status = sdk.Bonded
+	require.Equal(t, ValidatorStatusBonded, validator.GetValidatorStatus())
+	validator.Status = sdk.Unbonding
+	require.Equal(t, ValidatorStatusUnbonding, validator.GetValidatorStatus())
+	validator.Jailed = true
+	require.Equal(t, ValidatorStatusJailed, validator.GetValidatorStatus())
+}
@@ -48,3 +48,35 @@ func (vrs VoteResponses) String() (strFormat string) {
 
 	return strings.TrimSpace(strFormat)
 }
+
+// ValidatorSharesResponse is the struct for reconciling the shares recorded on a validator with the sum of its votes
+type ValidatorSharesResponse struct {
+	ValidatorAddress sdk.ValAddress `json:"validator_address"`
+	DelegatorShares  sdk.Dec        `json:"delegator_shares"`
+	TotalShares      sdk.Dec        `json:"total_shares"`
+	Drift            sdk.Dec        `json:"drift"`
+}
+
+// NewValidatorSharesResponse creates a new instance of ValidatorSharesResponse
+func NewValidatorSharesResponse(valAddr sdk.ValAddress, delegatorShares, totalShares sdk.Dec) ValidatorSharesResponse {
+	return ValidatorSharesResponse{
+		ValidatorAddress: valAddr,
+		DelegatorShares:  delegatorShares,
+		TotalShares:      totalShares,
+		Drift:            delegatorShares.Sub(totalShares),
+	}
+}
+
+// IsReconciled tells whether the shares recorded on the validator equal to the sum of its votes
+func (vsr ValidatorSharesResponse) IsReconciled() bool {
+	return vsr.Drift.IsZero()
+}
+
+// String returns a human readable string representation of ValidatorSharesResponse
+func (vsr ValidatorSharesResponse) String() string {
+	return fmt.Sprintf(`Validator Shares:
+  Validator Address:  %s
+  Delegator Shares:   %s
+  Total Shares:       %s
+  Drift:              %s`, vsr.ValidatorAddress, vsr.DelegatorShares, vsr.TotalShares, vsr.Drift)
+}
@@ -0,0 +1,94 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// WeightedDenom is a bondable coin denomination with the weight to convert its amount into the bonded tokens
+type WeightedDenom struct {
+	Denom  string  `json:"denom" yaml:"denom"`
+	Weight sdk.Dec `json:"weight" yaml:"weight"`
+}
+
+// NewWeightedDenom creates a new instance of WeightedDenom
+func NewWeightedDenom(denom string, weight sdk.Dec) WeightedDenom {
+	return WeightedDenom{
+		Denom:  denom,
+		Weight: weight,
+	}
+}
+
+// String returns a human readable string representation of WeightedDenom
+func (wd WeightedDenom) String() string {
+	return fmt.Sprintf("%s:%s", wd.Denom, wd.Weight)
+}
+
+// WeightedDenoms is a collection of WeightedDenom. The first one is the primary denom in which all the bonded tokens
+// are measured, so its weight is always one
+type WeightedDenoms []WeightedDenom
+
+// Primary returns the primary bondable denom
+func (wds WeightedDenoms) Primary() string {
+	if len(wds) == 0 {
+		return ""
+	}
+	return wds[0].Denom
+}
+
+// Weight returns the weight of a bondable denom
+func (wds WeightedDenoms) Weight(denom string) (sdk.Dec, bool) {
+	for _, wd := range wds {
+		if wd.Denom == denom {
+			return wd.Weight, true
+		}
+	}
+	return sdk.ZeroDec(), false
+}
+
+// WeightedAmount converts the coins into the amount of the primary denom by the weights
+// NOTE: the coins whose denom isn't bondable any more are weighted zero
+func (wds WeightedDenoms) WeightedAmount(coins sdk.DecCoins) sdk.Dec {
+	amount := sdk.ZeroDec()
+	for _, coin := range coins {
+		if weight, found := wds.Weight(coin.Denom); found {
+			amount = amount.Add(coin.Amount.Mul(weight))
+		}
+	}
+	return amount
+}
+
+// Validate gives a quick validity check for the weighted denoms
+func (wds WeightedDenoms) Validate() error {
+	if len(wds) == 0 {
+		return fmt.Errorf("at least one bondable denom is required")
+	}
+	seen := make(map[string]bool, len(wds))
+	for _, wd := range wds {
+		if err := sdk.ValidateDenom(wd.Denom); err != nil {
+			return err
+		}
+		if seen[wd.Denom] {
+			return fmt.Errorf("duplicate bondable denom %s", wd.Denom)
+		}
+		seen[wd.Denom] = true
+		if wd.Weight.IsNil() || !wd.Weight.IsPositive() {
+			return fmt.Errorf("weight of bondable denom %s must be positive", wd.Denom)
+		}
+	}
+	if !wds[0].Weight.Equal(sdk.OneDec()) {
+		return fmt.Errorf("weight of the primary bondable denom %s must be one", wds[0].Denom)
+	}
+	return nil
+}
+
+// String returns a human readable string representation of WeightedDenoms
+func (wds WeightedDenoms) String() string {
+	strs := make([]string, len(wds))
+	for i, wd := range wds {
+		strs[i] = wd.String()
+	}
+	return strings.Join(strs, ",")
+}
@@ -6,7 +6,6 @@ import (
 	"testing"
 
 	"github.com/cosmos/cosmos-sdk/codec"
-	store "github.com/cosmos/cosmos-sdk/store/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/cosmos/cosmos-sdk/x/bank"
@@ -37,6 +36,8 @@ type MockDexApp struct {
 	keyGov     *sdk.KVStoreKey
 	keyStaking *sdk.KVStoreKey
 
+	tkeyStaking *sdk.TransientStoreKey
+
 	bankKeeper    bank.Keeper
 	tokenKeeper   Keeper
 	supplyKeeper  supply.Keeper
@@ -151,6 +152,8 @@ func getMockDexAppEx(t *testing.T, numGenAccs int) (mockDexApp *MockDexApp, keep
 		keyLock:    sdk.NewKVStoreKey("lock"),
 		keyGov:     sdk.NewKVStoreKey(gov.ModuleName),
 		keyStaking: sdk.NewKVStoreKey(staking.StoreKey),
+
+		tkeyStaking: sdk.NewTransientStoreKey(staking.TStoreKey),
 	}
 
 	feeCollectorAcc := supply.NewEmptyModuleAccount(auth.FeeCollectorName)
@@ -198,7 +201,7 @@ func getMockDexAppEx(t *testing.T, numGenAccs int) (mockDexApp *MockDexApp, keep
 		//store.NewKVStoreKey(staking.RedelegationActonKey),
 		//store.NewKVStoreKey(staking.UnbondingKey),
 
-		store.NewKVStoreKey(staking.TStoreKey),
+		mockDexApp.tkeyStaking,
 		mockDexApp.supplyKeeper,
 		mockDexApp.ParamsKeeper.Subspace(staking.DefaultParamspace),
 		staking.DefaultCodespace,
@@ -243,6 +246,7 @@ func getMockDexAppEx(t *testing.T, numGenAccs int) (mockDexApp *MockDexApp, keep
 		app.keySupply,
 		app.keyGov,
 		app.keyStaking,
+		app.tkeyStaking,
 	)
 
 	require.NoError(t, mockDexApp.CompleteSetup())
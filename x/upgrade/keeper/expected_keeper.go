@@ -18,6 +18,7 @@ type BankKeeper interface {
 type StakingKeeper interface {
 	IsValidator(ctx sdk.Context, addr sdk.AccAddress) bool
 	IterateBondedValidatorsByPower(ctx sdk.Context, fn func(index int64, validator exported.ValidatorI) (stop bool))
+	ConsensusPower(ctx sdk.Context, validator exported.ValidatorI) int64
 	GetValidatorByConsAddr(ctx sdk.Context, consAddr sdk.ConsAddress) (validator types.Validator, found bool)
 }
 
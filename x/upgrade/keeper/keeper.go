@@ -126,6 +126,11 @@ func (k Keeper) IterateBondedValidatorsByPower(ctx sdk.Context,
 	k.stakingKeeper.IterateBondedValidatorsByPower(ctx, fn)
 }
 
+// GetConsensusPower gets the consensus power of a validator by the power reduction taking effect in staking
+func (k Keeper) GetConsensusPower(ctx sdk.Context, validator exported.ValidatorI) int64 {
+	return k.stakingKeeper.ConsensusPower(ctx, validator)
+}
+
 // GetCurrentVersion gets current version
 func (k Keeper) GetCurrentVersion(ctx sdk.Context) uint64 {
 	return k.protocolKeeper.GetCurrentVersion(ctx)
@@ -13,10 +13,12 @@ func tally(ctx sdk.Context, versionProtocol uint64, k Keeper, threshold sdk.Dec)
 
 	// computing voting power
 	k.IterateBondedValidatorsByPower(ctx, func(index int64, validator exported.ValidatorI) (stop bool) {
-		totalVotingPower = totalVotingPower.Add(sdk.NewDec(validator.GetConsensusPower()))
+		// the power reduction of staking is a param, so the power isn't the one by the default power reduction
+		power := sdk.NewDec(k.GetConsensusPower(ctx, validator))
+		totalVotingPower = totalVotingPower.Add(power)
 		valAcc := validator.GetConsAddr().String()
 		if ok := k.GetSignal(ctx, versionProtocol, valAcc); ok {
-			signalsVotingPower = signalsVotingPower.Add(sdk.NewDec(validator.GetConsensusPower()))
+			signalsVotingPower = signalsVotingPower.Add(power)
 		}
 		return false
 	})
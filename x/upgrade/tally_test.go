@@ -41,3 +41,25 @@ func TestTallyNotPassed(t *testing.T) {
 	require.True(t, tally(ctx, 1, keeper, sdk.NewDecWithPrec(5, 2)))
 	require.False(t, tally(ctx, 1, keeper, sdk.NewDecWithPrec(75, 2)))
 }
+
+func TestTallyByPowerReduction(t *testing.T) {
+	ctx, keeper, stakingKeeper, _ := testPrepare(t)
+	description := staking.NewDescription("moniker3", "identity3", "website3", "details3")
+	votes := []int64{10, 10, 19}
+	for i := range votes {
+		validator := staking.NewValidator(sdk.ValAddress(accAddrs[i]), pubKeys[i], description)
+		validator.Status = sdk.Bonded
+		validator.DelegatorShares = sdk.NewDec(votes[i])
+		stakingKeeper.SetValidator(ctx, validator)
+		stakingKeeper.SetValidatorByPowerIndex(ctx, validator)
+		if i < 2 {
+			keeper.SetSignal(ctx, 1, validator.GetConsAddr().String())
+		}
+	}
+	// 20 of 39 by the default power reduction
+	require.False(t, tally(ctx, 1, keeper, sdk.NewDecWithPrec(6, 1)))
+
+	// 2 of 3 by the power reduction in consensus
+	stakingKeeper.SetPowerReduction(ctx, sdk.PowerReduction.MulRaw(10))
+	require.True(t, tally(ctx, 1, keeper, sdk.NewDecWithPrec(6, 1)))
+}